@@ -1,7 +1,6 @@
 package app
 
 import (
-	"fmt"
 	"net/url"
 	"os"
 	"path/filepath"
@@ -31,6 +30,7 @@ func defaultConfig() *Config {
 			JSONViewerWordWrap:           false,
 			EnterOpensJSONViewer:         false,
 			ConfirmOnQuit:                true,
+			ContinueScriptOnError:        false,
 		},
 	}
 }
@@ -198,9 +198,21 @@ func expandEnvVars(s string) string {
 	})
 }
 
+// SaveConnections writes connections to the config file. The URL of a
+// connection set by its fields is left out, the fields being written
+// instead.
 func (c *Config) SaveConnections(connections []models.Connection) error {
 	c.Connections = connections
 
+	saved := *c
+	saved.Connections = make([]models.Connection, len(connections))
+	for i, conn := range connections {
+		if HasConnectionFields(conn) {
+			conn.URL = ""
+		}
+		saved.Connections[i] = conn
+	}
+
 	configFile := c.ConfigFile
 	if c.LocalConfigFile != "" {
 		configFile = c.LocalConfigFile
@@ -216,33 +228,91 @@ func (c *Config) SaveConnections(connections []models.Connection) error {
 	}
 	defer file.Close()
 
-	return toml.NewEncoder(file).Encode(c)
+	return toml.NewEncoder(file).Encode(&saved)
 }
 
-// parseConfigURL automatically generates the URL from the connection struct
-// if the URL is empty. It is useful for handling usernames and passwords with
-// special characters. NOTE: Only MSSQL is supported for now!
+// parseConfigURL returns the URL of conn, built from its fields by
+// BuildConnectionURL if it has none.
 func parseConfigURL(conn *models.Connection) string {
 	if conn.URL != "" {
 		return conn.URL
 	}
+	return BuildConnectionURL(*conn)
+}
 
-	// Only MSSQL is supported for now.
-	if conn.Provider != drivers.DriverMSSQL {
-		return conn.URL
+// SSLModeParam returns the URL parameter the SSLMode of a connection of
+// provider is passed as, empty for the providers without one.
+func SSLModeParam(provider string) string {
+	switch provider {
+	case drivers.DriverPostgres:
+		return "sslmode"
+	case drivers.DriverMySQL:
+		return "tls"
+	case drivers.DriverMSSQL:
+		return "encrypt"
+	}
+	return ""
+}
+
+// BuildConnectionURL builds the URL of conn from its Provider, Username,
+// Password, Hostname, Port, DBName, SSLMode and URLParams. The credentials
+// are URL-escaped, so they may hold any character, while the other fields
+// are kept as is for variables such as ${port} to be replaced later.
+func BuildConnectionURL(conn models.Connection) string {
+	var params []string
+	if key := SSLModeParam(conn.Provider); key != "" && conn.SSLMode != "" {
+		params = append(params, key+"="+url.QueryEscape(conn.SSLMode))
+	}
+	if extra := strings.TrimLeft(conn.URLParams, "?&"); extra != "" {
+		params = append(params, extra)
 	}
 
-	user := url.QueryEscape(conn.Username)
-	pass := url.QueryEscape(conn.Password)
+	if conn.Provider == drivers.DriverSqlite {
+		if len(params) == 0 {
+			return conn.DBName
+		}
+		return "file:" + conn.DBName + "?" + strings.Join(params, "&")
+	}
 
-	return fmt.Sprintf(
-		"%s://%s:%s@%s:%s?database=%s%s",
-		conn.Provider,
-		user,
-		pass,
-		conn.Hostname,
-		conn.Port,
-		conn.DBName,
-		conn.URLParams,
-	)
+	var credentials string
+	switch {
+	case conn.Password != "":
+		credentials = url.UserPassword(conn.Username, conn.Password).String() + "@"
+	case conn.Username != "":
+		credentials = url.User(conn.Username).String() + "@"
+	}
+
+	host := conn.Hostname
+	if strings.Contains(host, ":") && !strings.HasPrefix(host, "[") {
+		// An IPv6 address.
+		host = "[" + host + "]"
+	}
+	if conn.Port != "" {
+		host += ":" + conn.Port
+	}
+
+	path := ""
+	if conn.Provider == drivers.DriverMSSQL {
+		// The database of MSSQL is a parameter, the path being the instance.
+		if conn.DBName != "" {
+			params = append([]string{"database=" + url.QueryEscape(conn.DBName)}, params...)
+		}
+	} else if conn.DBName != "" {
+		path = "/" + url.PathEscape(conn.DBName)
+	}
+
+	connectionURL := conn.Provider + "://" + credentials + host + path
+	if len(params) > 0 {
+		connectionURL += "?" + strings.Join(params, "&")
+	}
+	return connectionURL
+}
+
+// HasConnectionFields reports whether conn is set by its fields rather
+// than by its URL, i.e. its URL is empty or the one built from them.
+func HasConnectionFields(conn models.Connection) bool {
+	if conn.Hostname == "" && conn.DBName == "" && conn.Username == "" {
+		return false
+	}
+	return conn.URL == "" || conn.URL == BuildConnectionURL(conn)
 }
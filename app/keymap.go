@@ -65,6 +65,7 @@ var Keymaps = KeymapSystem{
 			Bind{Key: Key{Code: tcell.KeyCtrlS}, Cmd: cmd.Save, Description: "Execute pending changes"},
 			Bind{Key: Key{Char: 'q'}, Cmd: cmd.Quit, Description: "Quit"},
 			Bind{Key: Key{Code: tcell.KeyBackspace2}, Cmd: cmd.SwitchToConnectionsView, Description: "Switch to connections list"},
+			Bind{Key: Key{Code: tcell.KeyCtrlO}, Cmd: cmd.SwitchConnection, Description: "Switch between open connections"},
 			Bind{Key: Key{Char: '?'}, Cmd: cmd.HelpPopup, Description: "Help"},
 			Bind{Key: Key{Code: tcell.KeyCtrlP}, Cmd: cmd.SearchGlobal, Description: "Global search"},
 			Bind{Key: Key{Code: tcell.KeyCtrlUnderscore}, Cmd: cmd.ToggleQueryHistory, Description: "Toggle query history modal"},
@@ -72,6 +73,10 @@ var Keymaps = KeymapSystem{
 			Bind{Key: Key{Char: '+'}, Cmd: cmd.WidenTree, Description: "Widen tree"},
 			Bind{Key: Key{Char: '='}, Cmd: cmd.WidenTree, Description: "Widen tree"},
 			Bind{Key: Key{Char: '-'}, Cmd: cmd.NarrowTree, Description: "Narrow tree"},
+			Bind{Key: Key{Char: 'B'}, Cmd: cmd.BeginTransaction, Description: "Begin transaction"},
+			Bind{Key: Key{Char: 'M'}, Cmd: cmd.CommitTransaction, Description: "Commit transaction"},
+			Bind{Key: Key{Char: 'U'}, Cmd: cmd.RollbackTransaction, Description: "Rollback transaction"},
+			Bind{Key: Key{Char: 'D'}, Cmd: cmd.CompareSchemas, Description: "Compare schemas"},
 		},
 		ConnectionGroup: {
 			Bind{Key: Key{Char: 'n'}, Cmd: cmd.NewConnection, Description: "Create a new database connection"},
@@ -99,6 +104,10 @@ var Keymaps = KeymapSystem{
 			Bind{Key: Key{Char: 'c'}, Cmd: cmd.TreeCollapseAll, Description: "Collapse all"},
 			Bind{Key: Key{Char: 'e'}, Cmd: cmd.ExpandAll, Description: "Expand all"},
 			Bind{Key: Key{Char: 'R'}, Cmd: cmd.Refresh, Description: "Refresh tree"},
+			Bind{Key: Key{Char: 'o'}, Cmd: cmd.NewTable, Description: "New table"},
+			Bind{Key: Key{Char: 'V'}, Cmd: cmd.ShowDefinition, Description: "Show definition"},
+			Bind{Key: Key{Char: 'r'}, Cmd: cmd.RefreshMaterializedView, Description: "Refresh materialized view"},
+			Bind{Key: Key{Char: 'E'}, Cmd: cmd.ShowERDiagram, Description: "Show ER diagram"},
 		},
 		TreeFilterGroup: {
 			Bind{Key: Key{Code: tcell.KeyEscape}, Cmd: cmd.UnfocusTreeFilter, Description: "Unfocus tree filter"},
@@ -140,12 +149,19 @@ var Keymaps = KeymapSystem{
 			Bind{Key: Key{Char: 'Z'}, Cmd: cmd.ShowRowJSONViewer, Description: "Toggle JSON viewer for row"},
 			Bind{Key: Key{Char: 'z'}, Cmd: cmd.ShowCellJSONViewer, Description: "Toggle JSON viewer for cell"},
 			// Export
-			Bind{Key: Key{Char: 'E'}, Cmd: cmd.ExportCSV, Description: "Export to CSV"},
+			Bind{Key: Key{Char: 'E'}, Cmd: cmd.ExportCSV, Description: "Export data"},
+			Bind{Key: Key{Char: 'I'}, Cmd: cmd.ImportData, Description: "Import data"},
+			Bind{Key: Key{Char: 'A'}, Cmd: cmd.CompareData, Description: "Compare data with another database"},
+			Bind{Key: Key{Char: 'V'}, Cmd: cmd.ShowDefinition, Description: "Show table definition"},
+			Bind{Key: Key{Char: 'F'}, Cmd: cmd.ShowReferencingTables, Description: "Show the tables referencing the row"},
 			// External editor
 			Bind{Key: Key{Char: 'e'}, Cmd: cmd.OpenCellInExternalEditor, Description: "Edit cell in external editor"},
 		},
 		EditorGroup: {
 			Bind{Key: Key{Code: tcell.KeyCtrlR}, Cmd: cmd.Execute, Description: "Execute query"},
+			Bind{Key: Key{Code: tcell.KeyCtrlG}, Cmd: cmd.ExecuteStatement, Description: "Execute statement under cursor"},
+			Bind{Key: Key{Code: tcell.KeyCtrlX}, Cmd: cmd.ExecuteSelection, Description: "Execute selection"},
+			Bind{Key: Key{Code: tcell.KeyCtrlT}, Cmd: cmd.ExplainQuery, Description: "Explain current query"},
 			Bind{Key: Key{Code: tcell.KeyEscape}, Cmd: cmd.UnfocusEditor, Description: "Unfocus editor"},
 			Bind{Key: Key{Code: tcell.KeyCtrlSpace}, Cmd: cmd.OpenInExternalEditor, Description: "Open in external editor"},
 		},
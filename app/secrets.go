@@ -0,0 +1,260 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jorgerojas26/lazysql/helpers/logger"
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+// secretCommandTimeout is how long the command of a ${cmd:} secret, or of
+// the keyring, may run.
+const secretCommandTimeout = 10 * time.Second
+
+// SecretProvider reads the secret of reference, e.g. the output of the
+// command of ${cmd:COMMAND}.
+type SecretProvider func(ctx context.Context, reference string) (string, error)
+
+var (
+	secretProvidersMu sync.RWMutex
+	secretProviders   = map[string]SecretProvider{
+		"cmd":     commandSecret,
+		"file":    fileSecret,
+		"keyring": keyringSecret,
+	}
+)
+
+// secretPattern matches the ${prefix:reference} references of secrets.
+var secretPattern = regexp.MustCompile(`\$\{([a-z]+):([^}]*)\}`)
+
+// RegisterSecretProvider makes ${prefix:reference} resolve through
+// provider when connecting.
+func RegisterSecretProvider(prefix string, provider SecretProvider) {
+	secretProvidersMu.Lock()
+	defer secretProvidersMu.Unlock()
+
+	secretProviders[prefix] = provider
+}
+
+// ResolveSecrets returns conn with the secrets referenced by its URL and
+// fields read from their provider. It is called when connecting, so the
+// loaded and saved connections only hold the references. The secrets read
+// are redacted from the log.
+func ResolveSecrets(ctx context.Context, conn models.Connection) (models.Connection, error) {
+	hasFields := HasConnectionFields(conn)
+
+	var secrets []string
+	for _, field := range []*string{&conn.Username, &conn.Password, &conn.Hostname, &conn.Port, &conn.DBName, &conn.SSLMode, &conn.URLParams} {
+		value, values, err := expandSecrets(ctx, *field, noEscape)
+		if err != nil {
+			return conn, err
+		}
+		*field = value
+		secrets = append(secrets, values...)
+	}
+
+	if hasFields {
+		// Built again for the secrets to be escaped.
+		conn.URL = BuildConnectionURL(conn)
+	} else {
+		value, values, err := expandSecrets(ctx, conn.URL, urlEscaper(conn.URL))
+		if err != nil {
+			return conn, err
+		}
+		conn.URL = value
+		secrets = append(secrets, values...)
+	}
+
+	redactSecrets(conn, secrets)
+	return conn, nil
+}
+
+// expandSecrets replaces the references of secrets in s by their value,
+// escaped by escape for the offset of the reference in s. The references of
+// no known provider, e.g. ${port}, are left unchanged. It returns the values
+// read.
+func expandSecrets(ctx context.Context, s string, escape func(value string, offset int) string) (string, []string, error) {
+	var expanded strings.Builder
+	var values []string
+
+	last := 0
+	for _, match := range secretPattern.FindAllStringSubmatchIndex(s, -1) {
+		expanded.WriteString(s[last:match[0]])
+		last = match[1]
+
+		prefix, reference := s[match[2]:match[3]], s[match[4]:match[5]]
+
+		secretProvidersMu.RLock()
+		provider, ok := secretProviders[prefix]
+		secretProvidersMu.RUnlock()
+		if !ok {
+			expanded.WriteString(s[match[0]:match[1]])
+			continue
+		}
+
+		value, err := provider(ctx, reference)
+		if err != nil {
+			return s, nil, fmt.Errorf("failed to read the %s secret: %w", prefix, err)
+		}
+		values = append(values, value)
+		expanded.WriteString(escape(value, match[0]))
+	}
+	expanded.WriteString(s[last:])
+
+	return expanded.String(), values, nil
+}
+
+func noEscape(value string, _ int) string {
+	return value
+}
+
+// urlEscaper returns the escape of the secrets referenced in urlstr for
+// their offset: in its user info, in its query or elsewhere. The secrets of
+// a string which is not a URL, e.g. a SQLite file, are not escaped.
+func urlEscaper(urlstr string) func(value string, offset int) string {
+	// Masked as the references may hold "/", "@" or "?".
+	masked := secretPattern.ReplaceAllStringFunc(urlstr, func(reference string) string {
+		return strings.Repeat("x", len(reference))
+	})
+
+	schemeEnd := strings.Index(masked, "://")
+	if schemeEnd < 0 {
+		return noEscape
+	}
+
+	authorityStart := schemeEnd + len("://")
+	authorityEnd := len(masked)
+	if i := strings.IndexAny(masked[authorityStart:], "/?#"); i >= 0 {
+		authorityEnd = authorityStart + i
+	}
+
+	userInfoEnd := -1
+	if i := strings.LastIndex(masked[authorityStart:authorityEnd], "@"); i >= 0 {
+		userInfoEnd = authorityStart + i
+	}
+
+	queryStart := len(masked)
+	if i := strings.Index(masked[authorityEnd:], "?"); i >= 0 {
+		queryStart = authorityEnd + i
+	}
+
+	return func(value string, offset int) string {
+		switch {
+		case offset < userInfoEnd:
+			return url.User(value).String()
+		case offset > queryStart:
+			return url.QueryEscape(value)
+		default:
+			return url.PathEscape(value)
+		}
+	}
+}
+
+// redactSecrets hides secrets from the log, as they are and as escaped in a
+// URL. Those which also are the user or database name of conn are not
+// secret, and hiding them would garble the log.
+func redactSecrets(conn models.Connection, secrets []string) {
+	public := []string{conn.Username, conn.DBName}
+	if u, err := url.Parse(conn.URL); err == nil {
+		public = append(public, u.User.Username(), strings.TrimPrefix(u.Path, "/"))
+	}
+
+	for _, secret := range secrets {
+		if slices.Contains(public, secret) {
+			continue
+		}
+
+		forms := []string{secret, url.QueryEscape(secret), url.PathEscape(secret), url.User(secret).String()}
+		slices.Sort(forms)
+		for _, form := range slices.Compact(forms) {
+			logger.AddSecret(form)
+		}
+	}
+}
+
+// commandSecret returns the output of command, run through the shell,
+// without its trailing newline.
+func commandSecret(ctx context.Context, command string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, secretCommandTimeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd.exe", "/C", command) // #nosec G204
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command) // #nosec G204
+	}
+
+	return runSecretCommand(cmd)
+}
+
+func runSecretCommand(cmd *exec.Cmd) (string, error) {
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return "", fmt.Errorf("%w: %s", err, message)
+		}
+		return "", err
+	}
+
+	return strings.TrimRight(string(output), "\r\n"), nil
+}
+
+// fileSecret returns the content of the file at path without its trailing
+// newline, e.g. of a Docker or Kubernetes secret.
+func fileSecret(_ context.Context, path string) (string, error) {
+	content, err := os.ReadFile(path) // #nosec G304
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(content), "\r\n"), nil
+}
+
+// keyringSecret returns the password of the system keyring for reference,
+// "service/account" or "service": through the security command on macOS
+// and secret-tool elsewhere.
+func keyringSecret(ctx context.Context, reference string) (string, error) {
+	service, account, _ := strings.Cut(reference, "/")
+	if service == "" {
+		return "", errors.New("the keyring secret needs a service, e.g. ${keyring:lazysql/prod}")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, secretCommandTimeout)
+	defer cancel()
+
+	var args []string
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "windows":
+		return "", errors.New("the keyring is not supported on Windows, use ${cmd:} instead")
+	case "darwin":
+		args = []string{"find-generic-password", "-s", service, "-w"}
+		if account != "" {
+			args = append(args, "-a", account)
+		}
+		cmd = exec.CommandContext(ctx, "security", args...) // #nosec G204
+	default:
+		args = []string{"lookup", "service", service}
+		if account != "" {
+			args = append(args, "username", account)
+		}
+		cmd = exec.CommandContext(ctx, "secret-tool", args...) // #nosec G204
+	}
+
+	return runSecretCommand(cmd)
+}
@@ -8,12 +8,21 @@ const (
 	// Views
 	SwitchToEditorView
 	SwitchToConnectionsView
+	SwitchConnection
 	HelpPopup
 	ToggleQueryHistory
 	ToggleTree
 	WidenTree
 	NarrowTree
 
+	// Transactions
+	BeginTransaction
+	CommitTransaction
+	RollbackTransaction
+
+	// Schema
+	CompareSchemas
+
 	// Movement: Basic
 	MoveUp
 	MoveDown
@@ -59,6 +68,9 @@ const (
 	SearchGlobal
 	Quit
 	Execute
+	ExecuteStatement
+	ExecuteSelection
+	ExplainQuery
 	OpenInExternalEditor
 	OpenCellInExternalEditor
 	AppendNewRow
@@ -71,6 +83,11 @@ const (
 	PreviousFoundNode
 	TreeCollapseAll
 	ExpandAll
+	NewTable
+	ShowDefinition
+	RefreshMaterializedView
+	ShowReferencingTables
+	ShowERDiagram
 	SetValue
 	FocusSidebar
 	UnfocusSidebar
@@ -88,6 +105,8 @@ const (
 
 	// Export
 	ExportCSV
+	ImportData
+	CompareData
 )
 
 func (c Command) String() string {
@@ -99,6 +118,8 @@ func (c Command) String() string {
 		return "SwitchToEditorView"
 	case SwitchToConnectionsView:
 		return "SwitchToConnectionsView"
+	case SwitchConnection:
+		return "SwitchConnection"
 	case HelpPopup:
 		return "HelpPopup"
 	case ToggleQueryHistory:
@@ -110,6 +131,18 @@ func (c Command) String() string {
 	case NarrowTree:
 		return "NarrowTree"
 
+	// Transactions
+	case BeginTransaction:
+		return "BeginTransaction"
+	case CommitTransaction:
+		return "CommitTransaction"
+	case RollbackTransaction:
+		return "RollbackTransaction"
+
+	// Schema
+	case CompareSchemas:
+		return "CompareSchemas"
+
 	// Movement: Basic
 	case MoveUp:
 		return "MoveUp"
@@ -170,6 +203,12 @@ func (c Command) String() string {
 		return "Quit"
 	case Execute:
 		return "Execute"
+	case ExecuteStatement:
+		return "ExecuteStatement"
+	case ExecuteSelection:
+		return "ExecuteSelection"
+	case ExplainQuery:
+		return "ExplainQuery"
 	case OpenInExternalEditor:
 		return "OpenInExternalEditor"
 	case OpenCellInExternalEditor:
@@ -218,6 +257,16 @@ func (c Command) String() string {
 		return "TreeCollapseAll"
 	case ExpandAll:
 		return "ExpandAll"
+	case NewTable:
+		return "NewTable"
+	case ShowDefinition:
+		return "ShowDefinition"
+	case RefreshMaterializedView:
+		return "RefreshMaterializedView"
+	case ShowReferencingTables:
+		return "ShowReferencingTables"
+	case ShowERDiagram:
+		return "ShowERDiagram"
 	case SetValue:
 		return "SetValue"
 	case FocusSidebar:
@@ -238,6 +287,10 @@ func (c Command) String() string {
 		return "ToggleJSONViewerWrap"
 	case ExportCSV:
 		return "ExportCSV"
+	case ImportData:
+		return "ImportData"
+	case CompareData:
+		return "CompareData"
 	}
 
 	return "Unknown"
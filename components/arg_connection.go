@@ -28,25 +28,21 @@ func InitFromArg(connectionString string, readOnly bool) error {
 		ReadOnly: readOnly,
 	}
 
-	var newDBDriver drivers.Driver
-	switch connection.Provider {
-	case drivers.DriverMySQL:
-		newDBDriver = &drivers.MySQL{}
-	case drivers.DriverPostgres:
-		newDBDriver = &drivers.Postgres{}
-	case drivers.DriverSqlite:
-		newDBDriver = &drivers.SQLite{}
-	case drivers.DriverMSSQL:
-		newDBDriver = &drivers.MSSQL{}
-	default:
+	newDBDriver, err := newDriver(connection)
+	if err != nil {
 		return fmt.Errorf("could not handle database driver %s", connection.Provider)
 	}
 
-	err = newDBDriver.Connect(connection.URL)
+	c := &connector{connection: connection}
+	connection, err = c.connect(App.Context(), newDBDriver, func(string) {})
 	if err != nil {
 		return fmt.Errorf("could not connect to database %s: %s", connectionString, err)
 	}
-	mainPages.AddAndSwitchToPage(connection.URL, NewHomePage(connection, newDBDriver).Flex, true)
+
+	reconnecting := &drivers.Reconnecting{Driver: newDBDriver}
+	newHome := NewHomePage(connection, reconnecting)
+	newHome.watchConnection(c, reconnecting)
+	mainPages.AddAndSwitchToPage(connection.URL, newHome.Flex, true)
 
 	return nil
 }
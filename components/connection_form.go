@@ -1,6 +1,8 @@
 package components
 
 import (
+	"errors"
+	"slices"
 	"strings"
 
 	"github.com/gdamore/tcell/v2"
@@ -12,11 +14,24 @@ import (
 	"github.com/jorgerojas26/lazysql/models"
 )
 
+// The modes of the connection form: a connection is set either by its URL
+// or by its host, port, user and other fields.
+const (
+	connectionModeURL    string = "URL"
+	connectionModeFields string = "Fields"
+)
+
+var (
+	connectionModes     = []string{connectionModeURL, connectionModeFields}
+	connectionProviders = []string{drivers.DriverPostgres, drivers.DriverMySQL, drivers.DriverMSSQL, drivers.DriverSqlite}
+)
+
 type ConnectionForm struct {
 	*tview.Flex
 	*tview.Form
 	StatusText *tview.TextView
 	Action     string
+	mode       string
 }
 
 func NewConnectionForm(connectionPages *models.ConnectionPages) *ConnectionForm {
@@ -25,9 +40,6 @@ func NewConnectionForm(connectionPages *models.ConnectionPages) *ConnectionForm
 	wrapper.SetDirection(tview.FlexColumnCSS)
 
 	addForm := tview.NewForm().SetFieldBackgroundColor(app.Styles.InverseTextColor).SetButtonBackgroundColor(tview.Styles.InverseTextColor).SetLabelColor(tview.Styles.PrimaryTextColor).SetFieldTextColor(tview.Styles.ContrastSecondaryTextColor)
-	addForm.AddInputField("Name", "", 0, nil, nil)
-	addForm.AddInputField("URL", "", 0, nil, nil)
-	addForm.AddCheckbox("Read-Only", false, nil)
 
 	buttonsWrapper := tview.NewFlex().SetDirection(tview.FlexColumn)
 
@@ -71,6 +83,8 @@ func NewConnectionForm(connectionPages *models.ConnectionPages) *ConnectionForm
 		StatusText: statusText,
 	}
 
+	form.SetConnectionData(models.Connection{})
+
 	wrapper.SetInputCapture(form.inputCapture(connectionPages))
 
 	return form
@@ -78,19 +92,15 @@ func NewConnectionForm(connectionPages *models.ConnectionPages) *ConnectionForm
 
 func (form *ConnectionForm) inputCapture(connectionPages *models.ConnectionPages) func(event *tcell.EventKey) *tcell.EventKey {
 	return func(event *tcell.EventKey) *tcell.EventKey {
+		if _, ok := App.GetFocus().(*tview.List); ok {
+			// The list of an open dropdown.
+			return event
+		}
+
 		if event.Key() == tcell.KeyEsc {
 			connectionPages.SwitchToPage(pageNameConnectionSelection)
 		} else if event.Key() == tcell.KeyF1 || event.Key() == tcell.KeyEnter {
-			connectionName := form.GetFormItem(0).(*tview.InputField).GetText()
-
-			if connectionName == "" {
-				form.StatusText.SetText("Connection name is required").SetTextStyle(tcell.StyleDefault.Foreground(tcell.ColorRed))
-				return event
-			}
-
-			connectionString := form.GetFormItem(1).(*tview.InputField).GetText()
-
-			parsed, err := helpers.ParseConnectionString(connectionString)
+			parsedDatabaseData, err := form.connection()
 			if err != nil {
 				form.StatusText.SetText(err.Error()).SetTextStyle(tcell.StyleDefault.Foreground(tcell.ColorRed))
 				return event
@@ -99,22 +109,6 @@ func (form *ConnectionForm) inputCapture(connectionPages *models.ConnectionPages
 			databases := app.App.Connections()
 			newDatabases := make([]models.Connection, len(databases))
 
-			DBName := strings.Split(parsed.Normalize(",", "NULL", 0), ",")[3]
-
-			if DBName == "NULL" {
-				DBName = ""
-			}
-
-			readOnly := form.GetFormItem(2).(*tview.Checkbox).IsChecked()
-
-			parsedDatabaseData := models.Connection{
-				Name:     connectionName,
-				Provider: parsed.Driver,
-				DBName:   DBName,
-				URL:      connectionString,
-				ReadOnly: readOnly,
-			}
-
 			switch form.Action {
 			case actionNewConnection:
 
@@ -132,14 +126,23 @@ func (form *ConnectionForm) inputCapture(connectionPages *models.ConnectionPages
 				for i, database := range databases {
 					if i == row {
 						// Start from the existing connection so fields not
-						// present on the form (Commands, Username, Password,
-						// Hostname, Port, URLParams, Schemas, ...) are preserved.
+						// present on the form (Commands, Schemas, ...) are
+						// preserved, as are the fields of a connection set
+						// by its URL.
 						updated := database
 						updated.Name = parsedDatabaseData.Name
 						updated.Provider = parsedDatabaseData.Provider
 						updated.DBName = parsedDatabaseData.DBName
 						updated.URL = parsedDatabaseData.URL
 						updated.ReadOnly = parsedDatabaseData.ReadOnly
+						if form.mode == connectionModeFields {
+							updated.Username = parsedDatabaseData.Username
+							updated.Password = parsedDatabaseData.Password
+							updated.Hostname = parsedDatabaseData.Hostname
+							updated.Port = parsedDatabaseData.Port
+							updated.SSLMode = parsedDatabaseData.SSLMode
+							updated.URLParams = parsedDatabaseData.URLParams
+						}
 						newDatabases[i] = updated
 					} else {
 						newDatabases[i] = database
@@ -158,14 +161,83 @@ func (form *ConnectionForm) inputCapture(connectionPages *models.ConnectionPages
 			connectionPages.SwitchToPage(pageNameConnectionSelection)
 
 		} else if event.Key() == tcell.KeyF2 {
-			connectionString := form.GetFormItem(1).(*tview.InputField).GetText()
-			go form.testConnection(connectionString)
+			connection, err := form.connection()
+			if err != nil {
+				form.StatusText.SetText(err.Error()).SetTextStyle(tcell.StyleDefault.Foreground(tcell.ColorRed))
+				return event
+			}
+			go form.testConnection(connection)
 		}
 		return event
 	}
 }
 
-func (form *ConnectionForm) testConnection(connectionString string) {
+// connection returns the connection set by the form.
+func (form *ConnectionForm) connection() (models.Connection, error) {
+	connection := models.Connection{
+		Name:     form.inputText("Name"),
+		ReadOnly: form.GetFormItemByLabel("Read-Only").(*tview.Checkbox).IsChecked(),
+	}
+	if connection.Name == "" {
+		return connection, errors.New("connection name is required")
+	}
+
+	if form.mode == connectionModeURL {
+		connectionString := form.inputText("URL")
+
+		parsed, err := helpers.ParseConnectionString(connectionString)
+		if err != nil {
+			return connection, err
+		}
+
+		DBName := strings.Split(parsed.Normalize(",", "NULL", 0), ",")[3]
+
+		if DBName == "NULL" {
+			DBName = ""
+		}
+
+		connection.Provider = parsed.Driver
+		connection.DBName = DBName
+		connection.URL = connectionString
+		return connection, nil
+	}
+
+	_, connection.Provider = form.GetFormItemByLabel("Provider").(*tview.DropDown).GetCurrentOption()
+	connection.Hostname = form.inputText("Host")
+	connection.Port = form.inputText("Port")
+	connection.Username = form.inputText("User")
+	connection.Password = form.inputText("Password")
+	connection.DBName = form.inputText("Database")
+	connection.SSLMode = form.inputText("SSL mode")
+	connection.URLParams = form.inputText("Extra params")
+
+	switch {
+	case connection.Provider == drivers.DriverSqlite && connection.DBName == "":
+		return connection, errors.New("database file is required")
+	case connection.Provider != drivers.DriverSqlite && connection.Hostname == "":
+		return connection, errors.New("host is required")
+	}
+
+	connection.URL = app.BuildConnectionURL(connection)
+	return connection, nil
+}
+
+func (form *ConnectionForm) inputText(label string) string {
+	if field, ok := form.GetFormItemByLabel(label).(*tview.InputField); ok {
+		return field.GetText()
+	}
+	return ""
+}
+
+func (form *ConnectionForm) testConnection(connection models.Connection) {
+	resolved, err := app.ResolveSecrets(App.Context(), connection)
+	if err != nil {
+		form.StatusText.SetText(err.Error()).SetTextStyle(tcell.StyleDefault.Foreground(tcell.ColorRed))
+		App.ForceDraw()
+		return
+	}
+	connectionString := resolved.URL
+
 	parsed, err := helpers.ParseConnectionString(connectionString)
 	if err != nil {
 		form.StatusText.SetText(err.Error()).SetTextStyle(tcell.StyleDefault.Foreground(tcell.ColorRed))
@@ -187,7 +259,7 @@ func (form *ConnectionForm) testConnection(connectionString string) {
 		db = &drivers.MSSQL{}
 	}
 
-	err = db.TestConnection(connectionString)
+	err = db.TestConnection(App.Context(), connectionString)
 
 	if err != nil {
 		form.StatusText.SetText(err.Error()).SetTextStyle(tcell.StyleDefault.Foreground(tcell.ColorRed))
@@ -201,8 +273,123 @@ func (form *ConnectionForm) SetAction(action string) {
 	form.Action = action
 }
 
+// SetConnectionData fills the form with conn, in the fields mode if it is
+// set by its fields.
 func (form *ConnectionForm) SetConnectionData(conn models.Connection) {
-	form.GetFormItem(0).(*tview.InputField).SetText(conn.Name)
-	form.GetFormItem(1).(*tview.InputField).SetText(conn.URL)
-	form.GetFormItem(2).(*tview.Checkbox).SetChecked(conn.ReadOnly)
+	mode := connectionModeURL
+	if app.HasConnectionFields(conn) {
+		mode = connectionModeFields
+	}
+	form.setMode(mode, conn)
+}
+
+// switchMode shows the form in mode, keeping what was entered in the other
+// mode: the URL is split into fields, the fields are joined into a URL.
+func (form *ConnectionForm) switchMode(mode string) {
+	if mode == form.mode {
+		return
+	}
+
+	conn := models.Connection{
+		Name:     form.inputText("Name"),
+		ReadOnly: form.GetFormItemByLabel("Read-Only").(*tview.Checkbox).IsChecked(),
+	}
+
+	if mode == connectionModeFields {
+		if fields, err := connectionFields(form.inputText("URL")); err == nil {
+			fields.Name, fields.ReadOnly = conn.Name, conn.ReadOnly
+			conn = fields
+		}
+	} else {
+		_, conn.Provider = form.GetFormItemByLabel("Provider").(*tview.DropDown).GetCurrentOption()
+		conn.Hostname = form.inputText("Host")
+		conn.Port = form.inputText("Port")
+		conn.Username = form.inputText("User")
+		conn.Password = form.inputText("Password")
+		conn.DBName = form.inputText("Database")
+		conn.SSLMode = form.inputText("SSL mode")
+		conn.URLParams = form.inputText("Extra params")
+		if conn.Hostname != "" || conn.DBName != "" {
+			conn.URL = app.BuildConnectionURL(conn)
+		}
+	}
+
+	form.setMode(mode, conn)
+	form.SetFocus(1)
+	App.SetFocus(form.Form)
+}
+
+// setMode replaces the items of the form by those of mode, filled with
+// conn.
+func (form *ConnectionForm) setMode(mode string, conn models.Connection) {
+	form.mode = mode
+	form.Form.Clear(false)
+
+	form.AddInputField("Name", conn.Name, 0, nil, nil)
+	form.AddDropDown("Mode", connectionModes, slices.Index(connectionModes, mode), nil)
+
+	if mode == connectionModeURL {
+		form.AddInputField("URL", conn.URL, 0, nil, nil)
+	} else {
+		provider := max(slices.Index(connectionProviders, conn.Provider), 0)
+		form.AddDropDown("Provider", connectionProviders, provider, nil)
+		form.AddInputField("Host", conn.Hostname, 0, nil, nil)
+		form.AddInputField("Port", conn.Port, 0, nil, nil)
+		form.AddInputField("User", conn.Username, 0, nil, nil)
+		form.AddPasswordField("Password", conn.Password, 0, '*', nil)
+		form.AddInputField("Database", conn.DBName, 0, nil, nil)
+		form.AddInputField("SSL mode", conn.SSLMode, 0, nil, nil)
+		form.AddInputField("Extra params", conn.URLParams, 0, nil, nil)
+	}
+
+	form.AddCheckbox("Read-Only", conn.ReadOnly, nil)
+
+	// Set once the items are added, since setting it selects the current
+	// option.
+	form.GetFormItemByLabel("Mode").(*tview.DropDown).SetSelectedFunc(func(text string, _ int) {
+		if text != form.mode {
+			// Not while the dropdown handles the selection.
+			go App.QueueUpdateDraw(func() { form.switchMode(text) })
+		}
+	})
+}
+
+// connectionFields splits connectionURL into the fields of a connection.
+func connectionFields(connectionURL string) (models.Connection, error) {
+	parsed, err := helpers.ParseConnectionString(connectionURL)
+	if err != nil {
+		return models.Connection{}, err
+	}
+
+	conn := models.Connection{
+		Provider: parsed.Driver,
+		Hostname: parsed.Hostname(),
+		Port:     parsed.Port(),
+	}
+	if parsed.User != nil {
+		conn.Username = parsed.User.Username()
+		conn.Password, _ = parsed.User.Password()
+	}
+
+	query := parsed.Query()
+	switch parsed.Driver {
+	case drivers.DriverSqlite:
+		conn.DBName = parsed.Opaque
+		if conn.DBName == "" {
+			conn.DBName = parsed.Path
+		}
+	case drivers.DriverMSSQL:
+		conn.DBName = query.Get("database")
+		query.Del("database")
+	default:
+		conn.DBName = strings.TrimPrefix(parsed.Path, "/")
+	}
+
+	if key := app.SSLModeParam(parsed.Driver); key != "" {
+		conn.SSLMode = query.Get(key)
+		query.Del(key)
+	}
+	conn.URLParams = query.Encode()
+
+	return conn, nil
 }
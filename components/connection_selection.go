@@ -2,10 +2,6 @@ package components
 
 import (
 	"fmt"
-	"slices"
-	"strconv"
-	"strings"
-	"time"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
@@ -13,7 +9,6 @@ import (
 	"github.com/jorgerojas26/lazysql/app"
 	"github.com/jorgerojas26/lazysql/commands"
 	"github.com/jorgerojas26/lazysql/drivers"
-	"github.com/jorgerojas26/lazysql/helpers"
 	"github.com/jorgerojas26/lazysql/helpers/logger"
 	"github.com/jorgerojas26/lazysql/models"
 )
@@ -90,8 +85,8 @@ func NewConnectionSelection(connectionForm *ConnectionForm, connectionPages *mod
 			case commands.Connect:
 				go cs.Connect(selectedConnection)
 			case commands.EditConnection:
-				connectionPages.SwitchToPage(pageNameConnectionForm)
 				connectionForm.SetConnectionData(selectedConnection)
+				connectionPages.SwitchToPage(pageNameConnectionForm)
 				connectionForm.StatusText.SetText("")
 
 				connectionForm.SetAction(actionEditConnection)
@@ -111,6 +106,7 @@ func NewConnectionSelection(connectionForm *ConnectionForm, connectionPages *mod
 							connectionsTable.SetError(err)
 						} else {
 							connectionsTable.SetConnections(newConnections)
+							closeConnectionHome(selectedConnection.Name)
 						}
 
 					}
@@ -125,8 +121,7 @@ func NewConnectionSelection(connectionForm *ConnectionForm, connectionPages *mod
 		switch command {
 		case commands.NewConnection:
 			connectionForm.SetAction(actionNewConnection)
-			connectionForm.GetFormItemByLabel("Name").(*tview.InputField).SetText("")
-			connectionForm.GetFormItemByLabel("URL").(*tview.InputField).SetText("")
+			connectionForm.SetConnectionData(models.Connection{})
 			connectionForm.StatusText.SetText("")
 			connectionPages.SwitchToPage(pageNameConnectionForm)
 		case commands.Quit:
@@ -148,105 +143,17 @@ func (cs *ConnectionSelection) Connect(connection models.Connection) *tview.Appl
 		return App.Draw()
 	}
 
-	if len(connection.Commands) > 0 {
-
-		// Contains variables -- both the generated port and user-defined.
-		variables := map[string]string{}
-
-		// Avoid getting the port when it's not requested.
-		waitsForPort := strings.Contains(connection.URL, "${port}")
-		waitsForPort = waitsForPort || slices.ContainsFunc(connection.Commands, func(command *models.Command) bool {
-			return command.WaitForPort != ""
-		})
-
-		if waitsForPort {
-			port, err := helpers.GetFreePort()
-			if err != nil {
-				cs.StatusText.SetText(err.Error()).SetTextStyle(tcell.StyleDefault.Foreground(tcell.ColorRed))
-				return App.Draw()
-			}
-			// Add port variable for the auto-generated port.
-			variables["port"] = port
-		}
-
-		for i, command := range connection.Commands {
-			message := fmt.Sprintf("Running command %d/%d...", i+1, len(connection.Commands))
-			cs.StatusText.SetText(message).SetTextColor(app.Styles.TertiaryTextColor)
-			App.Draw()
-
-			cmd := command.Command
-			for variable, value := range variables {
-				cmd = strings.ReplaceAll(cmd, "${"+variable+"}", value)
-			}
-
-			markCommandComplete := App.Register()
-			onCommandDone, waitToCaptureVariable := setupOutputVariableCommand(variables, command, markCommandComplete)
-
-			// Use configured timeout or default to 5 seconds
-			timeout := time.Duration(command.Timeout) * time.Second
-			if command.Timeout == 0 {
-				timeout = 5 * time.Second
-			}
-
-			if err := helpers.RunCommand(App.Context(), cmd, timeout, onCommandDone); err != nil {
-				cs.StatusText.SetText(err.Error()).SetTextStyle(tcell.StyleDefault.Foreground(tcell.ColorRed))
-				return App.Draw()
-			}
-
-			waitToCaptureVariable()
-
-			if command.WaitForPort != "" {
-				interpolatedPort := command.WaitForPort
-				for variable, value := range variables {
-					interpolatedPort = strings.ReplaceAll(interpolatedPort, "${"+variable+"}", value)
-				}
-
-				if portInt, err := strconv.Atoi(interpolatedPort); err != nil || portInt < 0 || portInt >= 1<<16 {
-					cs.StatusText.SetText("bad port: " + interpolatedPort).SetTextStyle(tcell.StyleDefault.Foreground(tcell.ColorRed))
-					return App.Draw()
-				}
-
-				message := fmt.Sprintf("Waiting for port %s...", interpolatedPort)
-				cs.StatusText.SetText(message).SetTextColor(app.Styles.TertiaryTextColor)
-				App.Draw()
-
-				if err := helpers.WaitForPort(App.Context(), interpolatedPort); err != nil {
-					cs.StatusText.SetText(err.Error()).SetTextStyle(tcell.StyleDefault.Foreground(tcell.ColorRed))
-					return App.Draw()
-				}
-			}
-		}
-
-		// Replace variables in URL.
-		for variable, value := range variables {
-			if variable == "" || value == "" {
-				continue
-			}
-			connection.URL = strings.ReplaceAll(connection.URL, "${"+variable+"}", value)
-		}
-	}
-
-	cs.StatusText.SetText("Connecting...").SetTextColor(app.Styles.TertiaryTextColor)
-	App.Draw()
-
-	var newDBDriver drivers.Driver
-
-	switch connection.Provider {
-	case drivers.DriverMySQL:
-		newDBDriver = &drivers.MySQL{}
-	case drivers.DriverPostgres:
-		newDBDriver = &drivers.Postgres{}
-	case drivers.DriverSqlite:
-		newDBDriver = &drivers.SQLite{}
-	case drivers.DriverMSSQL:
-		newDBDriver = &drivers.MSSQL{}
-	default:
-		errorMsg := fmt.Sprintf("Unsupported database provider: '%s'. Valid providers are: mysql, postgres, sqlite3, sqlserver", connection.Provider)
-		cs.StatusText.SetText(errorMsg).SetTextStyle(tcell.StyleDefault.Foreground(tcell.ColorRed))
+	newDBDriver, err := newDriver(connection)
+	if err != nil {
+		cs.StatusText.SetText(err.Error()).SetTextStyle(tcell.StyleDefault.Foreground(tcell.ColorRed))
 		return App.Draw()
 	}
 
-	err := newDBDriver.Connect(connection.URL)
+	c := &connector{connection: connection}
+	connection, err = c.connect(App.Context(), newDBDriver, func(status string) {
+		cs.StatusText.SetText(status).SetTextColor(app.Styles.TertiaryTextColor)
+		App.Draw()
+	})
 	if err != nil {
 		cs.StatusText.SetText(err.Error()).SetTextStyle(tcell.StyleDefault.Foreground(tcell.ColorRed))
 		return App.Draw()
@@ -257,9 +164,11 @@ func (cs *ConnectionSelection) Connect(connection models.Connection) *tview.Appl
 	cell.SetText(fmt.Sprintf("[green]* %s", cell.Text))
 	cs.StatusText.SetText("")
 
-	newHome := NewHomePage(connection, newDBDriver)
+	reconnecting := &drivers.Reconnecting{Driver: newDBDriver}
+	newHome := NewHomePage(connection, reconnecting)
 	newHome.Tree.SetCurrentNode(newHome.Tree.GetRoot())
 	newHome.Tree.Wrapper.SetTitle(connection.Name)
+	newHome.watchConnection(c, reconnecting)
 
 	mainPages.AddAndSwitchToPage(connection.Name, newHome, true)
 	App.SetFocus(newHome.Tree)
@@ -0,0 +1,178 @@
+package components
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/lithammer/fuzzysearch/fuzzy"
+	"github.com/rivo/tview"
+
+	"github.com/jorgerojas26/lazysql/app"
+)
+
+// ConnectionSwitcher lists the open connections, with their state, to
+// switch to one of them. Each connection keeps its tabs, editors and
+// pending changes.
+type ConnectionSwitcher struct {
+	tview.Primitive
+	current *Home
+	search  *tview.InputField
+	list    *tview.Table
+	homes   []*Home
+}
+
+// showConnectionSwitcher opens a ConnectionSwitcher from home.
+func (home *Home) showConnectionSwitcher() {
+	s := &ConnectionSwitcher{current: home}
+
+	s.list = tview.NewTable().SetSelectable(true, false)
+	s.list.SetBorder(true).SetTitle(" Open connections ").SetTitleAlign(tview.AlignLeft)
+	s.list.SetBorderColor(app.Styles.PrimaryTextColor)
+	s.list.SetSelectedFunc(func(row, _ int) { s.open(row) })
+
+	s.search = tview.NewInputField().SetLabel("Search: ")
+	s.search.SetFieldBackgroundColor(app.Styles.PrimitiveBackgroundColor)
+	s.search.SetChangedFunc(func(string) { s.filter() })
+	s.search.SetInputCapture(s.searchInputCapture)
+
+	hint := tview.NewTextView().
+		SetText("Type to search, Enter to switch, Esc to close").
+		SetTextAlign(tview.AlignCenter).
+		SetTextColor(app.Styles.TertiaryTextColor)
+
+	content := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(s.search, 1, 0, true).
+		AddItem(s.list, 0, 1, false).
+		AddItem(hint, 1, 0, false)
+
+	s.Primitive = tview.NewGrid().
+		SetRows(0, 20, 0).
+		SetColumns(0, 80, 0).
+		AddItem(content, 1, 1, 1, 1, 0, 0, true)
+
+	s.filter()
+
+	// Select the first other connection, to switch back and forth.
+	for i, h := range s.homes {
+		if h != home {
+			s.list.Select(i, 0)
+			break
+		}
+	}
+
+	mainPages.AddPage(pageNameConnectionSwitcher, s, true, true)
+	App.SetFocus(s.search)
+}
+
+func (s *ConnectionSwitcher) searchInputCapture(event *tcell.EventKey) *tcell.EventKey {
+	row, _ := s.list.GetSelection()
+
+	switch event.Key() {
+	case tcell.KeyEsc:
+		s.close()
+		return nil
+	case tcell.KeyEnter:
+		s.open(row)
+		return nil
+	case tcell.KeyDown, tcell.KeyCtrlJ, tcell.KeyTab:
+		if row < len(s.homes)-1 {
+			s.list.Select(row+1, 0)
+		}
+		return nil
+	case tcell.KeyUp, tcell.KeyCtrlK, tcell.KeyBacktab:
+		if row > 0 {
+			s.list.Select(row-1, 0)
+		}
+		return nil
+	}
+	return event
+}
+
+// filter lists the open connections matching the search, the best matches
+// first.
+func (s *ConnectionSwitcher) filter() {
+	s.homes = filterHomes(openHomes, s.search.GetText())
+
+	s.list.Clear()
+	if len(s.homes) == 0 {
+		s.list.SetCell(0, 0, tview.NewTableCell("No open connection matches").SetTextColor(app.Styles.TertiaryTextColor).SetSelectable(false))
+		return
+	}
+
+	for i, home := range s.homes {
+		name := home.ConnectionIdentifier
+		if home == s.current {
+			name = "* " + name
+		}
+		s.list.SetCell(i, 0, tview.NewTableCell(tview.Escape(name)).SetTextColor(app.Styles.PrimaryTextColor).SetExpansion(1))
+		s.list.SetCell(i, 1, tview.NewTableCell(tview.Escape(strings.Join(homeStates(home), ", "))).SetTextColor(tcell.ColorOrange))
+	}
+	s.list.Select(0, 0)
+}
+
+// filterHomes returns the homes whose connection fuzzily matches search,
+// the best matches first.
+func filterHomes(homes []*Home, search string) []*Home {
+	if search == "" {
+		return append([]*Home(nil), homes...)
+	}
+
+	type match struct {
+		home *Home
+		rank int
+	}
+	var matches []match
+	for _, home := range homes {
+		if rank := fuzzy.RankMatchFold(search, home.ConnectionIdentifier); rank >= 0 {
+			matches = append(matches, match{home, rank})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].rank < matches[j].rank
+	})
+
+	filtered := make([]*Home, len(matches))
+	for i, m := range matches {
+		filtered[i] = m.home
+	}
+	return filtered
+}
+
+// homeStates describes the state of the connection of home: read-only,
+// with pending changes, in a transaction or lost.
+func homeStates(home *Home) []string {
+	var states []string
+	if home.ReadOnly {
+		states = append(states, "read-only")
+	}
+	if pending := len(home.ListOfDBChanges) + len(home.ListOfDDLChanges); pending > 0 {
+		states = append(states, fmt.Sprintf("%d pending", pending))
+	}
+	if home.DBDriver.InTransaction() {
+		states = append(states, "transaction")
+	}
+	if connectionStatus, _ := home.statuses(); connectionStatus != "" {
+		states = append(states, "disconnected")
+	}
+	return states
+}
+
+func (s *ConnectionSwitcher) open(row int) {
+	if row < 0 || row >= len(s.homes) {
+		return
+	}
+
+	home := s.homes[row]
+	s.close()
+	if home != s.current {
+		mainPages.SwitchToPage(home.pageName)
+	}
+}
+
+func (s *ConnectionSwitcher) close() {
+	mainPages.RemovePage(pageNameConnectionSwitcher)
+	App.SetFocus(s.current)
+}
@@ -0,0 +1,56 @@
+package components
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jorgerojas26/lazysql/drivers"
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+func TestFilterHomes(t *testing.T) {
+	production := &Home{ConnectionIdentifier: "production"}
+	staging := &Home{ConnectionIdentifier: "staging"}
+	local := &Home{ConnectionIdentifier: "local-postgres"}
+	homes := []*Home{production, staging, local}
+
+	tests := []struct {
+		search   string
+		expected []*Home
+	}{
+		{search: "", expected: homes},
+		{search: "prod", expected: []*Home{production}},
+		{search: "PG", expected: []*Home{local}},
+		// The closest matches come first.
+		{search: "stg", expected: []*Home{staging, local}},
+		{search: "o", expected: []*Home{production, local}},
+		{search: "mysql", expected: []*Home{}},
+	}
+
+	for _, tt := range tests {
+		if got := filterHomes(homes, tt.search); !reflect.DeepEqual(identifiers(got), identifiers(tt.expected)) {
+			t.Errorf("filterHomes(%q) = %v, want %v", tt.search, identifiers(got), identifiers(tt.expected))
+		}
+	}
+}
+
+func identifiers(homes []*Home) []string {
+	names := make([]string, len(homes))
+	for i, home := range homes {
+		names[i] = home.ConnectionIdentifier
+	}
+	return names
+}
+
+func TestHomeStates(t *testing.T) {
+	home := &Home{
+		DBDriver:         &drivers.SQLite{},
+		ReadOnly:         true,
+		ListOfDBChanges:  []models.DBDMLChange{{}, {}},
+		ListOfDDLChanges: []models.DBDDLChange{{}},
+	}
+
+	if states := homeStates(home); !reflect.DeepEqual(states, []string{"read-only", "3 pending"}) {
+		t.Errorf("homeStates() = %v", states)
+	}
+}
@@ -0,0 +1,218 @@
+package components
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jorgerojas26/lazysql/app"
+	"github.com/jorgerojas26/lazysql/drivers"
+	"github.com/jorgerojas26/lazysql/helpers"
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+// connector connects a driver to a connection: it resolves the secrets of
+// the connection, opens its SSH tunnel and runs its commands first. The home
+// of the connection keeps it to connect again when the connection is lost.
+type connector struct {
+	connection models.Connection
+	// tunnel is opened on the first connection and kept open, opening its
+	// SSH connection again by itself.
+	tunnel *helpers.SSHTunnel
+}
+
+// newDriver returns the driver of the provider of connection, setting its
+// session options on every connection it opens.
+func newDriver(connection models.Connection) (drivers.Driver, error) {
+	provider := connection.Provider
+
+	var statements []string
+	switch provider {
+	case drivers.DriverMySQL, drivers.DriverPostgres, drivers.DriverSqlite, drivers.DriverMSSQL:
+		var err error
+		if statements, err = drivers.SessionStatements(provider, connection); err != nil {
+			return nil, err
+		}
+	}
+
+	switch provider {
+	case drivers.DriverMySQL:
+		return &drivers.MySQL{InitStatements: statements}, nil
+	case drivers.DriverPostgres:
+		return &drivers.Postgres{InitStatements: statements}, nil
+	case drivers.DriverSqlite:
+		return &drivers.SQLite{InitStatements: statements}, nil
+	case drivers.DriverMSSQL:
+		return &drivers.MSSQL{InitStatements: statements}, nil
+	default:
+		return nil, fmt.Errorf("unsupported database provider: '%s'. Valid providers are: mysql, postgres, sqlite3, sqlserver", provider)
+	}
+}
+
+// connect connects driver, reporting its progress to setStatus. It returns
+// the connection with its variables, but not its secrets, replaced.
+func (c *connector) connect(ctx context.Context, driver drivers.Driver, setStatus func(string)) (models.Connection, error) {
+	connection := c.connection
+
+	// The secrets are only resolved to connect: the home keeps their
+	// references.
+	resolved, err := app.ResolveSecrets(ctx, connection)
+	if err != nil {
+		return connection, err
+	}
+
+	// Contains variables -- both the generated port and user-defined.
+	variables := map[string]string{}
+
+	openedTunnel := false
+	if connection.SSH != nil {
+		if c.tunnel == nil {
+			setStatus("Opening the SSH tunnel...")
+
+			c.tunnel, err = openSSHTunnel(ctx, &resolved)
+			if err != nil {
+				return connection, err
+			}
+			openedTunnel = true
+		}
+		tunnelConnection(&connection)
+		tunnelConnection(&resolved)
+		// The port of the tunnel is the generated port.
+		variables["port"] = c.tunnel.Port()
+	}
+
+	err = c.runCommands(ctx, connection, variables, setStatus)
+
+	// Replace variables in URL.
+	for variable, value := range variables {
+		if variable == "" || value == "" {
+			continue
+		}
+		connection.URL = strings.ReplaceAll(connection.URL, "${"+variable+"}", value)
+		resolved.URL = strings.ReplaceAll(resolved.URL, "${"+variable+"}", value)
+	}
+
+	if err == nil {
+		setStatus("Connecting...")
+		err = driver.Connect(ctx, resolved.URL)
+	}
+
+	if err != nil && openedTunnel {
+		_ = c.tunnel.Close()
+		c.tunnel = nil
+	}
+	return connection, err
+}
+
+// runCommands runs the Commands of connection, saving their output and the
+// generated port to variables.
+func (c *connector) runCommands(ctx context.Context, connection models.Connection, variables map[string]string, setStatus func(string)) error {
+	if len(connection.Commands) == 0 {
+		return nil
+	}
+
+	// Avoid getting the port when it's not requested.
+	waitsForPort := strings.Contains(connection.URL, "${port}")
+	waitsForPort = waitsForPort || slices.ContainsFunc(connection.Commands, func(command *models.Command) bool {
+		return command.WaitForPort != ""
+	})
+
+	if waitsForPort && variables["port"] == "" {
+		port, err := helpers.GetFreePort()
+		if err != nil {
+			return err
+		}
+		// Add port variable for the auto-generated port.
+		variables["port"] = port
+	}
+
+	for i, command := range connection.Commands {
+		setStatus(fmt.Sprintf("Running command %d/%d...", i+1, len(connection.Commands)))
+
+		cmd := command.Command
+		for variable, value := range variables {
+			cmd = strings.ReplaceAll(cmd, "${"+variable+"}", value)
+		}
+
+		markCommandComplete := App.Register()
+		onCommandDone, waitToCaptureVariable := setupOutputVariableCommand(variables, command, markCommandComplete)
+
+		// Use configured timeout or default to 5 seconds
+		timeout := time.Duration(command.Timeout) * time.Second
+		if command.Timeout == 0 {
+			timeout = 5 * time.Second
+		}
+
+		if err := helpers.RunCommand(ctx, cmd, timeout, onCommandDone); err != nil {
+			return err
+		}
+
+		waitToCaptureVariable()
+
+		if command.WaitForPort != "" {
+			interpolatedPort := command.WaitForPort
+			for variable, value := range variables {
+				interpolatedPort = strings.ReplaceAll(interpolatedPort, "${"+variable+"}", value)
+			}
+
+			if portInt, err := strconv.Atoi(interpolatedPort); err != nil || portInt < 0 || portInt >= 1<<16 {
+				return errors.New("bad port: " + interpolatedPort)
+			}
+
+			setStatus(fmt.Sprintf("Waiting for port %s...", interpolatedPort))
+
+			if err := helpers.WaitForPort(ctx, interpolatedPort); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// defaultPorts are the ports of the providers an SSH tunnel forwards to
+// when the connection has no Port.
+var defaultPorts = map[string]string{
+	drivers.DriverMySQL:    "3306",
+	drivers.DriverPostgres: "5432",
+	drivers.DriverMSSQL:    "1433",
+}
+
+// openSSHTunnel opens the SSH tunnel of resolved, the connection with its
+// secrets. Without a Remote address, the tunnel forwards to the Hostname and
+// Port of the connection.
+func openSSHTunnel(ctx context.Context, resolved *models.Connection) (*helpers.SSHTunnel, error) {
+	remote := resolved.SSH.Remote
+
+	if remote == "" {
+		if !app.HasConnectionFields(*resolved) {
+			return nil, errors.New("the SSH tunnel needs a Remote address when the connection is set by its URL")
+		}
+
+		port := resolved.Port
+		if port == "" {
+			port = defaultPorts[resolved.Provider]
+		}
+		remote = net.JoinHostPort(resolved.Hostname, port)
+	} else if !strings.Contains(resolved.URL, "${port}") {
+		return nil, errors.New("the URL must connect to ${port}, the local port of the SSH tunnel")
+	}
+
+	return helpers.OpenSSHTunnel(ctx, *resolved.SSH, remote)
+}
+
+// tunnelConnection makes conn, forwarded by its SSH tunnel to its Hostname
+// and Port, to the local port of the tunnel, ${port}.
+func tunnelConnection(conn *models.Connection) {
+	if conn.SSH.Remote != "" {
+		return
+	}
+
+	conn.Hostname, conn.Port = "127.0.0.1", "${port}"
+	conn.URL = app.BuildConnectionURL(*conn)
+}
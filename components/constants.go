@@ -11,18 +11,20 @@ var App = app.App
 // Pages
 const (
 	// General
-	pageNameHelp          string = "Help"
-	pageNameConfirmation  string = "Confirmation"
-	pageNameConnections   string = "Connections"
-	pageNameDMLPreview    string = "DMLPreview"
-	pageNameErrorModal    string = "ErrorModal"
-	pageNameReadOnlyError string = "readOnlyError"
+	pageNameHelp             string = "Help"
+	pageNameConfirmation     string = "Confirmation"
+	pageNameConnections      string = "Connections"
+	pageNameDMLPreview       string = "DMLPreview"
+	pageNameErrorModal       string = "ErrorModal"
+	pageNameReadOnlyError    string = "readOnlyError"
+	pageNameTransactionError string = "TransactionError"
 
 	// Results table
 	pageNameTable                  string = "Table"
 	pageNameTableError             string = "TableError"
 	pageNameTableEditorTable       string = "TableEditorTable"
 	pageNameTableEditorResultsInfo string = "TableEditorResultsInfo"
+	pageNameTableEditorPlan        string = "TableEditorPlan"
 	pageNameTableEditCell          string = "TableEditCell"
 	pageNameQueryPreviewError      string = "QueryPreviewError"
 	pageNameJSONViewer                    = "json_viewer"
@@ -33,6 +35,7 @@ const (
 	// Connections
 	pageNameConnectionSelection string = "ConnectionSelection"
 	pageNameConnectionForm      string = "ConnectionForm"
+	pageNameConnectionSwitcher  string = "ConnectionSwitcher"
 
 	// SetValueList
 	pageNameSetValue string = "SetValue"
@@ -42,15 +45,49 @@ const (
 	pageNameSaveQuery        string = "SaveQueryModal"
 	pageNameSavedQueryDelete string = "SavedQueryDeleteModal"
 
-	// CSV Export
-	pageNameCSVExport        string = "CSVExportModal"
-	pageNameCSVExportSuccess string = "CSVExportSuccessModal"
-	pageNameCSVExportError   string = "CSVExportErrorModal"
+	// Query Parameters
+	pageNameQueryParameters      string = "QueryParametersModal"
+	pageNameQueryParametersError string = "QueryParametersErrorModal"
+
+	// Export
+	pageNameExport        string = "ExportModal"
+	pageNameExportSuccess string = "ExportSuccessModal"
+	pageNameExportError   string = "ExportErrorModal"
+
+	// Import
+	pageNameImport         string = "ImportModal"
+	pageNameImportError    string = "ImportErrorModal"
+	pageNameImportProgress string = "ImportProgressModal"
+	pageNameImportReport   string = "ImportReportModal"
+
+	// Schema and data diff
+	pageNameSchemaDiff string = "SchemaDiff"
+	pageNameDataDiff   string = "DataDiff"
+
+	// Structure
+	pageNameStructureForm string = "StructureFormModal"
+
+	// Create table
+	pageNameCreateTable string = "CreateTable"
+
+	// DefinitionViewer
+	pageNameDefinitionViewer string = "DefinitionViewer"
+	pageNameDefinitionError  string = "DefinitionError"
+
+	// Referenced by
+	pageNameReferencedBy string = "ReferencedBy"
+
+	// Materialized views
+	pageNameRefreshMaterializedView string = "RefreshMaterializedView"
+
+	// ERDiagram
+	pageNameERDiagram string = "ERDiagram"
 )
 
 // Tabs
 const (
-	tabNameEditor string = "Editor"
+	tabNameEditor      string = "Editor"
+	tabNameQueryResult string = "Result"
 
 	savedQueryTabReference   string = "saved_queries"
 	queryHistoryTabReference string = "query_history"
@@ -64,17 +101,22 @@ const (
 	eventSidebarCommitEditing string = "CommitEditingSidebar"
 	eventSidebarError         string = "ErrorSidebar"
 
-	eventSQLEditorQuery  string = "Query"
-	eventSQLEditorEscape string = "Escape"
+	eventSQLEditorQuery   string = "Query"
+	eventSQLEditorEscape  string = "Escape"
+	eventSQLEditorExplain string = "Explain"
 
 	eventResultsTableFiltering string = "FilteringResultsTable"
 
-	eventTreeSelectedDatabase  string = "SelectedDatabase"
-	eventTreeSelectedTable     string = "SelectedTable"
-	eventTreeSelectedFunction  string = "SelectedFunction"
-	eventTreeSelectedProcedure string = "SelectedProcedure"
-	eventTreeSelectedView      string = "SelectedView"
-	eventTreeIsFiltering       string = "IsFiltering"
+	eventTreeSelectedDatabase        string = "SelectedDatabase"
+	eventTreeSelectedTable           string = "SelectedTable"
+	eventTreeSelectedFunction        string = "SelectedFunction"
+	eventTreeSelectedProcedure       string = "SelectedProcedure"
+	eventTreeSelectedView            string = "SelectedView"
+	eventTreeIsFiltering             string = "IsFiltering"
+	eventTreeNewTable                string = "NewTable"
+	eventTreeShowDefinition          string = "ShowDefinition"
+	eventTreeRefreshMaterializedView string = "RefreshMaterializedView"
+	eventTreeShowERDiagram           string = "ShowERDiagram"
 )
 
 // Results table menu items
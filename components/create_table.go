@@ -0,0 +1,401 @@
+package components
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/jorgerojas26/lazysql/app"
+	"github.com/jorgerojas26/lazysql/drivers"
+	"github.com/jorgerojas26/lazysql/helpers/logger"
+	"github.com/jorgerojas26/lazysql/internal/history"
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+// newTableColumn is a column of a table created with the CreateTableView.
+type newTableColumn struct {
+	models.ColumnSchema
+	PrimaryKey bool
+	// References is the table the column references, empty if it references
+	// none, and ReferencedColumn the column it references.
+	References       string
+	ReferencedColumn string
+}
+
+// CreateTableView defines the columns of a new table of a database, shows
+// its CREATE TABLE statement and runs it, or opens it in the SQL editor.
+type CreateTableView struct {
+	*tview.Flex
+	home    *Home
+	form    *tview.Form
+	columns *tview.Table
+	ddl     *tview.TextView
+
+	database string
+	schema   string
+	name     string
+	// definitions are the columns of the table, in order.
+	definitions []newTableColumn
+	// tables are the tables of the database, the columns can reference
+	// them.
+	tables []string
+	types  []string
+	cancel context.CancelFunc
+}
+
+// NewCreateTableView creates a new CreateTableView for a table of database,
+// in schema for the databases that use schemas. It starts with an id
+// primary key column.
+func NewCreateTableView(home *Home, database, schema string) *CreateTableView {
+	v := &CreateTableView{
+		Flex:     tview.NewFlex().SetDirection(tview.FlexRow),
+		home:     home,
+		database: database,
+		schema:   schema,
+		types:    drivers.ColumnTypes(home.DBDriver.GetProvider()),
+	}
+
+	useSchemas := home.DBDriver.UseSchemas()
+	if useSchemas && v.schema == "" {
+		v.schema = "public"
+	}
+
+	if len(v.types) > 0 {
+		v.definitions = append(v.definitions, newTableColumn{
+			ColumnSchema: models.ColumnSchema{Name: "id", Type: v.types[0]},
+			PrimaryKey:   true,
+		})
+	}
+
+	v.form = tview.NewForm().SetHorizontal(true)
+	if useSchemas {
+		v.form.AddInputField("Schema", v.schema, 20, nil, func(text string) {
+			v.schema = strings.TrimSpace(text)
+			v.render()
+		})
+	}
+	v.form.AddInputField("Name", "", 30, nil, func(text string) {
+		v.name = strings.TrimSpace(text)
+		v.render()
+	}).
+		AddButton("Edit in SQL editor", v.openInEditor).
+		AddButton("Create", v.create)
+
+	v.form.SetFieldStyle(
+		tcell.StyleDefault.
+			Background(app.Styles.SecondaryTextColor).
+			Foreground(app.Styles.ContrastSecondaryTextColor),
+	).SetButtonActivatedStyle(tcell.StyleDefault.
+		Background(app.Styles.SecondaryTextColor).
+		Foreground(app.Styles.ContrastSecondaryTextColor),
+	).SetButtonStyle(tcell.StyleDefault.
+		Background(app.Styles.InverseTextColor).
+		Foreground(app.Styles.ContrastSecondaryTextColor),
+	)
+	v.form.SetBorder(true).SetTitle(fmt.Sprintf(" New table in %s ", database)).SetTitleAlign(tview.AlignLeft)
+
+	v.columns = tview.NewTable().SetSelectable(true, false).SetFixed(1, 0)
+	v.columns.SetBorder(true).SetTitle(" Columns ").SetTitleAlign(tview.AlignLeft)
+	v.columns.SetBorderColor(app.Styles.PrimaryTextColor)
+
+	v.ddl = tview.NewTextView().SetScrollable(true).SetWrap(false)
+	v.ddl.SetBorder(true).SetTitle(" DDL ").SetTitleAlign(tview.AlignLeft)
+	v.ddl.SetBorderColor(app.Styles.PrimaryTextColor)
+
+	hint := tview.NewTextView().
+		SetText("Tab to switch pane, o to add a column, c to change it, d to drop it, Esc to close").
+		SetTextAlign(tview.AlignCenter).
+		SetTextColor(app.Styles.TertiaryTextColor)
+
+	v.AddItem(v.form, 3, 0, true)
+	v.AddItem(v.columns, 0, 1, false)
+	v.AddItem(v.ddl, 0, 1, false)
+	v.AddItem(hint, 1, 0, false)
+
+	v.SetInputCapture(v.inputCapture)
+
+	v.render()
+	v.loadTables()
+
+	return v
+}
+
+// loadTables loads the tables of the database, for the foreign keys of the
+// columns.
+func (v *CreateTableView) loadTables() {
+	ctx, cancel := context.WithCancel(App.Context())
+	v.cancel = cancel
+
+	go func() {
+		tables, err := v.home.DBDriver.GetTables(ctx, v.database)
+		if err != nil {
+			logger.Error("Failed to load the tables of the database", map[string]any{"error": err.Error(), "database": v.database})
+			return
+		}
+
+		var names []string
+		for key, keyTables := range tables {
+			for _, table := range keyTables {
+				if v.home.DBDriver.UseSchemas() {
+					table = key + "." + table
+				}
+				names = append(names, table)
+			}
+		}
+		slices.Sort(names)
+
+		App.QueueUpdateDraw(func() {
+			if ctx.Err() == nil {
+				v.tables = names
+			}
+		})
+	}()
+}
+
+func (v *CreateTableView) inputCapture(event *tcell.EventKey) *tcell.EventKey {
+	focused := App.GetFocus()
+
+	switch event.Key() {
+	case tcell.KeyEsc:
+		if _, ok := focused.(*tview.List); !ok {
+			// Not the list of an open dropdown.
+			v.close()
+			return nil
+		}
+	case tcell.KeyTab, tcell.KeyBacktab:
+		if focused == v.columns {
+			App.SetFocus(v.ddl)
+			return nil
+		}
+		if focused == v.ddl {
+			App.SetFocus(v.form)
+			return nil
+		}
+		if _, button := v.form.GetFocusedItemIndex(); event.Key() == tcell.KeyTab && button == v.form.GetButtonCount()-1 {
+			App.SetFocus(v.columns)
+			return nil
+		}
+	}
+
+	if focused != v.columns {
+		return event
+	}
+
+	row, _ := v.columns.GetSelection()
+
+	switch event.Rune() {
+	case 'o':
+		v.showColumnForm("Add column", -1)
+		return nil
+	case 'c':
+		if row > 0 {
+			v.showColumnForm("Change column", row-1)
+		}
+		return nil
+	case 'd':
+		if row > 0 {
+			v.definitions = slices.Delete(v.definitions, row-1, row)
+			v.render()
+		}
+		return nil
+	}
+	if event.Key() == tcell.KeyEnter && row > 0 {
+		v.showColumnForm("Change column", row-1)
+		return nil
+	}
+
+	return event
+}
+
+// showColumnForm shows the form of the column at index, or of a new column
+// if index is -1.
+func (v *CreateTableView) showColumnForm(title string, index int) {
+	column := newTableColumn{ColumnSchema: models.ColumnSchema{Nullable: true}}
+	if index >= 0 {
+		column = v.definitions[index]
+	}
+
+	referencedColumns := func(table string) ([]string, error) {
+		results, err := v.home.DBDriver.GetTableColumns(App.Context(), v.database, table)
+		if err != nil {
+			return nil, err
+		}
+
+		var names []string
+		for _, column := range drivers.ParseTableColumns(results) {
+			names = append(names, column.Name)
+		}
+		return names, nil
+	}
+
+	modal := newTableColumnFormModal(title, column, v.types, v.tables, referencedColumns, func(column newTableColumn) error {
+		if column.Name == "" || column.Type == "" {
+			return errors.New("name and type are required")
+		}
+		if column.References != "" && column.ReferencedColumn == "" {
+			return errors.New("select the referenced column")
+		}
+		for i, definition := range v.definitions {
+			if i != index && strings.EqualFold(definition.Name, column.Name) {
+				return fmt.Errorf("there is already a column %s", column.Name)
+			}
+		}
+
+		if index >= 0 {
+			v.definitions[index] = column
+		} else {
+			v.definitions = append(v.definitions, column)
+		}
+		v.render()
+		// Once the form is closed.
+		App.QueueUpdateDraw(func() { App.SetFocus(v.columns) })
+		return nil
+	})
+	mainPages.AddPage(pageNameStructureForm, modal, true, true)
+}
+
+// tableSchema returns the definition of the table. The primary key columns
+// are never nullable.
+func (v *CreateTableView) tableSchema() models.TableSchema {
+	table := models.TableSchema{Name: v.name}
+	if v.home.DBDriver.UseSchemas() && v.schema != "" {
+		table.Name = v.schema + "." + v.name
+	}
+
+	var primaryKey []string
+	for _, definition := range v.definitions {
+		column := definition.ColumnSchema
+		if definition.PrimaryKey {
+			column.Nullable = false
+			primaryKey = append(primaryKey, column.Name)
+		}
+		table.Columns = append(table.Columns, column)
+
+		if definition.References != "" {
+			table.ForeignKeys = append(table.ForeignKeys, models.ForeignKeySchema{
+				Name:              fmt.Sprintf("%s_%s_fkey", v.name, column.Name),
+				Columns:           []string{column.Name},
+				ReferencedTable:   definition.References,
+				ReferencedColumns: []string{definition.ReferencedColumn},
+			})
+		}
+	}
+	if len(primaryKey) > 0 {
+		table.PrimaryKey = &models.ConstraintSchema{Columns: primaryKey}
+	}
+
+	return table
+}
+
+// query returns the CREATE TABLE statement of the table.
+func (v *CreateTableView) query() (string, error) {
+	if v.name == "" {
+		return "", errors.New("enter the name of the table")
+	}
+	if len(v.definitions) == 0 {
+		return "", errors.New("add a column with o")
+	}
+	return drivers.CreateTableQuery(v.home.DBDriver, v.database, v.tableSchema()), nil
+}
+
+// render shows the columns and the CREATE TABLE statement of the table.
+func (v *CreateTableView) render() {
+	row, _ := v.columns.GetSelection()
+
+	v.columns.Clear()
+	for i, header := range []string{"Name", "Type", "Nullable", "Default", "Primary key", "References"} {
+		v.columns.SetCell(0, i, tview.NewTableCell(header).
+			SetTextColor(app.Styles.PrimaryTextColor).
+			SetAttributes(tcell.AttrBold).
+			SetSelectable(false))
+	}
+
+	for i, definition := range v.definitions {
+		nullable, primaryKey, references := "YES", "", ""
+		if !definition.Nullable || definition.PrimaryKey {
+			nullable = "NO"
+		}
+		if definition.PrimaryKey {
+			primaryKey = "YES"
+		}
+		if definition.References != "" {
+			references = definition.References + " (" + definition.ReferencedColumn + ")"
+		}
+
+		for j, text := range []string{definition.Name, definition.Type, nullable, definition.Default, primaryKey, references} {
+			v.columns.SetCell(i+1, j, tview.NewTableCell(tview.Escape(text)).SetExpansion(1))
+		}
+	}
+	if len(v.definitions) > 0 {
+		v.columns.Select(min(max(row, 1), len(v.definitions)), 0)
+	}
+
+	v.ddl.SetTitle(" DDL ")
+	query, err := v.query()
+	if err != nil {
+		v.ddl.SetTextColor(app.Styles.TertiaryTextColor).SetText("-- " + err.Error())
+		return
+	}
+	v.ddl.SetTextColor(app.Styles.PrimaryTextColor).SetText(query)
+}
+
+// openInEditor opens the CREATE TABLE statement in the SQL editor, to run
+// it from there.
+func (v *CreateTableView) openInEditor() {
+	query, err := v.query()
+	if err != nil {
+		return
+	}
+
+	v.close()
+	v.home.createOrFocusEditorTab()
+	if tab := v.home.TabbedPane.GetCurrentTab(); tab != nil {
+		table := tab.Content.(*ResultsTable)
+		table.Editor.SetText(query, true)
+	}
+}
+
+// create runs the CREATE TABLE statement and refreshes the tree.
+func (v *CreateTableView) create() {
+	query, err := v.query()
+	if err != nil {
+		return
+	}
+	if v.home.ReadOnly {
+		v.ddl.SetTitle(" DDL (cannot create the table: connection is in read-only mode) ")
+		return
+	}
+
+	v.ddl.SetTitle(" DDL (creating...) ")
+
+	go func() {
+		_, err := v.home.DBDriver.ExecuteDMLStatement(App.Context(), query)
+
+		App.QueueUpdateDraw(func() {
+			if err != nil {
+				logger.Error("Failed to create the table", map[string]any{"error": err.Error(), "query": query})
+				v.ddl.SetTitle(" DDL (failed: " + err.Error() + ") ")
+				return
+			}
+
+			if err := history.AddQueryToHistory(v.home.ConnectionIdentifier, query); err != nil {
+				logger.Error("Failed to add CREATE TABLE query to history", map[string]any{"error": err, "query": query, "connection": v.home.ConnectionIdentifier})
+			}
+
+			v.close()
+			v.home.refreshTree()
+		})
+	}()
+}
+
+func (v *CreateTableView) close() {
+	if v.cancel != nil {
+		v.cancel()
+	}
+	mainPages.RemovePage(pageNameCreateTable)
+}
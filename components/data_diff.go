@@ -0,0 +1,329 @@
+package components
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/jorgerojas26/lazysql/app"
+	"github.com/jorgerojas26/lazysql/drivers"
+	"github.com/jorgerojas26/lazysql/helpers/logger"
+	"github.com/jorgerojas26/lazysql/lib"
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+// DataDiffView compares the rows of a table in two databases, of one
+// connection or of two open connections, and shows the DML script that
+// makes the target match the source.
+type DataDiffView struct {
+	*tview.Flex
+	home    *Home
+	form    *tview.Form
+	rows    *tview.Table
+	dml     *tview.TextView
+	tableID string
+
+	source, target                 *Home
+	sourceDatabase, targetDatabase string
+	// targetHome and targetDB are the connection and database the shown DML
+	// is for.
+	targetHome *Home
+	targetDB   string
+	statements []string
+	cancel     context.CancelFunc
+}
+
+// NewDataDiffView creates a new DataDiffView opened from home for table,
+// with home preselected as source and target.
+func NewDataDiffView(home *Home, table string) *DataDiffView {
+	v := &DataDiffView{
+		Flex:    tview.NewFlex().SetDirection(tview.FlexRow),
+		home:    home,
+		source:  home,
+		target:  home,
+		tableID: table,
+	}
+
+	connectionNames := make([]string, len(openHomes))
+	for i, openHome := range openHomes {
+		connectionNames[i] = openHome.ConnectionIdentifier
+	}
+	current := slices.Index(openHomes, home)
+
+	v.form = tview.NewForm().SetHorizontal(true)
+	v.form.AddDropDown("Source", connectionNames, current, func(_ string, index int) {
+		if index >= 0 {
+			v.source = openHomes[index]
+			loadDatabases(v.form, 1, v.source, func(database string) { v.sourceDatabase = database })
+		}
+	}).
+		AddDropDown("Database", []string{}, 0, nil).
+		AddDropDown("Target", connectionNames, current, func(_ string, index int) {
+			if index >= 0 {
+				v.target = openHomes[index]
+				loadDatabases(v.form, 3, v.target, func(database string) { v.targetDatabase = database })
+			}
+		}).
+		AddDropDown("Database", []string{}, 0, nil).
+		AddInputField("Table", table, 30, nil, func(text string) { v.tableID = strings.TrimSpace(text) }).
+		AddButton("Compare", v.compare)
+
+	v.form.SetFieldStyle(
+		tcell.StyleDefault.
+			Background(app.Styles.SecondaryTextColor).
+			Foreground(app.Styles.ContrastSecondaryTextColor),
+	).SetButtonActivatedStyle(tcell.StyleDefault.
+		Background(app.Styles.SecondaryTextColor).
+		Foreground(app.Styles.ContrastSecondaryTextColor),
+	).SetButtonStyle(tcell.StyleDefault.
+		Background(app.Styles.InverseTextColor).
+		Foreground(app.Styles.ContrastSecondaryTextColor),
+	)
+	v.form.SetBorder(true).SetTitle(" Compare data ").SetTitleAlign(tview.AlignLeft)
+
+	v.rows = tview.NewTable().SetSelectable(true, false).SetFixed(1, 1)
+	v.rows.SetBorder(true).SetTitle(" Rows ").SetTitleAlign(tview.AlignLeft)
+	v.rows.SetBorderColor(app.Styles.PrimaryTextColor)
+
+	v.dml = tview.NewTextView().SetScrollable(true).SetWrap(false)
+	v.dml.SetBorder(true).SetTitle(" DML ").SetTitleAlign(tview.AlignLeft)
+	v.dml.SetBorderColor(app.Styles.PrimaryTextColor)
+
+	hint := tview.NewTextView().
+		SetText("Tab to switch pane, y to copy the DML, w to write it to a file, e to open it in the SQL editor, Esc to close").
+		SetTextAlign(tview.AlignCenter).
+		SetTextColor(app.Styles.TertiaryTextColor)
+
+	v.AddItem(v.form, 3, 0, true)
+	v.AddItem(v.rows, 0, 2, false)
+	v.AddItem(v.dml, 0, 1, false)
+	v.AddItem(hint, 1, 0, false)
+
+	v.SetInputCapture(v.inputCapture)
+
+	loadDatabases(v.form, 1, v.source, func(database string) { v.sourceDatabase = database })
+	loadDatabases(v.form, 3, v.target, func(database string) { v.targetDatabase = database })
+
+	return v
+}
+
+func (v *DataDiffView) inputCapture(event *tcell.EventKey) *tcell.EventKey {
+	focused := App.GetFocus()
+
+	switch event.Key() {
+	case tcell.KeyEsc:
+		if _, ok := focused.(*tview.List); !ok {
+			// Not the list of an open dropdown.
+			v.close()
+			return nil
+		}
+	case tcell.KeyTab, tcell.KeyBacktab:
+		if focused == v.rows {
+			App.SetFocus(v.dml)
+			return nil
+		}
+		if focused == v.dml {
+			App.SetFocus(v.form)
+			return nil
+		}
+	}
+
+	if focused != v.rows && focused != v.dml {
+		return event
+	}
+
+	switch event.Rune() {
+	case 'y':
+		v.copyDML()
+		return nil
+	case 'w':
+		v.writeDML()
+		return nil
+	case 'e':
+		v.openDMLInEditor()
+		return nil
+	}
+
+	return event
+}
+
+func (v *DataDiffView) compare() {
+	if v.sourceDatabase == "" || v.targetDatabase == "" || v.tableID == "" {
+		v.setStatus("Select the databases and the table to compare", tcell.ColorRed)
+		return
+	}
+
+	if v.cancel != nil {
+		v.cancel()
+	}
+	ctx, cancel := context.WithCancel(App.Context())
+	v.cancel = cancel
+
+	source, sourceDatabase := v.source, v.sourceDatabase
+	target, targetDatabase := v.target, v.targetDatabase
+	table := v.tableID
+
+	v.setStatus("Comparing...", app.Styles.SecondaryTextColor)
+
+	go func() {
+		diff, err := drivers.DiffTableData(ctx, source.DBDriver, target.DBDriver, sourceDatabase, targetDatabase, table, func(read, total int) {
+			App.QueueUpdateDraw(func() {
+				if ctx.Err() == nil {
+					v.setStatus(fmt.Sprintf("Comparing... %d of %d rows read", read, total), app.Styles.SecondaryTextColor)
+				}
+			})
+		})
+
+		var statements []string
+		if err == nil {
+			statements, err = diff.DML(target.DBDriver, targetDatabase)
+		}
+
+		App.QueueUpdateDraw(func() {
+			if ctx.Err() != nil {
+				return
+			}
+			if err != nil {
+				logger.Error("Failed to compare data", map[string]any{"error": err.Error()})
+				v.setStatus("Failed to compare data: "+err.Error(), tcell.ColorRed)
+				return
+			}
+
+			v.targetHome = target
+			v.targetDB = targetDatabase
+			v.showDiff(diff, statements)
+			App.SetFocus(v.rows)
+		})
+	}()
+}
+
+func (v *DataDiffView) setStatus(text string, color tcell.Color) {
+	v.rows.Clear()
+	v.rows.SetCell(0, 0, tview.NewTableCell(text).SetTextColor(color).SetSelectable(false))
+}
+
+// showDiff lists the rows that differ: inserted rows in green, deleted rows
+// in red and, of changed rows, the changed cells in yellow as "target ->
+// source".
+func (v *DataDiffView) showDiff(diff *drivers.DataDiff, statements []string) {
+	v.statements = statements
+	v.dml.SetText(strings.Join(statements, "\n"))
+	v.dml.ScrollToBeginning()
+	v.dml.SetTitle(" DML ")
+
+	if len(diff.Rows) == 0 {
+		v.setStatus("The rows match", tcell.ColorGreen)
+		v.rows.SetTitle(" Rows ")
+		return
+	}
+
+	v.rows.Clear()
+	v.rows.SetCell(0, 0, tview.NewTableCell("").SetSelectable(false))
+	for j, column := range diff.Columns {
+		v.rows.SetCell(0, j+1, tview.NewTableCell(column).
+			SetTextColor(app.Styles.PrimaryTextColor).
+			SetAttributes(tcell.AttrBold).
+			SetSelectable(false))
+	}
+
+	for i, row := range diff.Rows {
+		marker, color, values := "~", app.Styles.PrimaryTextColor, row.Source
+		switch row.Type {
+		case models.DMLInsertType:
+			marker, color = "+", tcell.ColorGreen
+		case models.DMLDeleteType:
+			marker, color, values = "-", tcell.ColorRed, row.Target
+		}
+
+		v.rows.SetCell(i+1, 0, tview.NewTableCell(marker).SetTextColor(color))
+		for j, value := range values {
+			text, cellColor := dataDiffCellText(value), color
+			if slices.Contains(row.Changed, j) {
+				text, cellColor = dataDiffCellText(row.Target[j])+" -> "+text, tcell.ColorYellow
+			}
+			v.rows.SetCell(i+1, j+1, tview.NewTableCell(text).SetTextColor(cellColor).SetMaxWidth(40))
+		}
+	}
+	v.rows.Select(1, 0)
+
+	title := fmt.Sprintf(" Rows (%d) ", len(diff.Rows))
+	if diff.Truncated {
+		title = fmt.Sprintf(" Rows (first %d) ", len(diff.Rows))
+	}
+	v.rows.SetTitle(title)
+}
+
+func dataDiffCellText(value models.Value) string {
+	switch {
+	case value.Null:
+		return "NULL"
+	case value.IsEmpty():
+		return "EMPTY"
+	default:
+		return value.String()
+	}
+}
+
+func (v *DataDiffView) copyDML() {
+	if len(v.statements) == 0 {
+		return
+	}
+
+	clipboard := lib.NewClipboard()
+	if err := clipboard.Write(strings.Join(v.statements, "\n")); err != nil {
+		logger.Error("Error copying DML to clipboard", map[string]any{"error": err.Error()})
+		v.dml.SetTitle(" DML (copy failed) ")
+		return
+	}
+	v.dml.SetTitle(" DML (copied) ")
+}
+
+// writeDML writes the DML to a new file in the export directory.
+func (v *DataDiffView) writeDML() {
+	if len(v.statements) == 0 {
+		return
+	}
+
+	name := strings.NewReplacer(".", "_", "/", "_").Replace(v.targetDB + "_" + v.tableID)
+	filePath := filepath.Join(getDefaultExportDir(), fmt.Sprintf("%s_diff_%s.sql", name, time.Now().Format("20060102_150405")))
+
+	if err := os.WriteFile(filePath, []byte(strings.Join(v.statements, "\n")+"\n"), 0o600); err != nil {
+		logger.Error("Error writing DML", map[string]any{"error": err.Error()})
+		v.dml.SetTitle(" DML (write failed: " + err.Error() + ") ")
+		return
+	}
+	v.dml.SetTitle(" DML (written to " + filePath + ") ")
+}
+
+// openDMLInEditor opens the DML in the SQL editor of the connection it was
+// generated for, if it is the one the view was opened from.
+func (v *DataDiffView) openDMLInEditor() {
+	if len(v.statements) == 0 {
+		return
+	}
+	if v.targetHome != v.home {
+		v.dml.SetTitle(" DML (copy it with y to run it on " + v.targetHome.ConnectionIdentifier + ") ")
+		return
+	}
+
+	v.close()
+	v.home.createOrFocusEditorTab()
+	if tab := v.home.TabbedPane.GetCurrentTab(); tab != nil {
+		table := tab.Content.(*ResultsTable)
+		table.Editor.SetText(strings.Join(v.statements, "\n"), true)
+	}
+}
+
+func (v *DataDiffView) close() {
+	if v.cancel != nil {
+		v.cancel()
+	}
+	mainPages.RemovePage(pageNameDataDiff)
+}
@@ -0,0 +1,116 @@
+package components
+
+import (
+	"context"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/jorgerojas26/lazysql/app"
+	"github.com/jorgerojas26/lazysql/helpers/logger"
+	"github.com/jorgerojas26/lazysql/lib"
+)
+
+// DefinitionViewer shows the statements that create a table, view or
+// routine, read-only, to copy them or open them in the SQL editor.
+type DefinitionViewer struct {
+	*tview.Flex
+	home       *Home
+	text       *tview.TextView
+	title      string
+	definition string
+}
+
+// NewDefinitionViewer creates a new DefinitionViewer for the definition of
+// the object named name, opened from home.
+func NewDefinitionViewer(home *Home, name, definition string) *DefinitionViewer {
+	v := &DefinitionViewer{
+		Flex:       tview.NewFlex().SetDirection(tview.FlexRow),
+		home:       home,
+		title:      " Definition of " + name + " ",
+		definition: definition,
+	}
+
+	v.text = tview.NewTextView().SetScrollable(true).SetWrap(false).SetText(definition)
+	v.text.SetBorder(true).SetTitle(v.title).SetTitleAlign(tview.AlignLeft)
+	v.text.SetBorderColor(app.Styles.PrimaryTextColor)
+
+	hint := tview.NewTextView().
+		SetText("y to copy, e to open in the SQL editor, Esc to close").
+		SetTextAlign(tview.AlignCenter).
+		SetTextColor(app.Styles.TertiaryTextColor)
+
+	v.AddItem(v.text, 0, 1, true)
+	v.AddItem(hint, 1, 0, false)
+
+	v.SetInputCapture(v.inputCapture)
+
+	return v
+}
+
+func (v *DefinitionViewer) inputCapture(event *tcell.EventKey) *tcell.EventKey {
+	if event.Key() == tcell.KeyEsc {
+		v.close()
+		return nil
+	}
+
+	switch event.Rune() {
+	case 'q':
+		v.close()
+		return nil
+	case 'y':
+		v.copy()
+		return nil
+	case 'e':
+		v.openInEditor()
+		return nil
+	}
+
+	return event
+}
+
+func (v *DefinitionViewer) copy() {
+	clipboard := lib.NewClipboard()
+	if err := clipboard.Write(v.definition); err != nil {
+		logger.Error("Error copying definition to clipboard", map[string]any{"error": err.Error()})
+		v.text.SetTitle(v.title + "(copy failed) ")
+		return
+	}
+	v.text.SetTitle(v.title + "(copied) ")
+}
+
+func (v *DefinitionViewer) openInEditor() {
+	v.close()
+	v.home.createOrFocusEditorTab()
+	if tab := v.home.TabbedPane.GetCurrentTab(); tab != nil {
+		table := tab.Content.(*ResultsTable)
+		table.Editor.SetText(v.definition, true)
+	}
+}
+
+func (v *DefinitionViewer) close() {
+	mainPages.RemovePage(pageNameDefinitionViewer)
+}
+
+// showDefinition shows the definition of the object named name, as read by
+// load, in a DefinitionViewer.
+func (home *Home) showDefinition(name string, load func(ctx context.Context) (string, error)) {
+	go func() {
+		definition, err := load(App.Context())
+
+		App.QueueUpdateDraw(func() {
+			if err != nil {
+				logger.Error("Failed to get the definition", map[string]any{"error": err.Error(), "name": name})
+
+				modal := NewErrorModal("Failed to get the definition of " + name + ": " + err.Error())
+				modal.SetDoneFunc(func(_ int, _ string) {
+					mainPages.RemovePage(pageNameDefinitionError)
+				})
+				mainPages.AddPage(pageNameDefinitionError, modal, true, true)
+				return
+			}
+
+			mainPages.AddPage(pageNameDefinitionViewer, NewDefinitionViewer(home, name, definition), true, true)
+		})
+	}()
+}
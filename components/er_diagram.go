@@ -0,0 +1,732 @@
+package components
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/jorgerojas26/lazysql/app"
+	"github.com/jorgerojas26/lazysql/drivers"
+	"github.com/jorgerojas26/lazysql/helpers/logger"
+	"github.com/jorgerojas26/lazysql/lib"
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+const (
+	// erMaxLineWidth is the width past which the lines of a table box are
+	// truncated.
+	erMaxLineWidth = 40
+	// erLayerGap is the space between two layers of tables, to which two
+	// columns are added for each connector drawn in it.
+	erLayerGap = 6
+)
+
+// erDirection is the set of directions a connector leaves a cell in.
+type erDirection uint8
+
+const (
+	erUp erDirection = 1 << iota
+	erDown
+	erLeft
+	erRight
+)
+
+var erConnectorRunes = map[erDirection]rune{
+	erUp:                             '│',
+	erDown:                           '│',
+	erUp | erDown:                    '│',
+	erLeft:                           '─',
+	erRight:                          '─',
+	erLeft | erRight:                 '─',
+	erDown | erRight:                 '┌',
+	erDown | erLeft:                  '┐',
+	erUp | erRight:                   '└',
+	erUp | erLeft:                    '┘',
+	erUp | erDown | erRight:          '├',
+	erUp | erDown | erLeft:           '┤',
+	erLeft | erRight | erDown:        '┬',
+	erLeft | erRight | erUp:          '┴',
+	erUp | erDown | erLeft | erRight: '┼',
+}
+
+type erPoint struct{ x, y int }
+
+// erBox is the box of a table of an ER diagram.
+type erBox struct {
+	title               string
+	lines               []string
+	level               int
+	x, y, width, height int
+}
+
+// columnRow returns the row of the line of column, one of columns, or of
+// the title if there is no such column.
+func (b erBox) columnRow(columns []models.ColumnSchema, column string) int {
+	i := slices.IndexFunc(columns, func(c models.ColumnSchema) bool { return c.Name == column })
+	if i < 0 {
+		return b.y + 1
+	}
+	return b.y + 3 + i
+}
+
+// erConnector is the connector drawn for a foreign key of the table from
+// referencing the table to.
+type erConnector struct {
+	from, to int
+	points   []erPoint
+}
+
+// erLayout places the tables of an ER diagram in layers, the tables of each
+// layer referencing only tables of the layers to its left, and routes the
+// connectors of their foreign keys between the layers.
+type erLayout struct {
+	boxes []erBox
+	// levels holds the tables of each layer, from top to bottom.
+	levels     [][]int
+	connectors []erConnector
+	cells      map[erPoint]erDirection
+	arrows     map[erPoint]bool
+	// referenced and referencing are the tables each table references and
+	// is referenced by, itself left out.
+	referenced  [][]int
+	referencing [][]int
+}
+
+func newERLayout(diagram *drivers.ERDiagram) *erLayout {
+	l := &erLayout{
+		boxes:       make([]erBox, len(diagram.Tables)),
+		cells:       map[erPoint]erDirection{},
+		arrows:      map[erPoint]bool{},
+		referenced:  make([][]int, len(diagram.Tables)),
+		referencing: make([][]int, len(diagram.Tables)),
+	}
+
+	index := make(map[string]int, len(diagram.Tables))
+	for i, table := range diagram.Tables {
+		index[table.Name] = i
+	}
+
+	relations := diagram.Relations()
+	for _, relation := range relations {
+		from, to := index[relation.Table], index[relation.ReferencedTable]
+		if from == to || slices.Contains(l.referenced[from], to) {
+			continue
+		}
+		l.referenced[from] = append(l.referenced[from], to)
+		l.referencing[to] = append(l.referencing[to], from)
+	}
+
+	levels := erLevels(l.referenced)
+	for i, table := range diagram.Tables {
+		box := erBox{title: table.Name, level: levels[i]}
+		for _, column := range table.Columns {
+			line := column.Name + " " + column.Type
+			if keys := drivers.ERColumnKeys(table, column.Name); keys != "" {
+				line += " " + keys
+			}
+			box.lines = append(box.lines, truncateERLine(line))
+		}
+		box.title = truncateERLine(box.title)
+
+		box.width = len([]rune(box.title))
+		for _, line := range box.lines {
+			box.width = max(box.width, len([]rune(line)))
+		}
+		box.width += 4
+		box.height = 3
+		if len(box.lines) > 0 {
+			box.height += 1 + len(box.lines)
+		}
+		l.boxes[i] = box
+
+		for len(l.levels) <= box.level {
+			l.levels = append(l.levels, nil)
+		}
+		l.levels[box.level] = append(l.levels[box.level], i)
+	}
+
+	l.orderLevels()
+
+	// The connectors of the tables of a layer take a column each in the
+	// gap to its right.
+	channels := make([]int, len(l.levels))
+	var drawn []drivers.ERRelation
+	for _, relation := range relations {
+		from, to := index[relation.Table], index[relation.ReferencedTable]
+		if l.boxes[to].level < l.boxes[from].level {
+			channels[l.boxes[to].level]++
+			drawn = append(drawn, relation)
+		}
+	}
+
+	x := 1
+	layerRight := make([]int, len(l.levels))
+	for level, tables := range l.levels {
+		y := 1
+		width := 0
+		for _, i := range tables {
+			l.boxes[i].x, l.boxes[i].y = x, y
+			y += l.boxes[i].height + 1
+			width = max(width, l.boxes[i].width)
+		}
+		layerRight[level] = x + width
+		x += width + erLayerGap + 2*channels[level]
+	}
+
+	used := make([]int, len(l.levels))
+	for _, relation := range drawn {
+		from, to := index[relation.Table], index[relation.ReferencedTable]
+		parent, child := l.boxes[to], l.boxes[from]
+
+		channel := layerRight[parent.level] + 3 + 2*used[parent.level]
+		used[parent.level]++
+
+		referencedColumn, column := "", ""
+		if len(relation.ReferencedColumns) > 0 {
+			referencedColumn = relation.ReferencedColumns[0]
+		}
+		if len(relation.Columns) > 0 {
+			column = relation.Columns[0]
+		}
+
+		start := erPoint{parent.x + parent.width, parent.columnRow(diagram.Tables[to].Columns, referencedColumn)}
+		end := erPoint{child.x - 1, child.columnRow(diagram.Tables[from].Columns, column)}
+
+		connector := erConnector{from: from, to: to}
+		connector.points = append(connector.points, l.horizontal(start.y, start.x, channel)...)
+		connector.points = append(connector.points, l.vertical(channel, start.y, end.y)...)
+		connector.points = append(connector.points, l.horizontal(end.y, channel, end.x)...)
+		l.arrows[start] = true
+		l.connectors = append(l.connectors, connector)
+	}
+
+	return l
+}
+
+// erLevels returns the layer of each table: 0 for the tables referencing no
+// other, one more than the highest layer of the tables it references for
+// the others. The references closing a cycle are ignored.
+func erLevels(referenced [][]int) []int {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	levels := make([]int, len(referenced))
+	state := make([]int, len(referenced))
+
+	var visit func(i int) int
+	visit = func(i int) int {
+		if state[i] == visited {
+			return levels[i]
+		}
+		state[i] = visiting
+		level := 0
+		for _, j := range referenced[i] {
+			if state[j] != visiting {
+				level = max(level, visit(j)+1)
+			}
+		}
+		state[i] = visited
+		levels[i] = level
+		return level
+	}
+
+	for i := range referenced {
+		visit(i)
+	}
+	return levels
+}
+
+// orderLevels orders the tables of each layer but the first by the mean
+// position of the tables they reference, to keep the connectors short.
+func (l *erLayout) orderLevels() {
+	position := make([]int, len(l.boxes))
+	for level, tables := range l.levels {
+		if level > 0 {
+			mean := make(map[int]float64, len(tables))
+			for _, i := range tables {
+				sum, count := 0, 0
+				for _, j := range l.referenced[i] {
+					if l.boxes[j].level < level {
+						sum += position[j]
+						count++
+					}
+				}
+				if count > 0 {
+					mean[i] = float64(sum) / float64(count)
+				}
+			}
+			sort.SliceStable(tables, func(a, b int) bool { return mean[tables[a]] < mean[tables[b]] })
+		}
+		for p, i := range tables {
+			position[i] = p
+		}
+	}
+}
+
+func (l *erLayout) horizontal(y, x1, x2 int) []erPoint {
+	if x1 > x2 {
+		x1, x2 = x2, x1
+	}
+	points := make([]erPoint, 0, x2-x1+1)
+	for x := x1; x <= x2; x++ {
+		point := erPoint{x, y}
+		if x > x1 {
+			l.cells[point] |= erLeft
+		}
+		if x < x2 {
+			l.cells[point] |= erRight
+		}
+		points = append(points, point)
+	}
+	return points
+}
+
+func (l *erLayout) vertical(x, y1, y2 int) []erPoint {
+	if y1 > y2 {
+		y1, y2 = y2, y1
+	}
+	points := make([]erPoint, 0, y2-y1+1)
+	for y := y1; y <= y2; y++ {
+		point := erPoint{x, y}
+		if y > y1 {
+			l.cells[point] |= erUp
+		}
+		if y < y2 {
+			l.cells[point] |= erDown
+		}
+		points = append(points, point)
+	}
+	return points
+}
+
+// related returns the tables table references and is referenced by.
+func (l *erLayout) related(table int) []int {
+	return append(slices.Clone(l.referenced[table]), l.referencing[table]...)
+}
+
+func truncateERLine(line string) string {
+	runes := []rune(line)
+	if len(runes) <= erMaxLineWidth {
+		return line
+	}
+	return string(runes[:erMaxLineWidth-1]) + "…"
+}
+
+// ERDiagramView draws the entity-relationship diagram of a database or of
+// one of its schemas, to move between related tables, open them and export
+// the diagram as Graphviz DOT or Mermaid.
+type ERDiagramView struct {
+	*tview.Flex
+	home     *Home
+	canvas   *erCanvas
+	details  *tview.TextView
+	database string
+	cancel   context.CancelFunc
+}
+
+// erCanvas is the primitive the diagram is drawn on.
+type erCanvas struct {
+	*tview.Box
+	diagram *drivers.ERDiagram
+	layout  *erLayout
+	// selected is the table selected, and anchor the one whose related
+	// tables Tab cycles through.
+	selected         int
+	anchor           int
+	related          int
+	offsetX, offsetY int
+}
+
+// showERDiagram opens an ERDiagramView of database, or of its schema for
+// the databases that use schemas.
+func (home *Home) showERDiagram(database, schema string) {
+	name := database
+	if schema != "" {
+		name = schema
+	}
+
+	v := &ERDiagramView{
+		Flex:     tview.NewFlex().SetDirection(tview.FlexRow),
+		home:     home,
+		database: database,
+	}
+
+	v.canvas = &erCanvas{Box: tview.NewBox(), related: -1}
+	v.canvas.SetBorder(true).SetTitle(" ER diagram of " + name + " (loading...) ").SetTitleAlign(tview.AlignLeft)
+	v.canvas.SetBorderColor(app.Styles.PrimaryTextColor)
+
+	v.details = tview.NewTextView().SetWrap(false)
+	v.details.SetBorder(true).SetTitle(" Relations ").SetTitleAlign(tview.AlignLeft)
+	v.details.SetBorderColor(app.Styles.PrimaryTextColor)
+
+	hint := tview.NewTextView().
+		SetText("j/k to move in a layer, h/l to the referenced/referencing tables, Tab to cycle the related ones, Enter to open, d/m to copy as DOT/Mermaid, D/M to write it to a file, Esc to close").
+		SetTextAlign(tview.AlignCenter).
+		SetTextColor(app.Styles.TertiaryTextColor)
+
+	v.AddItem(v.canvas, 0, 1, true)
+	v.AddItem(v.details, 6, 0, false)
+	v.AddItem(hint, 1, 0, false)
+
+	v.SetInputCapture(v.inputCapture)
+
+	mainPages.AddPage(pageNameERDiagram, v, true, true)
+
+	ctx, cancel := context.WithCancel(App.Context())
+	v.cancel = cancel
+
+	go func() {
+		diagram, err := drivers.LoadERDiagram(ctx, home.DBDriver, database, schema)
+
+		App.QueueUpdateDraw(func() {
+			if ctx.Err() != nil {
+				return
+			}
+			if err != nil {
+				logger.Error("Failed to load the ER diagram", map[string]any{"error": err.Error(), "database": database})
+				v.canvas.SetTitle(" ER diagram of " + name + " (failed: " + err.Error() + ") ")
+				return
+			}
+
+			v.canvas.SetTitle(fmt.Sprintf(" ER diagram of %s (%d tables) ", name, len(diagram.Tables)))
+			v.canvas.diagram = diagram
+			v.canvas.layout = newERLayout(diagram)
+			v.showDetails()
+		})
+	}()
+}
+
+func (v *ERDiagramView) inputCapture(event *tcell.EventKey) *tcell.EventKey {
+	c := v.canvas
+
+	switch event.Key() {
+	case tcell.KeyEsc:
+		v.close()
+		return nil
+	case tcell.KeyEnter:
+		v.open()
+		return nil
+	case tcell.KeyDown:
+		c.moveInLevel(1)
+	case tcell.KeyUp:
+		c.moveInLevel(-1)
+	case tcell.KeyLeft:
+		c.moveTo(false)
+	case tcell.KeyRight:
+		c.moveTo(true)
+	case tcell.KeyTab:
+		c.cycleRelated(1)
+	case tcell.KeyBacktab:
+		c.cycleRelated(-1)
+	case tcell.KeyRune:
+		switch event.Rune() {
+		case 'q':
+			v.close()
+			return nil
+		case 'j':
+			c.moveInLevel(1)
+		case 'k':
+			c.moveInLevel(-1)
+		case 'h':
+			c.moveTo(false)
+		case 'l':
+			c.moveTo(true)
+		case 'd':
+			v.copy("DOT", c.diagram.DOT)
+		case 'm':
+			v.copy("Mermaid", c.diagram.Mermaid)
+		case 'D':
+			v.write("DOT", "dot", c.diagram.DOT)
+		case 'M':
+			v.write("Mermaid", "mmd", c.diagram.Mermaid)
+		default:
+			return event
+		}
+	default:
+		return event
+	}
+
+	v.showDetails()
+	return nil
+}
+
+// showDetails lists the foreign keys of the selected table and those
+// referencing it.
+func (v *ERDiagramView) showDetails() {
+	c := v.canvas
+	if !c.loaded() {
+		return
+	}
+
+	table := c.diagram.Tables[c.selected].Name
+	var lines []string
+	for _, relation := range c.diagram.Relations() {
+		if relation.Table != table && relation.ReferencedTable != table {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s (%s) -> %s (%s)",
+			relation.Table, strings.Join(relation.Columns, ", "),
+			relation.ReferencedTable, strings.Join(relation.ReferencedColumns, ", ")))
+	}
+	if len(lines) == 0 {
+		lines = append(lines, "No foreign key from or to "+table)
+	}
+
+	v.details.SetTitle(" Relations of " + table + " ")
+	v.details.SetText(strings.Join(lines, "\n")).ScrollToBeginning()
+}
+
+func (v *ERDiagramView) open() {
+	c := v.canvas
+	if !c.loaded() {
+		return
+	}
+
+	v.close()
+	v.home.showTable(v.database, c.diagram.Tables[c.selected].Name)
+}
+
+func (v *ERDiagramView) copy(format string, export func() string) {
+	if !v.canvas.loaded() {
+		return
+	}
+
+	clipboard := lib.NewClipboard()
+	if err := clipboard.Write(export()); err != nil {
+		logger.Error("Error copying the ER diagram to clipboard", map[string]any{"error": err.Error()})
+		v.details.SetTitle(" " + format + " copy failed ")
+		return
+	}
+	v.details.SetTitle(" " + format + " copied ")
+}
+
+// write writes the diagram to a new file in the export directory.
+func (v *ERDiagramView) write(format, extension string, export func() string) {
+	if !v.canvas.loaded() {
+		return
+	}
+
+	name := strings.NewReplacer(".", "_", "/", "_").Replace(v.canvas.diagram.Name)
+	filePath := filepath.Join(getDefaultExportDir(), fmt.Sprintf("%s_er_%s.%s", name, time.Now().Format("20060102_150405"), extension))
+
+	if err := os.WriteFile(filePath, []byte(export()), 0o600); err != nil {
+		logger.Error("Error writing the ER diagram", map[string]any{"error": err.Error()})
+		v.details.SetTitle(" " + format + " write failed: " + err.Error() + " ")
+		return
+	}
+	v.details.SetTitle(" " + format + " written to " + filePath + " ")
+}
+
+func (v *ERDiagramView) close() {
+	if v.cancel != nil {
+		v.cancel()
+	}
+	mainPages.RemovePage(pageNameERDiagram)
+}
+
+func (c *erCanvas) loaded() bool {
+	return c.layout != nil && len(c.diagram.Tables) > 0
+}
+
+func (c *erCanvas) selectTable(table int) {
+	c.selected = table
+	c.anchor = table
+	c.related = -1
+}
+
+// moveInLevel selects the table delta places below the selected one in its
+// layer.
+func (c *erCanvas) moveInLevel(delta int) {
+	if !c.loaded() {
+		return
+	}
+
+	tables := c.layout.levels[c.layout.boxes[c.selected].level]
+	i := slices.Index(tables, c.selected) + delta
+	if i >= 0 && i < len(tables) {
+		c.selectTable(tables[i])
+	}
+}
+
+// moveTo selects the table the selected table references, or one that
+// references it, that is the closest to it vertically.
+func (c *erCanvas) moveTo(referencing bool) {
+	if !c.loaded() {
+		return
+	}
+
+	tables := c.layout.referenced[c.selected]
+	if referencing {
+		tables = c.layout.referencing[c.selected]
+	}
+	if len(tables) == 0 {
+		return
+	}
+
+	y := c.layout.boxes[c.selected].y
+	closest := tables[0]
+	for _, table := range tables[1:] {
+		if abs(c.layout.boxes[table].y-y) < abs(c.layout.boxes[closest].y-y) {
+			closest = table
+		}
+	}
+	c.selectTable(closest)
+}
+
+// cycleRelated selects the next, or previous, of the tables related to the
+// table the cycle started from.
+func (c *erCanvas) cycleRelated(delta int) {
+	if !c.loaded() {
+		return
+	}
+
+	related := c.layout.related(c.anchor)
+	if len(related) == 0 {
+		return
+	}
+	if c.related < 0 && delta < 0 {
+		c.related = 0
+	}
+	c.related = (c.related + delta + len(related)) % len(related)
+	c.selected = related[c.related]
+}
+
+func abs(value int) int {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
+
+func (c *erCanvas) Draw(screen tcell.Screen) {
+	c.DrawForSubclass(screen, c)
+	if !c.loaded() {
+		return
+	}
+
+	x, y, width, height := c.GetInnerRect()
+	c.scrollToSelected(width, height)
+
+	set := func(point erPoint, r rune, style tcell.Style) {
+		px, py := point.x-c.offsetX, point.y-c.offsetY
+		if px >= 0 && px < width && py >= 0 && py < height {
+			screen.SetContent(x+px, y+py, r, nil, style)
+		}
+	}
+
+	lineStyle := tcell.StyleDefault.Foreground(app.Styles.GraphicsColor).Background(app.Styles.PrimitiveBackgroundColor)
+	selectedStyle := lineStyle.Foreground(app.Styles.SecondaryTextColor)
+
+	for point, direction := range c.layout.cells {
+		set(point, erConnectorRunes[direction], lineStyle)
+	}
+	for _, connector := range c.layout.connectors {
+		if connector.from != c.selected && connector.to != c.selected {
+			continue
+		}
+		for _, point := range connector.points {
+			set(point, erConnectorRunes[c.layout.cells[point]], selectedStyle)
+		}
+	}
+	for point := range c.layout.arrows {
+		style := lineStyle
+		if c.isSelectedArrow(point) {
+			style = selectedStyle
+		}
+		set(point, '◀', style)
+	}
+
+	related := c.layout.related(c.selected)
+	for i, box := range c.layout.boxes {
+		borderStyle := lineStyle.Foreground(app.Styles.BorderColor)
+		switch {
+		case i == c.selected:
+			borderStyle = selectedStyle
+		case slices.Contains(related, i):
+			borderStyle = lineStyle.Foreground(app.Styles.TertiaryTextColor)
+		}
+		c.drawBox(box, borderStyle, set)
+	}
+}
+
+// isSelectedArrow reports whether the arrow at point ends a connector of
+// the selected table.
+func (c *erCanvas) isSelectedArrow(point erPoint) bool {
+	for _, connector := range c.layout.connectors {
+		if (connector.from == c.selected || connector.to == c.selected) && len(connector.points) > 0 && connector.points[0] == point {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *erCanvas) drawBox(box erBox, borderStyle tcell.Style, set func(erPoint, rune, tcell.Style)) {
+	textStyle := tcell.StyleDefault.Foreground(app.Styles.PrimaryTextColor).Background(app.Styles.PrimitiveBackgroundColor)
+	right, bottom := box.x+box.width-1, box.y+box.height-1
+
+	for x := box.x; x <= right; x++ {
+		for y := box.y; y <= bottom; y++ {
+			set(erPoint{x, y}, ' ', textStyle)
+		}
+		set(erPoint{x, box.y}, tview.Borders.Horizontal, borderStyle)
+		set(erPoint{x, bottom}, tview.Borders.Horizontal, borderStyle)
+	}
+	for y := box.y; y <= bottom; y++ {
+		set(erPoint{box.x, y}, tview.Borders.Vertical, borderStyle)
+		set(erPoint{right, y}, tview.Borders.Vertical, borderStyle)
+	}
+	set(erPoint{box.x, box.y}, tview.Borders.TopLeft, borderStyle)
+	set(erPoint{right, box.y}, tview.Borders.TopRight, borderStyle)
+	set(erPoint{box.x, bottom}, tview.Borders.BottomLeft, borderStyle)
+	set(erPoint{right, bottom}, tview.Borders.BottomRight, borderStyle)
+
+	drawText := func(y int, text string, style tcell.Style) {
+		for i, r := range []rune(text) {
+			set(erPoint{box.x + 2 + i, y}, r, style)
+		}
+	}
+
+	drawText(box.y+1, box.title, textStyle.Bold(true))
+	if len(box.lines) == 0 {
+		return
+	}
+
+	set(erPoint{box.x, box.y + 2}, tview.Borders.LeftT, borderStyle)
+	set(erPoint{right, box.y + 2}, tview.Borders.RightT, borderStyle)
+	for x := box.x + 1; x < right; x++ {
+		set(erPoint{x, box.y + 2}, tview.Borders.Horizontal, borderStyle)
+	}
+	for i, line := range box.lines {
+		drawText(box.y+3+i, line, textStyle)
+	}
+}
+
+// scrollToSelected scrolls the diagram for the selected table to be in the
+// width by height view, its top left corner first if it does not fit.
+func (c *erCanvas) scrollToSelected(width, height int) {
+	box := c.layout.boxes[c.selected]
+
+	if box.x+box.width > c.offsetX+width {
+		c.offsetX = box.x + box.width - width + 1
+	}
+	if box.x-1 < c.offsetX {
+		c.offsetX = max(box.x-1, 0)
+	}
+	if box.y+box.height > c.offsetY+height {
+		c.offsetY = box.y + box.height - height + 1
+	}
+	if box.y-1 < c.offsetY {
+		c.offsetY = max(box.y-1, 0)
+	}
+}
@@ -0,0 +1,66 @@
+package components
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jorgerojas26/lazysql/drivers"
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+func TestERLevels(t *testing.T) {
+	// 0 references nothing, 1 and 2 reference 0, 3 references 2 and 4,
+	// which references 3 back: the cycle is broken at 4.
+	referenced := [][]int{nil, {0}, {0}, {2, 4}, {3}}
+
+	if levels := erLevels(referenced); !reflect.DeepEqual(levels, []int{0, 1, 1, 2, 0}) {
+		t.Fatalf("erLevels failed: got %v", levels)
+	}
+}
+
+func TestNewERLayout(t *testing.T) {
+	diagram := &drivers.ERDiagram{Tables: []models.TableSchema{
+		{
+			Name:        "orders",
+			Columns:     []models.ColumnSchema{{Name: "id", Type: "integer"}, {Name: "user_id", Type: "integer"}},
+			ForeignKeys: []models.ForeignKeySchema{{Columns: []string{"user_id"}, ReferencedTable: "users", ReferencedColumns: []string{"id"}}},
+		},
+		{
+			Name:    "users",
+			Columns: []models.ColumnSchema{{Name: "id", Type: "integer"}},
+		},
+	}}
+
+	l := newERLayout(diagram)
+
+	if !reflect.DeepEqual(l.levels, [][]int{{1}, {0}}) {
+		t.Fatalf("newERLayout failed: got levels %v", l.levels)
+	}
+
+	users, orders := l.boxes[1], l.boxes[0]
+	if users.x != 1 || users.y != 1 || users.width != len("id integer")+4 || users.height != 5 {
+		t.Fatalf("newERLayout failed: got users box %+v", users)
+	}
+	// The layer of orders follows the gap of users, widened by its
+	// connector.
+	if orders.x != users.x+users.width+erLayerGap+2 {
+		t.Fatalf("newERLayout failed: got orders box %+v", orders)
+	}
+
+	if len(l.connectors) != 1 {
+		t.Fatalf("newERLayout failed: got %d connectors", len(l.connectors))
+	}
+	points := l.connectors[0].points
+	// From the right of users.id to the left of orders.user_id.
+	start, end := erPoint{users.x + users.width, users.y + 3}, erPoint{orders.x - 1, orders.y + 4}
+	if points[0] != start || points[len(points)-1] != end || !l.arrows[start] {
+		t.Fatalf("newERLayout failed: got connector from %v to %v", points[0], points[len(points)-1])
+	}
+	if r := erConnectorRunes[l.cells[erPoint{users.x + users.width + 3, start.y}]]; r != '┐' {
+		t.Fatalf("newERLayout failed: got corner %q", r)
+	}
+
+	if !reflect.DeepEqual(l.related(1), []int{0}) || !reflect.DeepEqual(l.related(0), []int{1}) {
+		t.Fatalf("newERLayout failed: got related %v and %v", l.related(1), l.related(0))
+	}
+}
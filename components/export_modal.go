@@ -5,33 +5,35 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 
 	"github.com/jorgerojas26/lazysql/app"
+	"github.com/jorgerojas26/lazysql/helpers"
 )
 
 const defaultBatchSize = 10000
 
-// CSVExportScope defines the scope of data to export
-type CSVExportScope int
+// ExportScope defines the scope of data to export
+type ExportScope int
 
 const (
-	ExportCurrentPage CSVExportScope = iota
+	ExportCurrentPage ExportScope = iota
 	ExportAllRecords
 )
 
-// CSVExportOptions contains options for creating a CSV export modal.
-type CSVExportOptions struct {
+// ExportModalOptions contains options for creating an export modal.
+type ExportModalOptions struct {
 	DatabaseName  string // Database name for file naming
 	TableName     string // Table name for file naming
 	HasPagination bool   // Whether pagination exists (determines UI: 2 buttons vs 1)
-	RowCount      int    // Current row count for display (excluding header)
+	RowCount      int    // Current row count for display (excluding header), -1 if not known yet
 }
 
-// getDefaultExportDir returns the default directory for CSV export.
+// getDefaultExportDir returns the default directory for exports.
 // Uses ~/Downloads on all platforms (standard location), falls back to home directory.
 func getDefaultExportDir() string {
 	homeDir, err := os.UserHomeDir()
@@ -48,28 +50,41 @@ func getDefaultExportDir() string {
 	return homeDir
 }
 
-// CSVExportModal is a modal for exporting data to CSV.
-type CSVExportModal struct {
+// ExportModal is a modal for exporting data to a file in one of the
+// helpers.ExportFormats.
+type ExportModal struct {
 	tview.Primitive
 	form          *tview.Form
 	hasPagination bool
-	onExport      func(filePath string, scope CSVExportScope, batchSize int)
+	format        helpers.ExportFormat
+	onExport      func(filePath string, format helpers.ExportFormat, scope ExportScope, batchSize int)
 }
 
-// NewCSVExportModal creates a new CSVExportModal.
-func NewCSVExportModal(opts CSVExportOptions, onExport func(filePath string, scope CSVExportScope, batchSize int)) *CSVExportModal {
-	cem := &CSVExportModal{
+// NewExportModal creates a new ExportModal.
+func NewExportModal(opts ExportModalOptions, onExport func(filePath string, format helpers.ExportFormat, scope ExportScope, batchSize int)) *ExportModal {
+	cem := &ExportModal{
 		hasPagination: opts.HasPagination,
+		format:        helpers.ExportCSV,
 		onExport:      onExport,
 	}
 
 	// Default file path with timestamp to avoid conflicts
 	timestamp := time.Now().Format("20060102_150405")
-	fileName := fmt.Sprintf("%s_%s_%s.csv", opts.DatabaseName, opts.TableName, timestamp)
+	fileName := fmt.Sprintf("%s_%s_%s.%s", opts.DatabaseName, opts.TableName, timestamp, cem.format.Extension())
 	defaultPath := filepath.Join(getDefaultExportDir(), fileName)
 
+	formatNames := make([]string, len(helpers.ExportFormats))
+	for i, format := range helpers.ExportFormats {
+		formatNames[i] = format.String()
+	}
+
 	cem.form = tview.NewForm().
-		AddInputField("File Path", defaultPath, 0, nil, nil)
+		AddInputField("File Path", defaultPath, 0, nil, nil).
+		AddDropDown("Format", formatNames, 0, func(_ string, index int) {
+			if index >= 0 {
+				cem.setFormat(helpers.ExportFormats[index])
+			}
+		})
 
 	if opts.HasPagination {
 		// Add batch size field for table view (used for Export All Records)
@@ -85,6 +100,9 @@ func NewCSVExportModal(opts CSVExportOptions, onExport func(filePath string, sco
 	} else {
 		// Query result: show single export button with row count
 		buttonLabel := fmt.Sprintf("Export (%d rows)", opts.RowCount)
+		if opts.RowCount < 0 {
+			buttonLabel = "Export (all rows)"
+		}
 		cem.form.AddButton(buttonLabel, func() {
 			cem.export(ExportAllRecords)
 		})
@@ -121,10 +139,10 @@ func NewCSVExportModal(opts CSVExportOptions, onExport func(filePath string, sco
 		SetDirection(tview.FlexRow).
 		AddItem(cem.form, 0, 1, true).
 		AddItem(hint, 1, 0, false)
-	formWithHint.SetBorder(true).SetTitle(" Export to CSV ").SetTitleAlign(tview.AlignLeft)
+	formWithHint.SetBorder(true).SetTitle(" Export ").SetTitleAlign(tview.AlignLeft)
 
 	grid := tview.NewGrid().
-		SetRows(0, 11, 0).
+		SetRows(0, 13, 0).
 		SetColumns(0, 80, 0).
 		AddItem(formWithHint, 1, 1, 1, 1, 0, 0, true)
 
@@ -133,7 +151,19 @@ func NewCSVExportModal(opts CSVExportOptions, onExport func(filePath string, sco
 	return cem
 }
 
-func (cem *CSVExportModal) export(scope CSVExportScope) {
+// setFormat selects format and gives the file path its extension.
+func (cem *ExportModal) setFormat(format helpers.ExportFormat) {
+	input := cem.form.GetFormItem(0).(*tview.InputField)
+	filePath := input.GetText()
+
+	if previous := "." + cem.format.Extension(); strings.HasSuffix(filePath, previous) {
+		input.SetText(strings.TrimSuffix(filePath, previous) + "." + format.Extension())
+	}
+
+	cem.format = format
+}
+
+func (cem *ExportModal) export(scope ExportScope) {
 	filePath := cem.form.GetFormItem(0).(*tview.InputField).GetText()
 	if filePath == "" {
 		cem.showErrorModal("File path cannot be empty")
@@ -142,7 +172,7 @@ func (cem *CSVExportModal) export(scope CSVExportScope) {
 
 	batchSize := 0
 	if cem.hasPagination {
-		batchSizeText := cem.form.GetFormItem(1).(*tview.InputField).GetText()
+		batchSizeText := cem.form.GetFormItem(2).(*tview.InputField).GetText()
 		var err error
 		batchSize, err = strconv.Atoi(batchSizeText)
 		if err != nil || batchSize <= 0 {
@@ -151,23 +181,23 @@ func (cem *CSVExportModal) export(scope CSVExportScope) {
 		}
 	}
 
-	mainPages.RemovePage(pageNameCSVExport)
+	mainPages.RemovePage(pageNameExport)
 
 	if cem.onExport != nil {
-		cem.onExport(filePath, scope, batchSize)
+		cem.onExport(filePath, cem.format, scope, batchSize)
 	}
 }
 
-func (cem *CSVExportModal) showErrorModal(message string) {
+func (cem *ExportModal) showErrorModal(message string) {
 	modal := NewErrorModal(message)
 	modal.SetDoneFunc(func(_ int, _ string) {
-		mainPages.RemovePage(pageNameCSVExportError)
+		mainPages.RemovePage(pageNameExportError)
 	})
 
-	mainPages.AddPage(pageNameCSVExportError, modal, true, true)
+	mainPages.AddPage(pageNameExportError, modal, true, true)
 	App.SetFocus(modal)
 }
 
-func (cem *CSVExportModal) cancel() {
-	mainPages.RemovePage(pageNameCSVExport)
+func (cem *ExportModal) cancel() {
+	mainPages.RemovePage(pageNameExport)
 }
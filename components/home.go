@@ -1,9 +1,11 @@
 package components
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 	"strings"
+	"sync"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
@@ -32,11 +34,30 @@ type Home struct {
 	DBDriver             drivers.Driver
 	FocusedWrapper       string
 	ListOfDBChanges      []models.DBDMLChange
+	ListOfDDLChanges     []models.DBDDLChange
 	ConnectionIdentifier string
 	ConnectionURL        string
 	ReadOnly             bool
+	// pageName is the name of the page of the home in mainPages.
+	pageName string
+	// connector connects again when the connection is lost, see
+	// watchConnection.
+	connector    *connector
+	reconnectMu  sync.Mutex
+	stopWatching context.CancelFunc
+	// statusMu guards the states of the connection and of its SSH tunnel,
+	// set outside the UI goroutine.
+	statusMu         sync.Mutex
+	connectionStatus string
+	tunnelStatus     string
+	// queryResultTabs are the result tabs opened by the last script run
+	// from an editor, see showQueryResult.
+	queryResultTabs []*Tab
 }
 
+// openHomes holds every connection session opened during this run.
+var openHomes []*Home
+
 func NewHomePage(connection models.Connection, dbdriver drivers.Driver) *Home {
 	tree := NewTree(connection.DBName, dbdriver, connection.Schemas)
 	leftWrapper := tview.NewFlex()
@@ -94,11 +115,7 @@ func NewHomePage(connection models.Connection, dbdriver drivers.Driver) *Home {
 	leftWrapper.SetBorderColor(app.Styles.InverseTextColor)
 	leftWrapper.AddItem(tree.Wrapper, 0, 1, true)
 
-	if connection.ReadOnly {
-		leftWrapper.SetTitle(" [READ-ONLY] ")
-		leftWrapper.SetTitleColor(tcell.ColorLightBlue)
-		leftWrapper.SetBorder(true)
-	}
+	home.updateStatusTitle()
 
 	rightWrapper.SetBorderColor(app.Styles.InverseTextColor)
 	rightWrapper.SetBorder(true)
@@ -123,7 +140,13 @@ func NewHomePage(connection models.Connection, dbdriver drivers.Driver) *Home {
 		}
 	})
 
+	home.pageName = connection.Name
+	if home.pageName == "" {
+		home.pageName = connection.URL
+	}
+
 	mainPages.AddPage(connection.URL, home, true, false)
+	openHomes = append(openHomes, home)
 	return home
 }
 
@@ -144,6 +167,23 @@ func (home *Home) subscribeToTreeChanges() {
 			} else {
 				home.SetInputCapture(home.homeInputCapture)
 			}
+		case eventTreeNewTable:
+			location := stateChange.Value.(*TreeNodeData)
+			App.QueueUpdateDraw(func() {
+				mainPages.AddPage(pageNameCreateTable, NewCreateTableView(home, location.Database, location.Schema), true, true)
+			})
+		case eventTreeShowDefinition:
+			home.showNodeDefinition(stateChange.Value.(*TreeNodeData))
+		case eventTreeShowERDiagram:
+			location := stateChange.Value.(*TreeNodeData)
+			App.QueueUpdateDraw(func() {
+				home.showERDiagram(location.Database, location.Schema)
+			})
+		case eventTreeRefreshMaterializedView:
+			node := stateChange.Value.(*TreeNodeData)
+			App.QueueUpdateDraw(func() {
+				home.confirmRefreshMaterializedView(node)
+			})
 		case eventTreeSelectedFunction:
 			home.createOrFocusEditorTab()
 			currentTab := home.TabbedPane.GetCurrentTab()
@@ -151,7 +191,7 @@ func (home *Home) subscribeToTreeChanges() {
 				table := currentTab.Content.(*ResultsTable)
 				databaseName := home.Tree.GetSelectedDatabase()
 				functionName := stateChange.Value.(string)
-				functionDefinition, err := home.Tree.DBDriver.GetFunctionDefinition(databaseName, functionName)
+				functionDefinition, err := home.Tree.DBDriver.GetFunctionDefinition(App.Context(), databaseName, functionName)
 				if err != nil {
 					logger.Error(err.Error(), nil)
 					continue
@@ -166,7 +206,7 @@ func (home *Home) subscribeToTreeChanges() {
 				table := currentTab.Content.(*ResultsTable)
 				databaseName := home.Tree.GetSelectedDatabase()
 				procedureName := stateChange.Value.(string)
-				procedureDefinition, err := home.Tree.DBDriver.GetProcedureDefinition(databaseName, procedureName)
+				procedureDefinition, err := home.Tree.DBDriver.GetProcedureDefinition(App.Context(), databaseName, procedureName)
 				if err != nil {
 					logger.Error(err.Error(), nil)
 					continue
@@ -181,7 +221,7 @@ func (home *Home) subscribeToTreeChanges() {
 				table := currentTab.Content.(*ResultsTable)
 				databaseName := home.Tree.GetSelectedDatabase()
 				viewName := stateChange.Value.(string)
-				viewDefinition, err := home.Tree.DBDriver.GetViewDefinition(databaseName, viewName)
+				viewDefinition, err := home.Tree.DBDriver.GetViewDefinition(App.Context(), databaseName, viewName)
 				if err != nil {
 					logger.Error(err.Error(), nil)
 					continue
@@ -193,6 +233,91 @@ func (home *Home) subscribeToTreeChanges() {
 	}
 }
 
+// showNodeDefinition shows the definition of the table, view, routine or
+// other object of a node of the tree.
+func (home *Home) showNodeDefinition(node *TreeNodeData) {
+	name := node.Name
+	if node.Schema != "" {
+		name = node.Schema + "." + node.Name
+	}
+
+	var load func(ctx context.Context, database, name string) (string, error)
+	switch node.Type {
+	case NodeTypeTable:
+		load = home.DBDriver.GetTableDefinition
+	case NodeTypeView:
+		load = home.DBDriver.GetViewDefinition
+	case NodeTypeFunction:
+		load = home.DBDriver.GetFunctionDefinition
+	case NodeTypeProcedure:
+		load = home.DBDriver.GetProcedureDefinition
+	case NodeTypeTrigger:
+		load = home.DBDriver.GetTriggerDefinition
+	case NodeTypeSequence:
+		load = home.DBDriver.GetSequenceDefinition
+	case NodeTypeMaterializedView:
+		load = home.DBDriver.GetMaterializedViewDefinition
+	case NodeTypeType:
+		load = home.DBDriver.GetTypeDefinition
+	default:
+		return
+	}
+
+	home.showDefinition(name, func(ctx context.Context) (string, error) {
+		return load(ctx, node.Database, name)
+	})
+}
+
+// confirmRefreshMaterializedView asks to refresh the materialized view of
+// node, which recomputes all its rows, then refreshes it in the background.
+func (home *Home) confirmRefreshMaterializedView(node *TreeNodeData) {
+	if home.ReadOnly {
+		errorModal := NewErrorModal("Cannot refresh the materialized view: Connection is in read-only mode")
+		errorModal.SetDoneFunc(func(_ int, _ string) {
+			mainPages.RemovePage(pageNameReadOnlyError)
+		})
+		mainPages.AddPage(pageNameReadOnlyError, errorModal, true, true)
+		return
+	}
+
+	name := node.Schema + "." + node.Name
+
+	confirmation := NewConfirmationModal("Refresh the materialized view " + name + "?")
+	confirmation.SetDoneFunc(func(_ int, buttonLabel string) {
+		mainPages.RemovePage(pageNameRefreshMaterializedView)
+		if buttonLabel != confirmationYes {
+			return
+		}
+
+		go func() {
+			err := home.DBDriver.RefreshMaterializedView(App.Context(), node.Database, name)
+
+			if err != nil {
+				logger.Error("Failed to refresh the materialized view", map[string]any{"error": err.Error(), "name": name})
+			}
+
+			App.QueueUpdateDraw(func() {
+				modal := NewInfoModal("Refreshed the materialized view " + name)
+				if err != nil {
+					modal = NewErrorModal("Failed to refresh the materialized view " + name + ": " + err.Error())
+				}
+				modal.SetDoneFunc(func(_ int, _ string) {
+					mainPages.RemovePage(pageNameRefreshMaterializedView)
+				})
+				mainPages.AddPage(pageNameRefreshMaterializedView, modal, true, true)
+			})
+		}()
+	})
+
+	mainPages.AddPage(pageNameRefreshMaterializedView, confirmation, true, true)
+}
+
+// refreshTree reloads the databases and their objects in the tree, e.g.
+// after a table is created.
+func (home *Home) refreshTree() {
+	home.Tree.Refresh(home.Tree.dbName)
+}
+
 func (home *Home) showTable(databaseName, tableName string) {
 	if tableName == "" {
 		return
@@ -236,6 +361,30 @@ func (home *Home) showTable(databaseName, tableName string) {
 	home.focusRightWrapper()
 }
 
+// showQueryResult shows the records returned by a statement of an editor
+// script in a tab of its own, the index-th result tab of the script. The tab
+// opened for the same index by a previous run is reused if it is still open.
+func (home *Home) showQueryResult(index int, name string, records []models.Record, recordCount int) {
+	var table *ResultsTable
+
+	if index < len(home.queryResultTabs) && home.TabbedPane.HasTab(home.queryResultTabs[index]) {
+		table = home.queryResultTabs[index].Content.(*ResultsTable)
+	} else {
+		table = NewResultsTable(&home.ListOfDBChanges, home.Tree, home.DBDriver, home, home.ConnectionIdentifier, home.ConnectionURL, home.ReadOnly).WithQueryResult()
+		tab := home.TabbedPane.AppendTab(name, table, name)
+		if index < len(home.queryResultTabs) {
+			home.queryResultTabs[index] = tab
+		} else {
+			home.queryResultTabs = append(home.queryResultTabs, tab)
+		}
+	}
+
+	table.Pagination.SetTotalRecords(recordCount)
+	table.Pagination.SetLimit(recordCount)
+	table.SetRecords(records)
+	table.Select(1, 0)
+}
+
 func (home *Home) ShowTableWithFilter(databaseName, tableName, where string) {
 	if tableName == "" {
 		return
@@ -515,6 +664,11 @@ func (home *Home) homeInputCapture(event *tcell.EventKey) *tcell.EventKey {
 		if (table != nil && !table.GetIsEditing() && !table.GetIsFiltering()) || table == nil {
 			mainPages.SwitchToPage(pageNameConnections)
 		}
+	case commands.SwitchConnection:
+		if table == nil || (!table.GetIsEditing() && !table.GetIsFiltering()) {
+			home.showConnectionSwitcher()
+			return nil
+		}
 	case commands.Quit:
 		if tab == nil || (!table.GetIsEditing() && !table.GetIsFiltering()) {
 			showQuitConfirmation()
@@ -531,25 +685,8 @@ func (home *Home) homeInputCapture(event *tcell.EventKey) *tcell.EventKey {
 			mainPages.AddPage(pageNameReadOnlyError, errorModal, true, true)
 			return event
 		}
-		if (len(home.ListOfDBChanges) > 0) && !table.GetIsEditing() {
-			queryPreviewModal := NewQueryPreviewModal(&home.ListOfDBChanges, home.DBDriver, func() {
-				for _, change := range home.ListOfDBChanges {
-					queryString, err := home.DBDriver.DMLChangeToQueryString(change)
-					if err != nil {
-						logger.Error("Failed to convert DML change to query string", map[string]any{"error": err})
-						continue
-					}
-					err = history.AddQueryToHistory(home.ConnectionIdentifier, queryString)
-					if err != nil {
-						logger.Error("Failed to add query to history", map[string]any{"error": err})
-					}
-				}
-				home.ListOfDBChanges = []models.DBDMLChange{}
-				table.FetchRecords(nil, nil)
-				home.Tree.ForceRemoveHighlight()
-			})
-
-			mainPages.AddPage(pageNameDMLPreview, queryPreviewModal, true, true)
+		if (len(home.ListOfDBChanges) > 0 || len(home.ListOfDDLChanges) > 0) && !table.GetIsEditing() {
+			home.showQueryPreview(table)
 		}
 	case commands.HelpPopup:
 		if table != nil && (table.GetIsEditing() || table.GetIsFiltering()) {
@@ -602,12 +739,62 @@ func (home *Home) homeInputCapture(event *tcell.EventKey) *tcell.EventKey {
 			return nil
 		}
 
+		return event
+	case commands.BeginTransaction, commands.CommitTransaction, commands.RollbackTransaction:
+		if table == nil || (!table.GetIsEditing() && !table.GetIsFiltering()) {
+			home.runTransactionCommand(command)
+			return nil
+		}
+
+		return event
+	case commands.CompareSchemas:
+		if table == nil || (!table.GetIsEditing() && !table.GetIsFiltering()) {
+			mainPages.AddPage(pageNameSchemaDiff, NewSchemaDiffView(home), true, true)
+			return nil
+		}
+
 		return event
 	}
 
 	return event
 }
 
+// showQueryPreview shows the pending changes for review. Once they are
+// saved, the records of table are fetched again.
+func (home *Home) showQueryPreview(table *ResultsTable) {
+	queryPreviewModal := NewQueryPreviewModal(&home.ListOfDBChanges, &home.ListOfDDLChanges, home.DBDriver, func() {
+		var queryStrings []string
+		for _, change := range home.ListOfDDLChanges {
+			queries, err := drivers.DDLChangeQueries(home.DBDriver, change)
+			if err != nil {
+				logger.Error("Failed to convert DDL change to query string", map[string]any{"error": err})
+				continue
+			}
+			queryStrings = append(queryStrings, queries...)
+		}
+		for _, change := range home.ListOfDBChanges {
+			queryString, err := home.DBDriver.DMLChangeToQueryString(change)
+			if err != nil {
+				logger.Error("Failed to convert DML change to query string", map[string]any{"error": err})
+				continue
+			}
+			queryStrings = append(queryStrings, queryString)
+		}
+		for _, queryString := range queryStrings {
+			err := history.AddQueryToHistory(home.ConnectionIdentifier, queryString)
+			if err != nil {
+				logger.Error("Failed to add query to history", map[string]any{"error": err})
+			}
+		}
+		home.ListOfDBChanges = []models.DBDMLChange{}
+		home.ListOfDDLChanges = nil
+		table.FetchRecords(nil, nil)
+		home.Tree.ForceRemoveHighlight()
+	})
+
+	mainPages.AddPage(pageNameDMLPreview, queryPreviewModal, true, true)
+}
+
 func (home *Home) createOrFocusEditorTab() {
 	tab := home.TabbedPane.GetTabByName(tabNameEditor)
 	dbName := home.Tree.GetSelectedDatabase()
@@ -0,0 +1,174 @@
+package components
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/jorgerojas26/lazysql/drivers"
+	"github.com/jorgerojas26/lazysql/helpers/logger"
+)
+
+const (
+	healthCheckInterval = 15 * time.Second
+	healthCheckTimeout  = 5 * time.Second
+)
+
+// watchConnection pings the database of the home periodically, and connects
+// again through c when the connection is lost. The operations of driver
+// failing because of the lost connection connect again too, then run once
+// more. The tabs, editors and pending changes are kept.
+func (home *Home) watchConnection(c *connector, driver *drivers.Reconnecting) {
+	home.connector = c
+	driver.Reconnect = home.reconnect
+
+	ctx, cancel := context.WithCancel(App.Context())
+	home.stopWatching = cancel
+
+	if c.tunnel != nil {
+		c.tunnel.SetStatusFunc(func(err error) {
+			status := ""
+			if err != nil {
+				status = "SSH: " + err.Error()
+			}
+
+			home.statusMu.Lock()
+			home.tunnelStatus = status
+			home.statusMu.Unlock()
+			go App.QueueUpdateDraw(home.updateStatusTitle)
+		})
+	}
+
+	go func() {
+		ticker := time.NewTicker(healthCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			// Reconnecting pings first.
+			_ = home.reconnect(ctx)
+		}
+	}()
+}
+
+// closeConnectionHome closes the home of the connection named name, if
+// open.
+func closeConnectionHome(name string) {
+	for _, home := range openHomes {
+		if home.pageName == name {
+			home.close()
+			return
+		}
+	}
+}
+
+// close stops watching the connection of the home, rolls back its open
+// transaction, closes its SSH tunnel and removes its page.
+func (home *Home) close() {
+	if home.stopWatching != nil {
+		home.stopWatching()
+	}
+
+	// The database may not answer, so do not wait for it.
+	go func() {
+		if home.DBDriver.InTransaction() {
+			if err := home.DBDriver.RollbackTransaction(); err != nil {
+				logger.Error("Failed to roll back transaction", map[string]any{"connection": home.ConnectionIdentifier, "error": err})
+			}
+		}
+		if home.connector != nil && home.connector.tunnel != nil {
+			_ = home.connector.tunnel.Close()
+		}
+	}()
+
+	openHomes = slices.DeleteFunc(openHomes, func(h *Home) bool { return h == home })
+	mainPages.RemovePage(home.pageName)
+}
+
+// reconnect connects the home again if its connection is lost. It is called
+// by the health check and by the operations failing with a connection error,
+// one at a time.
+func (home *Home) reconnect(ctx context.Context) error {
+	home.reconnectMu.Lock()
+	defer home.reconnectMu.Unlock()
+
+	pingCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	err := home.DBDriver.Ping(pingCtx)
+	cancel()
+	if err == nil || home.connector == nil {
+		// The connection is fine, or was opened again while waiting.
+		return err
+	}
+
+	logger.Error("Connection lost", map[string]any{"connection": home.ConnectionIdentifier, "error": err})
+	lostTransaction := home.DBDriver.InTransaction()
+	home.queueConnectionStatus("Connection lost, reconnecting...")
+
+	connection, err := home.connector.connect(ctx, home.DBDriver, home.queueConnectionStatus)
+	if err != nil {
+		logger.Error("Failed to reconnect", map[string]any{"connection": home.ConnectionIdentifier, "error": err})
+		home.queueConnectionStatus("Connection lost: " + err.Error())
+		return err
+	}
+
+	logger.Info("Reconnected", map[string]any{"connection": home.ConnectionIdentifier})
+	home.queueConnectionStatus("")
+	go App.QueueUpdateDraw(func() {
+		home.ConnectionURL = connection.URL
+
+		if lostTransaction {
+			home.updateTransactionIndicator()
+			home.showTransactionError("The connection was lost: its open transaction was rolled back.")
+		}
+	})
+	return nil
+}
+
+// queueConnectionStatus shows status, the state of the connection, from
+// outside the UI goroutine. It does not wait for the status to be drawn, as
+// the UI goroutine may itself be waiting for the reconnection.
+func (home *Home) queueConnectionStatus(status string) {
+	home.statusMu.Lock()
+	home.connectionStatus = status
+	home.statusMu.Unlock()
+
+	go App.QueueUpdateDraw(home.updateStatusTitle)
+}
+
+// statuses returns the states of the connection and of its SSH tunnel, empty
+// when fine.
+func (home *Home) statuses() (connection, tunnel string) {
+	home.statusMu.Lock()
+	defer home.statusMu.Unlock()
+
+	return home.connectionStatus, home.tunnelStatus
+}
+
+// updateStatusTitle shows the state of the connection and of its SSH tunnel,
+// when not fine, in the title of the tree next to the read-only mark.
+func (home *Home) updateStatusTitle() {
+	title := ""
+	if home.ReadOnly {
+		title = " [READ-ONLY] "
+		home.LeftWrapper.SetTitleColor(tcell.ColorLightBlue)
+	}
+
+	connectionStatus, tunnelStatus := home.statuses()
+	for _, status := range []string{connectionStatus, tunnelStatus} {
+		if status != "" {
+			title += " " + tview.Escape(status) + " "
+			home.LeftWrapper.SetTitleColor(tcell.ColorRed)
+		}
+	}
+
+	home.LeftWrapper.SetTitle(title)
+	home.LeftWrapper.SetBorder(title != "")
+}
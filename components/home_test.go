@@ -1,6 +1,11 @@
 package components
 
-import "testing"
+import (
+	"reflect"
+	"testing"
+
+	"github.com/rivo/tview"
+)
 
 func TestClampTreeWidth(t *testing.T) {
 	tests := []struct {
@@ -68,3 +73,38 @@ func TestClampTreeWidth(t *testing.T) {
 		})
 	}
 }
+
+type boxTab struct{ *tview.Box }
+
+func (b boxTab) GetPrimitive() tview.Primitive { return b.Box }
+
+func TestRemoveQueryResults(t *testing.T) {
+	home := &Home{TabbedPane: NewTabbedPane()}
+	for _, name := range []string{"users", "Result 1", "Result 2", "Result 3"} {
+		tab := home.TabbedPane.AppendTab(name, boxTab{tview.NewBox()}, name)
+		if name != "users" {
+			home.queryResultTabs = append(home.queryResultTabs, tab)
+		}
+	}
+	// A tab of the user named like a result tab, and a result tab the user
+	// closed.
+	home.TabbedPane.AppendTab("Result 5", boxTab{tview.NewBox()}, "saved")
+	home.TabbedPane.RemoveTab(home.queryResultTabs[2])
+	home.TabbedPane.AppendTab(tabNameEditor, boxTab{tview.NewBox()}, tabNameEditor)
+
+	home.removeQueryResults(1)
+
+	names := []string{}
+	for tab := home.TabbedPane.state.FirstTab; tab != nil; tab = tab.NextTab {
+		names = append(names, tab.Name)
+	}
+	if want := []string{"users", "Result 1", "Result 5", tabNameEditor}; !reflect.DeepEqual(names, want) {
+		t.Errorf("got tabs %v, want %v", names, want)
+	}
+	if home.TabbedPane.GetLength() != 4 || home.TabbedPane.GetCurrentTab().Name != tabNameEditor {
+		t.Errorf("got %d tabs, current %q", home.TabbedPane.GetLength(), home.TabbedPane.GetCurrentTab().Name)
+	}
+	if len(home.queryResultTabs) != 1 || home.queryResultTabs[0].Name != "Result 1" {
+		t.Errorf("got %d result tabs left, want 1", len(home.queryResultTabs))
+	}
+}
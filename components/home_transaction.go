@@ -0,0 +1,94 @@
+package components
+
+import (
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/jorgerojas26/lazysql/commands"
+	"github.com/jorgerojas26/lazysql/helpers/logger"
+)
+
+// runTransactionCommand begins, commits or rolls back the transaction of the
+// connection session. The transaction is bound to the app context, so it is
+// rolled back if the app stops without committing it. The command runs in
+// the background, as the database may be slow to answer, e.g. waiting for a
+// lock.
+func (home *Home) runTransactionCommand(command commands.Command) {
+	go func() {
+		var err error
+
+		switch command {
+		case commands.BeginTransaction:
+			err = home.DBDriver.BeginTransaction(App.Context())
+		case commands.CommitTransaction:
+			err = home.DBDriver.CommitTransaction()
+		case commands.RollbackTransaction:
+			err = home.DBDriver.RollbackTransaction()
+		}
+
+		App.QueueUpdateDraw(func() {
+			home.updateTransactionIndicator()
+
+			if err != nil {
+				logger.Error("Transaction command failed", map[string]any{"command": command.String(), "error": err})
+				home.showTransactionError(err.Error())
+				return
+			}
+
+			if command == commands.BeginTransaction {
+				return
+			}
+
+			// Committed or rolled back rows may differ from what is on screen.
+			if tab := home.TabbedPane.GetCurrentTab(); tab != nil {
+				table := tab.Content.(*ResultsTable)
+				if table.Editor == nil && table.GetTableName() != "" {
+					table.FetchRecords(nil, nil)
+				}
+			}
+		})
+	}()
+}
+
+func (home *Home) updateTransactionIndicator() {
+	if home.DBDriver.InTransaction() {
+		home.RightWrapper.SetTitle(" [TRANSACTION] ")
+		home.RightWrapper.SetTitleColor(tcell.ColorOrange)
+	} else {
+		home.RightWrapper.SetTitle("")
+	}
+}
+
+func (home *Home) showTransactionError(message string) {
+	modal := NewErrorModal(message)
+	modal.SetDoneFunc(func(_ int, _ string) {
+		mainPages.RemovePage(pageNameTransactionError)
+	})
+
+	mainPages.AddPage(pageNameTransactionError, modal, true, true)
+	App.SetFocus(modal)
+}
+
+// openTransactions returns the identifiers of the connection sessions that
+// have an open transaction.
+func openTransactions() []string {
+	identifiers := []string{}
+	for _, home := range openHomes {
+		if home.DBDriver.InTransaction() {
+			identifiers = append(identifiers, home.ConnectionIdentifier)
+		}
+	}
+	return identifiers
+}
+
+// rollbackOpenTransactions rolls back the open transaction of every
+// connection session.
+func rollbackOpenTransactions() {
+	for _, home := range openHomes {
+		if !home.DBDriver.InTransaction() {
+			continue
+		}
+		if err := home.DBDriver.RollbackTransaction(); err != nil {
+			logger.Error("Failed to roll back transaction", map[string]any{"connection": home.ConnectionIdentifier, "error": err})
+		}
+	}
+}
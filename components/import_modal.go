@@ -0,0 +1,265 @@
+package components
+
+import (
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/jorgerojas26/lazysql/app"
+	"github.com/jorgerojas26/lazysql/helpers"
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+const (
+	importPreviewRows = 5
+	importSkipOption  = "(skip)"
+)
+
+// ImportModal is a modal for importing the rows of a CSV, TSV or NDJSON file
+// into a table. Once the file is loaded, each column of the table is mapped
+// to a column of the file, and a preview shows the first rows as they will
+// be inserted.
+type ImportModal struct {
+	tview.Primitive
+	container *tview.Flex
+	columns   []string
+	onImport  func(filePath string, mapping []int)
+
+	filePath    string
+	fileColumns []string
+	preview     []models.Record
+	mapping     []int
+	previewView *tview.Table
+}
+
+// NewImportModal creates a new ImportModal for a table with columns.
+// onImport receives the file path and, for each table column, the index of
+// the file column it is read from, -1 for the columns left to their default.
+func NewImportModal(tableName string, columns []string, onImport func(filePath string, mapping []int)) *ImportModal {
+	im := &ImportModal{
+		columns:  columns,
+		onImport: onImport,
+	}
+
+	im.container = tview.NewFlex().SetDirection(tview.FlexRow)
+	im.container.SetBorder(true).SetTitle(" Import into " + tableName + " ").SetTitleAlign(tview.AlignLeft)
+
+	grid := tview.NewGrid().
+		SetRows(0, 24, 0).
+		SetColumns(0, 100, 0).
+		AddItem(im.container, 1, 1, 1, 1, 0, 0, true)
+
+	im.Primitive = grid
+
+	im.showFileForm(getDefaultExportDir() + string(filepath.Separator))
+
+	return im
+}
+
+// showFileForm asks for the file to import.
+func (im *ImportModal) showFileForm(filePath string) {
+	form := newImportForm(im)
+	form.AddInputField("File Path", filePath, 0, nil, nil).
+		AddButton("Load", func() {
+			im.load(form.GetFormItem(0).(*tview.InputField).GetText())
+		}).
+		AddButton("Cancel", im.cancel)
+
+	hint := tview.NewTextView().
+		SetText("CSV, TSV, NDJSON or a JSON array of objects, by file extension. Esc to cancel").
+		SetTextAlign(tview.AlignCenter).
+		SetTextColor(app.Styles.TertiaryTextColor)
+
+	im.container.Clear()
+	im.container.AddItem(form, 0, 1, true)
+	im.container.AddItem(hint, 1, 0, false)
+	App.SetFocus(form)
+}
+
+// load reads the columns and the first rows of the file, and shows the
+// column mapping.
+func (im *ImportModal) load(filePath string) {
+	if strings.TrimSpace(filePath) == "" {
+		im.showErrorModal("File path cannot be empty")
+		return
+	}
+
+	reader, err := helpers.OpenImportFile(filePath)
+	if err != nil {
+		im.showErrorModal("Failed to read file: " + err.Error())
+		return
+	}
+	defer reader.Close()
+
+	fileColumns := reader.Columns()
+	if len(fileColumns) == 0 {
+		im.showErrorModal("The file has no columns")
+		return
+	}
+
+	var preview []models.Record
+	for len(preview) < importPreviewRows {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			im.showErrorModal("Failed to read file: " + err.Error())
+			return
+		}
+		preview = append(preview, record)
+	}
+
+	im.filePath = filePath
+	im.fileColumns = fileColumns
+	im.preview = preview
+	im.mapping = defaultImportMapping(im.columns, fileColumns)
+
+	im.showMappingForm()
+}
+
+// defaultImportMapping maps each table column to the file column of the
+// same name, compared case-insensitively.
+func defaultImportMapping(columns, fileColumns []string) []int {
+	mapping := make([]int, len(columns))
+	for i, column := range columns {
+		mapping[i] = -1
+		for j, fileColumn := range fileColumns {
+			if strings.EqualFold(strings.TrimSpace(fileColumn), column) {
+				mapping[i] = j
+				break
+			}
+		}
+	}
+	return mapping
+}
+
+// showMappingForm shows a dropdown per table column to choose the file
+// column it is read from, below the preview of the rows.
+func (im *ImportModal) showMappingForm() {
+	options := append([]string{importSkipOption}, im.fileColumns...)
+
+	// The dropdowns update the preview when they are added.
+	im.previewView = tview.NewTable().SetBorders(false).SetFixed(1, 0)
+	im.previewView.SetBorder(true).SetTitle(" Preview ").SetTitleAlign(tview.AlignLeft)
+
+	form := newImportForm(im)
+	for i, column := range im.columns {
+		form.AddDropDown(column, options, im.mapping[i]+1, func(_ string, index int) {
+			if index >= 0 {
+				im.mapping[i] = index - 1
+				im.updatePreview()
+			}
+		})
+	}
+	form.AddButton("Import", func() {
+		if !hasMappedColumn(im.mapping) {
+			im.showErrorModal("Map at least one column of the file")
+			return
+		}
+		mainPages.RemovePage(pageNameImport)
+		if im.onImport != nil {
+			im.onImport(im.filePath, im.mapping)
+		}
+	}).
+		AddButton("Back", func() {
+			im.showFileForm(im.filePath)
+		}).
+		AddButton("Cancel", im.cancel)
+
+	im.updatePreview()
+
+	hint := tview.NewTextView().
+		SetText("Columns mapped to (skip) get their default value. Esc to cancel").
+		SetTextAlign(tview.AlignCenter).
+		SetTextColor(app.Styles.TertiaryTextColor)
+
+	im.container.Clear()
+	im.container.AddItem(im.previewView, importPreviewRows+3, 0, false)
+	im.container.AddItem(form, 0, 1, true)
+	im.container.AddItem(hint, 1, 0, false)
+	App.SetFocus(form)
+}
+
+func hasMappedColumn(mapping []int) bool {
+	for _, index := range mapping {
+		if index >= 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// updatePreview shows the first rows of the file in the table columns they
+// are mapped to.
+func (im *ImportModal) updatePreview() {
+	im.previewView.Clear()
+
+	for j, column := range im.columns {
+		im.previewView.SetCell(0, j, tview.NewTableCell(column).
+			SetTextColor(app.Styles.PrimaryTextColor).
+			SetSelectable(false))
+	}
+
+	for i, record := range im.preview {
+		for j := range im.columns {
+			cell := tview.NewTableCell("").SetMaxWidth(20)
+
+			switch index := im.mapping[j]; {
+			case index < 0:
+				cell.SetText("DEFAULT").SetTextColor(app.Styles.InverseTextColor)
+			case record[index].Null:
+				cell.SetText("NULL").SetTextColor(app.Styles.InverseTextColor)
+			case record[index].IsEmpty():
+				cell.SetText("EMPTY").SetTextColor(app.Styles.InverseTextColor)
+			default:
+				cell.SetText(record[index].String()).SetTextColor(app.Styles.SecondaryTextColor)
+			}
+
+			im.previewView.SetCell(i+1, j, cell)
+		}
+	}
+}
+
+func newImportForm(im *ImportModal) *tview.Form {
+	form := tview.NewForm()
+	form.SetFieldStyle(
+		tcell.StyleDefault.
+			Background(app.Styles.SecondaryTextColor).
+			Foreground(app.Styles.ContrastSecondaryTextColor),
+	).SetButtonActivatedStyle(tcell.StyleDefault.
+		Background(app.Styles.SecondaryTextColor).
+		Foreground(app.Styles.ContrastSecondaryTextColor),
+	).SetButtonStyle(tcell.StyleDefault.
+		Background(app.Styles.InverseTextColor).
+		Foreground(app.Styles.ContrastSecondaryTextColor),
+	)
+
+	form.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEsc {
+			im.cancel()
+			return nil
+		}
+		return event
+	})
+
+	return form
+}
+
+func (im *ImportModal) showErrorModal(message string) {
+	modal := NewErrorModal(message)
+	modal.SetDoneFunc(func(_ int, _ string) {
+		mainPages.RemovePage(pageNameImportError)
+	})
+
+	mainPages.AddPage(pageNameImportError, modal, true, true)
+	App.SetFocus(modal)
+}
+
+func (im *ImportModal) cancel() {
+	mainPages.RemovePage(pageNameImport)
+}
@@ -0,0 +1,25 @@
+package components
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/jorgerojas26/lazysql/app"
+)
+
+// NewInfoModal returns a modal telling message, e.g. that an operation
+// succeeded, in the colors of the app instead of those of an error.
+func NewInfoModal(message string) *tview.Modal {
+	modal := tview.NewModal().
+		SetText(message).
+		AddButtons([]string{"OK"})
+	modal.SetBackgroundColor(app.Styles.PrimitiveBackgroundColor)
+	modal.SetBorderStyle(tcell.StyleDefault.Background(app.Styles.PrimitiveBackgroundColor))
+	modal.SetTextColor(app.Styles.PrimaryTextColor)
+	modal.SetButtonActivatedStyle(
+		tcell.StyleDefault.
+			Background(app.Styles.InverseTextColor).
+			Foreground(app.Styles.ContrastSecondaryTextColor),
+	)
+	return modal
+}
@@ -1,6 +1,9 @@
 package components
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/rivo/tview"
 
 	"github.com/jorgerojas26/lazysql/app"
@@ -9,7 +12,10 @@ import (
 var mainPages *tview.Pages
 
 func showQuitConfirmation() {
-	if !app.App.Config().ConfirmOnQuit {
+	transactions := openTransactions()
+
+	// An open transaction is rolled back on exit, so always ask first.
+	if !app.App.Config().ConfirmOnQuit && len(transactions) == 0 {
 		app.App.Stop()
 		return
 	}
@@ -22,10 +28,16 @@ func showQuitConfirmation() {
 		return
 	}
 
-	confirmationModal := NewConfirmationModal("Exit LazySQL?")
+	message := "Exit LazySQL?"
+	if len(transactions) > 0 {
+		message = fmt.Sprintf("Open transactions on %s will be rolled back.\nExit LazySQL?", strings.Join(transactions, ", "))
+	}
+
+	confirmationModal := NewConfirmationModal(message)
 	confirmationModal.SetDoneFunc(func(_ int, buttonLabel string) {
 		mainPages.RemovePage(pageNameConfirmation)
 		if buttonLabel == confirmationYes {
+			rollbackOpenTransactions()
 			app.App.Stop()
 		}
 	})
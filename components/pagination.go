@@ -2,6 +2,7 @@ package components
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/rivo/tview"
@@ -128,3 +129,18 @@ func (pagination *Pagination) SetLoadingText(text string) {
 	pagination.textView.SetText(text).SetTextColor(app.Styles.SecondaryTextColor)
 	pagination.SetBorderColor(app.Styles.SecondaryTextColor)
 }
+
+// SetStreamedRows shows the number of rows loaded from a result that is read
+// as the user scrolls. more marks that the result has not been fully read.
+func (pagination *Pagination) SetStreamedRows(loaded int, more bool) {
+	pagination.state.Offset = 0
+	pagination.state.Limit = loaded
+	pagination.state.TotalRecords = loaded
+
+	total := strconv.Itoa(loaded)
+	if more {
+		total += "+"
+	}
+
+	pagination.textView.SetText(fmt.Sprintf("%d-%d of %s rows", min(loaded, 1), loaded, total))
+}
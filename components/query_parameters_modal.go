@@ -0,0 +1,133 @@
+package components
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/jorgerojas26/lazysql/app"
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+// QueryParametersModal asks for the value and type of every placeholder of a
+// query before it is run.
+type QueryParametersModal struct {
+	tview.Primitive
+	form  *tview.Form
+	names []string
+	onRun func(values map[string]any, parameters []models.QueryParameter)
+}
+
+// NewQueryParametersModal creates a new QueryParametersModal for the
+// parameters called names. The fields are prefilled from lastUsed.
+func NewQueryParametersModal(names []string, lastUsed []models.QueryParameter, onRun func(values map[string]any, parameters []models.QueryParameter)) *QueryParametersModal {
+	qpm := &QueryParametersModal{
+		names: names,
+		onRun: onRun,
+	}
+
+	qpm.form = tview.NewForm()
+
+	for _, name := range names {
+		value, typeIndex := "", 0
+		for _, parameter := range lastUsed {
+			if parameter.Name == name {
+				value = parameter.Value
+				typeIndex = max(slices.Index(parameterTypes, parameter.Type), 0)
+				break
+			}
+		}
+
+		qpm.form.AddInputField(name, value, 0, nil, nil)
+		qpm.form.AddDropDown("Type", parameterTypes, typeIndex, nil)
+	}
+
+	qpm.form.AddButton("Run", qpm.run).
+		AddButton("Cancel", qpm.cancel).
+		SetFieldStyle(
+			tcell.StyleDefault.
+				Background(app.Styles.SecondaryTextColor).
+				Foreground(app.Styles.ContrastSecondaryTextColor),
+		).SetButtonActivatedStyle(tcell.StyleDefault.
+		Background(app.Styles.SecondaryTextColor).
+		Foreground(app.Styles.ContrastSecondaryTextColor),
+	).SetButtonStyle(tcell.StyleDefault.
+		Background(app.Styles.InverseTextColor).
+		Foreground(app.Styles.ContrastSecondaryTextColor),
+	)
+
+	qpm.form.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEsc {
+			qpm.cancel()
+			return nil
+		}
+		return event
+	})
+
+	qpm.form.SetBorder(false)
+
+	hint := tview.NewTextView().
+		SetText("Esc to cancel").
+		SetTextAlign(tview.AlignCenter).
+		SetTextColor(app.Styles.TertiaryTextColor)
+
+	formWithHint := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(qpm.form, 0, 1, true).
+		AddItem(hint, 1, 0, false)
+	formWithHint.SetBorder(true).SetTitle(" Query Parameters ").SetTitleAlign(tview.AlignLeft)
+
+	// Every parameter takes an input and a dropdown row plus their padding,
+	// the rest is the buttons, the hint and the border.
+	height := min(len(names)*4+6, 40)
+
+	grid := tview.NewGrid().
+		SetRows(0, height, 0).
+		SetColumns(0, 70, 0).
+		AddItem(formWithHint, 1, 1, 1, 1, 0, 0, true)
+
+	qpm.Primitive = grid
+
+	return qpm
+}
+
+func (qpm *QueryParametersModal) run() {
+	values := make(map[string]any, len(qpm.names))
+	parameters := make([]models.QueryParameter, 0, len(qpm.names))
+
+	for i, name := range qpm.names {
+		text := qpm.form.GetFormItem(i * 2).(*tview.InputField).GetText()
+		_, parameterType := qpm.form.GetFormItem(i*2 + 1).(*tview.DropDown).GetCurrentOption()
+
+		value, err := parameterValue(parameterType, text)
+		if err != nil {
+			qpm.showErrorModal(fmt.Sprintf("Parameter %s: %s", name, err.Error()))
+			return
+		}
+
+		values[name] = value
+		parameters = append(parameters, models.QueryParameter{Name: name, Type: parameterType, Value: text})
+	}
+
+	mainPages.RemovePage(pageNameQueryParameters)
+
+	if qpm.onRun != nil {
+		qpm.onRun(values, parameters)
+	}
+}
+
+func (qpm *QueryParametersModal) showErrorModal(message string) {
+	modal := NewErrorModal(message)
+	modal.SetDoneFunc(func(_ int, _ string) {
+		mainPages.RemovePage(pageNameQueryParametersError)
+	})
+
+	mainPages.AddPage(pageNameQueryParametersError, modal, true, true)
+	App.SetFocus(modal)
+}
+
+func (qpm *QueryParametersModal) cancel() {
+	mainPages.RemovePage(pageNameQueryParameters)
+}
@@ -0,0 +1,114 @@
+package components
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/jorgerojas26/lazysql/app"
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+// expensivePlanShare is the share of the total cost above which a plan node
+// is highlighted as expensive.
+const expensivePlanShare = 0.3
+
+// QueryPlanViewer shows a query plan as a collapsible tree.
+type QueryPlanViewer struct {
+	*tview.TreeView
+}
+
+// NewQueryPlanViewer creates a new QueryPlanViewer. onClose is called when
+// the user leaves the viewer with Esc.
+func NewQueryPlanViewer(onClose func()) *QueryPlanViewer {
+	treeView := tview.NewTreeView()
+	treeView.SetBorder(true)
+	treeView.SetTitle(" Query Plan ")
+	treeView.SetTitleAlign(tview.AlignLeft)
+	treeView.SetBorderColor(app.Styles.PrimaryTextColor)
+	treeView.SetGraphicsColor(app.Styles.PrimaryTextColor)
+
+	treeView.SetSelectedFunc(func(node *tview.TreeNode) {
+		node.SetExpanded(!node.IsExpanded())
+	})
+
+	treeView.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape {
+			if onClose != nil {
+				onClose()
+			}
+			return nil
+		}
+		return event
+	})
+
+	return &QueryPlanViewer{TreeView: treeView}
+}
+
+// SetPlan replaces the shown plan with plan.
+func (v *QueryPlanViewer) SetPlan(plan *models.PlanNode) {
+	root := queryPlanTreeNode(plan, plan.Cost)
+	v.SetRoot(root)
+	v.SetCurrentNode(root)
+}
+
+func queryPlanTreeNode(node *models.PlanNode, totalCost float64) *tview.TreeNode {
+	treeNode := tview.NewTreeNode(queryPlanNodeLabel(node)).
+		SetReference(node).
+		SetExpanded(true)
+
+	if isExpensivePlanNode(node, totalCost) {
+		treeNode.SetColor(tcell.ColorRed)
+	} else {
+		treeNode.SetColor(app.Styles.PrimaryTextColor)
+	}
+
+	for _, child := range node.Children {
+		treeNode.AddChild(queryPlanTreeNode(child, totalCost))
+	}
+
+	return treeNode
+}
+
+// queryPlanNodeLabel returns e.g. "Seq Scan on users (cost=12.5 rows=100)".
+func queryPlanNodeLabel(node *models.PlanNode) string {
+	label := node.Type
+	if node.Detail != "" {
+		label += " " + node.Detail
+	}
+
+	estimates := []string{}
+	if node.Cost > 0 {
+		estimates = append(estimates, "cost="+strconv.FormatFloat(node.Cost, 'f', -1, 64))
+	}
+	if node.Rows > 0 {
+		estimates = append(estimates, "rows="+strconv.FormatFloat(node.Rows, 'f', -1, 64))
+	}
+	if len(estimates) > 0 {
+		label += fmt.Sprintf(" (%s)", strings.Join(estimates, " "))
+	}
+
+	return label
+}
+
+// isExpensivePlanNode reports whether node reads a whole table, or costs at
+// least expensivePlanShare of totalCost without counting its children. A
+// plan of a single step has nothing to compare its cost with.
+func isExpensivePlanNode(node *models.PlanNode, totalCost float64) bool {
+	if node.FullScan {
+		return true
+	}
+	if totalCost <= 0 || (len(node.Children) == 0 && node.Cost == totalCost) {
+		return false
+	}
+
+	ownCost := node.Cost
+	for _, child := range node.Children {
+		ownCost -= child.Cost
+	}
+
+	return ownCost >= totalCost*expensivePlanShare
+}
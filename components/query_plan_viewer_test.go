@@ -0,0 +1,50 @@
+package components
+
+import (
+	"testing"
+
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+func TestQueryPlanNodeLabel(t *testing.T) {
+	tests := []struct {
+		node *models.PlanNode
+		want string
+	}{
+		{&models.PlanNode{Type: "Seq Scan", Detail: "on users", Cost: 12.5, Rows: 100}, "Seq Scan on users (cost=12.5 rows=100)"},
+		{&models.PlanNode{Type: "Hash", Rows: 3}, "Hash (rows=3)"},
+		{&models.PlanNode{Type: "SCAN users"}, "SCAN users"},
+	}
+
+	for _, tt := range tests {
+		if got := queryPlanNodeLabel(tt.node); got != tt.want {
+			t.Errorf("expected %q, got %q", tt.want, got)
+		}
+	}
+}
+
+func TestIsExpensivePlanNode(t *testing.T) {
+	cheap := &models.PlanNode{Type: "Index Scan", Cost: 5}
+	costly := &models.PlanNode{Type: "Sort", Cost: 90, Children: []*models.PlanNode{cheap}}
+	root := &models.PlanNode{Type: "Limit", Cost: 100, Children: []*models.PlanNode{costly}}
+
+	tests := []struct {
+		name string
+		node *models.PlanNode
+		want bool
+	}{
+		{"full scan", &models.PlanNode{Type: "SCAN users", FullScan: true}, true},
+		{"own cost above share", costly, true},
+		{"own cost below share", root, false},
+		{"cheap leaf", cheap, false},
+		{"single step plan", &models.PlanNode{Type: "Index Scan", Cost: 100}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isExpensivePlanNode(tt.node, root.Cost); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
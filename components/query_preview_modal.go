@@ -3,6 +3,7 @@ package components
 import (
 	"fmt"
 	"slices"
+	"strings"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
@@ -17,13 +18,15 @@ import (
 
 type QueryPreviewModal struct {
 	tview.Primitive
-	Queries  *[]models.DBDMLChange
-	Table    *tview.Table
-	DBDriver drivers.Driver
-	Error    *tview.Modal
+	Queries *[]models.DBDMLChange
+	// DDLChanges are run before Queries, and listed before them.
+	DDLChanges *[]models.DBDDLChange
+	Table      *tview.Table
+	DBDriver   drivers.Driver
+	Error      *tview.Modal
 }
 
-func NewQueryPreviewModal(queries *[]models.DBDMLChange, dbdriver drivers.Driver, onFinish func()) *QueryPreviewModal {
+func NewQueryPreviewModal(queries *[]models.DBDMLChange, ddlChanges *[]models.DBDDLChange, dbdriver drivers.Driver, onFinish func()) *QueryPreviewModal {
 	modal := func(p tview.Primitive) tview.Primitive {
 		return tview.NewFlex().
 			AddItem(nil, 0, 1, false).
@@ -67,11 +70,12 @@ func NewQueryPreviewModal(queries *[]models.DBDMLChange, dbdriver drivers.Driver
 	container.AddItem(keybindings, 3, 1, false)
 
 	r := &QueryPreviewModal{
-		Primitive: modal(container),
-		Queries:   queries,
-		Table:     table,
-		DBDriver:  dbdriver,
-		Error:     errorModal,
+		Primitive:  modal(container),
+		Queries:    queries,
+		DDLChanges: ddlChanges,
+		Table:      table,
+		DBDriver:   dbdriver,
+		Error:      errorModal,
 	}
 
 	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
@@ -84,7 +88,7 @@ func NewQueryPreviewModal(queries *[]models.DBDMLChange, dbdriver drivers.Driver
 
 			confirmationModal.SetDoneFunc(func(_ int, buttonLabel string) {
 				if buttonLabel == "Yes" {
-					err := dbdriver.ExecutePendingChanges(*queries)
+					err := drivers.ExecuteStructureChanges(App.Context(), dbdriver, *ddlChanges, *queries)
 					if err != nil {
 						r.SetError(err.Error())
 						return
@@ -117,7 +121,12 @@ func NewQueryPreviewModal(queries *[]models.DBDMLChange, dbdriver drivers.Driver
 
 			confirmationModal.SetDoneFunc(func(_ int, buttonLabel string) {
 				if buttonLabel == "Yes" {
-					*queries = slices.Delete((*queries), row, row+1)
+					if row < len(*ddlChanges) {
+						*ddlChanges = slices.Delete((*ddlChanges), row, row+1)
+					} else {
+						row -= len(*ddlChanges)
+						*queries = slices.Delete((*queries), row, row+1)
+					}
 					table.Clear()
 					r.populateTable()
 				}
@@ -151,6 +160,19 @@ func (modal *QueryPreviewModal) SetError(err string) {
 func (modal *QueryPreviewModal) populateTable() {
 	modal.Table.Clear()
 
+	for i, change := range *modal.DDLChanges {
+		queries, err := drivers.DDLChangeQueries(modal.DBDriver, change)
+		if err != nil {
+			return
+		}
+
+		cell := tview.NewTableCell(tview.Escape(strings.Join(queries, " ")))
+		cell.SetExpansion(1)
+
+		modal.Table.SetCell(i, 0, cell)
+	}
+
+	offset := len(*modal.DDLChanges)
 	for i, query := range *modal.Queries {
 
 		queryStr, err := modal.DBDriver.DMLChangeToQueryString(query)
@@ -161,6 +183,6 @@ func (modal *QueryPreviewModal) populateTable() {
 		cell := tview.NewTableCell(tview.Escape(queryStr))
 		cell.SetExpansion(1)
 
-		modal.Table.SetCell(i, 0, cell)
+		modal.Table.SetCell(offset+i, 0, cell)
 	}
 }
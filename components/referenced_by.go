@@ -0,0 +1,201 @@
+package components
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/jorgerojas26/lazysql/app"
+	"github.com/jorgerojas26/lazysql/helpers/logger"
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+// referencingTable is a table with a foreign key referencing a row, and the
+// filter of its rows referencing it.
+type referencingTable struct {
+	key models.ReferencingForeignKey
+	// where is empty when the row has no value for the referenced columns.
+	where string
+	count string
+}
+
+// ReferencedByView lists the tables with a foreign key referencing the
+// table of a row, with the number of their rows referencing it, to open
+// them filtered to those rows.
+type ReferencedByView struct {
+	tview.Primitive
+	home     *Home
+	tables   *tview.Table
+	database string
+	rows     []referencingTable
+}
+
+// showReferencingTables opens a ReferencedByView for the row rowIndex of
+// the records of table.
+func (table *ResultsTable) showReferencingTables(rowIndex int) {
+	if table.Home == nil || table.Menu == nil || table.Menu.GetSelectedOption() != 1 || rowIndex <= 0 {
+		return
+	}
+	if isInsertedRow, _ := table.isAnInsertedRow(rowIndex); isInsertedRow {
+		return
+	}
+
+	values := make(map[string]string)
+	for i, column := range table.GetColumns() {
+		if i > 0 {
+			values[column[0]] = table.getRawCellValue(rowIndex, i-1)
+		}
+	}
+
+	v := &ReferencedByView{home: table.Home, database: table.GetDatabaseName()}
+
+	v.tables = tview.NewTable().SetSelectable(true, false).SetFixed(1, 0)
+	v.tables.SetBorder(true).SetTitle(" Referenced by " + table.GetTableName() + " ").SetTitleAlign(tview.AlignLeft)
+	v.tables.SetBorderColor(app.Styles.PrimaryTextColor)
+	v.tables.SetCell(0, 0, tview.NewTableCell("Loading...").SetTextColor(app.Styles.TertiaryTextColor).SetSelectable(false))
+	v.tables.SetSelectedFunc(func(row, _ int) { v.open(row) })
+	v.tables.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEsc || event.Rune() == 'q' {
+			v.close()
+			return nil
+		}
+		return event
+	})
+
+	hint := tview.NewTextView().
+		SetText("Enter to open the referencing rows, Esc to close").
+		SetTextAlign(tview.AlignCenter).
+		SetTextColor(app.Styles.TertiaryTextColor)
+
+	content := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(v.tables, 0, 1, true).
+		AddItem(hint, 1, 0, false)
+
+	v.Primitive = tview.NewGrid().
+		SetRows(0, 20, 0).
+		SetColumns(0, 100, 0).
+		AddItem(content, 1, 1, 1, 1, 0, 0, true)
+
+	mainPages.AddPage(pageNameReferencedBy, v, true, true)
+
+	go v.load(table, values)
+}
+
+// load reads the foreign keys referencing the table of table and counts the
+// rows of each referencing the row of values.
+func (v *ReferencedByView) load(table *ResultsTable, values map[string]string) {
+	db := table.DBDriver
+	ctx := App.Context()
+
+	keys, err := db.GetReferencingForeignKeys(ctx, v.database, table.GetTableName())
+	if err != nil {
+		logger.Error("Failed to get the referencing foreign keys", map[string]any{"error": err.Error(), "table": table.GetTableName()})
+		App.QueueUpdateDraw(func() {
+			v.setStatus(err.Error(), tcell.ColorRed)
+		})
+		return
+	}
+
+	rows := make([]referencingTable, len(keys))
+	for i, key := range keys {
+		rows[i] = referencingTable{key: key, count: "-"}
+
+		where, ok := referencingWhere(key, values, db.FormatReference)
+		if !ok {
+			continue
+		}
+		rows[i].where = where
+
+		_, count, _, err := db.GetRecords(ctx, v.database, key.Table, where, "", 0, 1)
+		if err != nil {
+			logger.Error("Failed to count the referencing rows", map[string]any{"error": err.Error(), "table": key.Table})
+			rows[i].count = "?"
+			continue
+		}
+		rows[i].count = strconv.Itoa(count)
+	}
+
+	App.QueueUpdateDraw(func() {
+		v.show(rows)
+	})
+}
+
+func (v *ReferencedByView) setStatus(text string, color tcell.Color) {
+	v.tables.Clear()
+	v.tables.SetCell(0, 0, tview.NewTableCell(text).SetTextColor(color).SetSelectable(false))
+}
+
+func (v *ReferencedByView) show(rows []referencingTable) {
+	v.rows = rows
+	if len(rows) == 0 {
+		v.setStatus("No foreign key references this table", app.Styles.TertiaryTextColor)
+		return
+	}
+
+	v.tables.Clear()
+	for j, header := range []string{"Table", "Foreign key", "Rows"} {
+		v.tables.SetCell(0, j, tview.NewTableCell(header).
+			SetTextColor(app.Styles.PrimaryTextColor).
+			SetAttributes(tcell.AttrBold).
+			SetSelectable(false))
+	}
+
+	for i, row := range rows {
+		columns := make([]string, len(row.key.Columns))
+		for j, column := range row.key.Columns {
+			referencedColumn := ""
+			if j < len(row.key.ReferencedColumns) {
+				referencedColumn = row.key.ReferencedColumns[j]
+			}
+			columns[j] = column + " -> " + referencedColumn
+		}
+
+		color := app.Styles.PrimaryTextColor
+		if row.where == "" {
+			color = app.Styles.InverseTextColor
+		}
+		v.tables.SetCell(i+1, 0, tview.NewTableCell(row.key.Table).SetTextColor(color))
+		v.tables.SetCell(i+1, 1, tview.NewTableCell(strings.Join(columns, ", ")).SetTextColor(color).SetExpansion(1))
+		v.tables.SetCell(i+1, 2, tview.NewTableCell(row.count).SetTextColor(color).SetAlign(tview.AlignRight))
+	}
+	v.tables.Select(1, 0)
+}
+
+// open opens the table of the row-th referencing table, filtered to the
+// rows referencing the row.
+func (v *ReferencedByView) open(row int) {
+	if row <= 0 || row > len(v.rows) || v.rows[row-1].where == "" {
+		return
+	}
+
+	v.close()
+	v.home.ShowTableWithFilter(v.database, v.rows[row-1].key.Table, v.rows[row-1].where)
+}
+
+func (v *ReferencedByView) close() {
+	mainPages.RemovePage(pageNameReferencedBy)
+}
+
+// referencingWhere returns the filter of the rows of the table of key that
+// reference the row of values, by column name. It returns false when the
+// row has no value for one of the referenced columns.
+func referencingWhere(key models.ReferencingForeignKey, values map[string]string, reference func(string) string) (string, bool) {
+	if len(key.Columns) == 0 || len(key.Columns) != len(key.ReferencedColumns) {
+		return "", false
+	}
+
+	conditions := make([]string, len(key.Columns))
+	for i, column := range key.Columns {
+		value, ok := values[key.ReferencedColumns[i]]
+		if !ok || !isNavigableForeignKeyValue(value) {
+			return "", false
+		}
+		conditions[i] = fmt.Sprintf("%s = '%s'", reference(column), escapeSingleQuotes(value))
+	}
+
+	return "WHERE " + strings.Join(conditions, " AND "), true
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/jorgerojas26/lazysql/helpers"
 	"github.com/jorgerojas26/lazysql/helpers/logger"
 	"github.com/jorgerojas26/lazysql/internal/history"
+	"github.com/jorgerojas26/lazysql/internal/saved"
 	"github.com/jorgerojas26/lazysql/lib"
 	"github.com/jorgerojas26/lazysql/models"
 )
@@ -35,7 +36,7 @@ type ResultsTableState struct {
 	constraints           [][]string
 	foreignKeys           [][]string
 	indexes               [][]string
-	records               [][]string
+	records               []models.Record
 	foreignKeyColumns     map[string]bool
 	foreignKeyJumpTargets map[string]foreignKeyJumpTarget
 	fkRawCellValues       map[string]string
@@ -45,6 +46,10 @@ type ResultsTableState struct {
 	isLoading             bool
 	showSidebar           bool
 	loadingCancel         context.CancelFunc
+	resultStream          *resultStream
+	// stagedStructureRows maps the rows added for staged DDL changes to
+	// their index in the pending DDL changes.
+	stagedStructureRows map[int]int
 }
 
 type foreignKeyJumpTarget struct {
@@ -61,10 +66,12 @@ type ResultsTable struct {
 	Filter               *ResultsTableFilter
 	Error                *tview.Modal
 	jsonViewer           *JSONViewer
+	planViewer           *QueryPlanViewer
 	Pagination           *Pagination
 	Editor               *SQLEditor
 	EditorPages          *tview.Pages
 	ResultsInfo          *tview.TextView
+	resultsInfoWrapper   *tview.Flex
 	Tree                 *Tree
 	Sidebar              *Sidebar
 	SidebarContainer     *tview.Flex
@@ -77,7 +84,7 @@ type ResultsTable struct {
 
 func NewResultsTable(listOfDBChanges *[]models.DBDMLChange, tree *Tree, dbdriver drivers.Driver, home *Home, connectionIdentifier string, connectionURL string, readOnly bool) *ResultsTable {
 	state := &ResultsTableState{
-		records:               [][]string{},
+		records:               []models.Record{},
 		columns:               [][]string{},
 		constraints:           [][]string{},
 		foreignKeys:           [][]string{},
@@ -142,10 +149,12 @@ func NewResultsTable(listOfDBChanges *[]models.DBDMLChange, tree *Tree, dbdriver
 	table.SetInputCapture(table.tableInputCapture)
 	table.SetSelectedStyle(tcell.StyleDefault.Background(app.Styles.SecondaryTextColor).Foreground(tview.Styles.ContrastSecondaryTextColor))
 
-	table.SetSelectionChangedFunc(func(_, _ int) {
+	table.SetSelectionChangedFunc(func(row, _ int) {
 		if table.GetShowSidebar() {
 			go table.UpdateSidebar()
 		}
+
+		table.loadMoreRows(row)
 	})
 
 	go table.subscribeToTreeChanges()
@@ -201,6 +210,10 @@ func (table *ResultsTable) WithEditor() *ResultsTable {
 		table.SetIsEditing(false)
 	})
 
+	if table.DBDriver != nil {
+		editor.transactionOpen = table.DBDriver.InTransaction
+	}
+
 	table.Editor = editor
 
 	table.Wrapper.Clear()
@@ -222,8 +235,12 @@ func (table *ResultsTable) WithEditor() *ResultsTable {
 	editorPages.AddPage(pageNameTableEditorTable, tableWrapper, true, false)
 	editorPages.AddPage(pageNameTableEditorResultsInfo, resultsInfoWrapper, true, true)
 
+	table.planViewer = NewQueryPlanViewer(table.search)
+	editorPages.AddPage(pageNameTableEditorPlan, table.planViewer, true, false)
+
 	table.EditorPages = editorPages
 	table.ResultsInfo = resultsInfoText
+	table.resultsInfoWrapper = resultsInfoWrapper
 
 	table.Wrapper.AddItem(editorPages, 0, 1, true)
 
@@ -240,7 +257,7 @@ func (table *ResultsTable) loadEditorSchema() {
 		return
 	}
 
-	tablesMap, err := table.DBDriver.GetTables(dbName)
+	tablesMap, err := table.DBDriver.GetTables(app.App.Context(), dbName)
 	if err != nil {
 		logger.Error("Failed to load tables for editor autocomplete", map[string]any{"error": err.Error()})
 		return
@@ -280,7 +297,7 @@ func (table *ResultsTable) loadEditorSchema() {
 	// Load columns for each table, using the qualified name for the driver call
 	// but storing under the bare table name for autocomplete lookup ("table.col").
 	for _, nt := range tableList {
-		cols, err := table.DBDriver.GetTableColumns(dbName, nt.qualifiedName)
+		cols, err := table.DBDriver.GetTableColumns(app.App.Context(), dbName, nt.qualifiedName)
 		if err != nil {
 			_ = err
 			continue
@@ -371,26 +388,53 @@ func (table *ResultsTable) subscribeToSidebarChanges() {
 }
 
 func (table *ResultsTable) AddRows(rows [][]string) {
-	for i, row := range rows {
+	table.addRowsAt(0, rows)
+}
+
+// appendRecords adds records after the loaded records.
+func (table *ResultsTable) appendRecords(records []models.Record) {
+	start := len(table.state.records)
+	table.state.records = append(table.state.records, records...)
+	table.addRecordsAt(start, records)
+}
+
+// addRowsAt sets the cells of rows starting at table row start.
+func (table *ResultsTable) addRowsAt(start int, rows [][]string) {
+	for k, row := range rows {
+		i := start + k
 		for j, cell := range row {
-			tableCell := tview.NewTableCell(cell)
-			tableCell.SetTextColor(app.Styles.PrimaryTextColor)
+			table.SetCell(i, j, table.newCell(i, j, cell))
+		}
+	}
+}
 
-			if cell == "EMPTY&" || cell == "NULL&" || cell == "DEFAULT&" {
-				tableCell.SetText(strings.Replace(cell, "&", "", 1))
-				tableCell.SetStyle(table.GetItalicStyle())
-				tableCell.SetReference(cell)
-			}
+// addRecordsAt sets the cells of records starting at table row start. NULL
+// and empty values are shown as italic markers, and numbers are aligned to
+// the right.
+func (table *ResultsTable) addRecordsAt(start int, records []models.Record) {
+	for k, record := range records {
+		i := start + k
+		for j, value := range record {
+			tableCell := table.newCell(i, j, value.String())
 
-			tableCell.SetSelectable(i > 0)
-			tableCell.SetExpansion(1)
+			if i > 0 {
+				marker := ""
+				switch {
+				case value.Null:
+					marker = "NULL&"
+				case value.IsEmpty():
+					marker = "EMPTY&"
+				}
 
-			if i == 0 && table.shouldShowForeignKeyHeaderMarker(j) {
-				tableCell.SetStyle(tcell.StyleDefault.Underline(true))
+				if marker != "" {
+					tableCell.SetText(strings.TrimSuffix(marker, "&"))
+					tableCell.SetStyle(table.GetItalicStyle())
+					tableCell.SetReference(marker)
+				}
 			}
 
-			if i > 0 && table.shouldShowForeignKeyMarker(i, j, cell) {
-				tableCell.SetStyle(tcell.StyleDefault.Underline(true))
+			if value.IsNumeric() {
+				tableCell.SetAlign(tview.AlignRight)
 			}
 
 			table.SetCell(i, j, tableCell)
@@ -398,6 +442,24 @@ func (table *ResultsTable) AddRows(rows [][]string) {
 	}
 }
 
+// newCell returns the cell of row i and column j showing text.
+func (table *ResultsTable) newCell(i, j int, text string) *tview.TableCell {
+	tableCell := tview.NewTableCell(text)
+	tableCell.SetTextColor(app.Styles.PrimaryTextColor)
+	tableCell.SetSelectable(i > 0)
+	tableCell.SetExpansion(1)
+
+	if i == 0 && table.shouldShowForeignKeyHeaderMarker(j) {
+		tableCell.SetStyle(tcell.StyleDefault.Underline(true))
+	}
+
+	if i > 0 && table.shouldShowForeignKeyMarker(i, j, text) {
+		tableCell.SetStyle(tcell.StyleDefault.Underline(true))
+	}
+
+	return tableCell
+}
+
 func (table *ResultsTable) AddInsertedRows() {
 	inserts := make([]models.DBDMLChange, 0)
 
@@ -435,6 +497,15 @@ func (table *ResultsTable) AddInsertedRows() {
 }
 
 func (table *ResultsTable) AppendNewRow(cells []models.CellValue, index int, UUID string) {
+	table.setInsertedRow(cells, index, UUID)
+
+	table.Select(index, 0)
+	App.ForceDraw()
+}
+
+// setInsertedRow draws cells as the row at index of an insert that is not
+// saved yet.
+func (table *ResultsTable) setInsertedRow(cells []models.CellValue, index int, UUID string) {
 	for i, cell := range cells {
 		tableCell := tview.NewTableCell(cell.Value.(string))
 		tableCell.SetExpansion(1)
@@ -458,9 +529,6 @@ func (table *ResultsTable) AppendNewRow(cells []models.CellValue, index int, UUI
 		tableCell.SetBackgroundColor(colorTableInsert)
 		table.SetCell(index, i, tableCell)
 	}
-
-	table.Select(index, 0)
-	App.ForceDraw()
 }
 
 func (table *ResultsTable) tableInputCapture(event *tcell.EventKey) *tcell.EventKey {
@@ -482,28 +550,35 @@ func (table *ResultsTable) tableInputCapture(event *tcell.EventKey) *tcell.Event
 		switch command {
 		case commands.RecordsMenu:
 			table.Menu.SetSelectedOption(1)
-			table.UpdateRows(table.GetRecords())
+			table.UpdateRecords(table.GetRecords())
 			table.colorChangedCells()
 			table.AddInsertedRows()
 			table.UpdateRowsColor(app.Styles.PrimaryTextColor, tview.Styles.PrimaryTextColor)
 		case commands.ColumnsMenu:
 			table.Menu.SetSelectedOption(2)
 			table.UpdateRows(table.GetColumns())
+			table.colorStagedStructure()
 		case commands.ConstraintsMenu:
 			table.Menu.SetSelectedOption(3)
 			table.UpdateRows(table.GetConstraints())
+			table.colorStagedStructure()
 		case commands.ForeignKeysMenu:
 			table.Menu.SetSelectedOption(4)
 			table.UpdateRows(table.GetForeignKeys())
 		case commands.IndexesMenu:
 			table.Menu.SetSelectedOption(5)
 			table.UpdateRows(table.GetIndexes())
+			table.colorStagedStructure()
 		case commands.Refresh:
 			table.Menu.SetSelectedOption(1)
 			table.FetchRecords(nil, nil)
 		}
 	}
 
+	if table.structureCommand(command) {
+		return nil
+	}
+
 	switch command {
 	case commands.AppendNewRow:
 		if table.ReadOnly {
@@ -521,6 +596,28 @@ func (table *ResultsTable) tableInputCapture(event *tcell.EventKey) *tcell.Event
 		if table.Menu.GetSelectedOption() == 1 {
 			table.duplicateRow()
 		}
+	case commands.ImportData:
+		if table.ReadOnly {
+			table.SetError("Cannot modify data: Connection is in read-only mode", nil)
+			return nil
+		}
+		if table.Menu != nil && table.Menu.GetSelectedOption() == 1 {
+			table.showImportModal()
+		}
+	case commands.CompareData:
+		if table.Menu != nil && table.Home != nil {
+			mainPages.AddPage(pageNameDataDiff, NewDataDiffView(table.Home, table.GetTableName()), true, true)
+		}
+	case commands.ShowDefinition:
+		if table.Menu != nil && table.Home != nil {
+			database, name := table.GetDatabaseName(), table.GetTableName()
+			table.Home.showDefinition(name, func(ctx context.Context) (string, error) {
+				return table.DBDriver.GetTableDefinition(ctx, database, name)
+			})
+		}
+	case commands.ShowReferencingTables:
+		selectedRowIndex, _ := table.GetSelection()
+		table.showReferencingTables(selectedRowIndex)
 	case commands.Search:
 		table.search()
 	}
@@ -699,7 +796,7 @@ func (table *ResultsTable) tableInputCapture(event *tcell.EventKey) *tcell.Event
 		}
 		return nil
 	} else if command == commands.ExportCSV {
-		table.showCSVExportModal()
+		table.showExportModal()
 		return nil
 	}
 
@@ -728,6 +825,16 @@ func (table *ResultsTable) UpdateRows(rows [][]string) {
 	table.Select(1, 0)
 }
 
+// UpdateRecords replaces the shown rows with records.
+func (table *ResultsTable) UpdateRecords(records []models.Record) {
+	table.state.fkRawCellValues = map[string]string{}
+	table.clearRowMarks()
+	table.Clear()
+	table.addRecordsAt(0, records)
+	App.ForceDraw()
+	table.Select(1, 0)
+}
+
 func (table *ResultsTable) UpdateRowsColor(headerColor tcell.Color, rowColor tcell.Color) {
 	for i := 0; i < table.GetRowCount(); i++ {
 		for j := 0; j < table.GetColumnCount(); j++ {
@@ -743,7 +850,7 @@ func (table *ResultsTable) UpdateRowsColor(headerColor tcell.Color, rowColor tce
 			} else {
 				cellReference := cell.GetReference()
 
-				if cellReference != nil && (cellReference == "EMPTY&" || cellReference == "NULL&" || cellReference == "DEFAULT&") && (cell.BackgroundColor != colorTableDelete && cell.BackgroundColor != colorTableChange && cell.BackgroundColor != colorTableInsert) {
+				if isCellMarker(cellReference) && (cell.BackgroundColor != colorTableDelete && cell.BackgroundColor != colorTableChange && cell.BackgroundColor != colorTableInsert) {
 					cell.SetStyle(table.GetItalicStyle())
 				} else if table.shouldShowForeignKeyMarker(i, j, cell.Text) {
 					cell.SetStyle(tcell.StyleDefault.Underline(true))
@@ -840,6 +947,16 @@ func (table *ResultsTable) subscribeToFilterChanges() {
 	}
 }
 
+// WithQueryResult lays the table out without filter or editor. It is used for
+// the result tabs of statements run as part of an editor script.
+func (table *ResultsTable) WithQueryResult() *ResultsTable {
+	table.SetBorder(true)
+	table.Wrapper.AddItem(table, 0, 1, true)
+	table.Wrapper.AddItem(table.Pagination, 3, 0, false)
+
+	return table
+}
+
 func (table *ResultsTable) subscribeToEditorChanges() {
 	ch := table.Editor.Subscribe()
 
@@ -847,126 +964,223 @@ func (table *ResultsTable) subscribeToEditorChanges() {
 		switch stateChange.Key {
 		case eventSQLEditorQuery:
 			query := stateChange.Value.(string)
-			if query != "" {
-				queryLower := strings.ToLower(query)
-				queryTrimmed := strings.TrimSpace(queryLower)
-
-				isSelect := strings.HasPrefix(queryTrimmed, "select") ||
-					strings.HasPrefix(queryTrimmed, "with") ||
-					strings.HasPrefix(queryTrimmed, "explain") ||
-					strings.HasPrefix(queryTrimmed, "show") ||
-					strings.HasPrefix(queryTrimmed, "describe") ||
-					strings.HasPrefix(queryTrimmed, "desc")
-
-				// Clear existing records immediately for SQL editor queries and
-				// start a cancellable loading cycle on the UI goroutine.
-				var ctx context.Context
+			if query == "" {
+				continue
+			}
+
+			if parameters := findParameters(query, table.DBDriver.GetProvider()); len(parameters) > 0 {
 				App.QueueUpdateDraw(func() {
-					table.SetRecords([][]string{})
-					ctx = table.StartLoad()
+					table.promptQueryParameters(query, parameters, func(values map[string]any) {
+						go table.executeEditorQuery(query, parameters, values)
+					})
 				})
+				continue
+			}
 
-				if isSelect {
-					go func() {
-						if ctx.Err() != nil {
-							return
-						}
+			table.executeEditorQuery(query, nil, nil)
+		case eventSQLEditorExplain:
+			query := stateChange.Value.(string)
 
-						rows, records, err := table.DBDriver.ExecuteQuery(query)
+			if parameters := findParameters(query, table.DBDriver.GetProvider()); len(parameters) > 0 {
+				App.QueueUpdateDraw(func() {
+					table.promptQueryParameters(query, parameters, func(values map[string]any) {
+						go table.explainEditorQuery(query, parameters, values)
+					})
+				})
+				continue
+			}
 
-						if ctx.Err() != nil {
-							return
-						}
+			table.explainEditorQuery(query, nil, nil)
+		case eventSQLEditorEscape:
+			App.QueueUpdateDraw(func() {
+				table.SetIsFiltering(false)
+				App.SetFocus(table)
+				table.HighlightTable()
+				table.Editor.SetBlur()
+				table.SetInputCapture(table.tableInputCapture)
+			})
+		}
+	}
+}
 
-						App.QueueUpdateDraw(func() {
-							if ctx.Err() != nil {
-								return
-							}
-
-							if err != nil {
-								table.SetLoading(false)
-								table.SetError(err.Error(), nil)
-								return
-							}
-
-							table.Pagination.SetTotalRecords(records)
-							table.Pagination.SetLimit(records)
-							table.SetRecords(rows)
-							table.SetLoading(false)
-							closeQuitConfirmation()
-							table.SetIsFiltering(false)
-							table.HighlightTable()
-							table.Editor.SetBlur()
-							table.SetInputCapture(table.tableInputCapture)
-							table.EditorPages.SwitchToPage(pageNameTableEditorTable)
-							App.SetFocus(table)
-
-							if err := history.AddQueryToHistory(table.connectionIdentifier, query); err != nil {
-								logger.Error("Failed to add SELECT query to history", map[string]any{"error": err, "query": query, "connection": table.connectionIdentifier})
-							}
-						})
-					}()
-				} else {
-					if table.ReadOnly {
-						if err := drivers.ValidateQueryForReadOnly(query); err != nil {
-							App.QueueUpdateDraw(func() {
-								table.SetError("Cannot execute mutation query: Connection is in read-only mode", nil)
-								table.SetLoading(false)
-							})
-							continue
-						}
-					}
+// executeEditorQuery runs query from the SQL editor. The placeholders listed
+// in parameters are bound to values through the driver.
+func (table *ResultsTable) executeEditorQuery(query string, parameters []sqlParameter, values map[string]any) {
+	isSelect := returnsRows(query)
 
-					go func() {
-						if ctx.Err() != nil {
-							return
-						}
+	// Clear existing records immediately for SQL editor queries and
+	// start a cancellable loading cycle on the UI goroutine.
+	var ctx context.Context
+	App.QueueUpdateDraw(func() {
+		table.setResultStream(nil)
+		table.SetRecords([]models.Record{})
+		ctx = table.StartLoad()
+	})
 
-						result, err := table.DBDriver.ExecuteDMLStatement(query)
+	if statements := splitStatements(query); len(statements) > 1 {
+		go table.executeScript(ctx, statements, parameters, values)
+	} else if isSelect {
+		go func() {
+			if ctx.Err() != nil {
+				return
+			}
 
-						if ctx.Err() != nil {
-							return
-						}
+			boundQuery, args := bindParameters(query, parameters, values, table.DBDriver.FormatPlaceholder)
+			stream, rows, err := table.openResultStream(ctx, boundQuery, args)
 
-						App.QueueUpdateDraw(func() {
-							if ctx.Err() != nil {
-								return
-							}
-
-							if err != nil {
-								table.SetLoading(false)
-								table.SetError(err.Error(), nil)
-								return
-							}
-
-							table.SetResultsInfo(result)
-							table.SetLoading(false)
-							closeQuitConfirmation()
-							table.EditorPages.SwitchToPage(pageNameTableEditorResultsInfo)
-							App.SetFocus(table.Editor)
-
-							if err := history.AddQueryToHistory(table.connectionIdentifier, query); err != nil {
-								logger.Error("Failed to add DML query to history", map[string]any{"error": err, "query": query, "connection": table.connectionIdentifier})
-							}
-						})
-					}()
+			if ctx.Err() != nil {
+				if stream != nil {
+					stream.close()
 				}
+				return
 			}
-		case eventSQLEditorEscape:
+
 			App.QueueUpdateDraw(func() {
+				if ctx.Err() != nil {
+					if stream != nil {
+						stream.close()
+					}
+					return
+				}
+
+				if err != nil {
+					table.SetLoading(false)
+					table.SetError(err.Error(), nil)
+					return
+				}
+
+				table.SetRecords(rows)
+				table.SetLoading(false)
+				table.setResultStream(stream)
+				closeQuitConfirmation()
 				table.SetIsFiltering(false)
-				App.SetFocus(table)
 				table.HighlightTable()
 				table.Editor.SetBlur()
 				table.SetInputCapture(table.tableInputCapture)
+				table.EditorPages.SwitchToPage(pageNameTableEditorTable)
+				App.SetFocus(table)
+
+				if err := history.AddQueryToHistory(table.connectionIdentifier, query); err != nil {
+					logger.Error("Failed to add SELECT query to history", map[string]any{"error": err, "query": query, "connection": table.connectionIdentifier})
+				}
+			})
+		}()
+	} else {
+		if table.ReadOnly {
+			if err := drivers.ValidateQueryForReadOnly(query); err != nil {
+				App.QueueUpdateDraw(func() {
+					table.SetError("Cannot execute mutation query: Connection is in read-only mode", nil)
+					table.SetLoading(false)
+				})
+				return
+			}
+		}
+
+		go func() {
+			if ctx.Err() != nil {
+				return
+			}
+
+			boundQuery, args := bindParameters(query, parameters, values, table.DBDriver.FormatPlaceholder)
+			result, err := table.DBDriver.ExecuteDMLStatement(ctx, boundQuery, args...)
+
+			if ctx.Err() != nil {
+				return
+			}
+
+			App.QueueUpdateDraw(func() {
+				if ctx.Err() != nil {
+					return
+				}
+
+				if err != nil {
+					table.SetLoading(false)
+					table.SetError(err.Error(), nil)
+					return
+				}
+
+				table.SetResultsInfo(result)
+				table.SetLoading(false)
+				closeQuitConfirmation()
+				table.EditorPages.SwitchToPage(pageNameTableEditorResultsInfo)
+				App.SetFocus(table.Editor)
+
+				if table.Home != nil && changesSchema(query) {
+					table.Home.refreshTree()
+				}
+
+				if err := history.AddQueryToHistory(table.connectionIdentifier, query); err != nil {
+					logger.Error("Failed to add DML query to history", map[string]any{"error": err, "query": query, "connection": table.connectionIdentifier})
+				}
 			})
+		}()
+	}
+}
+
+// explainEditorQuery shows the plan of query from the SQL editor below the
+// editor.
+func (table *ResultsTable) explainEditorQuery(query string, parameters []sqlParameter, values map[string]any) {
+	var ctx context.Context
+	App.QueueUpdateDraw(func() {
+		ctx = table.StartLoad()
+	})
+
+	go func() {
+		if ctx.Err() != nil {
+			return
 		}
+
+		boundQuery, args := bindParameters(query, parameters, values, table.DBDriver.FormatPlaceholder)
+		plan, err := table.DBDriver.ExplainQuery(ctx, boundQuery, args...)
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		App.QueueUpdateDraw(func() {
+			if ctx.Err() != nil {
+				return
+			}
+
+			table.SetLoading(false)
+
+			if err != nil {
+				table.SetError(err.Error(), nil)
+				return
+			}
+
+			table.planViewer.SetPlan(plan)
+			table.SetIsFiltering(false)
+			table.Editor.SetBlur()
+			table.EditorPages.SwitchToPage(pageNameTableEditorPlan)
+			App.SetFocus(table.planViewer)
+		})
+	}()
+}
+
+// promptQueryParameters asks for the values of the placeholders of query and
+// passes them to run. The values are remembered if query is a saved query.
+func (table *ResultsTable) promptQueryParameters(query string, parameters []sqlParameter, run func(values map[string]any)) {
+	lastUsed, err := saved.GetQueryParameters(table.connectionIdentifier, query)
+	if err != nil {
+		logger.Error("Failed to read saved query parameters", map[string]any{"error": err, "connection": table.connectionIdentifier})
 	}
+
+	modal := NewQueryParametersModal(parameterNames(parameters), lastUsed, func(values map[string]any, used []models.QueryParameter) {
+		if err := saved.SaveQueryParameters(table.connectionIdentifier, query, used); err != nil {
+			logger.Error("Failed to save query parameters", map[string]any{"error": err, "connection": table.connectionIdentifier})
+		}
+
+		run(values)
+	})
+
+	mainPages.AddPage(pageNameQueryParameters, modal, true, true)
+	App.SetFocus(modal)
 }
 
 // Getters
 
-func (table *ResultsTable) GetRecords() [][]string {
+func (table *ResultsTable) GetRecords() []models.Record {
 	return table.state.records
 }
 
@@ -1061,9 +1275,9 @@ func (table *ResultsTable) GetPrimaryKeySort() string {
 
 // Setters
 
-func (table *ResultsTable) SetRecords(rows [][]string) {
-	table.state.records = rows
-	table.UpdateRows(rows)
+func (table *ResultsTable) SetRecords(records []models.Record) {
+	table.state.records = records
+	table.UpdateRecords(records)
 	table.colorChangedCells()
 }
 
@@ -1120,6 +1334,8 @@ func (table *ResultsTable) SetError(err string, done func()) {
 
 func (table *ResultsTable) SetResultsInfo(text string) {
 	table.ResultsInfo.SetText(text)
+	// Grow the box so a multi-line script summary is fully visible.
+	table.resultsInfoWrapper.ResizeItem(table.ResultsInfo, strings.Count(text, "\n")+3, 0)
 }
 
 func (table *ResultsTable) SetLoading(show bool) {
@@ -1170,7 +1386,7 @@ func (table *ResultsTable) SetSortedBy(column string, direction string) {
 				where = table.Filter.GetCurrentFilter()
 			}
 
-			records, _, _, err := table.DBDriver.GetRecords(table.GetDatabaseName(), table.GetTableName(), where, sort, table.Pagination.GetOffset(), table.Pagination.GetLimit())
+			records, _, _, err := table.DBDriver.GetRecords(ctx, table.GetDatabaseName(), table.GetTableName(), where, sort, table.Pagination.GetOffset(), table.Pagination.GetLimit())
 
 			if ctx.Err() != nil {
 				return
@@ -1243,7 +1459,7 @@ func (table *ResultsTable) FetchRecords(onError func(), onSuccess func()) {
 		}
 		sort := table.GetCurrentSort()
 
-		records, totalRecords, executedQuery, err := table.DBDriver.GetRecords(databaseName, tableName, where, sort, table.Pagination.GetOffset(), table.Pagination.GetLimit())
+		records, totalRecords, executedQuery, err := table.DBDriver.GetRecords(ctx, databaseName, tableName, where, sort, table.Pagination.GetOffset(), table.Pagination.GetLimit())
 
 		if ctx.Err() != nil {
 			return
@@ -1253,11 +1469,11 @@ func (table *ResultsTable) FetchRecords(onError func(), onSuccess func()) {
 			var columns, constraints, foreignKeys, indexes [][]string
 			var primaryKeyColumnNames []string
 
-			columns, _ = table.DBDriver.GetTableColumns(databaseName, tableName)
-			constraints, _ = table.DBDriver.GetConstraints(databaseName, tableName)
-			foreignKeys, _ = table.DBDriver.GetForeignKeys(databaseName, tableName)
-			indexes, _ = table.DBDriver.GetIndexes(databaseName, tableName)
-			primaryKeyColumnNames, _ = table.DBDriver.GetPrimaryKeyColumnNames(databaseName, tableName)
+			columns, _ = table.DBDriver.GetTableColumns(ctx, databaseName, tableName)
+			constraints, _ = table.DBDriver.GetConstraints(ctx, databaseName, tableName)
+			foreignKeys, _ = table.DBDriver.GetForeignKeys(ctx, databaseName, tableName)
+			indexes, _ = table.DBDriver.GetIndexes(ctx, databaseName, tableName)
+			primaryKeyColumnNames, _ = table.DBDriver.GetPrimaryKeyColumnNames(ctx, databaseName, tableName)
 
 			if ctx.Err() != nil {
 				return
@@ -1513,7 +1729,7 @@ func (table *ResultsTable) AppendNewChange(changeType models.DMLType, rowIndex i
 				originalValue := table.GetRecords()[rowIndex][colIndex]
 
 				if changeForColExists {
-					if originalValue == value.Value {
+					if isOriginalValue(originalValue, value) {
 						if len((*table.state.listOfDBChanges)[i].Values) == 1 {
 							*table.state.listOfDBChanges = append((*table.state.listOfDBChanges)[:i], (*table.state.listOfDBChanges)[i+1:]...)
 						} else {
@@ -1558,6 +1774,20 @@ func (table *ResultsTable) AppendNewChange(changeType models.DMLType, rowIndex i
 	return nil
 }
 
+// isOriginalValue reports whether value sets a cell back to original, the
+// value it was loaded with.
+func isOriginalValue(original models.Value, value models.CellValue) bool {
+	switch value.Type {
+	case models.Null:
+		return original.Null
+	case models.Empty:
+		return original.IsEmpty()
+	case models.String:
+		return !original.Null && original.String() == value.Value
+	}
+	return false
+}
+
 func (table *ResultsTable) GetPrimaryKeyValue(rowIndex int) []models.PrimaryKeyInfo {
 	primaryKeyColumnNames := table.GetPrimaryKeyColumnNames()
 
@@ -1578,7 +1808,7 @@ func (table *ResultsTable) GetPrimaryKeyValue(rowIndex int) []models.PrimaryKeyI
 			if i >= len(row) {
 				break
 			}
-			info = append(info, models.PrimaryKeyInfo{Name: colName, Value: row[i]})
+			info = append(info, models.PrimaryKeyInfo{Name: colName.String(), Value: row[i].String()})
 		}
 		return info
 	}
@@ -1593,7 +1823,7 @@ func (table *ResultsTable) GetPrimaryKeyValue(rowIndex int) []models.PrimaryKeyI
 		if columnIndex < 0 || columnIndex >= len(row) {
 			continue
 		}
-		primaryKeyValue := row[columnIndex]
+		primaryKeyValue := row[columnIndex].String()
 
 		info = append(info, models.PrimaryKeyInfo{Name: primaryKeyColumnName, Value: primaryKeyValue})
 	}
@@ -2144,7 +2374,15 @@ func (table *ResultsTable) UpdateSidebar() {
 
 			sidebarWidth := table.getSidebarWidth()
 
-			text := table.GetCell(selectedRow, i-1).Text
+			cell := table.GetCell(selectedRow, i-1)
+			text := cell.Text
+			// NULL, empty and default values are shown as a marker, so
+			// they can't be mistaken for text.
+			marker := ""
+			if isCellMarker(cell.GetReference()) {
+				marker = text
+				text = ""
+			}
 			title := name
 
 			repeatCount := sidebarWidth - len(name) - len(colType) - 4 // idk why 4 is needed, but it works.
@@ -2169,7 +2407,7 @@ func (table *ResultsTable) UpdateSidebar() {
 				}
 			}
 
-			table.Sidebar.AddField(title, text, sidebarWidth, pendingEditExist)
+			table.Sidebar.AddField(title, text, marker, sidebarWidth, pendingEditExist)
 		}
 
 	}
@@ -2207,12 +2445,7 @@ func (table *ResultsTable) isAnInsertedRow(rowIndex int) (isAnInsertedRow bool,
 				continue
 			}
 			cellReference := table.GetCell(rowIndex, 0).GetReference()
-			if cellReference == nil {
-				break
-			}
-			switch cellReference.(string) {
-			case "NULL&", "EMPTY&", "DEFAULT&":
-			default:
+			if cellReference != nil && !isCellMarker(cellReference) {
 				return true, i
 			}
 			break
@@ -2221,6 +2454,16 @@ func (table *ResultsTable) isAnInsertedRow(rowIndex int) (isAnInsertedRow bool,
 	return false, -1
 }
 
+// isCellMarker reports whether reference is the reference of a cell showing
+// NULL, an empty value or DEFAULT instead of text.
+func isCellMarker(reference any) bool {
+	switch reference {
+	case "NULL&", "EMPTY&", "DEFAULT&":
+		return true
+	}
+	return false
+}
+
 func (table *ResultsTable) colorChangedCells() {
 	tableName := table.GetTableName()
 	databaseName := table.GetDatabaseName()
@@ -2245,7 +2488,7 @@ func (table *ResultsTable) GetPrimitive() tview.Primitive {
 	return table.Page
 }
 
-func (table *ResultsTable) showCSVExportModal() {
+func (table *ResultsTable) showExportModal() {
 	databaseName := table.GetDatabaseName()
 	tableName := table.GetTableName()
 
@@ -2255,16 +2498,28 @@ func (table *ResultsTable) showCSVExportModal() {
 
 	rowCount := max(len(table.GetRecords())-1, 0)
 
-	opts := CSVExportOptions{
+	// A query result that is not fully read yet is exported by running the
+	// query again.
+	stream := table.state.resultStream
+	if stream != nil && !stream.done {
+		rowCount = -1
+	}
+
+	opts := ExportModalOptions{
 		DatabaseName:  cmp.Or(databaseName, "database"),
 		TableName:     cmp.Or(tableName, "query_result"),
 		HasPagination: hasPagination,
 		RowCount:      rowCount,
 	}
 
-	modal := NewCSVExportModal(opts, func(filePath string, scope CSVExportScope, batchSize int) {
+	modal := NewExportModal(opts, func(filePath string, format helpers.ExportFormat, scope ExportScope, batchSize int) {
 		App.ForceDraw()
 
+		exportOpts := helpers.ExportOptions{
+			TableName: cmp.Or(tableName, "query_result"),
+			Dialect:   table.DBDriver,
+		}
+
 		ctx := table.StartLoad()
 
 		go func() {
@@ -2275,8 +2530,10 @@ func (table *ResultsTable) showCSVExportModal() {
 			var exportedRowCount int
 			var exportErr error
 
-			if !hasPagination || scope == ExportCurrentPage {
-				exportedRowCount, exportErr = table.exportCurrentPage(filePath)
+			if !hasPagination && rowCount < 0 {
+				exportedRowCount, exportErr = table.exportResultStream(ctx, filePath, format, exportOpts, stream)
+			} else if !hasPagination || scope == ExportCurrentPage {
+				exportedRowCount, exportErr = table.exportCurrentPage(filePath, format, exportOpts)
 			} else {
 				where := ""
 				if table.Filter != nil {
@@ -2285,11 +2542,11 @@ func (table *ResultsTable) showCSVExportModal() {
 				sort := cmp.Or(table.GetCurrentSort(), table.GetPrimaryKeySort())
 				if sort == "" {
 					if records := table.GetRecords(); len(records) > 0 && len(records[0]) > 0 {
-						sort = records[0][0] + " ASC"
+						sort = records[0][0].String() + " ASC"
 					}
 				}
 				exportedRowCount, exportErr = table.exportAllRecordsInBatches(
-					filePath, databaseName, tableName, where, sort, batchSize,
+					ctx, filePath, format, exportOpts, databaseName, tableName, where, sort, batchSize,
 				)
 			}
 
@@ -2303,7 +2560,7 @@ func (table *ResultsTable) showCSVExportModal() {
 				}
 
 				if exportErr != nil {
-					table.SetError("Failed to export CSV: "+exportErr.Error(), nil)
+					table.SetError("Failed to export "+format.String()+": "+exportErr.Error(), nil)
 					App.ForceDraw()
 					return
 				}
@@ -2314,14 +2571,14 @@ func (table *ResultsTable) showCSVExportModal() {
 		}()
 	})
 
-	mainPages.AddPage(pageNameCSVExport, modal, true, true)
+	mainPages.AddPage(pageNameExport, modal, true, true)
 }
 
-// exportCurrentPage exports the current page records (already in memory) to CSV.
+// exportCurrentPage exports the current page records (already in memory) in format.
 // Returns the number of rows written (excluding header) and any error.
-func (table *ResultsTable) exportCurrentPage(filePath string) (int, error) {
+func (table *ResultsTable) exportCurrentPage(filePath string, format helpers.ExportFormat, opts helpers.ExportOptions) (int, error) {
 	records := table.GetRecords()
-	writer, err := helpers.NewCSVWriter(filePath)
+	writer, err := helpers.NewRecordWriter(filePath, format, opts)
 	if err != nil {
 		return 0, err
 	}
@@ -2339,10 +2596,14 @@ func (table *ResultsTable) exportCurrentPage(filePath string) (int, error) {
 // exportAllRecordsInBatches exports all records using batch fetching to avoid timeouts.
 // Returns the number of rows written (excluding header) and any error.
 func (table *ResultsTable) exportAllRecordsInBatches(
-	filePath, databaseName, tableName, where, sort string,
+	ctx context.Context,
+	filePath string,
+	format helpers.ExportFormat,
+	opts helpers.ExportOptions,
+	databaseName, tableName, where, sort string,
 	batchSize int,
 ) (int, error) {
-	writer, err := helpers.NewCSVWriter(filePath)
+	writer, err := helpers.NewRecordWriter(filePath, format, opts)
 	if err != nil {
 		return 0, err
 	}
@@ -2350,7 +2611,7 @@ func (table *ResultsTable) exportAllRecordsInBatches(
 
 	for offset := 0; ; offset += batchSize {
 		records, _, _, err := table.DBDriver.GetRecords(
-			databaseName, tableName, where, sort, offset, batchSize,
+			ctx, databaseName, tableName, where, sort, offset, batchSize,
 		)
 		if err != nil {
 			return writer.RowCount(), err
@@ -2386,10 +2647,10 @@ func (table *ResultsTable) showExportSuccessModal(filePath string, rowCount int)
 			Foreground(app.Styles.ContrastSecondaryTextColor),
 	)
 	modal.SetDoneFunc(func(_ int, _ string) {
-		mainPages.RemovePage(pageNameCSVExportSuccess)
+		mainPages.RemovePage(pageNameExportSuccess)
 	})
 
-	mainPages.AddPage(pageNameCSVExportSuccess, modal, true, true)
+	mainPages.AddPage(pageNameExportSuccess, modal, true, true)
 	App.SetFocus(modal)
 }
 
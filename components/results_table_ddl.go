@@ -0,0 +1,311 @@
+package components
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/jorgerojas26/lazysql/commands"
+	"github.com/jorgerojas26/lazysql/drivers"
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+// structureCommand stages the change of the structure of the table asked by
+// command in its Columns, Constraints or Indexes view: o adds a column,
+// constraint or index, c changes a column and d drops what is selected. It
+// reports whether command was handled.
+func (table *ResultsTable) structureCommand(command commands.Command) bool {
+	if table.Menu == nil || table.Home == nil {
+		return false
+	}
+	option := table.Menu.GetSelectedOption()
+	if option != 2 && option != 3 && option != 5 {
+		return false
+	}
+	if command != commands.AppendNewRow && command != commands.Edit && command != commands.Delete {
+		return false
+	}
+	if table.ReadOnly {
+		table.SetError("Cannot modify the table: Connection is in read-only mode", nil)
+		return true
+	}
+
+	row, _ := table.GetSelection()
+
+	// Deleting a staged row unstages its change.
+	if index, ok := table.state.stagedStructureRows[row]; ok {
+		if command == commands.Delete {
+			table.Home.ListOfDDLChanges = slices.Delete(table.Home.ListOfDDLChanges, index, index+1)
+			table.showStructureView()
+		}
+		return true
+	}
+
+	var err error
+	switch option {
+	case 2:
+		err = table.columnsCommand(command, row)
+	case 3:
+		err = table.indexesCommand(command, row, table.GetConstraints(), true)
+	case 5:
+		err = table.indexesCommand(command, row, table.GetIndexes(), false)
+	}
+	if err != nil {
+		table.SetError(err.Error(), nil)
+	}
+
+	return true
+}
+
+func (table *ResultsTable) columnsCommand(command commands.Command, row int) error {
+	columns := drivers.ParseTableColumns(table.GetColumns())
+
+	if command == commands.AppendNewRow {
+		table.showStructureForm(NewColumnFormModal("Add column", models.ColumnSchema{Nullable: true}, func(column models.ColumnSchema) error {
+			if column.Name == "" || column.Type == "" {
+				return errors.New("name and type are required")
+			}
+			return table.stageDDLChanges(models.DBDDLChange{Type: models.DDLAddColumn, Column: column})
+		}))
+		return nil
+	}
+
+	if row < 1 || row > len(columns) {
+		return nil
+	}
+	column := columns[row-1]
+
+	if command == commands.Delete {
+		if table.unstageDDLChange(func(change models.DBDDLChange) bool {
+			return change.Type == models.DDLDropColumn && change.Column.Name == column.Name
+		}) {
+			return nil
+		}
+		return table.stageDDLChanges(models.DBDDLChange{Type: models.DDLDropColumn, Column: column})
+	}
+
+	table.showStructureForm(NewColumnFormModal("Change column "+column.Name, column, func(changed models.ColumnSchema) error {
+		if changed.Name == "" || changed.Type == "" {
+			return errors.New("name and type are required")
+		}
+
+		var changes []models.DBDDLChange
+		if changed.Name != column.Name {
+			changes = append(changes, models.DBDDLChange{Type: models.DDLRenameColumn, Column: models.ColumnSchema{Name: changed.Name}, OldColumn: column})
+		}
+
+		renamed := column
+		renamed.Name = changed.Name
+		if !strings.EqualFold(changed.Type, renamed.Type) || changed.Nullable != renamed.Nullable || changed.Default != renamed.Default {
+			changes = append(changes, models.DBDDLChange{Type: models.DDLAlterColumn, Column: changed, OldColumn: renamed})
+		}
+
+		if len(changes) == 0 {
+			return nil
+		}
+		return table.stageDDLChanges(changes...)
+	}))
+	return nil
+}
+
+// indexesCommand handles command in the Indexes view, or in the
+// Constraints view for UNIQUE constraints if constraints is true.
+func (table *ResultsTable) indexesCommand(command commands.Command, row int, results [][]string, constraints bool) error {
+	switch command {
+	case commands.AppendNewRow:
+		title := "Create index"
+		if constraints {
+			title = "Add unique constraint"
+		}
+
+		index := models.IndexSchema{Unique: constraints, Constraint: constraints}
+		table.showStructureForm(NewIndexFormModal(title, index, func(index models.IndexSchema) error {
+			if index.Name == "" || len(index.Columns) == 0 {
+				return errors.New("name and columns are required")
+			}
+			return table.stageDDLChanges(models.DBDDLChange{Type: models.DDLCreateIndex, Index: index})
+		}))
+		return nil
+	case commands.Edit:
+		if constraints {
+			return errors.New("constraints can't be changed, drop it and add it again")
+		}
+		return errors.New("indexes can't be changed, drop it and create it again")
+	}
+
+	name := drivers.SchemaObjectName(results, row)
+	if name == "" {
+		return nil
+	}
+
+	indexes := drivers.ParseTableIndexes(table.GetIndexes(), table.GetConstraints())
+	i := slices.IndexFunc(indexes, func(index models.IndexSchema) bool {
+		return index.Name == name && (!constraints || index.Constraint)
+	})
+	if i < 0 {
+		if constraints {
+			return fmt.Errorf("only UNIQUE constraints can be dropped here, %s is not one", name)
+		}
+		return fmt.Errorf("%s is the index of the primary key", name)
+	}
+
+	if table.unstageDDLChange(func(change models.DBDDLChange) bool {
+		return change.Type == models.DDLDropIndex && change.Index.Name == name
+	}) {
+		return nil
+	}
+	return table.stageDDLChanges(models.DBDDLChange{Type: models.DDLDropIndex, Index: indexes[i]})
+}
+
+func (table *ResultsTable) showStructureForm(modal *StructureFormModal) {
+	mainPages.AddPage(pageNameStructureForm, modal, true, true)
+}
+
+// stageDDLChanges adds changes to the pending DDL changes of the connection,
+// if the database can make them.
+func (table *ResultsTable) stageDDLChanges(changes ...models.DBDDLChange) error {
+	for i := range changes {
+		changes[i].Database = table.GetDatabaseName()
+		changes[i].Table = table.GetTableName()
+
+		if _, err := drivers.DDLChangeQueries(table.DBDriver, changes[i]); err != nil {
+			return err
+		}
+	}
+
+	table.Home.ListOfDDLChanges = append(table.Home.ListOfDDLChanges, changes...)
+	table.showStructureView()
+	return nil
+}
+
+// unstageDDLChange removes the first staged change of the table that
+// matches, and reports whether there was one.
+func (table *ResultsTable) unstageDDLChange(match func(change models.DBDDLChange) bool) bool {
+	i := slices.IndexFunc(table.Home.ListOfDDLChanges, func(change models.DBDDLChange) bool {
+		return change.Database == table.GetDatabaseName() && change.Table == table.GetTableName() && match(change)
+	})
+	if i < 0 {
+		return false
+	}
+
+	table.Home.ListOfDDLChanges = slices.Delete(table.Home.ListOfDDLChanges, i, i+1)
+	table.showStructureView()
+	return true
+}
+
+// showStructureView shows the rows of the selected Columns, Constraints or
+// Indexes view again, with its staged changes.
+func (table *ResultsTable) showStructureView() {
+	row, column := table.GetSelection()
+
+	switch table.Menu.GetSelectedOption() {
+	case 2:
+		table.UpdateRows(table.GetColumns())
+	case 3:
+		table.UpdateRows(table.GetConstraints())
+	case 5:
+		table.UpdateRows(table.GetIndexes())
+	default:
+		return
+	}
+	table.colorStagedStructure()
+
+	if row < table.GetRowCount() {
+		table.Select(row, column)
+	}
+}
+
+// colorStagedStructure marks the rows of the selected view changed or
+// dropped by a staged change, and adds a row for each column, constraint or
+// index it adds.
+func (table *ResultsTable) colorStagedStructure() {
+	table.state.stagedStructureRows = map[int]int{}
+	if table.Home == nil || table.Menu == nil {
+		return
+	}
+
+	option := table.Menu.GetSelectedOption()
+	results := table.GetColumns()
+	switch option {
+	case 3:
+		results = table.GetConstraints()
+	case 5:
+		results = table.GetIndexes()
+	}
+	columns := drivers.ParseTableColumns(table.GetColumns())
+
+	for i, change := range table.Home.ListOfDDLChanges {
+		if change.Database != table.GetDatabaseName() || change.Table != table.GetTableName() {
+			continue
+		}
+
+		isColumnChange := change.Type == models.DDLAddColumn || change.Type == models.DDLDropColumn ||
+			change.Type == models.DDLRenameColumn || change.Type == models.DDLAlterColumn
+		if (option == 2) != isColumnChange || (option == 3 && !change.Index.Constraint) || (option == 5 && change.Index.Constraint) {
+			continue
+		}
+
+		switch change.Type {
+		case models.DDLAddColumn:
+			table.addStagedStructureRow(i, change.Column.Name, change)
+		case models.DDLCreateIndex:
+			table.addStagedStructureRow(i, change.Index.Name, change)
+		case models.DDLDropColumn, models.DDLRenameColumn, models.DDLAlterColumn:
+			name := change.Column.Name
+			if change.Type != models.DDLDropColumn {
+				name = change.OldColumn.Name
+			}
+			if j := slices.IndexFunc(columns, func(column models.ColumnSchema) bool { return column.Name == name }); j >= 0 {
+				table.colorStructureRow(j+1, colorForDDLChange(change.Type))
+			}
+		case models.DDLDropIndex:
+			for row := 1; row < len(results); row++ {
+				if drivers.SchemaObjectName(results, row) == change.Index.Name {
+					table.colorStructureRow(row, colorTableDelete)
+				}
+			}
+		}
+	}
+}
+
+func colorForDDLChange(changeType models.DDLChangeType) tcell.Color {
+	switch changeType {
+	case models.DDLAddColumn, models.DDLCreateIndex:
+		return colorTableInsert
+	case models.DDLDropColumn, models.DDLDropIndex:
+		return colorTableDelete
+	default:
+		return colorTableChange
+	}
+}
+
+// addStagedStructureRow adds a row for the column, constraint or index
+// change adds, with the statement that adds it.
+func (table *ResultsTable) addStagedStructureRow(index int, name string, change models.DBDDLChange) {
+	statement := ""
+	if queries, err := drivers.DDLChangeQueries(table.DBDriver, change); err == nil {
+		statement = strings.Join(queries, " ")
+	}
+
+	row := table.GetRowCount()
+	table.SetCell(row, 0, tview.NewTableCell(name).SetExpansion(1))
+	table.SetCell(row, 1, tview.NewTableCell(tview.Escape(statement)).SetExpansion(1))
+	for column := 2; column < table.GetColumnCount(); column++ {
+		table.SetCell(row, column, tview.NewTableCell("").SetExpansion(1))
+	}
+	table.colorStructureRow(row, colorTableInsert)
+
+	table.state.stagedStructureRows[row] = index
+}
+
+func (table *ResultsTable) colorStructureRow(row int, color tcell.Color) {
+	for column := 0; column < table.GetColumnCount(); column++ {
+		if cell := table.GetCell(row, column); cell != nil {
+			cell.SetBackgroundColor(color)
+		}
+	}
+}
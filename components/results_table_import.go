@@ -0,0 +1,348 @@
+package components
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/google/uuid"
+	"github.com/rivo/tview"
+
+	"github.com/jorgerojas26/lazysql/app"
+	"github.com/jorgerojas26/lazysql/helpers"
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+const (
+	// pendingImportLimit is the number of rows up to which an import is added
+	// to the pending changes, to be reviewed before it is saved. Larger
+	// imports are saved right away.
+	pendingImportLimit = 1000
+	// importBatchSize is the number of rows saved per transaction by a large
+	// import.
+	importBatchSize = 500
+	// importReportLimit is the number of row errors listed by the report.
+	importReportLimit = 200
+)
+
+// importRowError is the error of a row of the file, counted from 1.
+type importRowError struct {
+	row int
+	err error
+}
+
+// importResult is the outcome of a large import.
+type importResult struct {
+	imported int
+	total    int
+	errors   []importRowError
+	// err stopped the import, e.g. the file could not be read.
+	err error
+}
+
+func (table *ResultsTable) showImportModal() {
+	dbColumns := table.GetColumns()
+	if len(dbColumns) <= 1 {
+		table.SetError("The columns of the table are not loaded yet", nil)
+		return
+	}
+
+	columns := make([]string, 0, len(dbColumns)-1)
+	for _, column := range dbColumns[1:] {
+		columns = append(columns, column[0])
+	}
+
+	modal := NewImportModal(table.GetTableName(), columns, func(filePath string, mapping []int) {
+		table.importFile(filePath, columns, mapping)
+	})
+
+	mainPages.AddPage(pageNameImport, modal, true, true)
+}
+
+// importFile reads the file and either adds its rows to the pending changes,
+// if there are few, or saves them in batches.
+func (table *ResultsTable) importFile(filePath string, columns []string, mapping []int) {
+	ctx := table.StartLoad()
+
+	go func() {
+		records, done, err := readImportRecords(filePath, pendingImportLimit+1)
+
+		App.QueueUpdateDraw(func() {
+			table.SetLoading(false)
+			if ctx.Err() != nil {
+				return
+			}
+
+			switch {
+			case err != nil:
+				table.SetError("Failed to import: "+err.Error(), nil)
+			case len(records) == 0:
+				table.SetError("The file has no rows to import", nil)
+			case done:
+				table.queueImport(records, columns, mapping)
+			default:
+				table.runImport(filePath, columns, mapping)
+			}
+		})
+	}()
+}
+
+// readImportRecords reads up to limit rows of the file. done is true if the
+// file has no more rows.
+func readImportRecords(filePath string, limit int) (records []models.Record, done bool, err error) {
+	reader, err := helpers.OpenImportFile(filePath)
+	if err != nil {
+		return nil, false, err
+	}
+	defer reader.Close()
+
+	for len(records) < limit {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return records, true, nil
+		}
+		if err != nil {
+			return nil, false, fmt.Errorf("row %d: %w", len(records)+1, err)
+		}
+		records = append(records, record)
+	}
+
+	return records, false, nil
+}
+
+// importChange returns the insert of record, a row of the file, into the
+// table at rowIndex. mapping holds, for each of the columns of the table,
+// the index of the file column its value is read from, or -1 to leave it to
+// its default.
+func importChange(databaseName, tableName string, columns []string, mapping []int, record models.Record, rowIndex int) models.DBDMLChange {
+	values := make([]models.CellValue, len(columns))
+
+	for i, column := range columns {
+		value := models.CellValue{Type: models.Default, Column: column, Value: "DEFAULT", TableRowIndex: rowIndex, TableColumnIndex: i + 1}
+
+		if index := mapping[i]; index >= 0 && index < len(record) {
+			switch fileValue := record[index]; {
+			case fileValue.Null:
+				value.Type, value.Value = models.Null, "NULL"
+			case fileValue.IsEmpty():
+				value.Type, value.Value = models.Empty, "EMPTY"
+			default:
+				value.Type, value.Value = models.String, fileValue.String()
+			}
+		}
+
+		values[i] = value
+	}
+
+	return models.DBDMLChange{
+		Type:           models.DMLInsertType,
+		Database:       databaseName,
+		Table:          tableName,
+		Values:         values,
+		PrimaryKeyInfo: []models.PrimaryKeyInfo{{Name: "", Value: uuid.New().String()}},
+	}
+}
+
+// queueImport adds the rows to the pending changes and shows them for
+// review.
+func (table *ResultsTable) queueImport(records []models.Record, columns []string, mapping []int) {
+	databaseName := table.GetDatabaseName()
+	tableName := table.GetTableName()
+
+	for _, record := range records {
+		rowIndex := table.GetRowCount()
+		change := importChange(databaseName, tableName, columns, mapping, record, rowIndex)
+
+		*table.state.listOfDBChanges = append(*table.state.listOfDBChanges, change)
+		table.setInsertedRow(change.Values, rowIndex, change.PrimaryKeyInfo[0].Value.(string))
+	}
+
+	if table.Home != nil {
+		table.Home.showQueryPreview(table)
+	}
+}
+
+// runImport saves all the rows of the file in batches, each in its own
+// transaction, while a modal shows the progress. The rows of a batch that
+// fails are saved one by one to report the error of each row.
+func (table *ResultsTable) runImport(filePath string, columns []string, mapping []int) {
+	ctx, cancel := context.WithCancel(App.Context())
+
+	progress := tview.NewModal().
+		SetText("Counting rows...").
+		AddButtons([]string{"Cancel"}).
+		SetDoneFunc(func(_ int, _ string) {
+			cancel()
+		})
+	progress.SetBackgroundColor(app.Styles.PrimitiveBackgroundColor)
+	progress.SetBorderStyle(tcell.StyleDefault.Background(app.Styles.PrimitiveBackgroundColor))
+	progress.SetTitle(" Importing into " + table.GetTableName() + " ")
+	mainPages.AddPage(pageNameImportProgress, progress, true, true)
+
+	databaseName := table.GetDatabaseName()
+	tableName := table.GetTableName()
+
+	go func() {
+		defer cancel()
+
+		result := table.saveImport(ctx, filePath, databaseName, tableName, columns, mapping, func(result importResult) {
+			App.QueueUpdateDraw(func() {
+				progress.SetText(importProgressText(result))
+			})
+		})
+
+		App.QueueUpdateDraw(func() {
+			mainPages.RemovePage(pageNameImportProgress)
+			table.showImportReport(result)
+			table.FetchRecords(nil, nil)
+		})
+	}()
+}
+
+// saveImport saves the rows of the file and calls onProgress after each
+// batch.
+func (table *ResultsTable) saveImport(ctx context.Context, filePath, databaseName, tableName string, columns []string, mapping []int, onProgress func(importResult)) importResult {
+	result := importResult{}
+
+	total, err := helpers.CountImportRows(filePath)
+	if err != nil {
+		result.err = err
+		return result
+	}
+	result.total = total
+	onProgress(result)
+
+	reader, err := helpers.OpenImportFile(filePath)
+	if err != nil {
+		result.err = err
+		return result
+	}
+	defer reader.Close()
+
+	// Inside a transaction opened by the user, a failed statement may abort
+	// the whole transaction, so the import stops at the first error.
+	inTransaction := table.DBDriver.InTransaction()
+
+	row := 0
+	for ctx.Err() == nil {
+		batch := make([]models.DBDMLChange, 0, importBatchSize)
+		firstRow := row + 1
+
+		for len(batch) < importBatchSize {
+			record, err := reader.Read()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			row++
+			if err != nil {
+				result.err = fmt.Errorf("row %d: %w", row, err)
+				break
+			}
+			batch = append(batch, importChange(databaseName, tableName, columns, mapping, record, row))
+		}
+
+		if len(batch) > 0 {
+			if err := table.DBDriver.ExecutePendingChanges(ctx, batch); err == nil {
+				result.imported += len(batch)
+			} else if inTransaction {
+				result.err = fmt.Errorf("rows %d to %d: %w\nThe import stopped because a transaction is open", firstRow, row, err)
+			} else {
+				for i, change := range batch {
+					if ctx.Err() != nil {
+						break
+					}
+					if err := table.DBDriver.ExecutePendingChanges(ctx, []models.DBDMLChange{change}); err != nil {
+						result.errors = append(result.errors, importRowError{row: firstRow + i, err: err})
+					} else {
+						result.imported++
+					}
+				}
+			}
+			onProgress(result)
+		}
+
+		if result.err != nil || len(batch) < importBatchSize {
+			break
+		}
+	}
+
+	if ctx.Err() != nil && result.err == nil {
+		result.err = errors.New("the import was cancelled")
+	}
+
+	return result
+}
+
+// importProgressText returns the progress of an import as a bar followed by
+// the row counts.
+func importProgressText(result importResult) string {
+	const width = 40
+
+	done := result.imported + len(result.errors)
+	filled := 0
+	if result.total > 0 {
+		filled = min(done*width/result.total, width)
+	}
+
+	text := fmt.Sprintf("[%s%s]\n\n%d of %d rows imported", strings.Repeat("█", filled), strings.Repeat("░", width-filled), result.imported, result.total)
+	if len(result.errors) > 0 {
+		text += fmt.Sprintf(", %d failed", len(result.errors))
+	}
+	return text
+}
+
+// showImportReport shows the number of rows imported and the error of each
+// row that failed.
+func (table *ResultsTable) showImportReport(result importResult) {
+	var report strings.Builder
+	fmt.Fprintf(&report, "Imported %d of %d rows into %s.\n", result.imported, result.total, table.GetTableName())
+
+	if result.err != nil {
+		fmt.Fprintf(&report, "\n[red]%s[-]\n", tview.Escape(result.err.Error()))
+	}
+
+	if len(result.errors) > 0 {
+		fmt.Fprintf(&report, "\n%d rows failed:\n\n", len(result.errors))
+		for i, rowError := range result.errors {
+			if i == importReportLimit {
+				fmt.Fprintf(&report, "... and %d more\n", len(result.errors)-importReportLimit)
+				break
+			}
+			fmt.Fprintf(&report, "row %d: %s\n", rowError.row, tview.Escape(rowError.err.Error()))
+		}
+	}
+
+	text := tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true).
+		SetWrap(true).
+		SetText(report.String())
+	text.SetBorder(true).SetTitle(" Import report ").SetTitleAlign(tview.AlignLeft)
+	text.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEsc || event.Key() == tcell.KeyEnter {
+			mainPages.RemovePage(pageNameImportReport)
+			return nil
+		}
+		return event
+	})
+
+	hint := tview.NewTextView().
+		SetText("Enter or Esc to close").
+		SetTextAlign(tview.AlignCenter).
+		SetTextColor(app.Styles.TertiaryTextColor)
+
+	content := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(text, 0, 1, true).
+		AddItem(hint, 1, 0, false)
+
+	grid := tview.NewGrid().
+		SetRows(0, 20, 0).
+		SetColumns(0, 100, 0).
+		AddItem(content, 1, 1, 1, 1, 0, 0, true)
+
+	mainPages.AddPage(pageNameImportReport, grid, true, true)
+}
@@ -0,0 +1,163 @@
+package components
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jorgerojas26/lazysql/drivers"
+	"github.com/jorgerojas26/lazysql/helpers/logger"
+	"github.com/jorgerojas26/lazysql/internal/history"
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+// scriptStatementResult is the outcome of one statement of a script run from
+// the SQL editor.
+type scriptStatementResult struct {
+	query       string
+	records     []models.Record
+	recordCount int
+	info        string
+	err         error
+	skipped     bool
+}
+
+// executeScript runs the statements of a multi-statement editor buffer in
+// order. Statements returning rows get a result tab each, everything else is
+// reported in the results info of the editor. Unless ContinueScriptOnError is
+// set, the statements after the first failing one are skipped. parameters
+// are the placeholders of the whole buffer and are bound to values.
+func (table *ResultsTable) executeScript(ctx context.Context, statements []sqlStatement, parameters []sqlParameter, values map[string]any) {
+	continueOnError := App.Config().ContinueScriptOnError
+	results := make([]scriptStatementResult, len(statements))
+	failed := false
+
+	for i, statement := range statements {
+		result := &results[i]
+		result.query = statement.Text
+
+		if failed && !continueOnError {
+			result.skipped = true
+			continue
+		}
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		query, args := bindParameters(statement.Text, statementParameters(parameters, statement), values, table.DBDriver.FormatPlaceholder)
+
+		switch {
+		case returnsRows(statement.Text):
+			result.records, result.recordCount, result.err = table.DBDriver.ExecuteQuery(ctx, query, args...)
+		case table.ReadOnly && drivers.ValidateQueryForReadOnly(statement.Text) != nil:
+			result.err = errors.New("cannot execute mutation query: connection is in read-only mode")
+		default:
+			result.info, result.err = table.DBDriver.ExecuteDMLStatement(ctx, query, args...)
+		}
+
+		if result.err != nil {
+			failed = true
+		}
+	}
+
+	if ctx.Err() != nil {
+		return
+	}
+
+	App.QueueUpdateDraw(func() {
+		if ctx.Err() != nil {
+			return
+		}
+
+		table.showScriptResults(results)
+	})
+}
+
+// showScriptResults opens a result tab for every statement that returned rows
+// and writes a per-statement summary to the results info of the editor.
+func (table *ResultsTable) showScriptResults(results []scriptStatementResult) {
+	summary := make([]string, 0, len(results))
+	resultTabCount := 0
+	schemaChanged := false
+
+	for i, result := range results {
+		var outcome string
+
+		switch {
+		case result.skipped:
+			outcome = "skipped"
+		case result.err != nil:
+			outcome = "error: " + result.err.Error()
+		case result.records != nil:
+			tabName := fmt.Sprintf("%s %d", tabNameQueryResult, resultTabCount+1)
+			if table.Home != nil {
+				table.Home.showQueryResult(resultTabCount, tabName, result.records, result.recordCount)
+			}
+			resultTabCount++
+			outcome = fmt.Sprintf("%d rows returned (%s)", result.recordCount, tabName)
+		default:
+			outcome = result.info
+			schemaChanged = schemaChanged || changesSchema(result.query)
+		}
+
+		if !result.skipped && result.err == nil {
+			if err := history.AddQueryToHistory(table.connectionIdentifier, result.query); err != nil {
+				logger.Error("Failed to add script query to history", map[string]any{"error": err, "query": result.query, "connection": table.connectionIdentifier})
+			}
+		}
+
+		summary = append(summary, fmt.Sprintf("%d. %s: %s", i+1, summarizeStatement(result.query), outcome))
+	}
+
+	if table.Home != nil {
+		table.Home.removeQueryResults(resultTabCount)
+		if resultTabCount > 0 {
+			table.Home.TabbedPane.SwitchToTabByName(tabNameEditor)
+		}
+	}
+	if table.Home != nil && schemaChanged {
+		table.Home.refreshTree()
+	}
+
+	table.SetResultsInfo(strings.Join(summary, "\n"))
+	table.SetLoading(false)
+	closeQuitConfirmation()
+	table.EditorPages.SwitchToPage(pageNameTableEditorResultsInfo)
+	App.SetFocus(table.Editor)
+}
+
+// removeQueryResults removes the result tabs opened by a previous script
+// after the first count ones, left over from a longer script. Other tabs are
+// kept, even if named like a result tab.
+func (home *Home) removeQueryResults(count int) {
+	if count >= len(home.queryResultTabs) {
+		return
+	}
+
+	for _, tab := range home.queryResultTabs[count:] {
+		if home.TabbedPane.HasTab(tab) {
+			home.TabbedPane.RemoveTab(tab)
+		}
+	}
+	home.queryResultTabs = home.queryResultTabs[:count]
+}
+
+// summarizeStatement shortens a statement to its first line so it fits in a
+// single line of the script summary.
+func summarizeStatement(query string) string {
+	const maxLength = 60
+
+	summary, _, multiline := strings.Cut(query, "\n")
+	summary = strings.TrimSpace(summary)
+
+	runes := []rune(summary)
+	if len(runes) > maxLength {
+		return string(runes[:maxLength]) + "..."
+	}
+	if multiline {
+		return summary + " ..."
+	}
+	return summary
+}
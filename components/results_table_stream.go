@@ -0,0 +1,166 @@
+package components
+
+import (
+	"context"
+
+	"github.com/jorgerojas26/lazysql/drivers"
+	"github.com/jorgerojas26/lazysql/helpers"
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+const (
+	defaultStreamBatchSize = 300
+	// streamPrefetchRows is how close to the last loaded row the selection
+	// has to be for the next batch to be read.
+	streamPrefetchRows = 10
+)
+
+// resultStream is the open result of a query run from the SQL editor. Its
+// rows are read a batch at a time as the user scrolls down, so a large
+// result never has to be held in memory at once.
+type resultStream struct {
+	rows     drivers.Rows
+	cancel   context.CancelFunc
+	query    string
+	args     []any
+	loaded   int
+	fetching bool
+	done     bool
+}
+
+func streamBatchSize() int {
+	if size := App.Config().DefaultPageSize; size > 0 {
+		return size
+	}
+	return defaultStreamBatchSize
+}
+
+// close closes the result. It may be called more than once.
+func (s *resultStream) close() {
+	s.done = true
+	s.rows.Close()
+	s.cancel()
+}
+
+// openResultStream runs query and reads its first batch of rows, returned
+// after the header record. Cancelling loadCtx aborts the query
+// until the first batch is read. Inside a transaction the whole result is
+// read at once, because the connection of the transaction can't run other
+// statements while a result is open on it.
+func (table *ResultsTable) openResultStream(loadCtx context.Context, query string, args []any) (*resultStream, []models.Record, error) {
+	ctx, cancel := context.WithCancel(App.Context())
+	stop := context.AfterFunc(loadCtx, cancel)
+	defer stop()
+
+	rows, err := table.DBDriver.QueryRows(ctx, query, args...)
+	if err != nil {
+		cancel()
+		return nil, nil, err
+	}
+
+	limit := streamBatchSize()
+	if table.DBDriver.InTransaction() {
+		limit = 0
+	}
+
+	stream := &resultStream{rows: rows, cancel: cancel, query: query, args: args}
+
+	records, done, err := drivers.ReadRows(rows, limit)
+	if err != nil || done {
+		stream.close()
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	stream.loaded = len(records)
+
+	return stream, append([]models.Record{models.HeaderRecord(rows.Columns())}, records...), nil
+}
+
+// setResultStream makes stream the result shown by the table and closes the
+// previous one.
+func (table *ResultsTable) setResultStream(stream *resultStream) {
+	if previous := table.state.resultStream; previous != nil && previous != stream {
+		previous.close()
+	}
+	table.state.resultStream = stream
+
+	if stream != nil {
+		table.Pagination.SetStreamedRows(stream.loaded, !stream.done)
+	}
+}
+
+// loadMoreRows reads the next batch of the open result once the selection
+// is close to the last loaded row.
+func (table *ResultsTable) loadMoreRows(selectedRow int) {
+	stream := table.state.resultStream
+	if stream == nil || stream.done || stream.fetching || selectedRow < table.GetRowCount()-streamPrefetchRows {
+		return
+	}
+
+	stream.fetching = true
+	table.SetLoading(true)
+
+	go func() {
+		records, done, err := drivers.ReadRows(stream.rows, streamBatchSize())
+
+		App.QueueUpdateDraw(func() {
+			stream.fetching = false
+			if table.state.resultStream != stream {
+				return
+			}
+
+			table.SetLoading(false)
+
+			if err != nil {
+				stream.close()
+				table.Pagination.SetStreamedRows(stream.loaded, false)
+				table.SetError(err.Error(), nil)
+				return
+			}
+
+			if done {
+				stream.close()
+			}
+
+			table.appendRecords(records)
+			stream.loaded += len(records)
+			table.Pagination.SetStreamedRows(stream.loaded, !stream.done)
+		})
+	}()
+}
+
+// exportResultStream runs the query of stream again and writes its whole
+// result to a file in format row by row.
+// Returns the number of rows written (excluding header) and any error.
+func (table *ResultsTable) exportResultStream(ctx context.Context, filePath string, format helpers.ExportFormat, opts helpers.ExportOptions, stream *resultStream) (int, error) {
+	rows, err := table.DBDriver.QueryRows(ctx, stream.query, stream.args...)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	writer, err := helpers.NewRecordWriter(filePath, format, opts)
+	if err != nil {
+		return 0, err
+	}
+	defer writer.Abort()
+
+	if err := writer.WriteHeader(models.HeaderRecord(rows.Columns())); err != nil {
+		return 0, err
+	}
+
+	for rows.Next() {
+		if err := writer.WriteRow(rows.Values()); err != nil {
+			return writer.RowCount(), err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return writer.RowCount(), err
+	}
+
+	if err := writer.Commit(); err != nil {
+		return writer.RowCount(), err
+	}
+	return writer.RowCount(), nil
+}
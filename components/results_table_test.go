@@ -1,6 +1,11 @@
 package components
 
 import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
 	"time"
 
@@ -127,7 +132,7 @@ func TestSetLoadingIsSynchronous(t *testing.T) {
 	table := &ResultsTable{
 		Table: tview.NewTable(),
 		state: &ResultsTableState{
-			records:         [][]string{},
+			records:         []models.Record{},
 			isLoading:       false,
 			listOfDBChanges: &changes,
 		},
@@ -333,3 +338,146 @@ func TestRebuildForeignKeyJumpMetadataPostgresUsesForeignTableSchemaColumn(t *te
 		t.Fatalf("expected referenced table auth.users, got %q", target.ReferencedTable)
 	}
 }
+
+func TestReferencingWhere(t *testing.T) {
+	reference := func(name string) string { return `"` + name + `"` }
+	key := models.ReferencingForeignKey{
+		Table:            "public.order_lines",
+		ForeignKeySchema: models.ForeignKeySchema{Columns: []string{"order_id", "shop"}, ReferencedColumns: []string{"id", "shop"}},
+	}
+
+	where, ok := referencingWhere(key, map[string]string{"id": "7", "shop": "O'Hara", "total": "10"}, reference)
+	if !ok {
+		t.Fatal("expected a filter for a row with the referenced columns")
+	}
+	if expected := `WHERE "order_id" = '7' AND "shop" = 'O''Hara'`; where != expected {
+		t.Fatalf("expected %q, got %q", expected, where)
+	}
+
+	if _, ok := referencingWhere(key, map[string]string{"id": "7", "shop": "NULL"}, reference); ok {
+		t.Fatal("expected no filter for a NULL referenced value")
+	}
+	if _, ok := referencingWhere(key, map[string]string{"id": "7"}, reference); ok {
+		t.Fatal("expected no filter for a row missing a referenced column")
+	}
+}
+
+func TestAddRecordsAtShowsTypedValues(t *testing.T) {
+	changes := []models.DBDMLChange{}
+
+	db := &drivers.MySQL{}
+	db.SetProvider(drivers.DriverMySQL)
+
+	table := &ResultsTable{
+		Table: tview.NewTable(),
+		state: &ResultsTableState{
+			listOfDBChanges: &changes,
+			fkRawCellValues: map[string]string{},
+		},
+		DBDriver: db,
+	}
+
+	table.addRecordsAt(0, []models.Record{
+		models.HeaderRecord([]models.ResultColumn{{Name: "id", Type: "INT"}, {Name: "note", Type: "VARCHAR"}}),
+		{{Type: "INT", Raw: []byte("1")}, {Type: "VARCHAR", Null: true}},
+		{{Type: "INT", Raw: []byte("2")}, {Type: "VARCHAR", Raw: []byte{}}},
+		{{Type: "INT", Raw: []byte("3")}, {Type: "VARCHAR", Raw: []byte("NULL&")}},
+	})
+
+	tests := []struct {
+		row, column int
+		text        string
+		marker      bool
+		align       int
+	}{
+		{0, 0, "id", false, tview.AlignRight},
+		{1, 0, "1", false, tview.AlignRight},
+		{1, 1, "NULL", true, tview.AlignLeft},
+		{2, 1, "EMPTY", true, tview.AlignLeft},
+		{3, 1, "NULL&", false, tview.AlignLeft},
+	}
+
+	for _, tt := range tests {
+		cell := table.GetCell(tt.row, tt.column)
+		if cell.Text != tt.text || isCellMarker(cell.GetReference()) != tt.marker || cell.Align != tt.align {
+			t.Errorf("cell %d,%d: expected %q (marker %v, align %d), got %q (reference %v, align %d)",
+				tt.row, tt.column, tt.text, tt.marker, tt.align, cell.Text, cell.GetReference(), cell.Align)
+		}
+	}
+}
+
+func TestImportChangeMapsFileColumns(t *testing.T) {
+	record := models.Record{models.TextValue("ada@example.com"), {Null: true}, models.TextValue("")}
+
+	change := importChange("db", "users", []string{"id", "email", "name", "note"}, []int{-1, 0, 1, 2}, record, 3)
+
+	expected := []models.CellValue{
+		{Type: models.Default, Column: "id", Value: "DEFAULT", TableRowIndex: 3, TableColumnIndex: 1},
+		{Type: models.String, Column: "email", Value: "ada@example.com", TableRowIndex: 3, TableColumnIndex: 2},
+		{Type: models.Null, Column: "name", Value: "NULL", TableRowIndex: 3, TableColumnIndex: 3},
+		{Type: models.Empty, Column: "note", Value: "EMPTY", TableRowIndex: 3, TableColumnIndex: 4},
+	}
+
+	if change.Type != models.DMLInsertType || change.Database != "db" || change.Table != "users" {
+		t.Fatalf("unexpected change %+v", change)
+	}
+	if !reflect.DeepEqual(change.Values, expected) {
+		t.Fatalf("Values mismatch:\nexpected: %+v\ngot: %+v", expected, change.Values)
+	}
+	if len(change.PrimaryKeyInfo) != 1 || change.PrimaryKeyInfo[0].Value == "" {
+		t.Fatalf("expected the row UUID as primary key info, got %+v", change.PrimaryKeyInfo)
+	}
+}
+
+func TestDefaultImportMapping(t *testing.T) {
+	mapping := defaultImportMapping([]string{"id", "Email", "created_at"}, []string{"EMAIL", " id "})
+
+	expected := []int{1, 0, -1}
+	if !reflect.DeepEqual(mapping, expected) {
+		t.Fatalf("expected mapping %v, got %v", expected, mapping)
+	}
+}
+
+// importDriverMock fails to insert the rows holding "bad".
+type importDriverMock struct {
+	schemaProgrammingMock
+	calls int
+}
+
+func (m *importDriverMock) ExecutePendingChanges(_ context.Context, changes []models.DBDMLChange) error {
+	m.calls++
+	for _, change := range changes {
+		for _, value := range change.Values {
+			if value.Value == "bad" {
+				return errors.New("invalid value")
+			}
+		}
+	}
+	return nil
+}
+
+func TestSaveImportReportsRowErrors(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "users.csv")
+	if err := os.WriteFile(filePath, []byte("name\nada\nbad\ngrace\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	driver := &importDriverMock{}
+	table := &ResultsTable{DBDriver: driver}
+
+	result := table.saveImport(context.Background(), filePath, "db", "users", []string{"name"}, []int{0}, func(importResult) {})
+
+	if result.err != nil {
+		t.Fatalf("unexpected error: %v", result.err)
+	}
+	if result.total != 3 || result.imported != 2 {
+		t.Fatalf("expected 2 of 3 rows imported, got %d of %d", result.imported, result.total)
+	}
+	if len(result.errors) != 1 || result.errors[0].row != 2 {
+		t.Fatalf("expected an error for row 2, got %+v", result.errors)
+	}
+	// One call for the batch, then one per row.
+	if driver.calls != 4 {
+		t.Fatalf("expected 4 calls, got %d", driver.calls)
+	}
+}
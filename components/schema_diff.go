@@ -0,0 +1,309 @@
+package components
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/jorgerojas26/lazysql/app"
+	"github.com/jorgerojas26/lazysql/drivers"
+	"github.com/jorgerojas26/lazysql/helpers/logger"
+	"github.com/jorgerojas26/lazysql/lib"
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+// SchemaDiffView compares the schemas of two databases, of one connection
+// or of two open connections, and shows the DDL that makes the target match
+// the source.
+type SchemaDiffView struct {
+	*tview.Flex
+	home    *Home
+	form    *tview.Form
+	changes *tview.Table
+	ddl     *tview.TextView
+
+	source, target                 *Home
+	sourceDatabase, targetDatabase string
+	// targetHome is the connection the shown DDL is for.
+	targetHome *Home
+	statements []string
+	cancel     context.CancelFunc
+}
+
+// NewSchemaDiffView creates a new SchemaDiffView opened from home, which is
+// preselected as source and target.
+func NewSchemaDiffView(home *Home) *SchemaDiffView {
+	v := &SchemaDiffView{
+		Flex:   tview.NewFlex().SetDirection(tview.FlexRow),
+		home:   home,
+		source: home,
+		target: home,
+	}
+
+	connectionNames := make([]string, len(openHomes))
+	for i, openHome := range openHomes {
+		connectionNames[i] = openHome.ConnectionIdentifier
+	}
+	current := slices.Index(openHomes, home)
+
+	v.form = tview.NewForm().SetHorizontal(true)
+	v.form.AddDropDown("Source", connectionNames, current, func(_ string, index int) {
+		if index >= 0 {
+			v.source = openHomes[index]
+			loadDatabases(v.form, 1, v.source, func(database string) { v.sourceDatabase = database })
+		}
+	}).
+		AddDropDown("Database", []string{}, 0, nil).
+		AddDropDown("Target", connectionNames, current, func(_ string, index int) {
+			if index >= 0 {
+				v.target = openHomes[index]
+				loadDatabases(v.form, 3, v.target, func(database string) { v.targetDatabase = database })
+			}
+		}).
+		AddDropDown("Database", []string{}, 0, nil).
+		AddButton("Compare", v.compare)
+
+	v.form.SetFieldStyle(
+		tcell.StyleDefault.
+			Background(app.Styles.SecondaryTextColor).
+			Foreground(app.Styles.ContrastSecondaryTextColor),
+	).SetButtonActivatedStyle(tcell.StyleDefault.
+		Background(app.Styles.SecondaryTextColor).
+		Foreground(app.Styles.ContrastSecondaryTextColor),
+	).SetButtonStyle(tcell.StyleDefault.
+		Background(app.Styles.InverseTextColor).
+		Foreground(app.Styles.ContrastSecondaryTextColor),
+	)
+	v.form.SetBorder(true).SetTitle(" Compare schemas ").SetTitleAlign(tview.AlignLeft)
+
+	v.changes = tview.NewTable().SetSelectable(true, false).SetFixed(1, 0)
+	v.changes.SetBorder(true).SetTitle(" Changes ").SetTitleAlign(tview.AlignLeft)
+	v.changes.SetBorderColor(app.Styles.PrimaryTextColor)
+
+	v.ddl = tview.NewTextView().SetScrollable(true).SetWrap(false)
+	v.ddl.SetBorder(true).SetTitle(" DDL ").SetTitleAlign(tview.AlignLeft)
+	v.ddl.SetBorderColor(app.Styles.PrimaryTextColor)
+
+	hint := tview.NewTextView().
+		SetText("Tab to switch pane, y to copy the DDL, e to open it in the SQL editor, Esc to close").
+		SetTextAlign(tview.AlignCenter).
+		SetTextColor(app.Styles.TertiaryTextColor)
+
+	v.AddItem(v.form, 3, 0, true)
+	v.AddItem(v.changes, 0, 2, false)
+	v.AddItem(v.ddl, 0, 1, false)
+	v.AddItem(hint, 1, 0, false)
+
+	v.SetInputCapture(v.inputCapture)
+
+	// Selecting the connections loads their databases.
+	loadDatabases(v.form, 1, v.source, func(database string) { v.sourceDatabase = database })
+	loadDatabases(v.form, 3, v.target, func(database string) { v.targetDatabase = database })
+
+	return v
+}
+
+// loadDatabases fills the database dropdown at formIndex of form with the
+// databases of home, the selected one of its tree first selected.
+func loadDatabases(form *tview.Form, formIndex int, home *Home, onSelect func(database string)) {
+	dropDown := form.GetFormItem(formIndex).(*tview.DropDown)
+	dropDown.SetOptions([]string{"Loading..."}, nil)
+	onSelect("")
+
+	go func() {
+		databases, err := home.DBDriver.GetDatabases(App.Context())
+
+		App.QueueUpdateDraw(func() {
+			if err != nil {
+				dropDown.SetOptions([]string{"Error: " + err.Error()}, nil)
+				return
+			}
+
+			dropDown.SetOptions(databases, func(database string, index int) {
+				if index >= 0 {
+					onSelect(database)
+				}
+			})
+			selected := max(slices.Index(databases, home.Tree.GetSelectedDatabase()), 0)
+			if len(databases) > 0 {
+				dropDown.SetCurrentOption(selected)
+			}
+		})
+	}()
+}
+
+func (v *SchemaDiffView) inputCapture(event *tcell.EventKey) *tcell.EventKey {
+	focused := App.GetFocus()
+
+	switch event.Key() {
+	case tcell.KeyEsc:
+		if focused == v.changes || focused == v.ddl {
+			v.close()
+			return nil
+		}
+		if _, ok := focused.(*tview.List); !ok {
+			// Not the list of an open dropdown.
+			v.close()
+			return nil
+		}
+	case tcell.KeyTab, tcell.KeyBacktab:
+		if focused == v.changes {
+			App.SetFocus(v.ddl)
+			return nil
+		}
+		if focused == v.ddl {
+			App.SetFocus(v.form)
+			return nil
+		}
+	}
+
+	if focused != v.changes && focused != v.ddl {
+		return event
+	}
+
+	switch event.Rune() {
+	case 'y':
+		v.copyDDL()
+		return nil
+	case 'e':
+		v.openDDLInEditor()
+		return nil
+	}
+
+	return event
+}
+
+func (v *SchemaDiffView) compare() {
+	if v.sourceDatabase == "" || v.targetDatabase == "" {
+		v.setStatus("Select the databases to compare", tcell.ColorRed)
+		return
+	}
+
+	if v.cancel != nil {
+		v.cancel()
+	}
+	ctx, cancel := context.WithCancel(App.Context())
+	v.cancel = cancel
+
+	source, sourceDatabase := v.source, v.sourceDatabase
+	target, targetDatabase := v.target, v.targetDatabase
+
+	v.setStatus("Comparing...", app.Styles.SecondaryTextColor)
+
+	go func() {
+		sourceSchema, err := drivers.LoadSchema(ctx, source.DBDriver, sourceDatabase)
+		var targetSchema []models.TableSchema
+		if err == nil {
+			targetSchema, err = drivers.LoadSchema(ctx, target.DBDriver, targetDatabase)
+		}
+
+		App.QueueUpdateDraw(func() {
+			if ctx.Err() != nil {
+				return
+			}
+			if err != nil {
+				logger.Error("Failed to compare schemas", map[string]any{"error": err.Error()})
+				v.setStatus("Failed to compare schemas: "+err.Error(), tcell.ColorRed)
+				return
+			}
+
+			diff := drivers.DiffSchemas(sourceSchema, targetSchema)
+			v.targetHome = target
+			v.showDiff(diff, target.DBDriver)
+			App.SetFocus(v.changes)
+		})
+	}()
+}
+
+func (v *SchemaDiffView) setStatus(text string, color tcell.Color) {
+	v.changes.Clear()
+	v.changes.SetCell(0, 0, tview.NewTableCell(text).SetTextColor(color).SetSelectable(false))
+}
+
+// showDiff lists the changes, and the DDL in the dialect of target.
+func (v *SchemaDiffView) showDiff(diff *drivers.SchemaDiff, target drivers.Driver) {
+	v.changes.Clear()
+
+	if len(diff.Changes) == 0 {
+		v.setStatus("The schemas match", tcell.ColorGreen)
+		v.statements = nil
+		v.ddl.SetText("")
+		return
+	}
+
+	for j, title := range []string{"Table", "Object", "Change", "Source", "Target"} {
+		v.changes.SetCell(0, j, tview.NewTableCell(title).
+			SetTextColor(app.Styles.PrimaryTextColor).
+			SetAttributes(tcell.AttrBold).
+			SetSelectable(false))
+	}
+
+	for i, change := range diff.Changes {
+		color := tcell.ColorYellow
+		switch change.Type {
+		case models.SchemaAdded:
+			color = tcell.ColorGreen
+		case models.SchemaRemoved:
+			color = tcell.ColorRed
+		}
+
+		object := change.Kind.String()
+		if change.Name != "" {
+			object += " " + change.Name
+		}
+
+		for j, text := range []string{change.Table, object, change.Type.String(), change.Source, change.Target} {
+			v.changes.SetCell(i+1, j, tview.NewTableCell(text).SetTextColor(color).SetMaxWidth(50))
+		}
+	}
+	v.changes.Select(1, 0)
+	v.changes.SetTitle(fmt.Sprintf(" Changes (%d) ", len(diff.Changes)))
+
+	v.statements = diff.DDL(target)
+	v.ddl.SetText(strings.Join(v.statements, "\n"))
+	v.ddl.ScrollToBeginning()
+}
+
+func (v *SchemaDiffView) copyDDL() {
+	if len(v.statements) == 0 {
+		return
+	}
+
+	clipboard := lib.NewClipboard()
+	if err := clipboard.Write(strings.Join(v.statements, "\n")); err != nil {
+		logger.Error("Error copying DDL to clipboard", map[string]any{"error": err.Error()})
+		v.ddl.SetTitle(" DDL (copy failed) ")
+		return
+	}
+	v.ddl.SetTitle(" DDL (copied) ")
+}
+
+// openDDLInEditor opens the DDL in the SQL editor of the connection it was
+// generated for, if it is the one the view was opened from.
+func (v *SchemaDiffView) openDDLInEditor() {
+	if len(v.statements) == 0 {
+		return
+	}
+	if v.targetHome != v.home {
+		v.ddl.SetTitle(" DDL (copy it with y to run it on " + v.targetHome.ConnectionIdentifier + ") ")
+		return
+	}
+
+	v.close()
+	v.home.createOrFocusEditorTab()
+	if tab := v.home.TabbedPane.GetCurrentTab(); tab != nil {
+		table := tab.Content.(*ResultsTable)
+		table.Editor.SetText(strings.Join(v.statements, "\n"), true)
+	}
+}
+
+func (v *SchemaDiffView) close() {
+	if v.cancel != nil {
+		v.cancel()
+	}
+	mainPages.RemovePage(pageNameSchemaDiff)
+}
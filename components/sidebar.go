@@ -60,7 +60,9 @@ func NewSidebar(dbProvider string, readOnly bool) *Sidebar {
 	return newSidebar
 }
 
-func (sidebar *Sidebar) AddField(title, text string, fieldWidth int, pendingEdit bool) {
+// AddField adds a field showing text. A non-empty marker, e.g. "NULL", is
+// shown in italics in place of the text.
+func (sidebar *Sidebar) AddField(title, text, marker string, fieldWidth int, pendingEdit bool) {
 	field := tview.NewTextArea()
 	field.SetWrap(true)
 	field.SetDisabled(true)
@@ -71,6 +73,8 @@ func (sidebar *Sidebar) AddField(title, text string, fieldWidth int, pendingEdit
 	field.SetTitleColor(app.Styles.PrimaryTextColor)
 	field.SetText(text, true)
 	field.SetTextStyle(tcell.StyleDefault.Background(app.Styles.PrimitiveBackgroundColor).Foreground(tview.Styles.SecondaryTextColor))
+	field.SetPlaceholder(marker)
+	field.SetPlaceholderStyle(tcell.StyleDefault.Background(app.Styles.PrimitiveBackgroundColor).Foreground(tview.Styles.SecondaryTextColor).Italic(true))
 
 	if pendingEdit {
 		sidebar.SetEditedStyles(field)
@@ -289,7 +293,8 @@ func (sidebar *Sidebar) inputCapture(event *tcell.EventKey) *tcell.EventKey {
 
 			if selection >= 0 {
 				sidebar.SetEditedStyles(item)
-				item.SetText(value, true)
+				item.SetText("", true)
+				item.SetPlaceholder(value)
 				sidebar.Publish(models.StateChange{Key: eventSidebarCommitEditing, Value: models.SidebarEditingCommitParams{ColumnName: columnName, Type: selection, NewValue: value}})
 			}
 		})
@@ -315,6 +320,7 @@ func (sidebar *Sidebar) inputCapture(event *tcell.EventKey) *tcell.EventKey {
 func (sidebar *Sidebar) SetEditingStyles(item *tview.TextArea) {
 	item.SetBackgroundColor(app.Styles.SecondaryTextColor)
 	item.SetTextStyle(tcell.StyleDefault.Background(app.Styles.SecondaryTextColor).Foreground(tview.Styles.ContrastSecondaryTextColor))
+	item.SetPlaceholderStyle(tcell.StyleDefault.Background(app.Styles.SecondaryTextColor).Foreground(tview.Styles.ContrastSecondaryTextColor).Italic(true))
 	item.SetTitleColor(app.Styles.ContrastSecondaryTextColor)
 	item.SetBorderColor(app.Styles.SecondaryTextColor)
 
@@ -325,6 +331,7 @@ func (sidebar *Sidebar) SetEditingStyles(item *tview.TextArea) {
 func (sidebar *Sidebar) SetDisabledStyles(item *tview.TextArea) {
 	item.SetBackgroundColor(app.Styles.PrimitiveBackgroundColor)
 	item.SetTextStyle(tcell.StyleDefault.Background(app.Styles.PrimitiveBackgroundColor).Foreground(tview.Styles.SecondaryTextColor))
+	item.SetPlaceholderStyle(tcell.StyleDefault.Background(app.Styles.PrimitiveBackgroundColor).Foreground(tview.Styles.SecondaryTextColor).Italic(true))
 	item.SetTitleColor(app.Styles.PrimaryTextColor)
 	item.SetBorderColor(app.Styles.BorderColor)
 
@@ -335,6 +342,7 @@ func (sidebar *Sidebar) SetDisabledStyles(item *tview.TextArea) {
 func (sidebar *Sidebar) SetEditedStyles(item *tview.TextArea) {
 	item.SetBackgroundColor(colorTableChange)
 	item.SetTextStyle(tcell.StyleDefault.Background(colorTableChange).Foreground(tview.Styles.ContrastSecondaryTextColor))
+	item.SetPlaceholderStyle(tcell.StyleDefault.Background(colorTableChange).Foreground(tview.Styles.ContrastSecondaryTextColor).Italic(true))
 	item.SetTitleColor(app.Styles.ContrastSecondaryTextColor)
 	item.SetBorderColor(app.Styles.ContrastSecondaryTextColor)
 
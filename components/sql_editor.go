@@ -6,6 +6,7 @@ import (
 	"runtime"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
@@ -78,6 +79,10 @@ type SQLEditor struct {
 	selecting    bool
 	selCX, selCY int
 
+	// --- executed statement highlight ---
+	execSL, execSC, execEL, execEC int
+	execHighlightUntil             time.Time
+
 	// --- vim mode ---
 	vimMode VimMode
 
@@ -103,6 +108,9 @@ type SQLEditor struct {
 	acTableHint string
 	acVisible   bool
 
+	// --- connection status ---
+	transactionOpen func() bool
+
 	// --- existing API fields ---
 	state         *SQLEditorState
 	subscribers   []chan models.StateChange
@@ -278,8 +286,7 @@ func (e *SQLEditor) InputHandler() func(event *tcell.EventKey, setFocus func(p t
 		// In insert/visual mode, keymap (Ctrl+R = Execute) takes priority.
 		if e.vimMode == VimModeNormal {
 			// Check for keymap Execute (Ctrl+R) — still handled in normal mode
-			if cmd == commands.Execute {
-				e.Publish(eventSQLEditorQuery, e.GetText())
+			if e.executeCommand(cmd) {
 				return
 			}
 			e.handleNormalMode(event)
@@ -287,8 +294,7 @@ func (e *SQLEditor) InputHandler() func(event *tcell.EventKey, setFocus func(p t
 		}
 
 		// Insert & Visual mode: keymap commands first
-		if cmd == commands.Execute {
-			e.Publish(eventSQLEditorQuery, e.GetText())
+		if e.executeCommand(cmd) {
 			return
 		}
 
@@ -301,6 +307,132 @@ func (e *SQLEditor) InputHandler() func(event *tcell.EventKey, setFocus func(p t
 	}
 }
 
+// executeCommand publishes the query for one of the execute commands and
+// reports whether cmd was one of them.
+func (e *SQLEditor) executeCommand(cmd commands.Command) bool {
+	switch cmd {
+	case commands.Execute:
+		e.Publish(eventSQLEditorQuery, e.GetText())
+	case commands.ExecuteStatement:
+		e.executeStatementAtCursor()
+	case commands.ExecuteSelection:
+		e.executeSelection()
+	case commands.ExplainQuery:
+		e.explainCurrentQuery()
+	default:
+		return false
+	}
+	return true
+}
+
+// executeStatementAtCursor publishes only the statement around the cursor.
+func (e *SQLEditor) executeStatementAtCursor() {
+	statements := splitStatements(e.GetText())
+	index := statementAt(statements, e.cursorRuneOffset())
+	if index < 0 {
+		return
+	}
+
+	statement := statements[index]
+	sl, sc := e.positionForRuneOffset(statement.Start)
+	el, ec := e.positionForRuneOffset(statement.End)
+	e.highlightExecuted(sl, sc, el, ec)
+	e.Publish(eventSQLEditorQuery, statement.Text)
+}
+
+// executeSelection publishes the text selected in visual mode and returns
+// to normal mode. Outside of visual mode it does nothing.
+func (e *SQLEditor) executeSelection() {
+	if !e.selecting || (e.vimMode != VimModeVisual && e.vimMode != VimModeVisualLine) {
+		return
+	}
+
+	var text string
+	sl, sc, el, ec := e.getSelectionRange()
+	if e.vimMode == VimModeVisualLine {
+		sc, ec = 0, len(e.lines[el])
+		text = strings.Join(e.lines[sl:el+1], "\n")
+	} else {
+		text = e.getSelectedText()
+	}
+
+	e.vimMode = VimModeNormal
+	e.selecting = false
+
+	if strings.TrimSpace(text) == "" {
+		return
+	}
+
+	e.highlightExecuted(sl, sc, el, ec)
+	e.Publish(eventSQLEditorQuery, text)
+}
+
+// explainCurrentQuery publishes the selection in visual mode, and the
+// statement around the cursor otherwise, to be explained.
+func (e *SQLEditor) explainCurrentQuery() {
+	if e.selecting && (e.vimMode == VimModeVisual || e.vimMode == VimModeVisualLine) {
+		text := e.getSelectedText()
+		if e.vimMode == VimModeVisualLine {
+			sl, _, el, _ := e.getSelectionRange()
+			text = strings.Join(e.lines[sl:el+1], "\n")
+		}
+
+		e.vimMode = VimModeNormal
+		e.selecting = false
+
+		if strings.TrimSpace(text) != "" {
+			e.Publish(eventSQLEditorExplain, text)
+		}
+		return
+	}
+
+	statements := splitStatements(e.GetText())
+	index := statementAt(statements, e.cursorRuneOffset())
+	if index < 0 {
+		return
+	}
+
+	e.Publish(eventSQLEditorExplain, statements[index].Text)
+}
+
+// highlightExecuted briefly highlights the given range so it is visible
+// which part of the buffer was executed.
+func (e *SQLEditor) highlightExecuted(sl, sc, el, ec int) {
+	const duration = 500 * time.Millisecond
+
+	e.execSL, e.execSC, e.execEL, e.execEC = sl, sc, el, ec
+	e.execHighlightUntil = time.Now().Add(duration)
+
+	// Redraw once the highlight has expired.
+	time.AfterFunc(duration, func() {
+		app.App.QueueUpdateDraw(func() {})
+	})
+}
+
+// cursorRuneOffset returns the cursor position as a rune offset into GetText().
+func (e *SQLEditor) cursorRuneOffset() int {
+	offset := 0
+	for i := 0; i < e.cy && i < len(e.lines); i++ {
+		offset += utf8.RuneCountInString(e.lines[i]) + 1 // +1 for newline
+	}
+	line := e.lines[e.cy]
+	return offset + utf8.RuneCountInString(line[:min(e.cx, len(line))])
+}
+
+// positionForRuneOffset converts a rune offset into GetText() to a line index
+// and byte column.
+func (e *SQLEditor) positionForRuneOffset(offset int) (line, col int) {
+	for i, text := range e.lines {
+		runes := []rune(text)
+		if offset <= len(runes) {
+			return i, len(string(runes[:offset]))
+		}
+		offset -= len(runes) + 1 // +1 for newline
+	}
+	last := len(e.lines) - 1
+	return last, len(e.lines[last])
+}
+
 // ---------------------------------------------------------------------------
 // Insert mode handling
 // ---------------------------------------------------------------------------
@@ -1247,7 +1379,19 @@ func (e *SQLEditor) Draw(screen tcell.Screen) {
 
 		// Draw selection highlight
 		if inSelection {
-			e.drawSelection(screen, x, y+lineIdx, width, lineText, selStart, selEnd, e.ox)
+			e.drawSelection(screen, x, y+lineIdx, width, lineText, selStart, selEnd, e.ox, tcell.ColorDarkCyan)
+		}
+
+		// Draw highlight of the statement that was just executed
+		if time.Now().Before(e.execHighlightUntil) && bufIdx >= e.execSL && bufIdx <= e.execEL {
+			execStart, execEnd := 0, len(lineText)
+			if bufIdx == e.execSL {
+				execStart = e.execSC
+			}
+			if bufIdx == e.execEL {
+				execEnd = e.execEC
+			}
+			e.drawSelection(screen, x, y+lineIdx, width, lineText, execStart, execEnd, e.ox, tcell.ColorDarkGreen)
 		}
 	}
 
@@ -1327,12 +1471,12 @@ func (e *SQLEditor) drawLineText(screen tcell.Screen, x, y, width int, lineText
 	}
 }
 
-func (e *SQLEditor) drawSelection(screen tcell.Screen, x, y, width int, lineText string, selStart, selEnd int, ox int) {
+func (e *SQLEditor) drawSelection(screen tcell.Screen, x, y, width int, lineText string, selStart, selEnd int, ox int, bg tcell.Color) {
 	// Handle empty lines: select from start to width
 	if lineText == "" {
 		for col := 0; col < width; col++ {
 			mainc, combc, style, _ := screen.GetContent(x+col, y)
-			screen.SetContent(x+col, y, mainc, combc, style.Background(tcell.ColorDarkCyan))
+			screen.SetContent(x+col, y, mainc, combc, style.Background(bg))
 		}
 		return
 	}
@@ -1370,7 +1514,7 @@ func (e *SQLEditor) drawSelection(screen tcell.Screen, x, y, width int, lineText
 
 	for col := startCol; col < endCol; col++ {
 		mainc, combc, style, _ := screen.GetContent(x+col, y)
-		screen.SetContent(x+col, y, mainc, combc, style.Background(tcell.ColorDarkCyan))
+		screen.SetContent(x+col, y, mainc, combc, style.Background(bg))
 	}
 }
 
@@ -1396,6 +1540,18 @@ func (e *SQLEditor) drawStatusBar(screen tcell.Screen, x, y, width int, _, _ tce
 		screen.SetContent(x+i, y, ch, nil, style)
 	}
 
+	// Draw transaction marker after the mode
+	if e.transactionOpen != nil && e.transactionOpen() {
+		txStart := len(modeText) + 2
+		for i, ch := range "TRANSACTION" {
+			if txStart+i >= width {
+				break
+			}
+			style := tcell.StyleDefault.Foreground(tcell.ColorOrange).Background(statusBg).Bold(true)
+			screen.SetContent(x+txStart+i, y, ch, nil, style)
+		}
+	}
+
 	// Draw position text (right-aligned)
 	posStart := width - len(posText)
 	if posStart < 0 {
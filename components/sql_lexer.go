@@ -78,6 +78,9 @@ func tokenize(input string) []SQLToken {
 		pos:   0,
 	}
 	var tokens []SQLToken
+	// brackets is the depth of the [ ] of array subscripts, where ":" is a
+	// slice and not a named parameter.
+	brackets := 0
 
 	for l.pos < len(l.input) {
 		ch := l.input[l.pos]
@@ -93,14 +96,25 @@ func tokenize(input string) []SQLToken {
 			tokens = append(tokens, l.readQuotedIdentifier())
 		case ch == '`':
 			tokens = append(tokens, l.readBacktickIdentifier())
+		case ch == '$' && l.dollarQuoteTag() != "":
+			// Dollar-quoted string like $$ ... $$ or $body$ ... $body$
+			tokens = append(tokens, l.readDollarQuotedString())
 		case ch == '$' && l.peekRune(1) != '(' && l.peekRune(1) != '\'':
 			// Positional parameter like $1
 			tokens = append(tokens, l.readParameter())
+		case ch == '?' && (l.peek() == '|' || l.peek() == '&'):
+			// The ?| and ?& jsonb operators
+			tokens = append(tokens, SQLToken{Type: TokenOperator, Start: l.pos, End: l.pos + 2})
+			l.pos += 2
 		case ch == '?':
 			tokens = append(tokens, SQLToken{Type: TokenParameter, Start: l.pos, End: l.pos + 1})
 			l.pos++
-		case ch == ':' && l.pos+1 < len(l.input) && !unicode.IsSpace(l.input[l.pos+1]) && l.input[l.pos+1] != ':' && l.input[l.pos+1] != '=':
-			// Named parameter :name
+		case ch == ':' && (brackets > 0 || (l.pos > 0 && (l.input[l.pos-1] == ']' || isDigit(l.input[l.pos-1])))):
+			// The slice of an array, like arr[1:2]
+			tokens = append(tokens, SQLToken{Type: TokenOperator, Start: l.pos, End: l.pos + 1})
+			l.pos++
+		case ch == ':' && l.pos+1 < len(l.input) && !unicode.IsSpace(l.input[l.pos+1]) && l.input[l.pos+1] != ':' && l.input[l.pos+1] != '=' && (l.pos == 0 || l.input[l.pos-1] != ':'):
+			// Named parameter :name, but not the type of a ::cast
 			tokens = append(tokens, l.readNamedParameter())
 		case isDigit(ch) || (ch == '.' && l.pos+1 < len(l.input) && isDigit(l.input[l.pos+1])):
 			tokens = append(tokens, l.readNumber())
@@ -109,6 +123,12 @@ func tokenize(input string) []SQLToken {
 		case isOperator(ch):
 			tokens = append(tokens, l.readOperator())
 		case isPunctuation(ch):
+			switch {
+			case ch == '[':
+				brackets++
+			case ch == ']' && brackets > 0:
+				brackets--
+			}
 			tokens = append(tokens, SQLToken{Type: TokenPunctuation, Start: l.pos, End: l.pos + 1})
 			l.pos++
 		default:
@@ -216,6 +236,33 @@ func (l *sqllLexer) readBacktickIdentifier() SQLToken {
 	return SQLToken{Type: TokenIdentifier, Start: start, End: l.pos}
 }
 
+// dollarQuoteTag returns the opening tag of a dollar-quoted string starting at
+// the current position ("$$" or "$tag$"), or "" if there is none.
+func (l *sqllLexer) dollarQuoteTag() string {
+	i := l.pos + 1
+	for i < len(l.input) && (isLetter(l.input[i]) || l.input[i] == '_' || (i > l.pos+1 && isDigit(l.input[i]))) {
+		i++
+	}
+	if i < len(l.input) && l.input[i] == '$' {
+		return string(l.input[l.pos : i+1])
+	}
+	return ""
+}
+
+func (l *sqllLexer) readDollarQuotedString() SQLToken {
+	start := l.pos
+	tag := []rune(l.dollarQuoteTag())
+	l.pos += len(tag)
+	for l.pos < len(l.input) {
+		if l.input[l.pos] == '$' && l.pos+len(tag) <= len(l.input) && string(l.input[l.pos:l.pos+len(tag)]) == string(tag) {
+			l.pos += len(tag)
+			break
+		}
+		l.pos++
+	}
+	return SQLToken{Type: TokenString, Start: start, End: l.pos}
+}
+
 func (l *sqllLexer) readParameter() SQLToken {
 	start := l.pos
 	l.pos++ // skip $
@@ -0,0 +1,34 @@
+package components
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenizeParameters(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{name: "array slice", input: "SELECT arr[1:2], arr[lo:hi][2:3] FROM t", want: []string{}},
+		{name: "named parameter after a subscript", input: "SELECT arr[1] FROM t WHERE id = :id", want: []string{":id"}},
+		{name: "jsonb operators", input: "SELECT data ?| array['a'], data ?& array['b'] FROM t", want: []string{}},
+		{name: "question mark", input: "SELECT * FROM t WHERE id = ?", want: []string{"?"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runes := []rune(tt.input)
+			got := []string{}
+			for _, tok := range tokenize(tt.input) {
+				if tok.Type == TokenParameter {
+					got = append(got, string(runes[tok.Start:tok.End]))
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected parameters %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package components
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jorgerojas26/lazysql/drivers"
+)
+
+// Types a parameter value can be bound as.
+const (
+	parameterTypeText    = "Text"
+	parameterTypeInteger = "Integer"
+	parameterTypeDecimal = "Decimal"
+	parameterTypeBoolean = "Boolean"
+	parameterTypeNull    = "NULL"
+)
+
+var parameterTypes = []string{parameterTypeText, parameterTypeInteger, parameterTypeDecimal, parameterTypeBoolean, parameterTypeNull}
+
+// sqlParameter is a placeholder of a query: "$1", ":name" or "?". Every "?"
+// is its own parameter and is named "?N" after its position among the "?"
+// placeholders. Start and End are rune offsets into the query.
+type sqlParameter struct {
+	Name  string
+	Start int
+	End   int
+}
+
+// findParameters returns the placeholders of query, run on provider, in
+// order of appearance. Placeholders inside strings, comments and quoted
+// identifiers are ignored, as is "?" on PostgreSQL, where it is the jsonb
+// key exists operator.
+func findParameters(query, provider string) []sqlParameter {
+	runes := []rune(query)
+	parameters := []sqlParameter{}
+	questionMarks := 0
+
+	for _, tok := range tokenize(query) {
+		if tok.Type != TokenParameter {
+			continue
+		}
+
+		name := string(runes[tok.Start:tok.End])
+		switch {
+		case name == "?" && provider == drivers.DriverPostgres:
+			continue
+		case name == "?":
+			questionMarks++
+			name = "?" + strconv.Itoa(questionMarks)
+		case len(name) < 2:
+			// A lone "$" or ":" is not a placeholder.
+			continue
+		}
+
+		parameters = append(parameters, sqlParameter{Name: name, Start: tok.Start, End: tok.End})
+	}
+
+	return parameters
+}
+
+// parameterNames returns the distinct names of parameters in order of
+// appearance.
+func parameterNames(parameters []sqlParameter) []string {
+	names := []string{}
+	seen := map[string]bool{}
+
+	for _, parameter := range parameters {
+		if !seen[parameter.Name] {
+			seen[parameter.Name] = true
+			names = append(names, parameter.Name)
+		}
+	}
+
+	return names
+}
+
+// statementParameters returns the parameters found in statement, with their
+// offsets made relative to the statement text.
+func statementParameters(parameters []sqlParameter, statement sqlStatement) []sqlParameter {
+	inStatement := []sqlParameter{}
+
+	for _, parameter := range parameters {
+		if parameter.Start >= statement.Start && parameter.End <= statement.End {
+			inStatement = append(inStatement, sqlParameter{
+				Name:  parameter.Name,
+				Start: parameter.Start - statement.Start,
+				End:   parameter.End - statement.Start,
+			})
+		}
+	}
+
+	return inStatement
+}
+
+// bindParameters replaces the placeholders of query with the placeholders of
+// the driver, numbered from 1, and returns the args to run it with. A
+// parameter used more than once is bound once per use.
+func bindParameters(query string, parameters []sqlParameter, values map[string]any, placeholder func(index int) string) (string, []any) {
+	if len(parameters) == 0 {
+		return query, nil
+	}
+
+	runes := []rune(query)
+	args := make([]any, 0, len(parameters))
+
+	var builder strings.Builder
+	pos := 0
+	for i, parameter := range parameters {
+		builder.WriteString(string(runes[pos:parameter.Start]))
+		builder.WriteString(placeholder(i + 1))
+		args = append(args, values[parameter.Name])
+		pos = parameter.End
+	}
+	builder.WriteString(string(runes[pos:]))
+
+	return builder.String(), args
+}
+
+// parameterValue converts the text entered for a parameter to the Go value
+// of parameterType.
+func parameterValue(parameterType, value string) (any, error) {
+	switch parameterType {
+	case parameterTypeInteger:
+		number, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not an integer", value)
+		}
+		return number, nil
+	case parameterTypeDecimal:
+		number, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a decimal", value)
+		}
+		return number, nil
+	case parameterTypeBoolean:
+		boolean, err := strconv.ParseBool(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a boolean", value)
+		}
+		return boolean, nil
+	case parameterTypeNull:
+		return nil, nil
+	default:
+		return value, nil
+	}
+}
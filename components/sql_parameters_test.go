@@ -0,0 +1,130 @@
+package components
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/jorgerojas26/lazysql/drivers"
+)
+
+func TestFindParameters(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		provider string
+		want     []string
+	}{
+		{
+			name:  "positional parameters",
+			input: "SELECT * FROM users WHERE id = $1 AND age > $2",
+			want:  []string{"$1", "$2"},
+		},
+		{
+			name:  "question marks are numbered",
+			input: "SELECT * FROM users WHERE id = ? AND age > ?",
+			want:  []string{"?1", "?2"},
+		},
+		{
+			name:  "named parameters",
+			input: "SELECT * FROM users WHERE name = :name OR nickname = :name",
+			want:  []string{":name", ":name"},
+		},
+		{
+			name:  "placeholders inside strings and comments",
+			input: "SELECT '?', ':name' -- $1\nFROM users WHERE id = ?",
+			want:  []string{"?1"},
+		},
+		{
+			name:  "postgres casts and assignments",
+			input: "SELECT id::text, @total := 1 FROM users",
+			want:  []string{},
+		},
+		{
+			name:  "array slices",
+			input: "SELECT arr[1:2], arr[lo:hi], arr[:n] FROM t WHERE id = :id",
+			want:  []string{":id"},
+		},
+		{
+			name:     "postgres jsonb operators",
+			input:    "SELECT * FROM t WHERE data ? 'key' AND data ?| array['a'] AND data ?& array['b'] AND id = $1",
+			provider: drivers.DriverPostgres,
+			want:     []string{"$1"},
+		},
+		{
+			name:  "no parameters",
+			input: "SELECT 1",
+			want:  []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []string{}
+			for _, parameter := range findParameters(tt.input, tt.provider) {
+				got = append(got, parameter.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestBindParameters(t *testing.T) {
+	query := "SELECT * FROM users WHERE name = :name OR nickname = :name AND age > ?"
+	parameters := findParameters(query, drivers.DriverSqlite)
+
+	if names := parameterNames(parameters); !reflect.DeepEqual(names, []string{":name", "?1"}) {
+		t.Fatalf("unexpected parameter names %v", names)
+	}
+
+	values := map[string]any{":name": "bob", "?1": int64(30)}
+	dollar := func(index int) string { return fmt.Sprintf("$%d", index) }
+
+	got, args := bindParameters(query, parameters, values, dollar)
+
+	if want := "SELECT * FROM users WHERE name = $1 OR nickname = $2 AND age > $3"; got != want {
+		t.Errorf("expected query %q, got %q", want, got)
+	}
+	if want := []any{"bob", "bob", int64(30)}; !reflect.DeepEqual(args, want) {
+		t.Errorf("expected args %v, got %v", want, args)
+	}
+
+	script := "SELECT ?; SELECT ?"
+	statements := splitStatements(script)
+	second := statementParameters(findParameters(script, drivers.DriverSqlite), statements[1])
+	got, args = bindParameters(statements[1].Text, second, map[string]any{"?1": "a", "?2": "b"}, dollar)
+
+	if got != "SELECT $1" || !reflect.DeepEqual(args, []any{"b"}) {
+		t.Errorf("expected second statement to bind ?2, got %q %v", got, args)
+	}
+}
+
+func TestParameterValue(t *testing.T) {
+	tests := []struct {
+		parameterType string
+		value         string
+		want          any
+		wantErr       bool
+	}{
+		{parameterTypeText, " 42 ", " 42 ", false},
+		{parameterTypeInteger, " 42 ", int64(42), false},
+		{parameterTypeInteger, "4.2", nil, true},
+		{parameterTypeDecimal, "4.2", 4.2, false},
+		{parameterTypeBoolean, "true", true, false},
+		{parameterTypeBoolean, "yes", nil, true},
+		{parameterTypeNull, "ignored", nil, false},
+	}
+
+	for _, tt := range tests {
+		got, err := parameterValue(tt.parameterType, tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parameterValue(%s, %q): unexpected error %v", tt.parameterType, tt.value, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parameterValue(%s, %q) = %#v, expected %#v", tt.parameterType, tt.value, got, tt.want)
+		}
+	}
+}
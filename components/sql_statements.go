@@ -0,0 +1,205 @@
+package components
+
+import (
+	"strings"
+	"unicode"
+)
+
+// sqlStatement is one statement of an editor buffer. Start and End are rune
+// offsets into the buffer and cover the statement text without the
+// terminating semicolon and without surrounding whitespace or comments.
+type sqlStatement struct {
+	Text  string
+	Start int
+	End   int
+}
+
+// splitStatements splits input into statements at top-level semicolons and
+// at the GO lines separating the batches of SQL Server. Semicolons inside
+// strings, comments and quoted identifiers are part of those tokens, so they
+// never end a statement, and neither do those inside the BEGIN ... END body
+// of a trigger, procedure or function. Statements made only of whitespace
+// and comments are dropped.
+func splitStatements(input string) []sqlStatement {
+	runes := []rune(input)
+	tokens := tokenize(input)
+	statements := []sqlStatement{}
+	start, end := -1, -1
+
+	// words are the first words of the statement, telling whether it
+	// creates a routine, whose body is split at nothing but its end.
+	var words []string
+	routine := false
+	depth := 0
+
+	mark := func(from, to int) {
+		if start < 0 {
+			start = from
+		}
+		end = to
+	}
+
+	flush := func() {
+		if start >= 0 {
+			statements = append(statements, sqlStatement{
+				Text:  string(runes[start:end]),
+				Start: start,
+				End:   end,
+			})
+		}
+		start, end = -1, -1
+		words, routine, depth = nil, false, 0
+	}
+
+	pos := 0
+	for i, tok := range tokens {
+		// The lexer skips characters it does not know (e.g. '@' or '#'),
+		// they still belong to the statement.
+		if tok.Start > pos {
+			mark(pos, tok.Start)
+		}
+		pos = tok.End
+
+		word := tokenWord(runes, tok)
+		if word != "" && len(words) < routineWords {
+			words = append(words, word)
+			routine = routine || createsRoutine(words)
+		}
+
+		switch {
+		case tok.Type == TokenPunctuation && runes[tok.Start] == ';' && depth == 0:
+			flush()
+		case word == "GO" && isBatchSeparator(runes, tok):
+			flush()
+		case tok.Type == TokenWhitespace || tok.Type == TokenComment:
+		default:
+			mark(tok.Start, tok.End)
+
+			if !routine {
+				break
+			}
+			switch word {
+			case "BEGIN":
+				switch nextWord(runes, tokens[i+1:]) {
+				case "TRAN", "TRANSACTION", "WORK", "DEFERRED", "IMMEDIATE", "EXCLUSIVE":
+				default:
+					depth++
+				}
+			case "CASE":
+				depth++
+			case "END":
+				switch nextWord(runes, tokens[i+1:]) {
+				// Closing blocks that are not counted.
+				case "IF", "LOOP", "WHILE", "REPEAT":
+				default:
+					if depth > 0 {
+						depth--
+					}
+				}
+			}
+		}
+	}
+	if pos < len(runes) {
+		mark(pos, len(runes))
+	}
+	flush()
+
+	return statements
+}
+
+// routineWords is how many words of a statement are looked at to tell
+// whether it creates a routine, e.g. CREATE OR REPLACE TEMP TRIGGER.
+const routineWords = 5
+
+// createsRoutine reports whether words, the first words of a statement,
+// create a trigger, procedure, function or event.
+func createsRoutine(words []string) bool {
+	if words[0] != "CREATE" {
+		return false
+	}
+	for _, word := range words[1:] {
+		switch word {
+		case "TRIGGER", "PROCEDURE", "PROC", "FUNCTION", "EVENT":
+			return true
+		}
+	}
+	return false
+}
+
+// tokenWord returns tok in upper case if it is a word, e.g. a keyword or an
+// identifier not quoted.
+func tokenWord(runes []rune, tok SQLToken) string {
+	if tok.Start >= tok.End || !(isLetter(runes[tok.Start]) || runes[tok.Start] == '_') {
+		return ""
+	}
+	return strings.ToUpper(string(runes[tok.Start:tok.End]))
+}
+
+// nextWord returns the first word of tokens, skipping whitespace and
+// comments.
+func nextWord(runes []rune, tokens []SQLToken) string {
+	for _, tok := range tokens {
+		if tok.Type == TokenWhitespace || tok.Type == TokenComment {
+			continue
+		}
+		return tokenWord(runes, tok)
+	}
+	return ""
+}
+
+// isBatchSeparator reports whether tok, the word GO, is alone on its line.
+func isBatchSeparator(runes []rune, tok SQLToken) bool {
+	for i := tok.Start - 1; i >= 0 && runes[i] != '\n'; i-- {
+		if !unicode.IsSpace(runes[i]) {
+			return false
+		}
+	}
+	for i := tok.End; i < len(runes) && runes[i] != '\n'; i++ {
+		if !unicode.IsSpace(runes[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// statementAt returns the index of the statement at rune offset pos: the one
+// containing it, otherwise the closest one before it, otherwise the first one.
+// It returns -1 if there are no statements.
+func statementAt(statements []sqlStatement, pos int) int {
+	if len(statements) == 0 {
+		return -1
+	}
+
+	index := 0
+	for i, statement := range statements {
+		if statement.Start > pos {
+			break
+		}
+		index = i
+	}
+	return index
+}
+
+// returnsRows reports whether query is expected to return a result set and
+// must be run with ExecuteQuery instead of ExecuteDMLStatement.
+func returnsRows(query string) bool {
+	queryTrimmed := strings.TrimSpace(strings.ToLower(query))
+
+	return strings.HasPrefix(queryTrimmed, "select") ||
+		strings.HasPrefix(queryTrimmed, "with") ||
+		strings.HasPrefix(queryTrimmed, "explain") ||
+		strings.HasPrefix(queryTrimmed, "show") ||
+		strings.HasPrefix(queryTrimmed, "describe") ||
+		strings.HasPrefix(queryTrimmed, "desc")
+}
+
+// changesSchema reports whether query creates, alters or drops a database
+// object, after which the tree has to be refreshed.
+func changesSchema(query string) bool {
+	queryTrimmed := strings.TrimSpace(strings.ToLower(query))
+
+	return strings.HasPrefix(queryTrimmed, "create") ||
+		strings.HasPrefix(queryTrimmed, "alter") ||
+		strings.HasPrefix(queryTrimmed, "drop") ||
+		strings.HasPrefix(queryTrimmed, "rename")
+}
@@ -0,0 +1,163 @@
+package components
+
+import (
+	"testing"
+)
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{
+			name:  "single statement without semicolon",
+			input: "SELECT * FROM users",
+			want:  []string{"SELECT * FROM users"},
+		},
+		{
+			name:  "multiple statements",
+			input: "SELECT 1;\nUPDATE users SET name = 'a';\nDELETE FROM users;",
+			want:  []string{"SELECT 1", "UPDATE users SET name = 'a'", "DELETE FROM users"},
+		},
+		{
+			name:  "semicolon inside string",
+			input: "SELECT 'a;b'; SELECT 2",
+			want:  []string{"SELECT 'a;b'", "SELECT 2"},
+		},
+		{
+			name:  "semicolon inside quoted identifiers",
+			input: "SELECT \"a;b\", `c;d` FROM t; SELECT 2",
+			want:  []string{"SELECT \"a;b\", `c;d` FROM t", "SELECT 2"},
+		},
+		{
+			name:  "semicolon inside comments",
+			input: "-- first; query\nSELECT 1 /* ; */ FROM t; SELECT 2",
+			want:  []string{"SELECT 1 /* ; */ FROM t", "SELECT 2"},
+		},
+		{
+			name:  "dollar quoted function body",
+			input: "CREATE FUNCTION f() RETURNS int AS $$ BEGIN RETURN 1; END; $$ LANGUAGE plpgsql; SELECT f()",
+			want:  []string{"CREATE FUNCTION f() RETURNS int AS $$ BEGIN RETURN 1; END; $$ LANGUAGE plpgsql", "SELECT f()"},
+		},
+		{
+			name:  "sqlite trigger body",
+			input: "CREATE TRIGGER audit AFTER INSERT ON users BEGIN INSERT INTO log VALUES (new.id); UPDATE stats SET n = n + 1; END; SELECT 1",
+			want:  []string{"CREATE TRIGGER audit AFTER INSERT ON users BEGIN INSERT INTO log VALUES (new.id); UPDATE stats SET n = n + 1; END", "SELECT 1"},
+		},
+		{
+			name:  "mysql procedure body",
+			input: "CREATE DEFINER=`root`@`%` PROCEDURE p(IN x INT)\nBEGIN\n  IF x > 0 THEN\n    SELECT CASE WHEN x > 1 THEN 'a' ELSE 'b' END;\n  END IF;\n  BEGIN\n    SELECT 2;\n  END;\nEND;\nCALL p(1)",
+			want: []string{
+				"CREATE DEFINER=`root`@`%` PROCEDURE p(IN x INT)\nBEGIN\n  IF x > 0 THEN\n    SELECT CASE WHEN x > 1 THEN 'a' ELSE 'b' END;\n  END IF;\n  BEGIN\n    SELECT 2;\n  END;\nEND",
+				"CALL p(1)",
+			},
+		},
+		{
+			name:  "sql server batches",
+			input: "CREATE PROCEDURE p AS\nBEGIN\n  BEGIN TRAN;\n  UPDATE t SET a = 1;\n  COMMIT;\nEND\nGO\nEXEC p\ngo\nSELECT 1",
+			want:  []string{"CREATE PROCEDURE p AS\nBEGIN\n  BEGIN TRAN;\n  UPDATE t SET a = 1;\n  COMMIT;\nEND", "EXEC p", "SELECT 1"},
+		},
+		{
+			name:  "case outside a routine",
+			input: "SELECT CASE WHEN a THEN 1 END FROM t; SELECT 2",
+			want:  []string{"SELECT CASE WHEN a THEN 1 END FROM t", "SELECT 2"},
+		},
+		{
+			name:  "empty statements and comments only",
+			input: ";; -- nothing here\n ; /* still nothing */",
+			want:  []string{},
+		},
+		{
+			name:  "characters unknown to the lexer",
+			input: "DECLARE @id INT; SELECT @id",
+			want:  []string{"DECLARE @id INT", "SELECT @id"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := splitStatements(tt.input)
+			if len(got) != len(tt.want) {
+				t.Fatalf("expected %d statements, got %d: %#v", len(tt.want), len(got), got)
+			}
+			for i, statement := range got {
+				if statement.Text != tt.want[i] {
+					t.Errorf("statement %d: expected %q, got %q", i, tt.want[i], statement.Text)
+				}
+				if text := string([]rune(tt.input)[statement.Start:statement.End]); text != statement.Text {
+					t.Errorf("statement %d: offsets point to %q, expected %q", i, text, statement.Text)
+				}
+			}
+		})
+	}
+}
+
+func TestStatementAt(t *testing.T) {
+	input := "SELECT 1;\n\nSELECT 2; SELECT 3"
+	statements := splitStatements(input)
+
+	tests := []struct {
+		name string
+		pos  int
+		want int
+	}{
+		{"inside first statement", 3, 0},
+		{"right after semicolon", 9, 0},
+		{"blank line between statements", 10, 0},
+		{"start of second statement", 11, 1},
+		{"inside last statement", len(input), 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := statementAt(statements, tt.pos); got != tt.want {
+				t.Errorf("expected statement %d, got %d", tt.want, got)
+			}
+		})
+	}
+
+	if got := statementAt(nil, 0); got != -1 {
+		t.Errorf("expected -1 without statements, got %d", got)
+	}
+}
+
+func TestReturnsRows(t *testing.T) {
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{"SELECT 1", true},
+		{"  with t as (select 1) select * from t", true},
+		{"EXPLAIN SELECT 1", true},
+		{"SHOW TABLES", true},
+		{"DESCRIBE users", true},
+		{"UPDATE users SET name = 'a'", false},
+		{"INSERT INTO users VALUES (1)", false},
+	}
+
+	for _, tt := range tests {
+		if got := returnsRows(tt.query); got != tt.want {
+			t.Errorf("returnsRows(%q) = %v, expected %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestChangesSchema(t *testing.T) {
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{"CREATE TABLE users (id INT)", true},
+		{"  alter table users add column name text", true},
+		{"DROP VIEW active_users", true},
+		{"SELECT 1", false},
+		{"INSERT INTO users VALUES (1)", false},
+	}
+
+	for _, tt := range tests {
+		if got := changesSchema(tt.query); got != tt.want {
+			t.Errorf("changesSchema(%q) = %v, expected %v", tt.query, got, tt.want)
+		}
+	}
+}
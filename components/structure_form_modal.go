@@ -0,0 +1,207 @@
+package components
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/jorgerojas26/lazysql/app"
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+// StructureFormModal is a modal for the definition of a column or an index
+// to stage from the Columns, Constraints or Indexes view of a table.
+type StructureFormModal struct {
+	tview.Primitive
+	form     *tview.Form
+	errorBox *tview.TextView
+	onSubmit func() error
+}
+
+// NewColumnFormModal creates a StructureFormModal for column, which is
+// empty for a new column.
+func NewColumnFormModal(title string, column models.ColumnSchema, onSubmit func(column models.ColumnSchema) error) *StructureFormModal {
+	modal := newStructureFormModal(title, 15)
+
+	modal.form.
+		AddInputField("Name", column.Name, 40, nil, func(text string) { column.Name = strings.TrimSpace(text) }).
+		AddInputField("Type", column.Type, 40, nil, func(text string) { column.Type = strings.TrimSpace(text) }).
+		AddCheckbox("Nullable", column.Nullable, func(checked bool) { column.Nullable = checked }).
+		AddInputField("Default", column.Default, 40, nil, func(text string) { column.Default = strings.TrimSpace(text) })
+
+	modal.onSubmit = func() error { return onSubmit(column) }
+	modal.addButtons("Stage")
+
+	return modal
+}
+
+// NewIndexFormModal creates a StructureFormModal for a new index. The
+// columns are entered separated by commas.
+func NewIndexFormModal(title string, index models.IndexSchema, onSubmit func(index models.IndexSchema) error) *StructureFormModal {
+	modal := newStructureFormModal(title, 13)
+
+	columns := strings.Join(index.Columns, ", ")
+	modal.form.
+		AddInputField("Name", index.Name, 40, nil, func(text string) { index.Name = strings.TrimSpace(text) }).
+		AddInputField("Columns", columns, 40, nil, func(text string) { columns = text })
+	if !index.Constraint {
+		modal.form.AddCheckbox("Unique", index.Unique, func(checked bool) { index.Unique = checked })
+	}
+
+	modal.onSubmit = func() error {
+		index.Columns = nil
+		for _, column := range strings.Split(columns, ",") {
+			if column = strings.TrimSpace(column); column != "" {
+				index.Columns = append(index.Columns, column)
+			}
+		}
+		return onSubmit(index)
+	}
+	modal.addButtons("Stage")
+
+	return modal
+}
+
+// newTableColumnFormModal creates a StructureFormModal for a column of a new
+// table. Its type is one of types, with an optional size, and it may
+// reference a column of one of tables, whose columns are listed by
+// referencedColumns.
+func newTableColumnFormModal(title string, column newTableColumn, types, tables []string, referencedColumns func(table string) ([]string, error), onSubmit func(column newTableColumn) error) *StructureFormModal {
+	modal := newStructureFormModal(title, 23)
+
+	baseType, size := splitColumnType(column.Type)
+	if baseType != "" && !slices.ContainsFunc(types, func(t string) bool { return strings.EqualFold(t, baseType) }) {
+		types = append(slices.Clone(types), baseType)
+	}
+	typeIndex := max(slices.IndexFunc(types, func(t string) bool { return strings.EqualFold(t, baseType) }), 0)
+
+	references := append([]string{""}, tables...)
+	referenceIndex := max(slices.Index(references, column.References), 0)
+
+	modal.form.
+		AddInputField("Name", column.Name, 40, nil, func(text string) { column.Name = strings.TrimSpace(text) }).
+		AddDropDown("Type", types, typeIndex, func(option string, _ int) { baseType = option }).
+		AddInputField("Size", size, 20, nil, func(text string) { size = strings.TrimSpace(text) }).
+		AddCheckbox("Nullable", column.Nullable, func(checked bool) { column.Nullable = checked }).
+		AddInputField("Default", column.Default, 40, nil, func(text string) { column.Default = strings.TrimSpace(text) }).
+		AddCheckbox("Primary key", column.PrimaryKey, func(checked bool) { column.PrimaryKey = checked }).
+		AddDropDown("References", references, referenceIndex, nil).
+		AddDropDown("Column", []string{}, 0, nil)
+
+	referencedColumn := modal.form.GetFormItemByLabel("Column").(*tview.DropDown)
+	selectReferencedColumn := func(option string, index int) {
+		if index >= 0 {
+			column.ReferencedColumn = option
+		}
+	}
+	loadReferencedColumns := func(table string, index int) {
+		if index <= 0 {
+			column.References, column.ReferencedColumn = "", ""
+			referencedColumn.SetOptions([]string{}, nil)
+			return
+		}
+
+		if column.References != table {
+			column.References, column.ReferencedColumn = table, ""
+		}
+		referencedColumn.SetOptions([]string{"Loading..."}, nil)
+
+		go func() {
+			columns, err := referencedColumns(table)
+
+			App.QueueUpdateDraw(func() {
+				if column.References != table {
+					return
+				}
+				if err != nil {
+					referencedColumn.SetOptions([]string{}, nil)
+					modal.errorBox.SetText(" " + err.Error())
+					return
+				}
+
+				referencedColumn.SetOptions(columns, selectReferencedColumn)
+				if len(columns) > 0 {
+					referencedColumn.SetCurrentOption(max(slices.Index(columns, column.ReferencedColumn), 0))
+				}
+			})
+		}()
+	}
+	modal.form.GetFormItemByLabel("References").(*tview.DropDown).SetSelectedFunc(loadReferencedColumns)
+	loadReferencedColumns(column.References, referenceIndex)
+
+	modal.onSubmit = func() error {
+		column.Type = baseType
+		if size != "" {
+			column.Type += "(" + size + ")"
+		}
+		return onSubmit(column)
+	}
+	modal.addButtons("Save")
+
+	return modal
+}
+
+// splitColumnType splits a column type, e.g. "varchar(255)", into its base
+// type and its size.
+func splitColumnType(columnType string) (baseType, size string) {
+	baseType, size, _ = strings.Cut(columnType, "(")
+	return strings.TrimSpace(baseType), strings.TrimSuffix(strings.TrimSpace(size), ")")
+}
+
+func newStructureFormModal(title string, height int) *StructureFormModal {
+	modal := &StructureFormModal{
+		form:     tview.NewForm(),
+		errorBox: tview.NewTextView().SetTextColor(tcell.ColorRed),
+	}
+
+	modal.form.SetFieldStyle(
+		tcell.StyleDefault.
+			Background(app.Styles.SecondaryTextColor).
+			Foreground(app.Styles.ContrastSecondaryTextColor),
+	).SetButtonActivatedStyle(tcell.StyleDefault.
+		Background(app.Styles.SecondaryTextColor).
+		Foreground(app.Styles.ContrastSecondaryTextColor),
+	).SetButtonStyle(tcell.StyleDefault.
+		Background(app.Styles.InverseTextColor).
+		Foreground(app.Styles.ContrastSecondaryTextColor),
+	)
+
+	modal.form.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if _, ok := App.GetFocus().(*tview.List); event.Key() == tcell.KeyEsc && !ok {
+			// Not the list of an open dropdown.
+			modal.close()
+			return nil
+		}
+		return event
+	})
+
+	container := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(modal.form, 0, 1, true).
+		AddItem(modal.errorBox, 1, 0, false)
+	container.SetBorder(true).SetTitle(" " + title + " ").SetTitleAlign(tview.AlignLeft)
+
+	modal.Primitive = tview.NewGrid().
+		SetRows(0, height, 0).
+		SetColumns(0, 60, 0).
+		AddItem(container, 1, 1, 1, 1, 0, 0, true)
+
+	return modal
+}
+
+func (modal *StructureFormModal) addButtons(submitLabel string) {
+	modal.form.
+		AddButton(submitLabel, func() {
+			if err := modal.onSubmit(); err != nil {
+				modal.errorBox.SetText(" " + err.Error())
+				return
+			}
+			modal.close()
+		}).
+		AddButton("Cancel", modal.close)
+}
+
+func (modal *StructureFormModal) close() {
+	mainPages.RemovePage(pageNameStructureForm)
+}
@@ -49,7 +49,7 @@ func NewTabbedPane() *TabbedPane {
 	return tabbedPane
 }
 
-func (t *TabbedPane) AppendTab(name string, content TabContent, reference string) {
+func (t *TabbedPane) AppendTab(name string, content TabContent, reference string) *Tab {
 	textView := tview.NewTextView()
 	textView.SetText(name)
 	item := &Header{textView}
@@ -79,6 +79,8 @@ func (t *TabbedPane) AppendTab(name string, content TabContent, reference string
 	t.HighlightTabHeader(newTab)
 
 	t.AddAndSwitchToPage(reference, content.GetPrimitive(), true)
+
+	return newTab
 }
 
 func (t *TabbedPane) RemoveCurrentTab() *Tab {
@@ -123,6 +125,33 @@ func (t *TabbedPane) RemoveCurrentTab() *Tab {
 	return nil
 }
 
+// RemoveTab removes tab. The current tab is only changed if it is tab, see
+// RemoveCurrentTab.
+func (t *TabbedPane) RemoveTab(tab *Tab) {
+	if tab == t.state.CurrentTab {
+		t.RemoveCurrentTab()
+		return
+	}
+
+	t.HeaderContainer.RemoveItem(tab.Header)
+	t.RemovePage(tab.Reference)
+
+	t.state.Length--
+
+	if tab == t.state.FirstTab {
+		t.state.FirstTab = tab.NextTab
+	}
+	if tab == t.state.LastTab {
+		t.state.LastTab = tab.PreviousTab
+	}
+	if tab.PreviousTab != nil {
+		tab.PreviousTab.NextTab = tab.NextTab
+	}
+	if tab.NextTab != nil {
+		tab.NextTab.PreviousTab = tab.PreviousTab
+	}
+}
+
 func (t *TabbedPane) SetCurrentTab(tab *Tab) *Tab {
 	t.state.CurrentTab = tab
 	t.HighlightTabHeader(tab)
@@ -163,6 +192,16 @@ func (t *TabbedPane) GetTabByReference(reference string) *Tab {
 	return tab
 }
 
+// HasTab reports whether tab is still one of the tabs, i.e. was not removed.
+func (t *TabbedPane) HasTab(tab *Tab) bool {
+	for current := t.state.FirstTab; current != nil; current = current.NextTab {
+		if current == tab {
+			return true
+		}
+	}
+	return false
+}
+
 func (t *TabbedPane) GetLength() int {
 	return t.state.Length
 }
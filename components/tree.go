@@ -1,6 +1,7 @@
 package components
 
 import (
+	"context"
 	"fmt"
 	"maps"
 	"slices"
@@ -35,6 +36,9 @@ type Tree struct {
 	FoundNodeCountInput *tview.InputField
 	subscribers         []chan models.StateChange
 	Schemas             []string
+	// dbName is the database of the connection, empty if the tree shows
+	// all of them.
+	dbName string
 }
 
 type TreeNodeType int
@@ -46,8 +50,49 @@ const (
 	NodeTypeFunction
 	NodeTypeProcedure
 	NodeTypeView
+	NodeTypeTrigger
+	NodeTypeSequence
+	NodeTypeMaterializedView
+	NodeTypeType
 )
 
+// objectSection is a section of the tree listing the objects of a kind other
+// than tables and routines. It is only shown when the database has some.
+type objectSection struct {
+	name     string
+	nodeType TreeNodeType
+	list     func(db drivers.Driver, ctx context.Context, database string) (map[string][]string, error)
+}
+
+// objectSections are listed after the views, in this order.
+var objectSections = []objectSection{
+	{name: "triggers", nodeType: NodeTypeTrigger, list: drivers.Driver.GetTriggers},
+	{name: "sequences", nodeType: NodeTypeSequence, list: drivers.Driver.GetSequences},
+	{name: "materialized views", nodeType: NodeTypeMaterializedView, list: drivers.Driver.GetMaterializedViews},
+	{name: "types", nodeType: NodeTypeType, list: drivers.Driver.GetTypes},
+}
+
+// sectionNodeType returns the type of the nodes of section.
+func sectionNodeType(section string) TreeNodeType {
+	switch section {
+	case "tables":
+		return NodeTypeTable
+	case "procedures":
+		return NodeTypeProcedure
+	case "functions":
+		return NodeTypeFunction
+	case "views":
+		return NodeTypeView
+	}
+
+	for _, objectSection := range objectSections {
+		if objectSection.name == section {
+			return objectSection.nodeType
+		}
+	}
+	return NodeTypeSection
+}
+
 type TreeNodeData struct {
 	Type     TreeNodeType
 	Database string
@@ -80,33 +125,11 @@ func (tree *Tree) GetTreeNodeData(node *tview.TreeNode) *TreeNodeData {
 		nodeType = NodeTypeSection
 	case len(split) == 3 && !useSchemas && supportsProgramming:
 		// Flat (non-schema) items: [database, section, name]
-		switch parentType := split[len(split)-2]; parentType {
-		case "tables":
-			nodeType = NodeTypeTable
-		case "procedures":
-			nodeType = NodeTypeProcedure
-		case "functions":
-			nodeType = NodeTypeFunction
-		case "views":
-			nodeType = NodeTypeView
-		default:
-			nodeType = NodeTypeSection
-		}
+		nodeType = sectionNodeType(split[len(split)-2])
 	case len(split) == 4 && useSchemas && supportsProgramming:
 		// Items under a schema: [database, schema, section, name]
 		schema = split[1]
-		switch split[2] {
-		case "tables":
-			nodeType = NodeTypeTable
-		case "procedures":
-			nodeType = NodeTypeProcedure
-		case "functions":
-			nodeType = NodeTypeFunction
-		case "views":
-			nodeType = NodeTypeView
-		default:
-			nodeType = NodeTypeSection
-		}
+		nodeType = sectionNodeType(split[2])
 	default:
 		nodeType = NodeTypeSection
 	}
@@ -134,6 +157,7 @@ func NewTree(dbName string, dbdriver drivers.Driver, schemas []string) *Tree {
 		Filter:              tview.NewInputField(),
 		FoundNodeCountInput: tview.NewInputField(),
 		Schemas:             schemas,
+		dbName:              dbName,
 	}
 
 	tree.SetTopLevel(1)
@@ -213,6 +237,9 @@ func NewTree(dbName string, dbdriver drivers.Driver, schemas []string) *Tree {
 			} else {
 				tree.SetSelectedView(fmt.Sprintf("%s.%s", nodeData.Schema, nodeData.Name))
 			}
+		case NodeTypeTrigger, NodeTypeSequence, NodeTypeMaterializedView, NodeTypeType:
+			tree.SetSelectedDatabase(nodeData.Database)
+			tree.Publish(models.StateChange{Key: eventTreeShowDefinition, Value: nodeData})
 		default:
 			break
 		}
@@ -261,6 +288,24 @@ func NewTree(dbName string, dbdriver drivers.Driver, schemas []string) *Tree {
 			tree.ExpandAll()
 		case commands.Refresh:
 			tree.Refresh(dbName)
+		case commands.NewTable:
+			if node := tree.GetCurrentNode(); node != nil && node != rootNode {
+				tree.Publish(models.StateChange{Key: eventTreeNewTable, Value: tree.newTableLocation(node)})
+			}
+		case commands.ShowDefinition:
+			if node := tree.GetCurrentNode(); node != nil && node != rootNode {
+				tree.Publish(models.StateChange{Key: eventTreeShowDefinition, Value: tree.GetTreeNodeData(node)})
+			}
+		case commands.RefreshMaterializedView:
+			if node := tree.GetCurrentNode(); node != nil && node != rootNode {
+				if nodeData := tree.GetTreeNodeData(node); nodeData.Type == NodeTypeMaterializedView {
+					tree.Publish(models.StateChange{Key: eventTreeRefreshMaterializedView, Value: nodeData})
+				}
+			}
+		case commands.ShowERDiagram:
+			if node := tree.GetCurrentNode(); node != nil && node != rootNode {
+				tree.Publish(models.StateChange{Key: eventTreeShowERDiagram, Value: tree.newTableLocation(node)})
+			}
 		}
 		return nil
 	})
@@ -592,6 +637,84 @@ func (tree *Tree) addProgrammingNodes(functions map[string][]string, procedures
 	}
 }
 
+// getObjects lists the objects of the objectSections of database, keyed by
+// section. A section that fails to load is left out of the tree.
+func (tree *Tree) getObjects(database string) map[string]map[string][]string {
+	objects := make(map[string]map[string][]string)
+	for _, section := range objectSections {
+		items, err := section.list(tree.DBDriver, App.Context(), database)
+		if err != nil {
+			logger.Error("Failed to list the "+section.name, map[string]any{"error": err.Error(), "database": database})
+			continue
+		}
+		objects[section.name] = items
+	}
+	return objects
+}
+
+// addObjectSections adds the objectSections of database having objects, as
+// read by getObjects, after the views of node. With schemas, they go under
+// the schema nodes built by buildSchemaTree.
+func (tree *Tree) addObjectSections(database string, node *tview.TreeNode, objects map[string]map[string][]string) {
+	if !tree.DBDriver.UseSchemas() {
+		for _, section := range objectSections {
+			items := slices.Sorted(slices.Values(objects[section.name][database]))
+			if len(items) == 0 {
+				continue
+			}
+
+			sectionReference := fmt.Sprintf("%s.%s", node.GetReference().(string), section.name)
+			sectionNode := tview.NewTreeNode(section.name)
+			sectionNode.SetExpanded(false)
+			sectionNode.SetReference(sectionReference)
+			sectionNode.SetColor(app.Styles.PrimaryTextColor)
+			node.AddChild(sectionNode)
+
+			for _, item := range items {
+				itemNode := tview.NewTreeNode(item)
+				itemNode.SetExpanded(false)
+				itemNode.SetColor(app.Styles.PrimaryTextColor)
+				itemNode.SetReference(fmt.Sprintf("%s.%s", sectionReference, item))
+				sectionNode.AddChild(itemNode)
+			}
+		}
+		return
+	}
+
+	schemaNodes := make(map[string]*tview.TreeNode)
+	for _, schemaNode := range node.GetChildren() {
+		schemaNodes[schemaNode.GetReference().(string)] = schemaNode
+	}
+
+	for _, section := range objectSections {
+		schemas := make(map[string]struct{})
+		for _, item := range objects[section.name][database] {
+			if idx := strings.IndexByte(item, '.'); idx > 0 {
+				schemas[item[:idx]] = struct{}{}
+			}
+		}
+
+		for _, schema := range slices.Sorted(maps.Keys(schemas)) {
+			if len(tree.Schemas) > 0 && !slices.Contains(tree.Schemas, schema) {
+				continue
+			}
+
+			// A schema with no tables nor routines.
+			schemaNode, ok := schemaNodes[schema]
+			if !ok {
+				schemaNode = tview.NewTreeNode(schema)
+				schemaNode.SetExpanded(false)
+				schemaNode.SetReference(schema)
+				schemaNode.SetColor(app.Styles.PrimaryTextColor)
+				node.AddChild(schemaNode)
+				schemaNodes[schema] = schemaNode
+			}
+
+			tree.addSchemaProgrammingSection(schemaNode, database, schema, section.name, objects[section.name])
+		}
+	}
+}
+
 // stripColorTags removes tview color formatting like [black:primary] from node text
 func stripColorTags(text string) string {
 	for {
@@ -1096,23 +1219,33 @@ func (tree *Tree) InitializeNodes(dbName string) {
 		panic("Internal Error: No tree root")
 	}
 
-	var databases []string
+	if dbName != "" {
+		tree.addDatabaseNodes(rootNode, dbName, []string{sanitizeDBName(dbName)})
+		return
+	}
 
-	if dbName == "" {
-		dbs, err := tree.DBDriver.GetDatabases()
+	// Listing the databases may wait for the connection to be opened again,
+	// so it does not run on the UI goroutine.
+	go func() {
+		dbs, err := tree.DBDriver.GetDatabases(App.Context())
 		if err != nil {
-			panic(err.Error())
+			logger.Error("Failed to list the databases", map[string]any{"error": err})
+			return
 		}
 		sanitizedDbs := make([]string, 0, len(dbs))
 		for _, db := range dbs {
 			sanitizedDbs = append(sanitizedDbs, sanitizeDBName(db))
 		}
-		databases = sanitizedDbs
-	} else {
-		sanitizedDBName := sanitizeDBName(dbName)
-		databases = []string{sanitizedDBName}
-	}
 
+		App.QueueUpdateDraw(func() {
+			tree.addDatabaseNodes(rootNode, dbName, sanitizedDbs)
+		})
+	}()
+}
+
+// addDatabaseNodes adds the nodes of databases to rootNode, loading their
+// objects in the background.
+func (tree *Tree) addDatabaseNodes(rootNode *tview.TreeNode, dbName string, databases []string) {
 	for _, database := range databases {
 		childNode := tview.NewTreeNode(database)
 		childNode.SetExpanded(dbName != "")
@@ -1121,7 +1254,7 @@ func (tree *Tree) InitializeNodes(dbName string) {
 		rootNode.AddChild(childNode)
 
 		go func(database string, node *tview.TreeNode) {
-			tables, err := tree.DBDriver.GetTables(database)
+			tables, err := tree.DBDriver.GetTables(App.Context(), database)
 			if err != nil {
 				logger.Error(err.Error(), nil)
 				return
@@ -1131,24 +1264,27 @@ func (tree *Tree) InitializeNodes(dbName string) {
 			useSchemas := tree.DBDriver.UseSchemas()
 
 			var functions, procedures, views map[string][]string
+			var objects map[string]map[string][]string
 			if supportsProgramming {
-				functions, err = tree.DBDriver.GetFunctions(database)
+				functions, err = tree.DBDriver.GetFunctions(App.Context(), database)
 				if err != nil {
 					logger.Error(err.Error(), nil)
 					return
 				}
 
-				procedures, err = tree.DBDriver.GetProcedures(database)
+				procedures, err = tree.DBDriver.GetProcedures(App.Context(), database)
 				if err != nil {
 					logger.Error(err.Error(), nil)
 					return
 				}
 
-				views, err = tree.DBDriver.GetViews(database)
+				views, err = tree.DBDriver.GetViews(App.Context(), database)
 				if err != nil {
 					logger.Error(err.Error(), nil)
 					return
 				}
+
+				objects = tree.getObjects(database)
 			}
 
 			if useSchemas {
@@ -1159,12 +1295,34 @@ func (tree *Tree) InitializeNodes(dbName string) {
 					tree.addProgrammingNodes(functions, procedures, views, node)
 				}
 			}
+			if supportsProgramming {
+				tree.addObjectSections(database, node, objects)
+			}
 
 			App.Draw()
 		}(database, childNode)
 	}
 }
 
+// newTableLocation returns the database, and the schema for the databases
+// that use schemas, of node, where a table created from it goes and what
+// the ER diagram opened from it shows.
+func (tree *Tree) newTableLocation(node *tview.TreeNode) *TreeNodeData {
+	path := tree.GetPath(node)
+	location := &TreeNodeData{Type: NodeTypeDatabase}
+	if len(path) < 2 {
+		return location
+	}
+
+	location.Database = path[1].GetReference().(string)
+	// Schema nodes are the children of the database nodes, see
+	// buildSchemaTree.
+	if tree.DBDriver.UseSchemas() && len(path) > 2 {
+		location.Schema = path[2].GetReference().(string)
+	}
+	return location
+}
+
 func (tree *Tree) Refresh(dbName string) {
 	rootNode := tree.GetRoot()
 	rootNode.ClearChildren()
@@ -1,7 +1,9 @@
 package components
 
 import (
+	"context"
 	"fmt"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -429,38 +431,110 @@ var _ drivers.Driver = (*schemaProgrammingMock)(nil)
 
 type schemaProgrammingMock struct{}
 
-func (m *schemaProgrammingMock) Connect(string) error                               { return nil }
-func (m *schemaProgrammingMock) TestConnection(string) error                        { return nil }
-func (m *schemaProgrammingMock) GetDatabases() ([]string, error)                    { return nil, nil }
-func (m *schemaProgrammingMock) GetTables(string) (map[string][]string, error)      { return nil, nil }
-func (m *schemaProgrammingMock) GetTableColumns(string, string) ([][]string, error) { return nil, nil }
-func (m *schemaProgrammingMock) GetConstraints(string, string) ([][]string, error)  { return nil, nil }
-func (m *schemaProgrammingMock) GetForeignKeys(string, string) ([][]string, error)  { return nil, nil }
-func (m *schemaProgrammingMock) GetIndexes(string, string) ([][]string, error)      { return nil, nil }
-func (m *schemaProgrammingMock) GetRecords(string, string, string, string, int, int) ([][]string, int, string, error) {
+func (m *schemaProgrammingMock) Connect(context.Context, string) error          { return nil }
+func (m *schemaProgrammingMock) TestConnection(context.Context, string) error   { return nil }
+func (m *schemaProgrammingMock) Ping(context.Context) error                     { return nil }
+func (m *schemaProgrammingMock) GetDatabases(context.Context) ([]string, error) { return nil, nil }
+func (m *schemaProgrammingMock) GetTables(context.Context, string) (map[string][]string, error) {
+	return nil, nil
+}
+func (m *schemaProgrammingMock) GetTableColumns(context.Context, string, string) ([][]string, error) {
+	return nil, nil
+}
+func (m *schemaProgrammingMock) GetConstraints(context.Context, string, string) ([][]string, error) {
+	return nil, nil
+}
+func (m *schemaProgrammingMock) GetForeignKeys(context.Context, string, string) ([][]string, error) {
+	return nil, nil
+}
+func (m *schemaProgrammingMock) GetIndexes(context.Context, string, string) ([][]string, error) {
+	return nil, nil
+}
+func (m *schemaProgrammingMock) GetRecords(context.Context, string, string, string, string, int, int) ([]models.Record, int, string, error) {
 	return nil, 0, "", nil
 }
-func (m *schemaProgrammingMock) UpdateRecord(string, string, string, string, string, string) error {
+func (m *schemaProgrammingMock) UpdateRecord(context.Context, string, string, string, string, string, string) error {
 	return nil
 }
-func (m *schemaProgrammingMock) DeleteRecord(string, string, string, string) error { return nil }
-func (m *schemaProgrammingMock) ExecuteDMLStatement(string) (string, error)        { return "", nil }
-func (m *schemaProgrammingMock) ExecuteQuery(string) ([][]string, int, error)      { return nil, 0, nil }
-func (m *schemaProgrammingMock) ExecutePendingChanges([]models.DBDMLChange) error  { return nil }
-func (m *schemaProgrammingMock) GetProvider() string                               { return "mock" }
-func (m *schemaProgrammingMock) GetPrimaryKeyColumnNames(string, string) ([]string, error) {
+func (m *schemaProgrammingMock) DeleteRecord(context.Context, string, string, string, string) error {
+	return nil
+}
+func (m *schemaProgrammingMock) ExecuteDMLStatement(context.Context, string, ...any) (string, error) {
+	return "", nil
+}
+func (m *schemaProgrammingMock) ExecuteQuery(context.Context, string, ...any) ([]models.Record, int, error) {
+	return nil, 0, nil
+}
+func (m *schemaProgrammingMock) QueryRows(context.Context, string, ...any) (drivers.Rows, error) {
+	return nil, nil
+}
+func (m *schemaProgrammingMock) ExplainQuery(context.Context, string, ...any) (*models.PlanNode, error) {
+	return nil, nil
+}
+func (m *schemaProgrammingMock) ExecutePendingChanges(context.Context, []models.DBDMLChange) error {
+	return nil
+}
+func (m *schemaProgrammingMock) GetProvider() string { return "mock" }
+func (m *schemaProgrammingMock) GetPrimaryKeyColumnNames(context.Context, string, string) ([]string, error) {
+	return nil, nil
+}
+func (m *schemaProgrammingMock) BeginTransaction(context.Context) error { return nil }
+func (m *schemaProgrammingMock) CommitTransaction() error               { return nil }
+func (m *schemaProgrammingMock) RollbackTransaction() error             { return nil }
+func (m *schemaProgrammingMock) InTransaction() bool                    { return false }
+func (m *schemaProgrammingMock) SupportsProgramming() bool              { return true }
+func (m *schemaProgrammingMock) UseSchemas() bool                       { return true }
+func (m *schemaProgrammingMock) GetFunctions(context.Context, string) (map[string][]string, error) {
+	return nil, nil
+}
+func (m *schemaProgrammingMock) GetProcedures(context.Context, string) (map[string][]string, error) {
+	return nil, nil
+}
+func (m *schemaProgrammingMock) GetViews(context.Context, string) (map[string][]string, error) {
 	return nil, nil
 }
-func (m *schemaProgrammingMock) SupportsProgramming() bool                            { return true }
-func (m *schemaProgrammingMock) UseSchemas() bool                                     { return true }
-func (m *schemaProgrammingMock) GetFunctions(string) (map[string][]string, error)     { return nil, nil }
-func (m *schemaProgrammingMock) GetProcedures(string) (map[string][]string, error)    { return nil, nil }
-func (m *schemaProgrammingMock) GetViews(string) (map[string][]string, error)         { return nil, nil }
-func (m *schemaProgrammingMock) GetFunctionDefinition(string, string) (string, error) { return "", nil }
-func (m *schemaProgrammingMock) GetProcedureDefinition(string, string) (string, error) {
+func (m *schemaProgrammingMock) GetFunctionDefinition(context.Context, string, string) (string, error) {
 	return "", nil
 }
-func (m *schemaProgrammingMock) GetViewDefinition(string, string) (string, error) { return "", nil }
+func (m *schemaProgrammingMock) GetProcedureDefinition(context.Context, string, string) (string, error) {
+	return "", nil
+}
+func (m *schemaProgrammingMock) GetViewDefinition(context.Context, string, string) (string, error) {
+	return "", nil
+}
+func (m *schemaProgrammingMock) GetTableDefinition(context.Context, string, string) (string, error) {
+	return "", nil
+}
+func (m *schemaProgrammingMock) GetReferencingForeignKeys(context.Context, string, string) ([]models.ReferencingForeignKey, error) {
+	return nil, nil
+}
+func (m *schemaProgrammingMock) GetTriggers(context.Context, string) (map[string][]string, error) {
+	return nil, nil
+}
+func (m *schemaProgrammingMock) GetSequences(context.Context, string) (map[string][]string, error) {
+	return nil, nil
+}
+func (m *schemaProgrammingMock) GetMaterializedViews(context.Context, string) (map[string][]string, error) {
+	return nil, nil
+}
+func (m *schemaProgrammingMock) GetTypes(context.Context, string) (map[string][]string, error) {
+	return nil, nil
+}
+func (m *schemaProgrammingMock) GetTriggerDefinition(context.Context, string, string) (string, error) {
+	return "", nil
+}
+func (m *schemaProgrammingMock) GetSequenceDefinition(context.Context, string, string) (string, error) {
+	return "", nil
+}
+func (m *schemaProgrammingMock) GetMaterializedViewDefinition(context.Context, string, string) (string, error) {
+	return "", nil
+}
+func (m *schemaProgrammingMock) GetTypeDefinition(context.Context, string, string) (string, error) {
+	return "", nil
+}
+func (m *schemaProgrammingMock) RefreshMaterializedView(context.Context, string, string) error {
+	return nil
+}
 
 func (m *schemaProgrammingMock) FormatArg(arg any, _ models.CellValueType) any {
 	return arg
@@ -771,6 +845,82 @@ func TestGetTreeNodeDataSchemaProgramming_TableItem(t *testing.T) {
 	}
 }
 
+func TestAddObjectSections_Schemas(t *testing.T) {
+	tree := &Tree{DBDriver: &schemaProgrammingMock{}}
+
+	dbNode := tview.NewTreeNode("mydb")
+	dbNode.SetReference("mydb")
+	tree.buildSchemaTree("mydb", dbNode, map[string][]string{"public": {"users"}}, nil, nil, nil)
+
+	tree.addObjectSections("mydb", dbNode, map[string]map[string][]string{
+		"triggers":           {"mydb": {"public.users_touch"}},
+		"materialized views": {"mydb": {"reports.daily_sales"}},
+		"types":              {"mydb": {"public.mood"}},
+	})
+
+	// ── "reports" has no tables, it gets a schema node of its own ──
+	children := dbNode.GetChildren()
+	if len(children) != 2 {
+		t.Fatalf("expected 2 schema nodes, got %d", len(children))
+	}
+
+	var sections []string
+	for _, section := range children[0].GetChildren() {
+		sections = append(sections, section.GetText())
+	}
+	if expected := []string{"tables", "triggers", "types"}; !reflect.DeepEqual(sections, expected) {
+		t.Errorf("expected sections %v under public, got %v", expected, sections)
+	}
+
+	matviewsSection := children[1].GetChildren()[0]
+	if matviewsSection.GetText() != "materialized views" {
+		t.Fatalf("expected the materialized views section under reports, got '%s'", matviewsSection.GetText())
+	}
+
+	data := tree.GetTreeNodeData(matviewsSection.GetChildren()[0])
+	expected := &TreeNodeData{Type: NodeTypeMaterializedView, Database: "mydb", Schema: "reports", Name: "daily_sales"}
+	if !reflect.DeepEqual(data, expected) {
+		t.Errorf("expected %+v, got %+v", expected, data)
+	}
+}
+
+func TestAddObjectSections_Flat(t *testing.T) {
+	tree := &Tree{DBDriver: &flatProgrammingMock{}}
+
+	dbNode := tview.NewTreeNode("mydb")
+	dbNode.SetReference("mydb")
+
+	tree.addObjectSections("mydb", dbNode, map[string]map[string][]string{
+		"triggers":  {"mydb": {"users_touch", "orders_audit"}},
+		"sequences": {},
+	})
+
+	// ── sections without objects are left out ──
+	children := dbNode.GetChildren()
+	if len(children) != 1 || children[0].GetText() != "triggers" {
+		t.Fatalf("expected a single triggers section, got %d sections", len(children))
+	}
+
+	triggers := children[0].GetChildren()
+	if len(triggers) != 2 || triggers[0].GetText() != "orders_audit" {
+		t.Fatalf("expected the triggers sorted, got %d triggers", len(triggers))
+	}
+
+	data := tree.GetTreeNodeData(triggers[0])
+	expected := &TreeNodeData{Type: NodeTypeTrigger, Database: "mydb", Name: "orders_audit"}
+	if !reflect.DeepEqual(data, expected) {
+		t.Errorf("expected %+v, got %+v", expected, data)
+	}
+}
+
+// flatProgrammingMock is a schemaProgrammingMock for the databases without
+// schemas, e.g. MySQL.
+type flatProgrammingMock struct {
+	schemaProgrammingMock
+}
+
+func (m *flatProgrammingMock) UseSchemas() bool { return false }
+
 // ── search ancestor-walk tests ─────────────────────────────────────────────────
 
 func TestSearch_TwoPartFindsDeepNodeThroughSectionHeaders(t *testing.T) {
@@ -1198,3 +1348,42 @@ func TestSearch_SinglePartExactMatchDoesNotCollapseSiblings(t *testing.T) {
 		t.Errorf("expected unqualified search to keep pre-existing fuzzy behavior (should still include 'dado'), got %v", foundNames)
 	}
 }
+
+// ── newTableLocation ────────────────────────────────────────────────────────
+
+func TestNewTableLocation(t *testing.T) {
+	tree := &Tree{DBDriver: &schemaProgrammingMock{}, TreeView: tview.NewTreeView()}
+
+	root := tview.NewTreeNode("-")
+	root.SetReference("-")
+	tree.SetRoot(root)
+
+	dbNode := tview.NewTreeNode("mydb")
+	dbNode.SetReference("mydb")
+	root.AddChild(dbNode)
+
+	tree.buildSchemaTree("mydb", dbNode, map[string][]string{"sales": {"orders"}}, nil, nil, nil)
+
+	schemaNode := dbNode.GetChildren()[0]
+	tableNode := schemaNode.GetChildren()[0].GetChildren()[0]
+
+	tests := []struct {
+		name     string
+		node     *tview.TreeNode
+		database string
+		schema   string
+	}{
+		{"database", dbNode, "mydb", ""},
+		{"schema", schemaNode, "mydb", "sales"},
+		{"table", tableNode, "mydb", "sales"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			location := tree.newTableLocation(tt.node)
+			if location.Database != tt.database || location.Schema != tt.schema {
+				t.Errorf("expected %s.%s, got %s.%s", tt.database, tt.schema, location.Database, location.Schema)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package drivers
+
+import (
+	"strings"
+
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+// ColumnTypes returns the column types offered for the columns of a new
+// table of provider, the most common first.
+func ColumnTypes(provider string) []string {
+	switch provider {
+	case DriverMySQL:
+		return []string{
+			"INT", "BIGINT", "SMALLINT", "TINYINT", "DECIMAL", "FLOAT", "DOUBLE", "BOOLEAN",
+			"VARCHAR", "CHAR", "TEXT", "MEDIUMTEXT", "LONGTEXT",
+			"DATE", "DATETIME", "TIMESTAMP", "TIME", "YEAR",
+			"JSON", "BLOB", "BINARY", "VARBINARY",
+		}
+	case DriverPostgres:
+		return []string{
+			"integer", "bigint", "smallint", "serial", "bigserial", "numeric", "real", "double precision", "boolean",
+			"varchar", "char", "text",
+			"date", "timestamp", "timestamptz", "time", "interval",
+			"uuid", "json", "jsonb", "bytea", "inet",
+		}
+	case DriverMSSQL:
+		return []string{
+			"INT", "BIGINT", "SMALLINT", "TINYINT", "DECIMAL", "FLOAT", "REAL", "BIT",
+			"NVARCHAR", "VARCHAR", "NCHAR", "CHAR", "NVARCHAR(MAX)",
+			"DATE", "DATETIME2", "DATETIMEOFFSET", "TIME",
+			"UNIQUEIDENTIFIER", "VARBINARY",
+		}
+	case DriverSqlite:
+		return []string{"INTEGER", "REAL", "NUMERIC", "TEXT", "BLOB"}
+	default:
+		return nil
+	}
+}
+
+// CreateTableQuery returns the CREATE TABLE statement of table, a new table
+// of database, in the dialect of db. Its foreign keys are declared in the
+// statement, and reference tables of the same database.
+//
+// MySQL and MSSQL tables are qualified by database, SQL Server doesn't
+// allow it for the referenced tables though, and MSSQL tables without a
+// schema go to dbo. Postgres tables are created in the database of the
+// connection.
+func CreateTableQuery(db Driver, database string, table models.TableSchema) string {
+	w := &ddlWriter{provider: db.GetProvider(), reference: db.FormatReference}
+
+	name := table.Name
+	switch {
+	case w.provider == DriverMySQL && database != "":
+		name = database + "." + name
+	case w.provider == DriverMSSQL && database != "":
+		if !strings.Contains(name, ".") {
+			name = "dbo." + name
+		}
+		name = database + "." + name
+	}
+
+	definitions := w.tableDefinitions(table)
+	for _, key := range table.ForeignKeys {
+		if w.provider == DriverMySQL && database != "" {
+			key.ReferencedTable = database + "." + key.ReferencedTable
+		}
+
+		definition := w.foreignKeyDefinition(key)
+		if key.Name != "" {
+			definition = "CONSTRAINT " + w.reference(key.Name) + " " + definition
+		}
+		definitions = append(definitions, definition)
+	}
+
+	w.add("CREATE TABLE %s (\n  %s\n);", w.table(name), strings.Join(definitions, ",\n  "))
+
+	return w.statements[0]
+}
@@ -0,0 +1,131 @@
+package drivers
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+func TestCreateTableQuery(t *testing.T) {
+	orders := models.TableSchema{
+		Name: "orders",
+		Columns: []models.ColumnSchema{
+			{Name: "id", Type: "INT"},
+			{Name: "user_id", Type: "INT", Nullable: true},
+			{Name: "status", Type: "VARCHAR(20)", Default: "'new'"},
+		},
+		PrimaryKey:  &models.ConstraintSchema{Columns: []string{"id"}},
+		ForeignKeys: []models.ForeignKeySchema{{Name: "orders_user_id_fkey", Columns: []string{"user_id"}, ReferencedTable: "users", ReferencedColumns: []string{"id"}}},
+	}
+	postgresOrders := orders
+	postgresOrders.Name = "sales.orders"
+	postgresOrders.ForeignKeys = []models.ForeignKeySchema{{Columns: []string{"user_id"}, ReferencedTable: "public.users", ReferencedColumns: []string{"id"}}}
+	mssqlOrders := orders
+	mssqlOrders.ForeignKeys = []models.ForeignKeySchema{{Name: "orders_user_id_fkey", Columns: []string{"user_id"}, ReferencedTable: "users", ReferencedColumns: []string{"id"}}}
+
+	tests := []struct {
+		name     string
+		db       Driver
+		table    models.TableSchema
+		expected string
+	}{
+		{
+			name:  "MySQL",
+			db:    &MySQL{Provider: DriverMySQL},
+			table: orders,
+			expected: "CREATE TABLE `shop`.`orders` (\n" +
+				"  `id` INT NOT NULL,\n" +
+				"  `user_id` INT,\n" +
+				"  `status` VARCHAR(20) NOT NULL DEFAULT 'new',\n" +
+				"  PRIMARY KEY (`id`),\n" +
+				"  CONSTRAINT `orders_user_id_fkey` FOREIGN KEY (`user_id`) REFERENCES `shop`.`users` (`id`)\n" +
+				");",
+		},
+		{
+			name:  "Postgres",
+			db:    &Postgres{Provider: DriverPostgres},
+			table: postgresOrders,
+			expected: `CREATE TABLE "sales"."orders" (` + "\n" +
+				`  "id" INT NOT NULL,` + "\n" +
+				`  "user_id" INT,` + "\n" +
+				`  "status" VARCHAR(20) NOT NULL DEFAULT 'new',` + "\n" +
+				`  PRIMARY KEY ("id"),` + "\n" +
+				`  FOREIGN KEY ("user_id") REFERENCES "public"."users" ("id")` + "\n" +
+				");",
+		},
+		{
+			name:  "MSSQL",
+			db:    &MSSQL{Provider: DriverMSSQL},
+			table: mssqlOrders,
+			expected: "CREATE TABLE [shop].[dbo].[orders] (\n" +
+				"  [id] INT NOT NULL,\n" +
+				"  [user_id] INT,\n" +
+				"  [status] VARCHAR(20) NOT NULL DEFAULT 'new',\n" +
+				"  PRIMARY KEY ([id]),\n" +
+				"  CONSTRAINT [orders_user_id_fkey] FOREIGN KEY ([user_id]) REFERENCES [users] ([id])\n" +
+				");",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := CreateTableQuery(tt.db, "shop", tt.table)
+			if query != tt.expected {
+				t.Fatalf("Query mismatch:\nexpected: %s\ngot: %s", tt.expected, query)
+			}
+		})
+	}
+}
+
+func TestCreateTableQuery_SQLite(t *testing.T) {
+	ctx := context.Background()
+
+	db := &SQLite{}
+	if err := db.Connect(ctx, filepath.Join(t.TempDir(), "test.db")); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	t.Cleanup(func() { db.Connection.Close() })
+	if _, err := db.Connection.ExecContext(ctx, "CREATE TABLE users (id INTEGER PRIMARY KEY)"); err != nil {
+		t.Fatalf("CREATE TABLE failed: %v", err)
+	}
+
+	query := CreateTableQuery(db, "main", models.TableSchema{
+		Name: "orders",
+		Columns: []models.ColumnSchema{
+			{Name: "id", Type: "INTEGER"},
+			{Name: "user_id", Type: "INTEGER", Nullable: true},
+		},
+		PrimaryKey:  &models.ConstraintSchema{Columns: []string{"id"}},
+		ForeignKeys: []models.ForeignKeySchema{{Name: "orders_user_id_fkey", Columns: []string{"user_id"}, ReferencedTable: "users", ReferencedColumns: []string{"id"}}},
+	})
+	if _, err := db.ExecuteDMLStatement(ctx, query); err != nil {
+		t.Fatalf("Running %s failed: %v", query, err)
+	}
+
+	tables, err := db.GetTables(ctx, "main")
+	if err != nil {
+		t.Fatalf("GetTables failed: %v", err)
+	}
+	if !slices.Contains(tables["main"], "orders") {
+		t.Fatalf("Expected the orders table to be created, got %v", tables)
+	}
+
+	foreignKeys, err := db.GetForeignKeys(ctx, "main", "orders")
+	if err != nil {
+		t.Fatalf("GetForeignKeys failed: %v", err)
+	}
+	if len(foreignKeys) != 2 {
+		t.Fatalf("Expected the foreign key to be created, got %v", foreignKeys)
+	}
+}
+
+func TestColumnTypes(t *testing.T) {
+	for _, provider := range []string{DriverMySQL, DriverPostgres, DriverMSSQL, DriverSqlite} {
+		if len(ColumnTypes(provider)) == 0 {
+			t.Errorf("Expected column types for %s", provider)
+		}
+	}
+}
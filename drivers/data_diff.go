@@ -0,0 +1,342 @@
+package drivers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+const (
+	// DataDiffPageSize is the number of rows a data diff reads at once from
+	// each side.
+	DataDiffPageSize = 500
+	// DataDiffLimit is the number of differing rows after which a data diff
+	// stops.
+	DataDiffLimit = 10000
+)
+
+// DataDiff holds the rows that differ between a table of two databases.
+type DataDiff struct {
+	Table string
+	// Columns are the compared columns, the columns of the source that the
+	// target has too, in the order of the source.
+	Columns []string
+	// PrimaryKey holds the indexes in Columns of the primary key columns.
+	PrimaryKey []int
+	Rows       []models.RowDiff
+	// Truncated is true if the diff stopped at DataDiffLimit rows.
+	Truncated bool
+}
+
+// tablePager reads the rows of one side of a data diff a page at a time,
+// sorted by primary key.
+type tablePager struct {
+	db       Driver
+	database string
+	table    string
+	sort     string
+	// columns holds the index in the records of the driver of each compared
+	// column.
+	columns    []int
+	primaryKey []int
+
+	header   models.Record
+	rows     []models.Record
+	offset   int
+	total    int
+	done     bool
+	previous models.Record
+	onPage   func()
+}
+
+func newTablePager(db Driver, database, table string, primaryKey []string, onPage func()) *tablePager {
+	sort := make([]string, len(primaryKey))
+	for i, column := range primaryKey {
+		sort[i] = db.FormatReference(column) + " ASC"
+	}
+
+	return &tablePager{
+		db:       db,
+		database: database,
+		table:    table,
+		sort:     strings.Join(sort, ", "),
+		onPage:   onPage,
+	}
+}
+
+func (p *tablePager) fetch(ctx context.Context) error {
+	records, total, _, err := p.db.GetRecords(ctx, p.database, p.table, "", p.sort, p.offset, DataDiffPageSize)
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		return fmt.Errorf("no columns read from %s", p.table)
+	}
+
+	p.header = records[0]
+	p.rows = records[1:]
+	p.offset += len(p.rows)
+	p.total = total
+	p.done = len(p.rows) < DataDiffPageSize
+	p.onPage()
+	return nil
+}
+
+// next returns the compared columns of the next row, nil after the last
+// one.
+func (p *tablePager) next(ctx context.Context) (models.Record, error) {
+	if len(p.rows) == 0 {
+		if p.done {
+			return nil, nil
+		}
+		if err := p.fetch(ctx); err != nil {
+			return nil, err
+		}
+		if len(p.rows) == 0 {
+			return nil, nil
+		}
+	}
+
+	row := make(models.Record, len(p.columns))
+	for i, index := range p.columns {
+		row[i] = p.rows[0][index]
+	}
+	p.rows = p.rows[1:]
+
+	// The merge relies on both sides being sorted as compareKeys sorts them,
+	// which a collation of the database may not.
+	if p.previous != nil && compareKeys(p.previous, row, p.primaryKey) >= 0 {
+		return nil, fmt.Errorf("the rows of %s in %s are not sorted by primary key as expected, check the collation of its key columns", p.table, p.database)
+	}
+	p.previous = row
+
+	return row, nil
+}
+
+// DiffTableData compares the rows of table in the source and target
+// databases. Both sides are read through GetRecords sorted by primary key
+// and merged, so that only a page of each is in memory at once. progress
+// is called with the rows read and the total rows of both sides after each
+// page.
+func DiffTableData(ctx context.Context, source, target Driver, sourceDatabase, targetDatabase, table string, progress func(read, total int)) (*DataDiff, error) {
+	primaryKey, err := source.GetPrimaryKeyColumnNames(ctx, sourceDatabase, table)
+	if err != nil {
+		return nil, err
+	}
+	if len(primaryKey) == 0 {
+		return nil, fmt.Errorf("%s has no primary key", table)
+	}
+	targetPrimaryKey, err := target.GetPrimaryKeyColumnNames(ctx, targetDatabase, table)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(strings.Join(primaryKey, ","), strings.Join(targetPrimaryKey, ",")) {
+		return nil, fmt.Errorf("the primary keys of %s differ: (%s) and (%s)", table, strings.Join(primaryKey, ", "), strings.Join(targetPrimaryKey, ", "))
+	}
+
+	var sourcePager, targetPager *tablePager
+	onPage := func() {
+		if progress != nil && sourcePager != nil && targetPager != nil {
+			progress(sourcePager.offset+targetPager.offset, sourcePager.total+targetPager.total)
+		}
+	}
+	sourcePager = newTablePager(source, sourceDatabase, table, primaryKey, onPage)
+	targetPager = newTablePager(target, targetDatabase, table, primaryKey, onPage)
+
+	if err := sourcePager.fetch(ctx); err != nil {
+		return nil, err
+	}
+	if err := targetPager.fetch(ctx); err != nil {
+		return nil, err
+	}
+
+	diff := &DataDiff{Table: table}
+	if err := diff.matchColumns(sourcePager, targetPager, primaryKey); err != nil {
+		return nil, err
+	}
+
+	sourceRow, err := sourcePager.next(ctx)
+	if err != nil {
+		return nil, err
+	}
+	targetRow, err := targetPager.next(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for sourceRow != nil || targetRow != nil {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if len(diff.Rows) >= DataDiffLimit {
+			diff.Truncated = true
+			break
+		}
+
+		order := 0
+		switch {
+		case targetRow == nil:
+			order = -1
+		case sourceRow == nil:
+			order = 1
+		default:
+			order = compareKeys(sourceRow, targetRow, diff.PrimaryKey)
+		}
+
+		if order < 0 {
+			diff.Rows = append(diff.Rows, models.RowDiff{Type: models.DMLInsertType, Source: sourceRow})
+		} else if order > 0 {
+			diff.Rows = append(diff.Rows, models.RowDiff{Type: models.DMLDeleteType, Target: targetRow})
+		} else if changed := changedColumns(sourceRow, targetRow); len(changed) > 0 {
+			diff.Rows = append(diff.Rows, models.RowDiff{Type: models.DMLUpdateType, Source: sourceRow, Target: targetRow, Changed: changed})
+		}
+
+		if order <= 0 {
+			if sourceRow, err = sourcePager.next(ctx); err != nil {
+				return nil, err
+			}
+		}
+		if order >= 0 {
+			if targetRow, err = targetPager.next(ctx); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return diff, nil
+}
+
+// matchColumns sets the compared columns, the columns of the source that
+// the target has too.
+func (d *DataDiff) matchColumns(source, target *tablePager, primaryKey []string) error {
+	targetColumns := make(map[string]int, len(target.header))
+	for i, column := range target.header {
+		targetColumns[strings.ToLower(column.String())] = i
+	}
+
+	for i, column := range source.header {
+		targetIndex, ok := targetColumns[strings.ToLower(column.String())]
+		if !ok {
+			continue
+		}
+		d.Columns = append(d.Columns, column.String())
+		source.columns = append(source.columns, i)
+		target.columns = append(target.columns, targetIndex)
+	}
+
+	for _, key := range primaryKey {
+		index := -1
+		for i, column := range d.Columns {
+			if strings.EqualFold(column, key) {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			return fmt.Errorf("primary key column %s of %s is missing", key, d.Table)
+		}
+		d.PrimaryKey = append(d.PrimaryKey, index)
+	}
+	source.primaryKey = d.PrimaryKey
+	target.primaryKey = d.PrimaryKey
+
+	return nil
+}
+
+// compareKeys compares the primary key values of two rows, as numbers for
+// numeric columns and byte by byte otherwise.
+func compareKeys(a, b models.Record, primaryKey []int) int {
+	for _, index := range primaryKey {
+		if order := compareValues(a[index], b[index]); order != 0 {
+			return order
+		}
+	}
+	return 0
+}
+
+func compareValues(a, b models.Value) int {
+	switch {
+	case a.Null && b.Null:
+		return 0
+	case a.Null:
+		return -1
+	case b.Null:
+		return 1
+	}
+
+	if a.IsNumeric() || b.IsNumeric() {
+		x, xOk := new(big.Rat).SetString(a.String())
+		y, yOk := new(big.Rat).SetString(b.String())
+		if xOk && yOk {
+			return x.Cmp(y)
+		}
+	}
+
+	return bytes.Compare(a.Raw, b.Raw)
+}
+
+// changedColumns returns the indexes of the columns whose values differ.
+func changedColumns(source, target models.Record) []int {
+	var changed []int
+	for i := range source {
+		if compareValues(source[i], target[i]) != 0 {
+			changed = append(changed, i)
+		}
+	}
+	return changed
+}
+
+// DML returns the statements that make the table of the target match the
+// source, in the dialect of db, the driver of the target.
+func (d *DataDiff) DML(db Driver, database string) ([]string, error) {
+	statements := make([]string, 0, len(d.Rows))
+
+	for _, row := range d.Rows {
+		change := models.DBDMLChange{Database: database, Table: d.Table, Type: row.Type}
+
+		switch row.Type {
+		case models.DMLInsertType:
+			for i, value := range row.Source {
+				change.Values = append(change.Values, dataDiffCellValue(d.Columns[i], value))
+			}
+		case models.DMLUpdateType:
+			for _, i := range row.Changed {
+				change.Values = append(change.Values, dataDiffCellValue(d.Columns[i], row.Source[i]))
+			}
+			change.PrimaryKeyInfo = d.primaryKeyInfo(row.Target)
+		case models.DMLDeleteType:
+			change.PrimaryKeyInfo = d.primaryKeyInfo(row.Target)
+		}
+
+		statement, err := db.DMLChangeToQueryString(change)
+		if err != nil {
+			return nil, err
+		}
+		statements = append(statements, statement+";")
+	}
+
+	return statements, nil
+}
+
+func (d *DataDiff) primaryKeyInfo(row models.Record) []models.PrimaryKeyInfo {
+	info := make([]models.PrimaryKeyInfo, len(d.PrimaryKey))
+	for i, index := range d.PrimaryKey {
+		info[i] = models.PrimaryKeyInfo{Name: d.Columns[index], Value: row[index].String()}
+	}
+	return info
+}
+
+func dataDiffCellValue(column string, value models.Value) models.CellValue {
+	switch {
+	case value.Null:
+		return models.CellValue{Column: column, Type: models.Null}
+	case value.IsEmpty():
+		return models.CellValue{Column: column, Type: models.Empty}
+	default:
+		return models.CellValue{Column: column, Value: value.String(), Type: models.String}
+	}
+}
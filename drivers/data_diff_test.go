@@ -0,0 +1,115 @@
+package drivers
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+func TestDiffTableData_SQLite(t *testing.T) {
+	ctx := context.Background()
+
+	open := func(statements ...string) *SQLite {
+		t.Helper()
+
+		db := &SQLite{}
+		if err := db.Connect(ctx, filepath.Join(t.TempDir(), "test.db")); err != nil {
+			t.Fatalf("Connect failed: %v", err)
+		}
+		t.Cleanup(func() { db.Connection.Close() })
+
+		for _, statement := range statements {
+			if _, err := db.Connection.ExecContext(ctx, statement); err != nil {
+				t.Fatalf("%s: %v", statement, err)
+			}
+		}
+		return db
+	}
+
+	source := open(
+		"CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT, email TEXT, age INTEGER)",
+		"INSERT INTO users VALUES (1, 'Alice', 'alice@example.com', 30), (2, 'Bob', NULL, 25), (10, 'Carol', '', 40)",
+	)
+	target := open(
+		"CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT, email TEXT, nickname TEXT)",
+		"INSERT INTO users VALUES (1, 'Alice', 'alice@example.com', 'al'), (2, 'Bobby', 'bob@example.com', NULL), (3, 'Dave', NULL, NULL)",
+	)
+
+	var read, total int
+	diff, err := DiffTableData(ctx, source, target, "test", "test", "users", func(r, t int) { read, total = r, t })
+	if err != nil {
+		t.Fatalf("DiffTableData failed: %v", err)
+	}
+
+	if expected := []string{"id", "name", "email"}; !reflect.DeepEqual(diff.Columns, expected) {
+		t.Fatalf("Columns mismatch: expected %v, got %v", expected, diff.Columns)
+	}
+
+	types := make([]models.DMLType, len(diff.Rows))
+	for i, row := range diff.Rows {
+		types[i] = row.Type
+	}
+	expectedTypes := []models.DMLType{models.DMLUpdateType, models.DMLDeleteType, models.DMLInsertType}
+	if !reflect.DeepEqual(types, expectedTypes) {
+		t.Fatalf("Row types mismatch: expected %v, got %v", expectedTypes, types)
+	}
+	if expected := []int{1, 2}; !reflect.DeepEqual(diff.Rows[0].Changed, expected) {
+		t.Fatalf("Changed columns mismatch: expected %v, got %v", expected, diff.Rows[0].Changed)
+	}
+	if read != 6 || total != 6 {
+		t.Fatalf("Progress mismatch: expected 6 of 6 rows, got %d of %d", read, total)
+	}
+
+	statements, err := diff.DML(target, "test")
+	if err != nil {
+		t.Fatalf("DML failed: %v", err)
+	}
+	expectedStatements := []string{
+		"UPDATE `users` SET `name` = 'Bob', `email` = NULL WHERE `id` = '2';",
+		"DELETE FROM `users` WHERE `id` = '3';",
+		"INSERT INTO `users` (id, name, email) VALUES ('10', 'Carol', '');",
+	}
+	if !reflect.DeepEqual(statements, expectedStatements) {
+		t.Fatalf("DML mismatch:\nexpected: %q\ngot: %q", expectedStatements, statements)
+	}
+
+	for _, statement := range statements {
+		if _, err := target.Connection.ExecContext(ctx, statement); err != nil {
+			t.Fatalf("%s: %v", statement, err)
+		}
+	}
+	diff, err = DiffTableData(ctx, source, target, "test", "test", "users", nil)
+	if err != nil {
+		t.Fatalf("DiffTableData after applying the DML failed: %v", err)
+	}
+	if len(diff.Rows) != 0 {
+		t.Fatalf("Expected no differences after applying the DML, got %+v", diff.Rows)
+	}
+}
+
+func TestCompareKeys(t *testing.T) {
+	number := func(text string) models.Value { return models.Value{Type: "INTEGER", Raw: []byte(text)} }
+	text := func(text string) models.Value { return models.Value{Type: "TEXT", Raw: []byte(text)} }
+
+	tests := []struct {
+		name     string
+		a, b     models.Record
+		expected int
+	}{
+		{"numbers", models.Record{number("9")}, models.Record{number("10")}, -1},
+		{"decimals", models.Record{number("1.50")}, models.Record{number("1.5")}, 0},
+		{"text", models.Record{text("9")}, models.Record{text("10")}, 1},
+		{"second column", models.Record{number("1"), text("b")}, models.Record{number("1"), text("a")}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := compareKeys(tt.a, tt.b, []int{0, 1}[:len(tt.a)]); got != tt.expected {
+				t.Errorf("compareKeys() = %d, expected %d", got, tt.expected)
+			}
+		})
+	}
+}
@@ -1,35 +1,79 @@
 package drivers
 
 import (
+	"context"
+
 	"github.com/jorgerojas26/lazysql/models"
 )
 
 type Driver interface {
-	Connect(urlstr string) error
-	TestConnection(urlstr string) error
-	GetDatabases() ([]string, error)
-	GetTables(database string) (map[string][]string, error)
-	GetTableColumns(database, table string) ([][]string, error)
-	GetConstraints(database, table string) ([][]string, error)
-	GetForeignKeys(database, table string) ([][]string, error)
-	GetIndexes(database, table string) ([][]string, error)
-	GetRecords(database, table, where, sort string, offset, limit int) ([][]string, int, string, error)
-	UpdateRecord(database, table, column, value, primaryKeyColumnName, primaryKeyValue string) error
-	DeleteRecord(database, table string, primaryKeyColumnName, primaryKeyValue string) error
-	ExecuteDMLStatement(query string) (string, error)
-	ExecuteQuery(query string) ([][]string, int, error)
-	ExecutePendingChanges(changes []models.DBDMLChange) error
+	// Connect opens the connection to urlstr. Called again, it reconnects:
+	// the previous connection is closed, with its open transaction, once the
+	// new one is opened.
+	Connect(ctx context.Context, urlstr string) error
+	TestConnection(ctx context.Context, urlstr string) error
+	// Ping checks that the database can still be reached.
+	Ping(ctx context.Context) error
+	GetDatabases(ctx context.Context) ([]string, error)
+	GetTables(ctx context.Context, database string) (map[string][]string, error)
+	GetTableColumns(ctx context.Context, database, table string) ([][]string, error)
+	GetConstraints(ctx context.Context, database, table string) ([][]string, error)
+	GetForeignKeys(ctx context.Context, database, table string) ([][]string, error)
+	// GetReferencingForeignKeys returns the foreign keys of the tables of
+	// database that reference table.
+	GetReferencingForeignKeys(ctx context.Context, database, table string) ([]models.ReferencingForeignKey, error)
+	GetIndexes(ctx context.Context, database, table string) ([][]string, error)
+	GetRecords(ctx context.Context, database, table, where, sort string, offset, limit int) ([]models.Record, int, string, error)
+	UpdateRecord(ctx context.Context, database, table, column, value, primaryKeyColumnName, primaryKeyValue string) error
+	DeleteRecord(ctx context.Context, database, table string, primaryKeyColumnName, primaryKeyValue string) error
+	ExecuteDMLStatement(ctx context.Context, query string, args ...any) (string, error)
+	ExecuteQuery(ctx context.Context, query string, args ...any) ([]models.Record, int, error)
+	// QueryRows runs query and returns a cursor over its result, for
+	// results too large to be read at once.
+	QueryRows(ctx context.Context, query string, args ...any) (Rows, error)
+	ExecutePendingChanges(ctx context.Context, changes []models.DBDMLChange) error
 	GetProvider() string
-	GetPrimaryKeyColumnNames(database, table string) ([]string, error)
+	GetPrimaryKeyColumnNames(ctx context.Context, database, table string) ([]string, error)
+
+	// ExplainQuery runs the EXPLAIN statement of the database for query and
+	// returns the plan it reports.
+	ExplainQuery(ctx context.Context, query string, args ...any) (*models.PlanNode, error)
+
+	// BeginTransaction pins one connection and opens a transaction on it.
+	// Until it is committed or rolled back, records and the statements of
+	// the SQL editor are read and written inside this transaction.
+	BeginTransaction(ctx context.Context) error
+	CommitTransaction() error
+	RollbackTransaction() error
+	InTransaction() bool
 
 	SupportsProgramming() bool
 	UseSchemas() bool
-	GetFunctions(database string) (map[string][]string, error)
-	GetProcedures(database string) (map[string][]string, error)
-	GetViews(database string) (map[string][]string, error)
-	GetFunctionDefinition(database string, name string) (string, error)
-	GetProcedureDefinition(database string, name string) (string, error)
-	GetViewDefinition(database string, name string) (string, error)
+	GetFunctions(ctx context.Context, database string) (map[string][]string, error)
+	GetProcedures(ctx context.Context, database string) (map[string][]string, error)
+	GetViews(ctx context.Context, database string) (map[string][]string, error)
+	GetFunctionDefinition(ctx context.Context, database string, name string) (string, error)
+	GetProcedureDefinition(ctx context.Context, database string, name string) (string, error)
+	GetViewDefinition(ctx context.Context, database string, name string) (string, error)
+	// GetTableDefinition returns the statements that create table: its
+	// CREATE TABLE statement and those of its indexes and foreign keys.
+	GetTableDefinition(ctx context.Context, database string, table string) (string, error)
+
+	// GetTriggers, GetSequences, GetMaterializedViews and GetTypes list
+	// the objects of their kind like GetFunctions does. The databases
+	// without objects of a kind return none. GetTypes lists the
+	// user-defined types, enums and domains among them.
+	GetTriggers(ctx context.Context, database string) (map[string][]string, error)
+	GetSequences(ctx context.Context, database string) (map[string][]string, error)
+	GetMaterializedViews(ctx context.Context, database string) (map[string][]string, error)
+	GetTypes(ctx context.Context, database string) (map[string][]string, error)
+	GetTriggerDefinition(ctx context.Context, database string, name string) (string, error)
+	GetSequenceDefinition(ctx context.Context, database string, name string) (string, error)
+	GetMaterializedViewDefinition(ctx context.Context, database string, name string) (string, error)
+	GetTypeDefinition(ctx context.Context, database string, name string) (string, error)
+	// RefreshMaterializedView replaces the rows of the materialized view
+	// name by those of its query.
+	RefreshMaterializedView(ctx context.Context, database string, name string) error
 
 	FormatArg(arg any, colype models.CellValueType) any
 	FormatArgForQueryString(arg any) string
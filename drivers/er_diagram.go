@@ -0,0 +1,258 @@
+package drivers
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+// ERDiagram is the entity-relationship diagram of the tables of a database
+// or of one of its schemas.
+type ERDiagram struct {
+	// Name is the database, or the schema, the diagram is of.
+	Name string
+	// Tables are sorted by name. Only their columns, primary key, when the
+	// driver reports it with the columns, and foreign keys are read.
+	Tables []models.TableSchema
+}
+
+// ERRelation is a foreign key of Table referencing another table of the
+// diagram.
+type ERRelation struct {
+	Table string
+	models.ForeignKeySchema
+}
+
+// LoadERDiagram reads the tables of database, or of its schema for the
+// databases that use schemas, through GetTables, GetTableColumns and
+// GetForeignKeys. An empty schema reads the tables of every schema but the
+// system ones.
+func LoadERDiagram(ctx context.Context, db Driver, database, schema string) (*ERDiagram, error) {
+	tables, err := db.GetTables(ctx, database)
+	if err != nil {
+		return nil, err
+	}
+
+	var tableNames []string
+	for tableSchema, names := range tables {
+		if systemSchemas[tableSchema] || (schema != "" && db.UseSchemas() && tableSchema != schema) {
+			continue
+		}
+		for _, name := range names {
+			if db.UseSchemas() {
+				name = tableSchema + "." + name
+			}
+			tableNames = append(tableNames, name)
+		}
+	}
+	sort.Strings(tableNames)
+
+	diagram := &ERDiagram{Name: database, Tables: make([]models.TableSchema, 0, len(tableNames))}
+	if schema != "" && db.UseSchemas() {
+		diagram.Name = schema
+	}
+
+	// The foreign keys of MySQL are those referencing the table they are
+	// read for, they are added to their own table once all are read.
+	var referencingKeys []schemaRows
+
+	for _, tableName := range tableNames {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		columns, err := db.GetTableColumns(ctx, database, tableName)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", tableName, err)
+		}
+		foreignKeys, err := db.GetForeignKeys(ctx, database, tableName)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", tableName, err)
+		}
+
+		table := models.TableSchema{Name: tableName}
+		table.Columns, table.PrimaryKey = parseSchemaColumns(columns)
+		if db.GetProvider() == DriverMySQL {
+			referencingKeys = append(referencingKeys, newSchemaRows(foreignKeys))
+		} else {
+			table.ForeignKeys = parseSchemaForeignKeys(foreignKeys)
+		}
+		diagram.Tables = append(diagram.Tables, table)
+	}
+
+	for _, r := range referencingKeys {
+		for _, row := range r.rows {
+			i := diagram.tableIndex(r.value(row, "table_name"))
+			if i < 0 {
+				continue
+			}
+			diagram.Tables[i].ForeignKeys = addForeignKeyColumn(diagram.Tables[i].ForeignKeys,
+				r.value(row, "constraint_name"), r.value(row, "referenced_table_name"),
+				r.value(row, "column_name"), r.value(row, "referenced_column_name"))
+		}
+	}
+
+	return diagram, nil
+}
+
+func (d *ERDiagram) tableIndex(name string) int {
+	return slices.IndexFunc(d.Tables, func(table models.TableSchema) bool { return table.Name == name })
+}
+
+// Relations returns the foreign keys of the tables of the diagram that
+// reference a table of the diagram, e.g. not one of another schema.
+func (d *ERDiagram) Relations() []ERRelation {
+	var relations []ERRelation
+	for _, table := range d.Tables {
+		for _, key := range table.ForeignKeys {
+			if d.tableIndex(key.ReferencedTable) >= 0 {
+				relations = append(relations, ERRelation{Table: table.Name, ForeignKeySchema: key})
+			}
+		}
+	}
+	return relations
+}
+
+// isForeignKeyColumn reports whether column of table is part of one of its
+// foreign keys.
+func isForeignKeyColumn(table models.TableSchema, column string) bool {
+	for _, key := range table.ForeignKeys {
+		if slices.Contains(key.Columns, column) {
+			return true
+		}
+	}
+	return false
+}
+
+// isPrimaryKeyColumn reports whether column of table is part of its primary
+// key.
+func isPrimaryKeyColumn(table models.TableSchema, column string) bool {
+	return table.PrimaryKey != nil && slices.Contains(table.PrimaryKey.Columns, column)
+}
+
+// relationNullable reports whether the foreign key of relation may be
+// NULL, i.e. whether the referencing rows may have no referenced row.
+func (d *ERDiagram) relationNullable(relation ERRelation) bool {
+	i := d.tableIndex(relation.Table)
+	for _, column := range d.Tables[i].Columns {
+		if column.Nullable && slices.Contains(relation.Columns, column.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// DOT returns the diagram in the Graphviz DOT language, a node for each
+// table listing its columns and an edge for each foreign key, from the
+// referencing table to the referenced one.
+func (d *ERDiagram) DOT() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "digraph %s {\n", dotID(d.Name))
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=plaintext];\n")
+
+	for _, table := range d.Tables {
+		fmt.Fprintf(&b, "\n  %s [label=<\n", dotID(table.Name))
+		b.WriteString("    <table border=\"0\" cellborder=\"1\" cellspacing=\"0\" cellpadding=\"4\">\n")
+		fmt.Fprintf(&b, "      <tr><td bgcolor=\"lightgrey\"><b>%s</b></td></tr>\n", dotHTML(table.Name))
+		for _, column := range table.Columns {
+			text := column.Name + " " + column.Type
+			if keys := ERColumnKeys(table, column.Name); keys != "" {
+				text += " " + keys
+			}
+			fmt.Fprintf(&b, "      <tr><td align=\"left\">%s</td></tr>\n", dotHTML(text))
+		}
+		b.WriteString("    </table>\n  >];\n")
+	}
+
+	relations := d.Relations()
+	if len(relations) > 0 {
+		b.WriteString("\n")
+	}
+	for _, relation := range relations {
+		label := strings.Join(relation.Columns, ", ") + " -> " + strings.Join(relation.ReferencedColumns, ", ")
+		fmt.Fprintf(&b, "  %s -> %s [label=%s];\n", dotID(relation.Table), dotID(relation.ReferencedTable), dotID(label))
+	}
+
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// Mermaid returns the diagram as a Mermaid erDiagram, an entity for each
+// table and a one-to-many relationship for each foreign key. The names and
+// types are reduced to the characters Mermaid accepts.
+func (d *ERDiagram) Mermaid() string {
+	var b strings.Builder
+
+	b.WriteString("erDiagram\n")
+
+	for _, table := range d.Tables {
+		if len(table.Columns) == 0 {
+			fmt.Fprintf(&b, "    %s\n", mermaidName(table.Name))
+			continue
+		}
+
+		fmt.Fprintf(&b, "    %s {\n", mermaidName(table.Name))
+		for _, column := range table.Columns {
+			columnType := mermaidType.ReplaceAllString(column.Type, "_")
+			if columnType == "" {
+				columnType = "unknown"
+			}
+			line := columnType + " " + mermaidName(column.Name)
+			if keys := ERColumnKeys(table, column.Name); keys != "" {
+				line += " " + keys
+			}
+			fmt.Fprintf(&b, "        %s\n", line)
+		}
+		b.WriteString("    }\n")
+	}
+
+	for _, relation := range d.Relations() {
+		// The referenced row is optional to the referencing ones when the
+		// foreign key may be NULL.
+		cardinality := "||--o{"
+		if d.relationNullable(relation) {
+			cardinality = "|o--o{"
+		}
+		label := strings.ReplaceAll(strings.Join(relation.Columns, ", "), `"`, "'")
+		fmt.Fprintf(&b, "    %s %s %s : \"%s\"\n", mermaidName(relation.ReferencedTable), cardinality, mermaidName(relation.Table), label)
+	}
+
+	return b.String()
+}
+
+// ERColumnKeys returns "PK", "FK" or "PK, FK" for a column of the primary
+// key or of a foreign key of table.
+func ERColumnKeys(table models.TableSchema, column string) string {
+	var keys []string
+	if isPrimaryKeyColumn(table, column) {
+		keys = append(keys, "PK")
+	}
+	if isForeignKeyColumn(table, column) {
+		keys = append(keys, "FK")
+	}
+	return strings.Join(keys, ", ")
+}
+
+func dotID(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
+
+func dotHTML(value string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;").Replace(value)
+}
+
+var (
+	mermaidInvalidName = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+	mermaidType        = regexp.MustCompile(`[^A-Za-z0-9_()\[\]-]+`)
+)
+
+func mermaidName(value string) string {
+	return mermaidInvalidName.ReplaceAllString(value, "_")
+}
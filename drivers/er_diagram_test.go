@@ -0,0 +1,120 @@
+package drivers
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+func TestLoadERDiagram_SQLite(t *testing.T) {
+	ctx := context.Background()
+
+	db := &SQLite{}
+	if err := db.Connect(ctx, filepath.Join(t.TempDir(), "test.db")); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	t.Cleanup(func() { db.Connection.Close() })
+
+	for _, statement := range []string{
+		"CREATE TABLE users (id INTEGER PRIMARY KEY, email TEXT NOT NULL)",
+		"CREATE TABLE orders (id INTEGER PRIMARY KEY, user_id INTEGER NOT NULL REFERENCES users (id), total NUMERIC)",
+	} {
+		if _, err := db.Connection.ExecContext(ctx, statement); err != nil {
+			t.Fatalf("%s failed: %v", statement, err)
+		}
+	}
+
+	diagram, err := LoadERDiagram(ctx, db, "test_db", "")
+	if err != nil {
+		t.Fatalf("LoadERDiagram failed: %v", err)
+	}
+
+	var names []string
+	for _, table := range diagram.Tables {
+		names = append(names, table.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"orders", "users"}) {
+		t.Fatalf("LoadERDiagram failed: got tables %v", names)
+	}
+
+	expected := []ERRelation{{
+		Table: "orders",
+		ForeignKeySchema: models.ForeignKeySchema{
+			Name:              "0",
+			Columns:           []string{"user_id"},
+			ReferencedTable:   "users",
+			ReferencedColumns: []string{"id"},
+		},
+	}}
+	if relations := diagram.Relations(); !reflect.DeepEqual(relations, expected) {
+		t.Fatalf("Relations failed: got %+v, expected %+v", relations, expected)
+	}
+}
+
+func TestERDiagram_Export(t *testing.T) {
+	diagram := &ERDiagram{
+		Name: "public",
+		Tables: []models.TableSchema{
+			{
+				Name:       "public.orders",
+				Columns:    []models.ColumnSchema{{Name: "id", Type: "integer"}, {Name: "user_id", Type: "integer", Nullable: true}},
+				PrimaryKey: &models.ConstraintSchema{Columns: []string{"id"}},
+				ForeignKeys: []models.ForeignKeySchema{
+					{Name: "orders_user_id_fkey", Columns: []string{"user_id"}, ReferencedTable: "public.users", ReferencedColumns: []string{"id"}},
+					// Tables of other schemas are left out.
+					{Name: "orders_shop_fkey", Columns: []string{"user_id"}, ReferencedTable: "shop.users", ReferencedColumns: []string{"id"}},
+				},
+			},
+			{
+				Name:       "public.users",
+				Columns:    []models.ColumnSchema{{Name: "id", Type: "integer"}, {Name: "email", Type: "character varying(255)"}},
+				PrimaryKey: &models.ConstraintSchema{Columns: []string{"id"}},
+			},
+		},
+	}
+
+	expectedDOT := `digraph "public" {
+  rankdir=LR;
+  node [shape=plaintext];
+
+  "public.orders" [label=<
+    <table border="0" cellborder="1" cellspacing="0" cellpadding="4">
+      <tr><td bgcolor="lightgrey"><b>public.orders</b></td></tr>
+      <tr><td align="left">id integer PK</td></tr>
+      <tr><td align="left">user_id integer FK</td></tr>
+    </table>
+  >];
+
+  "public.users" [label=<
+    <table border="0" cellborder="1" cellspacing="0" cellpadding="4">
+      <tr><td bgcolor="lightgrey"><b>public.users</b></td></tr>
+      <tr><td align="left">id integer PK</td></tr>
+      <tr><td align="left">email character varying(255)</td></tr>
+    </table>
+  >];
+
+  "public.orders" -> "public.users" [label="user_id -> id"];
+}
+`
+	if dot := diagram.DOT(); dot != expectedDOT {
+		t.Errorf("DOT failed: got\n%s\nexpected\n%s", dot, expectedDOT)
+	}
+
+	expectedMermaid := `erDiagram
+    public_orders {
+        integer id PK
+        integer user_id FK
+    }
+    public_users {
+        integer id PK
+        character_varying(255) email
+    }
+    public_users |o--o{ public_orders : "user_id"
+`
+	if mermaid := diagram.Mermaid(); mermaid != expectedMermaid {
+		t.Errorf("Mermaid failed: got\n%s\nexpected\n%s", mermaid, expectedMermaid)
+	}
+}
@@ -1,18 +1,19 @@
 package drivers
 
 import (
+	"context"
 	"database/sql"
 	"encoding/hex"
 	"errors"
 	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/google/uuid"
 	// MSSQL driver
 	_ "github.com/microsoft/go-mssqldb"
 	// Azure AD auth support for Azure SQL (e.g. fedauth=ActiveDirectoryDefault)
 	_ "github.com/microsoft/go-mssqldb/azuread"
-	"github.com/xo/dburl"
 
 	"github.com/jorgerojas26/lazysql/helpers/logger"
 	"github.com/jorgerojas26/lazysql/models"
@@ -21,6 +22,14 @@ import (
 type MSSQL struct {
 	Connection *sql.DB
 	Provider   string
+	// InitStatements are run on every connection opened, see
+	// SessionStatements.
+	InitStatements []string
+
+	// mu guards Connection, replaced when connecting again while other
+	// goroutines use it.
+	mu sync.RWMutex
+	tx transaction
 }
 
 // mssqlGUIDToUUID converts a 16-byte little-endian GUID from MSSQL
@@ -52,32 +61,49 @@ func mssqlGUIDToUUID(dbBytes []byte) (uuid.UUID, error) {
 	return uuid.FromBytes(b)
 }
 
-func (db *MSSQL) TestConnection(urlstr string) error {
-	return db.Connect(urlstr)
+func (db *MSSQL) TestConnection(ctx context.Context, urlstr string) error {
+	return db.Connect(ctx, urlstr)
 }
 
-func (db *MSSQL) Connect(urlstr string) error {
+func (db *MSSQL) Connect(ctx context.Context, urlstr string) error {
 	if urlstr == "" {
 		return errors.New("url string can not be empty")
 	}
 
 	db.SetProvider(DriverMSSQL)
 
-	var err error
-
-	db.Connection, err = dburl.Open(urlstr)
+	connection, err := openDB(urlstr, db.InitStatements)
 	if err != nil {
 		return err
 	}
 
-	if err := db.Connection.Ping(); err != nil {
+	if err := pingPool(ctx, connection); err != nil {
 		return err
 	}
 
+	db.mu.Lock()
+	previous := db.Connection
+	db.Connection = connection
+	db.mu.Unlock()
+
+	db.tx.replacePool(previous)
+
 	return nil
 }
 
-func (db *MSSQL) GetDatabases() ([]string, error) {
+// pool returns the connection pool, replaced when connecting again.
+func (db *MSSQL) pool() *sql.DB {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	return db.Connection
+}
+
+func (db *MSSQL) Ping(ctx context.Context) error {
+	return db.pool().PingContext(ctx)
+}
+
+func (db *MSSQL) GetDatabases(ctx context.Context) ([]string, error) {
 	databases := make([]string, 0)
 
 	query := `
@@ -86,7 +112,7 @@ func (db *MSSQL) GetDatabases() ([]string, error) {
 		FROM
 			sys.databases
 	`
-	rows, err := db.Connection.Query(query)
+	rows, err := db.pool().QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -109,7 +135,7 @@ func (db *MSSQL) GetDatabases() ([]string, error) {
 	return databases, nil
 }
 
-func (db *MSSQL) GetTables(database string) (map[string][]string, error) {
+func (db *MSSQL) GetTables(ctx context.Context, database string) (map[string][]string, error) {
 	if database == "" {
 		return nil, errors.New("database name is required")
 	}
@@ -120,7 +146,7 @@ func (db *MSSQL) GetTables(database string) (map[string][]string, error) {
 	query += database
 	query += ".sys.tables"
 
-	rows, err := db.Connection.Query(query)
+	rows, err := db.pool().QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -143,30 +169,41 @@ func (db *MSSQL) GetTables(database string) (map[string][]string, error) {
 	return tables, nil
 }
 
-func (db *MSSQL) GetTableColumns(database, table string) ([][]string, error) {
+// GetTableColumns returns the columns of table, with their type written
+// with its length, precision or scale as in the DDL that creates it.
+func (db *MSSQL) GetTableColumns(ctx context.Context, database, table string) ([][]string, error) {
 	query := fmt.Sprintf(`
 		USE %s;
         SELECT
             c.name AS column_name,
-            t.name AS data_type,
+            CASE
+                WHEN t.name IN ('char', 'varchar', 'binary', 'varbinary')
+                    THEN t.name + '(' + IIF(c.max_length = -1, 'max', CAST(c.max_length AS varchar(10))) + ')'
+                WHEN t.name IN ('nchar', 'nvarchar')
+                    THEN t.name + '(' + IIF(c.max_length = -1, 'max', CAST(c.max_length / 2 AS varchar(10))) + ')'
+                WHEN t.name IN ('decimal', 'numeric')
+                    THEN t.name + '(' + CAST(c.precision AS varchar(10)) + ',' + CAST(c.scale AS varchar(10)) + ')'
+                WHEN t.name IN ('datetime2', 'datetimeoffset', 'time')
+                    THEN t.name + '(' + CAST(c.scale AS varchar(10)) + ')'
+                ELSE t.name
+            END AS data_type,
             c.is_nullable,
             def.definition AS column_default,
             ISNULL(ep.value, '') AS comment
         FROM sys.columns c
-        INNER JOIN sys.types t ON c.system_type_id = t.system_type_id
-        LEFT JOIN sys.default_constraints def ON c.default_object_id = def.parent_column_id
+        INNER JOIN sys.types t ON c.user_type_id = t.user_type_id
+        LEFT JOIN sys.default_constraints def ON def.object_id = c.default_object_id
         LEFT JOIN sys.extended_properties ep ON ep.major_id = c.object_id
             AND ep.minor_id = c.column_id
             AND ep.name = 'MS_Description'
         WHERE c.object_id = OBJECT_ID(@p2)
-        AND t.name <> 'sysname'
         ORDER BY c.column_id;
     `, database)
-	return db.getTableInformation(query, database, table, "")
+	return db.getTableInformation(ctx, query, database, table, "")
 }
 
-func (db *MSSQL) GetConstraints(database, table string) ([][]string, error) {
-	currentSchema, err := db.getCurrentSchema()
+func (db *MSSQL) GetConstraints(ctx context.Context, database, table string) ([][]string, error) {
+	currentSchema, err := db.getCurrentSchema(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -192,10 +229,10 @@ func (db *MSSQL) GetConstraints(database, table string) ([][]string, error) {
           AND t.name = @p2
           AND kc.type IN ('PK', 'UQ')  -- Primary keys and unique constraints
     `, database)
-	return db.getTableInformation(query, currentSchema, table, "")
+	return db.getTableInformation(ctx, query, currentSchema, table, "")
 }
 
-func (db *MSSQL) GetForeignKeys(database, table string) ([][]string, error) {
+func (db *MSSQL) GetForeignKeys(ctx context.Context, database, table string) ([][]string, error) {
 	query := fmt.Sprintf(`
 		USE %s;
         SELECT
@@ -223,11 +260,38 @@ func (db *MSSQL) GetForeignKeys(database, table string) ([][]string, error) {
         WHERE t.name = @p2
           AND DB_NAME(DB_ID(@p1)) = @p1
     `, database)
-	return db.getTableInformation(query, database, table, "")
+	return db.getTableInformation(ctx, query, database, table, "")
+}
+
+func (db *MSSQL) GetReferencingForeignKeys(ctx context.Context, database, table string) ([]models.ReferencingForeignKey, error) {
+	if database == "" {
+		return nil, errors.New("database name is required")
+	}
+	if table == "" {
+		return nil, errors.New("table name is required")
+	}
+
+	rows, err := db.pool().QueryContext(ctx, "USE "+database+"; "+`
+		SELECT t.name, fk.name, fk.name, c.name, rc.name
+		FROM sys.foreign_keys fk
+		INNER JOIN sys.foreign_key_columns fkc ON fk.object_id = fkc.constraint_object_id
+		INNER JOIN sys.tables t ON t.object_id = fk.parent_object_id
+		INNER JOIN sys.tables rt ON rt.object_id = fk.referenced_object_id
+		INNER JOIN sys.columns c ON c.object_id = fkc.parent_object_id AND c.column_id = fkc.parent_column_id
+		INNER JOIN sys.columns rc ON rc.object_id = fkc.referenced_object_id AND rc.column_id = fkc.referenced_column_id
+		WHERE rt.name = @table
+		ORDER BY t.name, fk.name, fkc.constraint_column_id
+	`, sql.Named("table", table))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanReferencingForeignKeys(rows)
 }
 
-func (db *MSSQL) GetIndexes(database, table string) ([][]string, error) {
-	currentSchema, err := db.getCurrentSchema()
+func (db *MSSQL) GetIndexes(ctx context.Context, database, table string) ([][]string, error) {
+	currentSchema, err := db.getCurrentSchema(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -263,10 +327,10 @@ func (db *MSSQL) GetIndexes(database, table string) ([][]string, error) {
           AND DB_ID(@p1) = d.database_id
         ORDER BY i.type_desc
     `, database)
-	return db.getTableInformation(query, database, table, currentSchema)
+	return db.getTableInformation(ctx, query, database, table, currentSchema)
 }
 
-func (db *MSSQL) GetRecords(database, table, where, sort string, offset, limit int) (results [][]string, totalRecords int, displayQueryString string, err error) {
+func (db *MSSQL) GetRecords(ctx context.Context, database, table, where, sort string, offset, limit int) (results []models.Record, totalRecords int, displayQueryString string, err error) {
 	if database == "" {
 		return nil, 0, "", errors.New("database name is required")
 	}
@@ -279,7 +343,7 @@ func (db *MSSQL) GetRecords(database, table, where, sort string, offset, limit i
 		limit = DefaultRowLimit
 	}
 
-	results = make([][]string, 0)
+	results = make([]models.Record, 0)
 
 	baseQuery := fmt.Sprintf("USE %s; SELECT * FROM ", database)
 	baseQuery += db.FormatReference(table)
@@ -299,86 +363,48 @@ func (db *MSSQL) GetRecords(database, table, where, sort string, offset, limit i
 	// Query for display with actual values
 	displayQueryString = fmt.Sprintf("%s ORDER BY %s OFFSET %s ROWS FETCH NEXT %s ROWS ONLY", baseQuery, sort, db.FormatArg(offset, models.String), db.FormatArg(limit, models.String))
 
-	rows, err := db.Connection.Query(executableQuery, offset, limit)
+	rows, err := db.tx.on(db.pool()).QueryContext(ctx, executableQuery, offset, limit)
 	if err != nil {
 		return nil, 0, displayQueryString, err // Return display query even on error
 	}
 
 	defer rows.Close()
 
-	columns, err := rows.Columns()
+	wrapped, err := newSQLRows(rows)
 	if err != nil {
 		return nil, 0, displayQueryString, err
 	}
 
-	results = append(results, columns)
-
-	for rows.Next() {
-		rowValues := make([]any, len(columns))
-
-		for i := range columns {
-			rowValues[i] = new(sql.RawBytes)
-		}
-
-		if errScan := rows.Scan(rowValues...); errScan != nil {
-			return nil, 0, displayQueryString, errScan
-		}
-		// Get column types to identify UNIQUEIDENTIFIER
-		columnTypes, err := rows.ColumnTypes()
-		if err != nil {
-			return nil, 0, displayQueryString, err
-		}
-
-		if len(columnTypes) != len(rowValues) {
-			return nil, 0, displayQueryString, errors.New("unexpected number of column")
-		}
-		var row []string
-		for i, col := range rowValues {
-			if col == nil {
-				row = append(row, "NULL&")
-				continue
-			}
+	results = append(results, models.HeaderRecord(wrapped.Columns()))
 
-			rawBytes, ok := col.(*sql.RawBytes)
-			if !ok {
-				return nil, 0, displayQueryString, errors.New("unexpected type in column value")
-			}
+	for wrapped.Next() {
+		row := wrapped.Values()
 
-			columnType := columnTypes[i]
-			colType := columnType.DatabaseTypeName()
-
-			if colType == "UNIQUEIDENTIFIER" {
-				// Try to parse as a GUID
-				if guid, errParse := mssqlGUIDToUUID(*rawBytes); errParse == nil {
-					row = append(row, guid.String()) // Now this will be the correct format
-				} else {
-					// Fallback to hex string if parsing fails
-					hexValue := hex.EncodeToString(*rawBytes)
-					row = append(row, "0x"+hexValue) // Prefix with "0x" for clarity
-					logger.Warn("Invalid GUID", map[string]any{
-						"table":  table,
-						"column": columns[i],
-						"value":  hexValue,
-						"error":  errParse,
-					})
-				}
+		for i, value := range row {
+			if value.Null || value.Type != "UNIQUEIDENTIFIER" {
 				continue
 			}
 
-			// Handle other columns as strings
-			colval := string(*rawBytes)
-			// Check nullability and handle empty strings
-			nullable, _ := columnType.Nullable()
-			if nullable && colval == "" {
-				row = append(row, "NULL&") // show "NULL" instead if "EMPTY" when column is Nullable and it's set to null
+			// Try to parse as a GUID
+			if guid, errParse := mssqlGUIDToUUID(value.Raw); errParse == nil {
+				row[i].Raw = []byte(guid.String()) // Now this will be the correct format
 			} else {
-				row = append(row, colval)
+				// Fallback to hex string if parsing fails
+				hexValue := hex.EncodeToString(value.Raw)
+				row[i].Raw = []byte("0x" + hexValue) // Prefix with "0x" for clarity
+				logger.Warn("Invalid GUID", map[string]any{
+					"table":  table,
+					"column": wrapped.Columns()[i].Name,
+					"value":  hexValue,
+					"error":  errParse,
+				})
 			}
 		}
+
 		results = append(results, row)
 	}
 
-	if err := rows.Err(); err != nil {
+	if err := wrapped.Err(); err != nil {
 		return nil, 0, displayQueryString, err
 	}
 
@@ -393,7 +419,7 @@ func (db *MSSQL) GetRecords(database, table, where, sort string, offset, limit i
 	}
 
 	totalRecords = 0
-	countRow := db.Connection.QueryRow(countQuery)
+	countRow := db.tx.on(db.pool()).QueryRowContext(ctx, countQuery)
 	if err := countRow.Scan(&totalRecords); err != nil {
 		return results, 0, displayQueryString, err // Return display query even on count error
 	}
@@ -404,7 +430,7 @@ func (db *MSSQL) GetRecords(database, table, where, sort string, offset, limit i
 	return results, totalRecords, displayQueryString, nil
 }
 
-func (db *MSSQL) UpdateRecord(database, table, column, value, primaryKeyColumnName, primaryKeyValue string) error {
+func (db *MSSQL) UpdateRecord(ctx context.Context, database, table, column, value, primaryKeyColumnName, primaryKeyValue string) error {
 	if database == "" {
 		return errors.New("database name is required")
 	}
@@ -435,12 +461,16 @@ func (db *MSSQL) UpdateRecord(database, table, column, value, primaryKeyColumnNa
 	query += " = @p1 WHERE "
 	query += primaryKeyColumnName
 	query += " = @p2"
-	_, err := db.Connection.Exec(query, value, primaryKeyValue)
+	q, err := db.tx.writer(db.pool())
+	if err != nil {
+		return err
+	}
+	_, err = q.ExecContext(ctx, query, value, primaryKeyValue)
 
 	return err
 }
 
-func (db *MSSQL) DeleteRecord(database, table, primaryKeyColumnName, primaryKeyValue string) error {
+func (db *MSSQL) DeleteRecord(ctx context.Context, database, table, primaryKeyColumnName, primaryKeyValue string) error {
 	if database == "" {
 		return errors.New("database name is required")
 	}
@@ -464,17 +494,25 @@ func (db *MSSQL) DeleteRecord(database, table, primaryKeyColumnName, primaryKeyV
 	query += " WHERE "
 	query += primaryKeyColumnName
 	query += " = @p1"
-	_, err := db.Connection.Exec(query, primaryKeyValue)
+	q, err := db.tx.writer(db.pool())
+	if err != nil {
+		return err
+	}
+	_, err = q.ExecContext(ctx, query, primaryKeyValue)
 
 	return err
 }
 
-func (db *MSSQL) ExecuteDMLStatement(query string) (string, error) {
+func (db *MSSQL) ExecuteDMLStatement(ctx context.Context, query string, args ...any) (string, error) {
 	if query == "" {
 		return "", errors.New("query is required")
 	}
 
-	res, err := db.Connection.Exec(query)
+	q, err := db.tx.writer(db.pool())
+	if err != nil {
+		return "", err
+	}
+	res, err := q.ExecContext(ctx, query, args...)
 	if err != nil {
 		return "", err
 	}
@@ -487,54 +525,61 @@ func (db *MSSQL) ExecuteDMLStatement(query string) (string, error) {
 	return fmt.Sprintf("%d rows affected", rowsAffected), nil
 }
 
-func (db *MSSQL) ExecuteQuery(query string) ([][]string, int, error) {
-	if query == "" {
-		return nil, 0, errors.New("query can not be empty")
-	}
-
-	rows, err := db.Connection.Query(query)
+func (db *MSSQL) ExecuteQuery(ctx context.Context, query string, args ...any) ([]models.Record, int, error) {
+	rows, err := db.QueryRows(ctx, query, args...)
 	if err != nil {
 		return nil, 0, err
 	}
 
-	defer rows.Close()
+	return readAllRows(rows)
+}
 
-	columns, err := rows.Columns()
+func (db *MSSQL) QueryRows(ctx context.Context, query string, args ...any) (Rows, error) {
+	if query == "" {
+		return nil, errors.New("query can not be empty")
+	}
+
+	rows, err := db.tx.on(db.pool()).QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 
-	records := make([][]string, 0)
-	for rows.Next() {
-		rowValues := make([]any, len(columns))
+	return newSQLRows(rows)
+}
 
-		for i := range columns {
-			rowValues[i] = new(sql.RawBytes)
+// ExplainQuery turns SHOWPLAN_XML on for the query only, so it needs a
+// single connection to run the three batches on.
+func (db *MSSQL) ExplainQuery(ctx context.Context, query string, args ...any) (*models.PlanNode, error) {
+	q := db.tx.on(db.pool())
+	if pool, ok := q.(*sql.DB); ok {
+		conn, err := pool.Conn(ctx)
+		if err != nil {
+			return nil, err
 		}
+		defer conn.Close()
+		q = conn
+	}
 
-		if err := rows.Scan(rowValues...); err != nil {
-			return nil, 0, err
-		}
+	if _, err := q.ExecContext(ctx, "SET SHOWPLAN_XML ON"); err != nil {
+		return nil, err
+	}
 
-		var row []string
-		for _, col := range rowValues {
-			row = append(row, string(*col.(*sql.RawBytes)))
-		}
+	var plan string
+	err := q.QueryRowContext(ctx, query, args...).Scan(&plan)
 
-		records = append(records, row)
+	// The connection goes back to the pool, so showplan must be off again
+	// even if ctx was cancelled.
+	if _, offErr := q.ExecContext(context.Background(), "SET SHOWPLAN_XML OFF"); offErr != nil {
+		err = errors.Join(err, offErr)
 	}
-
-	if err := rows.Err(); err != nil {
-		return nil, 0, err
+	if err != nil {
+		return nil, err
 	}
 
-	// Prepend the columns to the records.
-	results := append([][]string{columns}, records...)
-
-	return results, len(records), nil
+	return parseMSSQLPlan([]byte(plan))
 }
 
-func (db *MSSQL) ExecutePendingChanges(changes []models.DBDMLChange) error {
+func (db *MSSQL) ExecutePendingChanges(ctx context.Context, changes []models.DBDMLChange) error {
 	var queries []models.Query
 
 	for _, change := range changes {
@@ -554,10 +599,10 @@ func (db *MSSQL) ExecutePendingChanges(changes []models.DBDMLChange) error {
 
 	logger.Info("queries", map[string]any{"queries": queries})
 
-	return queriesInTransaction(db.Connection, queries)
+	return db.tx.execQueries(ctx, db.pool(), queries)
 }
 
-func (db *MSSQL) GetPrimaryKeyColumnNames(database, table string) ([]string, error) {
+func (db *MSSQL) GetPrimaryKeyColumnNames(ctx context.Context, database, table string) ([]string, error) {
 	if database == "" {
 		return nil, errors.New("database name is required")
 	}
@@ -566,7 +611,7 @@ func (db *MSSQL) GetPrimaryKeyColumnNames(database, table string) ([]string, err
 		return nil, errors.New("table name is required")
 	}
 
-	currentSchema, err := db.getCurrentSchema()
+	currentSchema, err := db.getCurrentSchema(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -600,7 +645,7 @@ func (db *MSSQL) GetPrimaryKeyColumnNames(database, table string) ([]string, err
 			AND t.name = @p3
 		ORDER BY ic.key_ordinal
 	`
-	rows, err := db.Connection.Query(query, "PK", currentSchema, table)
+	rows, err := db.pool().QueryContext(ctx, query, "PK", currentSchema, table)
 	if err != nil {
 		return nil, err
 	}
@@ -636,6 +681,22 @@ func (db *MSSQL) GetProvider() string {
 	return db.Provider
 }
 
+func (db *MSSQL) BeginTransaction(ctx context.Context) error {
+	return db.tx.begin(ctx, db.pool())
+}
+
+func (db *MSSQL) CommitTransaction() error {
+	return db.tx.commit()
+}
+
+func (db *MSSQL) RollbackTransaction() error {
+	return db.tx.rollback()
+}
+
+func (db *MSSQL) InTransaction() bool {
+	return db.tx.active()
+}
+
 // getTableInformation is used for following func:
 //
 //   - [GetTableColumns]
@@ -647,7 +708,7 @@ func (db *MSSQL) GetProvider() string {
 //
 //   - database name, used for filtering table_catalog
 //   - table name, used for filtering table_name
-func (db *MSSQL) getTableInformation(query, database, table, schema string) ([][]string, error) {
+func (db *MSSQL) getTableInformation(ctx context.Context, query, database, table, schema string) ([][]string, error) {
 	if database == "" {
 		return nil, errors.New("database name is required")
 	}
@@ -668,7 +729,7 @@ func (db *MSSQL) getTableInformation(query, database, table, schema string) ([][
 		args = append(args, schema)
 	}
 
-	rows, err := db.Connection.Query(query, args...)
+	rows, err := db.pool().QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -799,9 +860,9 @@ func (db *MSSQL) DMLChangeToQueryString(change models.DBDMLChange) (string, erro
 	return queryStr, nil
 }
 
-func (db *MSSQL) getCurrentSchema() (string, error) {
+func (db *MSSQL) getCurrentSchema(ctx context.Context) (string, error) {
 	query := "SELECT SCHEMA_NAME() AS CurrentSchema"
-	row := db.Connection.QueryRow(query)
+	row := db.pool().QueryRowContext(ctx, query)
 
 	var currentSchema string
 	err := row.Scan(&currentSchema)
@@ -812,7 +873,7 @@ func (db *MSSQL) getCurrentSchema() (string, error) {
 	return currentSchema, nil
 }
 
-func (db *MSSQL) GetFunctions(database string) (map[string][]string, error) {
+func (db *MSSQL) GetFunctions(ctx context.Context, database string) (map[string][]string, error) {
 	if database == "" {
 		return nil, errors.New("database name is required")
 	}
@@ -829,7 +890,7 @@ func (db *MSSQL) GetFunctions(database string) (map[string][]string, error) {
 		WHERE o.type_desc IN ('SQL_SCALAR_FUNCTION', 'SQL_TABLE_VALUED_FUNCTION')
 		`
 
-	rows, err := db.Connection.Query(query, database)
+	rows, err := db.pool().QueryContext(ctx, query, database)
 	if err != nil {
 		return nil, err
 	}
@@ -852,7 +913,7 @@ func (db *MSSQL) GetFunctions(database string) (map[string][]string, error) {
 	return functions, nil
 }
 
-func (db *MSSQL) GetProcedures(database string) (map[string][]string, error) {
+func (db *MSSQL) GetProcedures(ctx context.Context, database string) (map[string][]string, error) {
 	if database == "" {
 		return nil, errors.New("database name is required")
 	}
@@ -869,7 +930,7 @@ func (db *MSSQL) GetProcedures(database string) (map[string][]string, error) {
 		WHERE o.type_desc IN ('SQL_STORED_PROCEDURE')
 		`
 
-	rows, err := db.Connection.Query(query)
+	rows, err := db.pool().QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -900,7 +961,7 @@ func (db *MSSQL) UseSchemas() bool {
 	return false
 }
 
-func (db *MSSQL) GetViews(database string) (map[string][]string, error) {
+func (db *MSSQL) GetViews(ctx context.Context, database string) (map[string][]string, error) {
 	if database == "" {
 		return nil, errors.New("database name is required")
 	}
@@ -917,7 +978,7 @@ func (db *MSSQL) GetViews(database string) (map[string][]string, error) {
 		WHERE o.type_desc IN ('VIEW')
 	`
 
-	rows, err := db.Connection.Query(query)
+	rows, err := db.pool().QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -940,7 +1001,7 @@ func (db *MSSQL) GetViews(database string) (map[string][]string, error) {
 	return views, nil
 }
 
-func (db *MSSQL) GetObjectDefinition(database string, name string) (string, error) {
+func (db *MSSQL) GetObjectDefinition(ctx context.Context, database string, name string) (string, error) {
 	if database == "" {
 		return "", errors.New("database name is required")
 	}
@@ -963,7 +1024,7 @@ func (db *MSSQL) GetObjectDefinition(database string, name string) (string, erro
     select @proc_source as result;
 	`
 
-	row := db.Connection.QueryRow(query, sql.Named("name", name))
+	row := db.pool().QueryRowContext(ctx, query, sql.Named("name", name))
 	if err := row.Scan(&result); err != nil {
 		return result, err
 	}
@@ -971,14 +1032,229 @@ func (db *MSSQL) GetObjectDefinition(database string, name string) (string, erro
 	return result, nil
 }
 
-func (db *MSSQL) GetFunctionDefinition(database string, name string) (string, error) {
-	return db.GetObjectDefinition(database, name)
+func (db *MSSQL) GetFunctionDefinition(ctx context.Context, database string, name string) (string, error) {
+	return db.GetObjectDefinition(ctx, database, name)
+}
+
+func (db *MSSQL) GetProcedureDefinition(ctx context.Context, database string, name string) (string, error) {
+	return db.GetObjectDefinition(ctx, database, name)
+}
+
+func (db *MSSQL) GetViewDefinition(ctx context.Context, database string, name string) (string, error) {
+	return db.GetObjectDefinition(ctx, database, name)
+}
+
+// GetTableDefinition rebuilds the statements that create table from the
+// catalog, OBJECT_DEFINITION only covers views and routines.
+func (db *MSSQL) GetTableDefinition(ctx context.Context, database string, table string) (string, error) {
+	if database == "" {
+		return "", errors.New("database name is required")
+	}
+	if table == "" {
+		return "", errors.New("table name is required")
+	}
+
+	return tableDefinition(ctx, db, database, table)
+}
+
+func (db *MSSQL) GetTriggers(ctx context.Context, database string) (map[string][]string, error) {
+	return db.getObjectNames(ctx, database, `
+		SELECT name
+		FROM sys.triggers
+		WHERE parent_class = 1
+		ORDER BY name
+	`)
+}
+
+func (db *MSSQL) GetSequences(ctx context.Context, database string) (map[string][]string, error) {
+	return db.getObjectNames(ctx, database, `
+		SELECT name
+		FROM sys.sequences
+		ORDER BY name
+	`)
 }
 
-func (db *MSSQL) GetProcedureDefinition(database string, name string) (string, error) {
-	return db.GetObjectDefinition(database, name)
+// GetMaterializedViews returns no materialized views, MSSQL has none. Its
+// indexed views are listed among the views.
+func (db *MSSQL) GetMaterializedViews(_ context.Context, _ string) (map[string][]string, error) {
+	return map[string][]string{}, nil
 }
 
-func (db *MSSQL) GetViewDefinition(database string, name string) (string, error) {
-	return db.GetObjectDefinition(database, name)
+// GetTypes lists the alias types and the table types.
+func (db *MSSQL) GetTypes(ctx context.Context, database string) (map[string][]string, error) {
+	return db.getObjectNames(ctx, database, `
+		SELECT name
+		FROM sys.types
+		WHERE is_user_defined = 1
+		ORDER BY name
+	`)
+}
+
+// getObjectNames returns the names query returns in database, keyed by
+// database.
+func (db *MSSQL) getObjectNames(ctx context.Context, database, query string) (map[string][]string, error) {
+	if database == "" {
+		return nil, errors.New("database name is required")
+	}
+
+	rows, err := db.pool().QueryContext(ctx, "USE "+database+"; "+query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	names := make(map[string][]string)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names[database] = append(names[database], name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return names, nil
+}
+
+func (db *MSSQL) GetTriggerDefinition(ctx context.Context, database string, name string) (string, error) {
+	return db.GetObjectDefinition(ctx, database, name)
+}
+
+// GetSequenceDefinition rebuilds the CREATE SEQUENCE statement of name from
+// the catalog.
+func (db *MSSQL) GetSequenceDefinition(ctx context.Context, database string, name string) (string, error) {
+	if database == "" {
+		return "", errors.New("database name is required")
+	}
+	if name == "" {
+		return "", errors.New("sequence name is required")
+	}
+
+	var (
+		schema   string
+		sequence = sequenceSchema{Name: name}
+		cache    sql.NullString
+	)
+	row := db.pool().QueryRowContext(ctx, "USE "+database+"; "+`
+		SELECT SCHEMA_NAME(s.schema_id), TYPE_NAME(s.user_type_id),
+			CONVERT(nvarchar(64), s.start_value), CONVERT(nvarchar(64), s.increment),
+			CONVERT(nvarchar(64), s.minimum_value), CONVERT(nvarchar(64), s.maximum_value),
+			s.is_cached, CONVERT(nvarchar(64), s.cache_size), s.is_cycling
+		FROM sys.sequences s
+		WHERE s.name = @name
+	`, sql.Named("name", name))
+	if err := row.Scan(&schema, &sequence.Type, &sequence.Start, &sequence.Increment, &sequence.MinValue, &sequence.MaxValue, &sequence.Cached, &cache, &sequence.Cycle); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", fmt.Errorf("sequence %s not found", name)
+		}
+		return "", err
+	}
+	sequence.Name = schema + "." + name
+	// The cache size is NULL for the default one.
+	sequence.Cache = cache.String
+
+	w := &ddlWriter{provider: db.GetProvider(), reference: db.FormatReference}
+	w.createSequence(sequence)
+
+	return w.statements[0], nil
+}
+
+func (db *MSSQL) GetMaterializedViewDefinition(_ context.Context, _ string, _ string) (string, error) {
+	return "", errors.New("mssql has no materialized views")
+}
+
+// GetTypeDefinition rebuilds the CREATE TYPE statement of the alias type or
+// the table type name from the catalog.
+func (db *MSSQL) GetTypeDefinition(ctx context.Context, database string, name string) (string, error) {
+	if database == "" {
+		return "", errors.New("database name is required")
+	}
+	if name == "" {
+		return "", errors.New("type name is required")
+	}
+
+	var (
+		schema      string
+		baseType    string
+		maxLength   int
+		precision   int
+		scale       int
+		nullable    bool
+		isTableType bool
+	)
+	row := db.pool().QueryRowContext(ctx, "USE "+database+"; "+`
+		SELECT SCHEMA_NAME(t.schema_id), TYPE_NAME(t.system_type_id), t.max_length, t.precision, t.scale, t.is_nullable, t.is_table_type
+		FROM sys.types t
+		WHERE t.is_user_defined = 1 AND t.name = @name
+	`, sql.Named("name", name))
+	if err := row.Scan(&schema, &baseType, &maxLength, &precision, &scale, &nullable, &isTableType); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", fmt.Errorf("type %s not found", name)
+		}
+		return "", err
+	}
+
+	w := &ddlWriter{provider: db.GetProvider(), reference: db.FormatReference}
+	if !isTableType {
+		w.createAliasType(schema+"."+name, mssqlColumnType(baseType, maxLength, precision, scale), nullable)
+		return w.statements[0], nil
+	}
+
+	rows, err := db.pool().QueryContext(ctx, "USE "+database+"; "+`
+		SELECT c.name, TYPE_NAME(c.user_type_id), c.max_length, c.precision, c.scale, c.is_nullable
+		FROM sys.table_types tt
+		JOIN sys.columns c ON c.object_id = tt.type_table_object_id
+		WHERE tt.name = @name
+		ORDER BY c.column_id
+	`, sql.Named("name", name))
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	var columns []models.ColumnSchema
+	for rows.Next() {
+		var column models.ColumnSchema
+		var columnType string
+		if err := rows.Scan(&column.Name, &columnType, &maxLength, &precision, &scale, &column.Nullable); err != nil {
+			return "", err
+		}
+		column.Type = mssqlColumnType(columnType, maxLength, precision, scale)
+		columns = append(columns, column)
+	}
+	if err := rows.Err(); err != nil {
+		return "", err
+	}
+
+	w.createCompositeType(schema+"."+name, columns)
+
+	return w.statements[0], nil
+}
+
+func (db *MSSQL) RefreshMaterializedView(_ context.Context, _ string, _ string) error {
+	return errors.New("mssql has no materialized views")
+}
+
+// mssqlColumnType returns the type of a column as declared, e.g.
+// nvarchar(50), from the type name and the sizes kept in the catalog.
+func mssqlColumnType(typeName string, maxLength, precision, scale int) string {
+	switch strings.ToLower(typeName) {
+	case "varchar", "char", "varbinary", "binary", "nvarchar", "nchar":
+		if maxLength == -1 {
+			return typeName + "(max)"
+		}
+		// The length of the unicode types is kept in bytes.
+		if strings.HasPrefix(strings.ToLower(typeName), "n") {
+			maxLength /= 2
+		}
+		return fmt.Sprintf("%s(%d)", typeName, maxLength)
+	case "decimal", "numeric":
+		return fmt.Sprintf("%s(%d, %d)", typeName, precision, scale)
+	case "datetime2", "datetimeoffset", "time":
+		return fmt.Sprintf("%s(%d)", typeName, scale)
+	default:
+		return typeName
+	}
 }
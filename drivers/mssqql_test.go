@@ -1,6 +1,7 @@
 package drivers
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"testing"
@@ -102,7 +103,7 @@ func TestMSSQL_GetPrimaryKeyColumnNames(t *testing.T) {
 		WithArgs("PK", schemaMSSQL, tableNameMSSQL). // Use schema, not database name
 		WillReturnRows(rows)
 
-	keys, err := pg.GetPrimaryKeyColumnNames(DBNameMSSQL, tableNameMSSQL)
+	keys, err := pg.GetPrimaryKeyColumnNames(context.Background(), DBNameMSSQL, tableNameMSSQL)
 	if err != nil {
 		t.Fatalf("GetPrimaryKeyColumnNames failed: %v", err)
 	}
@@ -173,7 +174,7 @@ func TestMSSQL_GetForeignKeys(t *testing.T) {
           AND DB_NAME(DB_ID(@p1)) = @p1
     `).WithArgs(DBNameMSSQL, tableNameMSSQL).WillReturnRows(rows)
 
-	constraints, err := pg.GetForeignKeys(DBNameMSSQL, tableNameMSSQL)
+	constraints, err := pg.GetForeignKeys(context.Background(), DBNameMSSQL, tableNameMSSQL)
 	if err != nil {
 		t.Fatalf("GetForeignKeys failed: %v", err)
 	}
@@ -326,7 +327,7 @@ func TestMSSQL_GetIndexes(t *testing.T) {
 		WithArgs(DBNameMSSQL, tableNameMSSQL, schemaMSSQL).
 		WillReturnRows(rows)
 
-	indexes, err := pg.GetIndexes(DBNameMSSQL, tableNameMSSQL)
+	indexes, err := pg.GetIndexes(context.Background(), DBNameMSSQL, tableNameMSSQL)
 	if err != nil {
 		t.Fatalf("GetIndexes failed: %v", err)
 	}
@@ -381,7 +382,7 @@ func TestMSSQL_ExecutePendingChanges(t *testing.T) {
 	)).WithArgs("New'; DROP TABLE Users;--", 1).WillReturnResult(sqlmock.NewResult(0, 1))
 	mock.ExpectCommit()
 
-	err = pg.ExecutePendingChanges(changes)
+	err = pg.ExecutePendingChanges(context.Background(), changes)
 	if err != nil {
 		t.Fatalf("ExecutePendingChanges failed: %v", err)
 	}
@@ -415,7 +416,7 @@ func TestMSSQL_GetTableColumns(t *testing.T) {
 		"Primary key identifier",
 	).AddRow(
 		"name",
-		"varchar",
+		"varchar(255)",
 		"1",
 		"",
 		"User name field",
@@ -430,24 +431,33 @@ func TestMSSQL_GetTableColumns(t *testing.T) {
 	mock.ExpectQuery(`USE test_db;
         SELECT
             c.name AS column_name,
-            t.name AS data_type,
+            CASE
+                WHEN t.name IN ('char', 'varchar', 'binary', 'varbinary')
+                    THEN t.name + '(' + IIF(c.max_length = -1, 'max', CAST(c.max_length AS varchar(10))) + ')'
+                WHEN t.name IN ('nchar', 'nvarchar')
+                    THEN t.name + '(' + IIF(c.max_length = -1, 'max', CAST(c.max_length / 2 AS varchar(10))) + ')'
+                WHEN t.name IN ('decimal', 'numeric')
+                    THEN t.name + '(' + CAST(c.precision AS varchar(10)) + ',' + CAST(c.scale AS varchar(10)) + ')'
+                WHEN t.name IN ('datetime2', 'datetimeoffset', 'time')
+                    THEN t.name + '(' + CAST(c.scale AS varchar(10)) + ')'
+                ELSE t.name
+            END AS data_type,
             c.is_nullable,
             def.definition AS column_default,
             ISNULL(ep.value, '') AS comment
         FROM sys.columns c
-        INNER JOIN sys.types t ON c.system_type_id = t.system_type_id
-        LEFT JOIN sys.default_constraints def ON c.default_object_id = def.parent_column_id
-        LEFT JOIN sys.extended_properties ep ON ep.major_id = c.object_id 
-            AND ep.minor_id = c.column_id 
+        INNER JOIN sys.types t ON c.user_type_id = t.user_type_id
+        LEFT JOIN sys.default_constraints def ON def.object_id = c.default_object_id
+        LEFT JOIN sys.extended_properties ep ON ep.major_id = c.object_id
+            AND ep.minor_id = c.column_id
             AND ep.name = 'MS_Description'
         WHERE c.object_id = OBJECT_ID(@p2)
-        AND t.name <> 'sysname'
         ORDER BY c.column_id;
     `).
 		WithArgs(DBNameMSSQL, tableNameMSSQL).
 		WillReturnRows(rows)
 
-	columns, err := pg.GetTableColumns(DBNameMSSQL, tableNameMSSQL)
+	columns, err := pg.GetTableColumns(context.Background(), DBNameMSSQL, tableNameMSSQL)
 	if err != nil {
 		t.Fatalf("GetTableColumns failed: %v", err)
 	}
@@ -455,7 +465,7 @@ func TestMSSQL_GetTableColumns(t *testing.T) {
 	expected := [][]string{
 		{"column_name", "data_type", "is_nullable", "column_default", "comment"},
 		{"id", "int", "0", "", "Primary key identifier"},
-		{"name", "varchar", "1", "", "User name field"},
+		{"name", "varchar(255)", "1", "", "User name field"},
 		{"email", "varchar", "0", "", ""},
 	}
 
@@ -488,7 +498,7 @@ func TestMSSQL_GetRecords(t *testing.T) {
 	mock.ExpectQuery(fmt.Sprintf("SELECT COUNT\\(\\*\\) FROM \\[%s\\]", tableNameMSSQL)).
 		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
 
-	records, total, _, err := pg.GetRecords(DBNameMSSQL, tableNameMSSQL, "", "", 0, DefaultRowLimit)
+	records, total, _, err := pg.GetRecords(context.Background(), DBNameMSSQL, tableNameMSSQL, "", "", 0, DefaultRowLimit)
 	if err != nil {
 		t.Fatalf("GetRecords failed: %v", err)
 	}
@@ -499,7 +509,7 @@ func TestMSSQL_GetRecords(t *testing.T) {
 		{"2", "Bob"},
 	}
 
-	if !reflect.DeepEqual(records, expected) {
+	if !reflect.DeepEqual(models.RecordStrings(records), expected) {
 		t.Fatalf("Expected %v, got %v", expected, records)
 	}
 
@@ -1,13 +1,14 @@
 package drivers
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
-
-	"github.com/xo/dburl"
+	"sync"
 
 	"github.com/jorgerojas26/lazysql/models"
 )
@@ -15,32 +16,59 @@ import (
 type MySQL struct {
 	Connection *sql.DB
 	Provider   string
+	// InitStatements are run on every connection opened, see
+	// SessionStatements.
+	InitStatements []string
+
+	// mu guards Connection, replaced when connecting again while other
+	// goroutines use it.
+	mu sync.RWMutex
+	tx transaction
 }
 
-func (db *MySQL) TestConnection(urlstr string) (err error) {
-	return db.Connect(urlstr)
+func (db *MySQL) TestConnection(ctx context.Context, urlstr string) (err error) {
+	return db.Connect(ctx, urlstr)
 }
 
-func (db *MySQL) Connect(urlstr string) (err error) {
+func (db *MySQL) Connect(ctx context.Context, urlstr string) (err error) {
 	db.SetProvider(DriverMySQL)
 
-	db.Connection, err = dburl.Open(urlstr)
+	connection, err := openDB(urlstr, db.InitStatements)
 	if err != nil {
 		return err
 	}
 
-	err = db.Connection.Ping()
+	err = pingPool(ctx, connection)
 	if err != nil {
 		return err
 	}
 
+	db.mu.Lock()
+	previous := db.Connection
+	db.Connection = connection
+	db.mu.Unlock()
+
+	db.tx.replacePool(previous)
+
 	return nil
 }
 
-func (db *MySQL) GetDatabases() ([]string, error) {
+// pool returns the connection pool, replaced when connecting again.
+func (db *MySQL) pool() *sql.DB {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	return db.Connection
+}
+
+func (db *MySQL) Ping(ctx context.Context) error {
+	return db.pool().PingContext(ctx)
+}
+
+func (db *MySQL) GetDatabases(ctx context.Context) ([]string, error) {
 	var databases []string
 
-	rows, err := db.Connection.Query("SHOW DATABASES")
+	rows, err := db.pool().QueryContext(ctx, "SHOW DATABASES")
 	if err != nil {
 		return nil, err
 	}
@@ -63,12 +91,13 @@ func (db *MySQL) GetDatabases() ([]string, error) {
 	return databases, nil
 }
 
-func (db *MySQL) GetTables(database string) (map[string][]string, error) {
+func (db *MySQL) GetTables(ctx context.Context, database string) (map[string][]string, error) {
 	if database == "" {
 		return nil, errors.New("database name is required")
 	}
 
-	rows, err := db.Connection.Query(fmt.Sprintf("SHOW TABLES FROM `%s`", database))
+	// Views are listed by GetViews, SHOW TABLES alone would list them twice.
+	rows, err := db.pool().QueryContext(ctx, fmt.Sprintf("SHOW FULL TABLES FROM `%s` WHERE Table_type = 'BASE TABLE'", database))
 	if err != nil {
 		return nil, err
 	}
@@ -76,8 +105,8 @@ func (db *MySQL) GetTables(database string) (map[string][]string, error) {
 
 	tables := make(map[string][]string)
 	for rows.Next() {
-		var table string
-		err = rows.Scan(&table)
+		var table, tableType string
+		err = rows.Scan(&table, &tableType)
 		if err != nil {
 			return nil, err
 		}
@@ -91,7 +120,7 @@ func (db *MySQL) GetTables(database string) (map[string][]string, error) {
 	return tables, nil
 }
 
-func (db *MySQL) GetTableColumns(database, table string) (results [][]string, err error) {
+func (db *MySQL) GetTableColumns(ctx context.Context, database, table string) (results [][]string, err error) {
 	if database == "" {
 		return nil, errors.New("database name is required")
 	}
@@ -103,7 +132,7 @@ func (db *MySQL) GetTableColumns(database, table string) (results [][]string, er
 	query := "SHOW FULL COLUMNS FROM "
 	query += db.formatTableName(database, table)
 
-	rows, err := db.Connection.Query(query)
+	rows, err := db.pool().QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -142,7 +171,7 @@ func (db *MySQL) GetTableColumns(database, table string) (results [][]string, er
 	return results, nil
 }
 
-func (db *MySQL) GetConstraints(database, table string) (results [][]string, err error) {
+func (db *MySQL) GetConstraints(ctx context.Context, database, table string) (results [][]string, err error) {
 	if database == "" {
 		return nil, errors.New("database name is required")
 	}
@@ -153,7 +182,7 @@ func (db *MySQL) GetConstraints(database, table string) (results [][]string, err
 
 	query := "SELECT CONSTRAINT_NAME, COLUMN_NAME, REFERENCED_TABLE_NAME, REFERENCED_COLUMN_NAME FROM information_schema.KEY_COLUMN_USAGE WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?"
 
-	rows, err := db.Connection.Query(query, database, table)
+	rows, err := db.pool().QueryContext(ctx, query, database, table)
 	if err != nil {
 		return nil, err
 	}
@@ -191,7 +220,7 @@ func (db *MySQL) GetConstraints(database, table string) (results [][]string, err
 	return results, nil
 }
 
-func (db *MySQL) GetForeignKeys(database, table string) (results [][]string, err error) {
+func (db *MySQL) GetForeignKeys(ctx context.Context, database, table string) (results [][]string, err error) {
 	if database == "" {
 		return nil, errors.New("database name is required")
 	}
@@ -202,7 +231,7 @@ func (db *MySQL) GetForeignKeys(database, table string) (results [][]string, err
 
 	query := "SELECT TABLE_NAME, COLUMN_NAME, CONSTRAINT_NAME, REFERENCED_COLUMN_NAME, REFERENCED_TABLE_NAME FROM information_schema.KEY_COLUMN_USAGE WHERE REFERENCED_TABLE_SCHEMA = ? AND REFERENCED_TABLE_NAME = ?"
 
-	rows, err := db.Connection.Query(query, database, table)
+	rows, err := db.pool().QueryContext(ctx, query, database, table)
 	if err != nil {
 		return nil, err
 	}
@@ -240,7 +269,31 @@ func (db *MySQL) GetForeignKeys(database, table string) (results [][]string, err
 	return results, nil
 }
 
-func (db *MySQL) GetIndexes(database, table string) (results [][]string, err error) {
+// GetReferencingForeignKeys returns the foreign keys referencing table from
+// the tables of the same database.
+func (db *MySQL) GetReferencingForeignKeys(ctx context.Context, database, table string) ([]models.ReferencingForeignKey, error) {
+	if database == "" {
+		return nil, errors.New("database name is required")
+	}
+	if table == "" {
+		return nil, errors.New("table name is required")
+	}
+
+	rows, err := db.pool().QueryContext(ctx, `
+		SELECT TABLE_NAME, CONSTRAINT_NAME, CONSTRAINT_NAME, COLUMN_NAME, REFERENCED_COLUMN_NAME
+		FROM information_schema.KEY_COLUMN_USAGE
+		WHERE REFERENCED_TABLE_SCHEMA = ? AND REFERENCED_TABLE_NAME = ? AND TABLE_SCHEMA = ?
+		ORDER BY TABLE_NAME, CONSTRAINT_NAME, ORDINAL_POSITION
+	`, database, table, database)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanReferencingForeignKeys(rows)
+}
+
+func (db *MySQL) GetIndexes(ctx context.Context, database, table string) (results [][]string, err error) {
 	if database == "" {
 		return nil, errors.New("database name is required")
 	}
@@ -252,7 +305,7 @@ func (db *MySQL) GetIndexes(database, table string) (results [][]string, err err
 	query := "SHOW INDEX FROM "
 	query += db.formatTableName(database, table)
 
-	rows, err := db.Connection.Query(query)
+	rows, err := db.pool().QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -290,7 +343,7 @@ func (db *MySQL) GetIndexes(database, table string) (results [][]string, err err
 	return results, nil
 }
 
-func (db *MySQL) GetRecords(database, table, where, sort string, offset, limit int) (paginatedResults [][]string, totalRecords int, queryString string, err error) {
+func (db *MySQL) GetRecords(ctx context.Context, database, table, where, sort string, offset, limit int) (paginatedResults []models.Record, totalRecords int, queryString string, err error) {
 	if table == "" {
 		return nil, 0, "", errors.New("table name is required")
 	}
@@ -316,62 +369,25 @@ func (db *MySQL) GetRecords(database, table, where, sort string, offset, limit i
 
 	queryString += " LIMIT ?, ?"
 
-	paginatedRows, err := db.Connection.Query(queryString, offset, limit)
+	paginatedRows, err := db.tx.on(db.pool()).QueryContext(ctx, queryString, offset, limit)
 	if err != nil {
 		return nil, 0, queryString, err
 	}
 	defer paginatedRows.Close()
 
-	columns, err := paginatedRows.Columns()
+	// reading the records closes the rows to release the connection
+	paginatedResults, err = readRecords(paginatedRows)
 	if err != nil {
 		return nil, 0, queryString, err
 	}
 
-	paginatedResults = append(paginatedResults, columns)
-
-	for paginatedRows.Next() {
-		nullStringSlice := make([]sql.NullString, len(columns))
-
-		rowValues := make([]any, len(columns))
-		for i := range nullStringSlice {
-			rowValues[i] = &nullStringSlice[i]
-		}
-
-		err = paginatedRows.Scan(rowValues...)
-		if err != nil {
-			return nil, 0, queryString, err
-		}
-
-		var row []string
-		for _, col := range nullStringSlice {
-			if col.Valid {
-				if col.String == "" {
-					row = append(row, "EMPTY&")
-				} else {
-					row = append(row, col.String)
-				}
-			} else {
-				row = append(row, "NULL&")
-			}
-		}
-
-		paginatedResults = append(paginatedResults, row)
-	}
-	if err := paginatedRows.Err(); err != nil {
-		return nil, 0, queryString, err
-	}
-	// close to release the connection
-	if err := paginatedRows.Close(); err != nil {
-		return nil, 0, queryString, err
-	}
-
 	countQuery := "SELECT COUNT(*) FROM "
 	countQuery += fmt.Sprintf("`%s`.", database)
 	countQuery += fmt.Sprintf("`%s`", table)
 	if where != "" { // Add WHERE clause to count query as well if it exists
 		countQuery += fmt.Sprintf(" %s", where)
 	}
-	countRow := db.Connection.QueryRow(countQuery)
+	countRow := db.tx.on(db.pool()).QueryRowContext(ctx, countQuery)
 	if err := countRow.Scan(&totalRecords); err != nil {
 		// Return the main query string even if count fails, for debugging.
 		return paginatedResults, 0, queryString, err
@@ -384,68 +400,67 @@ func (db *MySQL) GetRecords(database, table, where, sort string, offset, limit i
 	return paginatedResults, totalRecords, queryString, nil
 }
 
-func (db *MySQL) ExecuteQuery(query string) ([][]string, int, error) {
-	rows, err := db.Connection.Query(query)
+func (db *MySQL) ExecuteQuery(ctx context.Context, query string, args ...any) ([]models.Record, int, error) {
+	rows, err := db.QueryRows(ctx, query, args...)
 	if err != nil {
 		return nil, 0, err
 	}
-	defer rows.Close()
 
-	columns, err := rows.Columns()
+	return readAllRows(rows)
+}
+
+func (db *MySQL) QueryRows(ctx context.Context, query string, args ...any) (Rows, error) {
+	rows, err := db.tx.on(db.pool()).QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 
-	records := make([][]string, 0)
-	for rows.Next() {
-		rowValues := make([]any, len(columns))
-		for i := range columns {
-			rowValues[i] = new(sql.RawBytes)
-		}
-
-		err = rows.Scan(rowValues...)
-		if err != nil {
-			return nil, 0, err
-		}
-
-		var row []string
-		for _, col := range rowValues {
-			row = append(row, string(*col.(*sql.RawBytes)))
-		}
+	return newSQLRows(rows)
+}
 
-		records = append(records, row)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, 0, err
+func (db *MySQL) ExplainQuery(ctx context.Context, query string, args ...any) (*models.PlanNode, error) {
+	var plan string
+	err := db.tx.on(db.pool()).QueryRowContext(ctx, "EXPLAIN FORMAT=JSON "+query, args...).Scan(&plan)
+	if err != nil {
+		return nil, err
 	}
 
-	// Prepend the columns to the records.
-	results := append([][]string{columns}, records...)
-
-	return results, len(records), nil
+	return parseMySQLPlan([]byte(plan))
 }
 
-func (db *MySQL) UpdateRecord(database, table, column, value, primaryKeyColumnName, primaryKeyValue string) error {
+func (db *MySQL) UpdateRecord(ctx context.Context, database, table, column, value, primaryKeyColumnName, primaryKeyValue string) error {
 	query := "UPDATE "
 	query += db.formatTableName(database, table)
 	query += fmt.Sprintf(" SET %s = ? WHERE %s = ?", column, primaryKeyColumnName)
 
-	_, err := db.Connection.Exec(query, value, primaryKeyValue)
+	q, err := db.tx.writer(db.pool())
+	if err != nil {
+		return err
+	}
+	_, err = q.ExecContext(ctx, query, value, primaryKeyValue)
 
 	return err
 }
 
-func (db *MySQL) DeleteRecord(database, table, primaryKeyColumnName, primaryKeyValue string) error {
+func (db *MySQL) DeleteRecord(ctx context.Context, database, table, primaryKeyColumnName, primaryKeyValue string) error {
 	query := "DELETE FROM "
 	query += db.formatTableName(database, table)
 	query += fmt.Sprintf(" WHERE %s = ?", primaryKeyColumnName)
-	_, err := db.Connection.Exec(query, primaryKeyValue)
+	q, err := db.tx.writer(db.pool())
+	if err != nil {
+		return err
+	}
+	_, err = q.ExecContext(ctx, query, primaryKeyValue)
 
 	return err
 }
 
-func (db *MySQL) ExecuteDMLStatement(query string) (result string, err error) {
-	res, err := db.Connection.Exec(query)
+func (db *MySQL) ExecuteDMLStatement(ctx context.Context, query string, args ...any) (result string, err error) {
+	q, err := db.tx.writer(db.pool())
+	if err != nil {
+		return "", err
+	}
+	res, err := q.ExecContext(ctx, query, args...)
 	if err != nil {
 		return "", err
 	}
@@ -458,7 +473,7 @@ func (db *MySQL) ExecuteDMLStatement(query string) (result string, err error) {
 	return fmt.Sprintf("%d rows affected", rowsAffected), nil
 }
 
-func (db *MySQL) ExecutePendingChanges(changes []models.DBDMLChange) error {
+func (db *MySQL) ExecutePendingChanges(ctx context.Context, changes []models.DBDMLChange) error {
 	var queries []models.Query
 
 	for _, change := range changes {
@@ -476,10 +491,10 @@ func (db *MySQL) ExecutePendingChanges(changes []models.DBDMLChange) error {
 		}
 	}
 
-	return queriesInTransaction(db.Connection, queries)
+	return db.tx.execQueries(ctx, db.pool(), queries)
 }
 
-func (db *MySQL) GetPrimaryKeyColumnNames(database, table string) (primaryKeyColumnName []string, err error) {
+func (db *MySQL) GetPrimaryKeyColumnNames(ctx context.Context, database, table string) (primaryKeyColumnName []string, err error) {
 	if database == "" {
 		return nil, errors.New("database name is required")
 	}
@@ -488,7 +503,7 @@ func (db *MySQL) GetPrimaryKeyColumnNames(database, table string) (primaryKeyCol
 		return nil, errors.New("table name is required")
 	}
 
-	rows, err := db.Connection.Query("SELECT column_name FROM information_schema.key_column_usage WHERE table_schema = ? AND table_name = ? AND constraint_name = ?", database, table, "PRIMARY")
+	rows, err := db.pool().QueryContext(ctx, "SELECT column_name FROM information_schema.key_column_usage WHERE table_schema = ? AND table_name = ? AND constraint_name = ?", database, table, "PRIMARY")
 	if err != nil {
 		return nil, err
 	}
@@ -524,6 +539,22 @@ func (db *MySQL) GetProvider() string {
 	return db.Provider
 }
 
+func (db *MySQL) BeginTransaction(ctx context.Context) error {
+	return db.tx.begin(ctx, db.pool())
+}
+
+func (db *MySQL) CommitTransaction() error {
+	return db.tx.commit()
+}
+
+func (db *MySQL) RollbackTransaction() error {
+	return db.tx.rollback()
+}
+
+func (db *MySQL) InTransaction() bool {
+	return db.tx.active()
+}
+
 func (db *MySQL) formatTableName(database, table string) string {
 	return fmt.Sprintf("`%s`.`%s`", database, table)
 }
@@ -621,34 +652,206 @@ func (db *MySQL) DMLChangeToQueryString(change models.DBDMLChange) (string, erro
 	return queryStr, nil
 }
 
-func (db *MySQL) GetFunctions(_ string) (map[string][]string, error) {
-	return nil, errors.New("not implemented")
+func (db *MySQL) GetFunctions(ctx context.Context, database string) (map[string][]string, error) {
+	return db.getRoutines(ctx, database, "FUNCTION")
 }
 
-func (db *MySQL) GetProcedures(_ string) (map[string][]string, error) {
-	return nil, errors.New("not implemented")
+func (db *MySQL) GetProcedures(ctx context.Context, database string) (map[string][]string, error) {
+	return db.getRoutines(ctx, database, "PROCEDURE")
 }
 
-func (db *MySQL) GetViews(_ string) (map[string][]string, error) {
-	return nil, errors.New("not implemented")
+// getRoutines returns the names of the routines of routineType, FUNCTION or
+// PROCEDURE, of database.
+func (db *MySQL) getRoutines(ctx context.Context, database, routineType string) (map[string][]string, error) {
+	if database == "" {
+		return nil, errors.New("database name is required")
+	}
+
+	return db.getObjectNames(ctx, database, `
+		SELECT ROUTINE_NAME
+		FROM information_schema.ROUTINES
+		WHERE ROUTINE_SCHEMA = ? AND ROUTINE_TYPE = ?
+		ORDER BY ROUTINE_NAME
+	`, database, routineType)
+}
+
+func (db *MySQL) GetViews(ctx context.Context, database string) (map[string][]string, error) {
+	if database == "" {
+		return nil, errors.New("database name is required")
+	}
+
+	return db.getObjectNames(ctx, database, `
+		SELECT TABLE_NAME
+		FROM information_schema.VIEWS
+		WHERE TABLE_SCHEMA = ?
+		ORDER BY TABLE_NAME
+	`, database)
+}
+
+// getObjectNames returns the names query returns, keyed by database.
+func (db *MySQL) getObjectNames(ctx context.Context, database, query string, args ...any) (map[string][]string, error) {
+	rows, err := db.pool().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	names := make(map[string][]string)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+
+		names[database] = append(names[database], name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return names, nil
 }
 
 func (db *MySQL) SupportsProgramming() bool {
-	return false
+	return true
 }
 
 func (db *MySQL) UseSchemas() bool {
 	return false
 }
 
-func (db *MySQL) GetFunctionDefinition(_ string, _ string) (string, error) {
-	return "", errors.New("not implemented")
+func (db *MySQL) GetFunctionDefinition(ctx context.Context, database string, name string) (string, error) {
+	return db.showCreate(ctx, "FUNCTION", database, name)
+}
+
+func (db *MySQL) GetProcedureDefinition(ctx context.Context, database string, name string) (string, error) {
+	return db.showCreate(ctx, "PROCEDURE", database, name)
 }
 
-func (db *MySQL) GetProcedureDefinition(_ string, _ string) (string, error) {
-	return "", errors.New("not implemented")
+func (db *MySQL) GetViewDefinition(ctx context.Context, database string, name string) (string, error) {
+	return db.showCreate(ctx, "VIEW", database, name)
 }
 
-func (db *MySQL) GetViewDefinition(_ string, _ string) (string, error) {
-	return "", errors.New("not implemented")
+func (db *MySQL) GetTriggers(ctx context.Context, database string) (map[string][]string, error) {
+	if database == "" {
+		return nil, errors.New("database name is required")
+	}
+
+	return db.getObjectNames(ctx, database, `
+		SELECT TRIGGER_NAME
+		FROM information_schema.TRIGGERS
+		WHERE TRIGGER_SCHEMA = ?
+		ORDER BY TRIGGER_NAME
+	`, database)
+}
+
+// GetSequences returns no sequences, MySQL has none.
+func (db *MySQL) GetSequences(_ context.Context, _ string) (map[string][]string, error) {
+	return map[string][]string{}, nil
+}
+
+// GetMaterializedViews returns no materialized views, MySQL has none.
+func (db *MySQL) GetMaterializedViews(_ context.Context, _ string) (map[string][]string, error) {
+	return map[string][]string{}, nil
+}
+
+// GetTypes returns no types, MySQL has no user-defined types. Its enums
+// are column types.
+func (db *MySQL) GetTypes(_ context.Context, _ string) (map[string][]string, error) {
+	return map[string][]string{}, nil
+}
+
+func (db *MySQL) GetTriggerDefinition(ctx context.Context, database string, name string) (string, error) {
+	return db.showCreate(ctx, "TRIGGER", database, name)
+}
+
+func (db *MySQL) GetSequenceDefinition(_ context.Context, _ string, _ string) (string, error) {
+	return "", errors.New("mysql has no sequences")
+}
+
+func (db *MySQL) GetMaterializedViewDefinition(_ context.Context, _ string, _ string) (string, error) {
+	return "", errors.New("mysql has no materialized views")
+}
+
+func (db *MySQL) GetTypeDefinition(_ context.Context, _ string, _ string) (string, error) {
+	return "", errors.New("mysql has no user-defined types")
+}
+
+func (db *MySQL) RefreshMaterializedView(_ context.Context, _ string, _ string) error {
+	return errors.New("mysql has no materialized views")
+}
+
+// showCreate returns the statement that creates the object of kind, e.g.
+// FUNCTION, named name, as returned by SHOW CREATE. It is in the "Create
+// Function" column of the result, among others, or in the "SQL Original
+// Statement" column for triggers.
+func (db *MySQL) showCreate(ctx context.Context, kind, database, name string) (string, error) {
+	if database == "" {
+		return "", errors.New("database name is required")
+	}
+	if name == "" {
+		return "", errors.New("name is required")
+	}
+
+	rows, err := db.pool().QueryContext(ctx, fmt.Sprintf("SHOW CREATE %s %s", kind, db.formatTableName(database, name)))
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return "", err
+	}
+	definitionColumnName := "Create " + kind
+	if kind == "TRIGGER" {
+		definitionColumnName = "SQL Original Statement"
+	}
+	definitionColumn := slices.IndexFunc(columns, func(column string) bool {
+		return strings.EqualFold(column, definitionColumnName)
+	})
+	if definitionColumn < 0 {
+		return "", fmt.Errorf("no definition returned for %s", name)
+	}
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return "", err
+		}
+		return "", fmt.Errorf("%s not found", name)
+	}
+
+	values := make([]sql.NullString, len(columns))
+	scanArgs := make([]any, len(columns))
+	for i := range values {
+		scanArgs[i] = &values[i]
+	}
+	if err := rows.Scan(scanArgs...); err != nil {
+		return "", err
+	}
+
+	// MySQL hides the body of the routines of other users without the
+	// privilege to see it.
+	if !values[definitionColumn].Valid {
+		return "", fmt.Errorf("no privilege to see the definition of %s", name)
+	}
+
+	return values[definitionColumn].String + ";", nil
+}
+
+func (db *MySQL) GetTableDefinition(ctx context.Context, database, table string) (string, error) {
+	if database == "" {
+		return "", errors.New("database name is required")
+	}
+	if table == "" {
+		return "", errors.New("table name is required")
+	}
+
+	var name, definition string
+	row := db.pool().QueryRowContext(ctx, "SHOW CREATE TABLE "+db.formatTableName(database, table))
+	if err := row.Scan(&name, &definition); err != nil {
+		return "", err
+	}
+
+	return definition + ";", nil
 }
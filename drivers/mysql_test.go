@@ -1,10 +1,12 @@
 package drivers
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log"
 	"reflect"
+	"regexp"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
@@ -642,17 +644,17 @@ func TestMySQL_ErrorScenarios(t *testing.T) {
 				mock.ExpectQuery("SHOW DATABASES").WillReturnError(errors.New("query error"))
 			},
 			testFunc: func(db *MySQL) error {
-				_, err := db.GetDatabases()
+				_, err := db.GetDatabases(context.Background())
 				return err
 			},
 		},
 		{
 			name: "GetTables error",
 			setupMock: func(mock sqlmock.Sqlmock) {
-				mock.ExpectQuery(fmt.Sprintf("SHOW TABLES FROM `%s`", testDBNameMySQL)).WillReturnError(errors.New("query error"))
+				mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf("SHOW FULL TABLES FROM `%s` WHERE Table_type = 'BASE TABLE'", testDBNameMySQL))).WillReturnError(errors.New("query error"))
 			},
 			testFunc: func(db *MySQL) error {
-				_, err := db.GetTables("test_db")
+				_, err := db.GetTables(context.Background(), "test_db")
 				return err
 			},
 		},
@@ -662,7 +664,7 @@ func TestMySQL_ErrorScenarios(t *testing.T) {
 				// No expectations needed for this case
 			},
 			testFunc: func(db *MySQL) error {
-				_, err := db.GetTables("")
+				_, err := db.GetTables(context.Background(), "")
 				return err
 			},
 		},
@@ -703,7 +705,7 @@ func TestMySQL_GetTableColumns_Error(t *testing.T) {
 
 	mock.ExpectQuery(fmt.Sprintf("SHOW FULL COLUMNS FROM %s", mysql.formatTableName(testDBNameMySQL, testDBTableNameMySQL))).WillReturnError(errors.New("query error"))
 
-	_, err = mysql.GetTableColumns(testDBNameMySQL, testDBTableNameMySQL)
+	_, err = mysql.GetTableColumns(context.Background(), testDBNameMySQL, testDBTableNameMySQL)
 
 	if err == nil {
 		t.Fatalf("Expected error, but got nil")
@@ -726,7 +728,7 @@ func TestMySQL_GetConstraints_Error(t *testing.T) {
 
 	mock.ExpectQuery("SELECT CONSTRAINT_NAME, COLUMN_NAME, REFERENCED_TABLE_NAME, REFERENCED_COLUMN_NAME FROM information_schema.KEY_COLUMN_USAGE WHERE TABLE_SCHEMA = \\? AND TABLE_NAME = \\?").WithArgs(testDBNameMySQL, testDBTableNameMySQL).WillReturnError(errors.New("query error"))
 
-	_, err = mysql.GetConstraints(testDBNameMySQL, testDBTableNameMySQL)
+	_, err = mysql.GetConstraints(context.Background(), testDBNameMySQL, testDBTableNameMySQL)
 
 	log.Println("errrorrrrrr", err.Error())
 
@@ -750,7 +752,7 @@ func TestMySQL_GetForeignKeys_Error(t *testing.T) {
 
 	mock.ExpectQuery("SELECT TABLE_NAME, COLUMN_NAME, CONSTRAINT_NAME, REFERENCED_COLUMN_NAME, REFERENCED_TABLE_NAME FROM information_schema.KEY_COLUMN_USAGE WHERE REFERENCED_TABLE_SCHEMA = \\? AND REFERENCED_TABLE_NAME = \\?").WithArgs(testDBNameMySQL, testDBTableNameMySQL).WillReturnError(errors.New("query error"))
 
-	_, err = mysql.GetForeignKeys(testDBNameMySQL, testDBTableNameMySQL)
+	_, err = mysql.GetForeignKeys(context.Background(), testDBNameMySQL, testDBTableNameMySQL)
 
 	if err == nil {
 		t.Fatalf("Expected error, but got nil")
@@ -772,7 +774,7 @@ func TestMySQL_GetIndexes_Error(t *testing.T) {
 
 	mock.ExpectQuery(fmt.Sprintf("SHOW INDEX FROM %s", mysql.formatTableName(testDBNameMySQL, testDBTableNameMySQL))).WillReturnError(errors.New("query error"))
 
-	_, err = mysql.GetIndexes(testDBNameMySQL, testDBTableNameMySQL)
+	_, err = mysql.GetIndexes(context.Background(), testDBNameMySQL, testDBTableNameMySQL)
 
 	if err == nil {
 		t.Fatalf("Expected error, but got nil")
@@ -803,7 +805,7 @@ func TestMySQL_GetRecords_Error(t *testing.T) {
 				mock.ExpectQuery(fmt.Sprintf("SELECT \\* FROM %s LIMIT \\?, \\?", mysql.formatTableName(testDBNameMySQL, testDBTableNameMySQL))).WithArgs(0, DefaultRowLimit).WillReturnError(errors.New("query error"))
 			},
 			testFunc: func(db *MySQL) error {
-				_, _, _, err := db.GetRecords("test_db", "test_table", "", "", 0, DefaultRowLimit)
+				_, _, _, err := db.GetRecords(context.Background(), "test_db", "test_table", "", "", 0, DefaultRowLimit)
 				return err
 			},
 		},
@@ -813,7 +815,7 @@ func TestMySQL_GetRecords_Error(t *testing.T) {
 				mock.ExpectQuery(fmt.Sprintf("SELECT \\* FROM %s WHERE id = 1 LIMIT \\?, \\?", mysql.formatTableName(testDBNameMySQL, testDBTableNameMySQL))).WithArgs(0, DefaultRowLimit).WillReturnError(errors.New("query error"))
 			},
 			testFunc: func(db *MySQL) error {
-				_, _, _, err := db.GetRecords("test_db", "test_table", "WHERE id = 1", "", 0, DefaultRowLimit)
+				_, _, _, err := db.GetRecords(context.Background(), "test_db", "test_table", "WHERE id = 1", "", 0, DefaultRowLimit)
 				return err
 			},
 		},
@@ -844,7 +846,7 @@ func TestMySQL_ExecuteQuery_Error(t *testing.T) {
 
 	mock.ExpectQuery(fmt.Sprintf("SELECT \\* FROM %s", mysql.formatTableName(testDBNameMySQL, testDBTableNameMySQL))).WillReturnError(errors.New("query error"))
 
-	_, _, err = mysql.ExecuteQuery(fmt.Sprintf("SELECT * FROM %s", mysql.formatTableName(testDBNameMySQL, testDBTableNameMySQL)))
+	_, _, err = mysql.ExecuteQuery(context.Background(), fmt.Sprintf("SELECT * FROM %s", mysql.formatTableName(testDBNameMySQL, testDBTableNameMySQL)))
 
 	if err == nil {
 		t.Fatalf("Expected error, but got nil")
@@ -866,7 +868,7 @@ func TestMySQL_UpdateRecord_Error(t *testing.T) {
 
 	mock.ExpectExec(fmt.Sprintf("UPDATE %s SET name = \\? WHERE id = \\?", mysql.formatTableName(testDBNameMySQL, testDBTableNameMySQL))).WithArgs("updated_test", "1").WillReturnError(errors.New("query error"))
 
-	err = mysql.UpdateRecord(testDBNameMySQL, testDBTableNameMySQL, "name", "updated_test", "id", "1")
+	err = mysql.UpdateRecord(context.Background(), testDBNameMySQL, testDBTableNameMySQL, "name", "updated_test", "id", "1")
 
 	log.Println(err.Error())
 
@@ -890,7 +892,7 @@ func TestMySQL_DeleteRecord_Error(t *testing.T) {
 
 	mock.ExpectExec(fmt.Sprintf("DELETE FROM %s WHERE id = ?", mysql.formatTableName(testDBNameMySQL, testDBTableNameMySQL))).WithArgs("1").WillReturnError(errors.New("query error"))
 
-	err = mysql.DeleteRecord(testDBNameMySQL, testDBTableNameMySQL, "id", "1")
+	err = mysql.DeleteRecord(context.Background(), testDBNameMySQL, testDBTableNameMySQL, "id", "1")
 
 	if err == nil {
 		t.Fatalf("Expected error, but got nil")
@@ -912,7 +914,7 @@ func TestMySQL_ExecuteDMLStatement_Error(t *testing.T) {
 
 	mock.ExpectExec("UPDATE test_table SET value = 3 WHERE name = 'test1'").WillReturnError(errors.New("query error"))
 
-	_, err = mysql.ExecuteDMLStatement(fmt.Sprintf("UPDATE %s SET value = 3 WHERE name = 'test1'", testDBTableNameMySQL))
+	_, err = mysql.ExecuteDMLStatement(context.Background(), fmt.Sprintf("UPDATE %s SET value = 3 WHERE name = 'test1'", testDBTableNameMySQL))
 
 	if err == nil {
 		t.Fatalf("Expected error, but got nil")
@@ -976,7 +978,7 @@ func TestMySQL_ExecutePendingChanges_PartialFailure(t *testing.T) {
 	mock.ExpectExec(fmt.Sprintf("UPDATE %s SET `value` = \\? WHERE `id` = \\?", mysql.formatTableName(testDBNameMySQL, testDBTableNameMySQL))).WithArgs("4", "2").WillReturnError(errors.New("query error"))
 	mock.ExpectRollback()
 
-	err = mysql.ExecutePendingChanges(changes)
+	err = mysql.ExecutePendingChanges(context.Background(), changes)
 
 	if err == nil {
 		t.Fatalf("Expected error, but got nil")
@@ -1021,7 +1023,7 @@ func TestMySQL_ExecutePendingChanges_Error(t *testing.T) {
 	mock.ExpectExec(fmt.Sprintf("UPDATE %s SET `value` = \\? WHERE `id` = \\?", mysql.formatTableName(testDBNameMySQL, testDBTableNameMySQL))).WithArgs("3", "1").WillReturnError(errors.New("query error"))
 	mock.ExpectRollback()
 
-	err = mysql.ExecutePendingChanges(changes)
+	err = mysql.ExecutePendingChanges(context.Background(), changes)
 
 	if err == nil {
 		t.Fatalf("Expected error, but got nil")
@@ -1043,7 +1045,7 @@ func TestMySQL_GetPrimaryKeyColumnNames_Error(t *testing.T) {
 
 	mock.ExpectQuery("SELECT column_name FROM information_schema.key_column_usage WHERE table_schema = \\? AND table_name = \\? AND constraint_name = \\?").WithArgs(testDBNameMySQL, testDBTableNameMySQL, "PRIMARY").WillReturnError(errors.New("query error"))
 
-	_, err = mysql.GetPrimaryKeyColumnNames(testDBNameMySQL, testDBTableNameMySQL)
+	_, err = mysql.GetPrimaryKeyColumnNames(context.Background(), testDBNameMySQL, testDBTableNameMySQL)
 
 	log.Println(err.Error())
 
@@ -1124,7 +1126,7 @@ func TestMySQL_Transactions(t *testing.T) {
 // 	mysql := &MySQL{Connection: db}
 //
 // 	// Test multiple connections
-// 	err = mysql.Connect(testDBNameMySQL)
+// 	err = mysql.Connect(context.Background(), testDBNameMySQL)
 // 	if err != nil {
 // 		t.Fatalf("Failed to connect: %v", err)
 // 	}
@@ -1132,7 +1134,7 @@ func TestMySQL_Transactions(t *testing.T) {
 //
 // 	// Verify connection is reusable
 // 	for range 3 {
-// 		_, err := mysql.GetDatabases()
+// 		_, err := mysql.GetDatabases(context.Background())
 // 		if err != nil {
 // 			t.Fatalf("Failed to use connection: %v", err)
 // 		}
@@ -1150,7 +1152,7 @@ func TestMySQL_Transactions(t *testing.T) {
 //
 // 	mysql := &MySQL{Connection: db}
 //
-// 	err = mysql.Connect(testDBNameMySQL)
+// 	err = mysql.Connect(context.Background(), testDBNameMySQL)
 // 	if err != nil {
 // 		t.Fatalf("Connect failed: %v", err)
 // 	}
@@ -1172,7 +1174,7 @@ func TestMySQL_GetDatabases(t *testing.T) {
 
 	mock.ExpectQuery("SHOW DATABASES").WillReturnRows(rows)
 
-	databases, err := mysql.GetDatabases()
+	databases, err := mysql.GetDatabases(context.Background())
 	if err != nil {
 		t.Fatalf("GetDatabases failed: %v", err)
 	}
@@ -1197,13 +1199,13 @@ func TestMySQL_GetTables(t *testing.T) {
 	mysql := &MySQL{Connection: db}
 
 	// Set up mock expectations
-	rows := sqlmock.NewRows([]string{"Tables_in_test_db"}).
-		AddRow("test_table").
-		AddRow("another_table")
+	rows := sqlmock.NewRows([]string{"Tables_in_test_db", "Table_type"}).
+		AddRow("test_table", "BASE TABLE").
+		AddRow("another_table", "BASE TABLE")
 
-	mock.ExpectQuery("SHOW TABLES FROM `test_db`").WillReturnRows(rows)
+	mock.ExpectQuery(regexp.QuoteMeta("SHOW FULL TABLES FROM `test_db` WHERE Table_type = 'BASE TABLE'")).WillReturnRows(rows)
 
-	tables, err := mysql.GetTables("test_db")
+	tables, err := mysql.GetTables(context.Background(), "test_db")
 	if err != nil {
 		t.Fatalf("GetTables failed: %v", err)
 	}
@@ -1237,7 +1239,7 @@ func TestMySQL_GetTableColumns(t *testing.T) {
 
 	mock.ExpectQuery(fmt.Sprintf("SHOW FULL COLUMNS FROM %s", mysql.formatTableName(testDBNameMySQL, testDBTableNameMySQL))).WillReturnRows(rows)
 
-	columns, err := mysql.GetTableColumns(testDBNameMySQL, testDBTableNameMySQL)
+	columns, err := mysql.GetTableColumns(context.Background(), testDBNameMySQL, testDBTableNameMySQL)
 	if err != nil {
 		t.Fatalf("GetTableColumns failed: %v", err)
 	}
@@ -1274,7 +1276,7 @@ func TestMySQL_GetConstraints(t *testing.T) {
 		WithArgs(testDBNameMySQL, testDBTableNameMySQL).
 		WillReturnRows(rows)
 
-	constraints, err := mysql.GetConstraints(testDBNameMySQL, testDBTableNameMySQL)
+	constraints, err := mysql.GetConstraints(context.Background(), testDBNameMySQL, testDBTableNameMySQL)
 	if err != nil {
 		t.Fatalf("GetConstraints failed: %v", err)
 	}
@@ -1318,7 +1320,7 @@ func TestMySQL_GetForeignKeys(t *testing.T) {
 		WithArgs(testDBNameMySQL, testDBTableNameMySQL).
 		WillReturnRows(rows)
 
-	foreignKeys, err := mysql.GetForeignKeys(testDBNameMySQL, testDBTableNameMySQL)
+	foreignKeys, err := mysql.GetForeignKeys(context.Background(), testDBNameMySQL, testDBTableNameMySQL)
 	if err != nil {
 		t.Fatalf("GetForeignKeys failed: %v", err)
 	}
@@ -1355,7 +1357,7 @@ func TestMySQL_GetIndexes(t *testing.T) {
 	mock.ExpectQuery(fmt.Sprintf("SHOW INDEX FROM %s", mysql.formatTableName(testDBNameMySQL, testDBTableNameMySQL))).
 		WillReturnRows(rows)
 
-	indexes, err := mysql.GetIndexes(testDBNameMySQL, testDBTableNameMySQL)
+	indexes, err := mysql.GetIndexes(context.Background(), testDBNameMySQL, testDBTableNameMySQL)
 	if err != nil {
 		t.Fatalf("GetIndexes failed: %v", err)
 	}
@@ -1397,7 +1399,7 @@ func TestMySQL_GetRecords(t *testing.T) {
 	mock.ExpectQuery(fmt.Sprintf("SELECT COUNT\\(\\*\\) FROM %s", mysql.formatTableName(testDBNameMySQL, testDBTableNameMySQL))).
 		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
 
-	records, total, _, err := mysql.GetRecords(testDBNameMySQL, testDBTableNameMySQL, "", "", 0, DefaultRowLimit)
+	records, total, _, err := mysql.GetRecords(context.Background(), testDBNameMySQL, testDBTableNameMySQL, "", "", 0, DefaultRowLimit)
 	if err != nil {
 		t.Fatalf("GetRecords failed: %v", err)
 	}
@@ -1412,7 +1414,7 @@ func TestMySQL_GetRecords(t *testing.T) {
 		{"2", "test2", "200"},
 	}
 
-	if !reflect.DeepEqual(records, expectedRecords) {
+	if !reflect.DeepEqual(models.RecordStrings(records), expectedRecords) {
 		t.Fatalf("Expected %v, got %v", expectedRecords, records)
 	}
 
@@ -1439,7 +1441,7 @@ func TestMySQL_ExecuteQuery(t *testing.T) {
 	mock.ExpectQuery(fmt.Sprintf("SELECT \\* FROM %s", mysql.formatTableName(testDBNameMySQL, testDBTableNameMySQL))).
 		WillReturnRows(rows)
 
-	results, _, err := mysql.ExecuteQuery(fmt.Sprintf("SELECT * FROM %s", mysql.formatTableName(testDBNameMySQL, testDBTableNameMySQL)))
+	results, _, err := mysql.ExecuteQuery(context.Background(), fmt.Sprintf("SELECT * FROM %s", mysql.formatTableName(testDBNameMySQL, testDBTableNameMySQL)))
 	if err != nil {
 		t.Fatalf("ExecuteQuery failed: %v", err)
 	}
@@ -1450,7 +1452,7 @@ func TestMySQL_ExecuteQuery(t *testing.T) {
 		{"2", "Bob"},
 	}
 
-	if !reflect.DeepEqual(results, expectedResults) {
+	if !reflect.DeepEqual(models.RecordStrings(results), expectedResults) {
 		t.Fatalf("Expected results:\n%v\nGot:\n%v", expectedResults, results)
 	}
 
@@ -1472,7 +1474,7 @@ func TestMySQL_UpdateRecord(t *testing.T) {
 		WithArgs("new_name", "1").
 		WillReturnResult(sqlmock.NewResult(0, 1))
 
-	err = mysql.UpdateRecord(testDBNameMySQL, testDBTableNameMySQL, "name", "new_name", "id", "1")
+	err = mysql.UpdateRecord(context.Background(), testDBNameMySQL, testDBTableNameMySQL, "name", "new_name", "id", "1")
 	if err != nil {
 		t.Fatalf("UpdateRecord failed: %v", err)
 	}
@@ -1495,7 +1497,7 @@ func TestMySQL_DeleteRecord(t *testing.T) {
 		WithArgs("1").
 		WillReturnResult(sqlmock.NewResult(0, 1))
 
-	err = mysql.DeleteRecord(testDBNameMySQL, testDBTableNameMySQL, "id", "1")
+	err = mysql.DeleteRecord(context.Background(), testDBNameMySQL, testDBTableNameMySQL, "id", "1")
 	if err != nil {
 		t.Fatalf("DeleteRecord failed: %v", err)
 	}
@@ -1518,7 +1520,7 @@ func TestMySQL_ExecuteDMLStatement(t *testing.T) {
 	mock.ExpectExec(fmt.Sprintf("UPDATE %s SET value = 3 WHERE name = 'test1'", mysql.formatTableName(testDBNameMySQL, testDBTableNameMySQL))).
 		WillReturnResult(sqlmock.NewResult(0, 2))
 
-	result, err := mysql.ExecuteDMLStatement(fmt.Sprintf("UPDATE %s SET value = 3 WHERE name = 'test1'", mysql.formatTableName(testDBNameMySQL, testDBTableNameMySQL)))
+	result, err := mysql.ExecuteDMLStatement(context.Background(), fmt.Sprintf("UPDATE %s SET value = 3 WHERE name = 'test1'", mysql.formatTableName(testDBNameMySQL, testDBTableNameMySQL)))
 	if err != nil {
 		t.Fatalf("ExecuteDMLStatement failed: %v", err)
 	}
@@ -1575,7 +1577,7 @@ func TestMySQL_ExecutePendingChanges(t *testing.T) {
 		WillReturnResult(sqlmock.NewResult(0, 1))
 	mock.ExpectCommit()
 
-	err = mysql.ExecutePendingChanges(changes)
+	err = mysql.ExecutePendingChanges(context.Background(), changes)
 	if err != nil {
 		t.Fatalf("ExecutePendingChanges failed: %v", err)
 	}
@@ -1602,7 +1604,7 @@ func TestMySQL_GetPrimaryKeyColumnNames(t *testing.T) {
 		WithArgs(testDBNameMySQL, testDBTableNameMySQL, "PRIMARY").
 		WillReturnRows(rows)
 
-	keys, err := mysql.GetPrimaryKeyColumnNames(testDBNameMySQL, testDBTableNameMySQL)
+	keys, err := mysql.GetPrimaryKeyColumnNames(context.Background(), testDBNameMySQL, testDBTableNameMySQL)
 	if err != nil {
 		t.Fatalf("GetPrimaryKeyColumnNames failed: %v", err)
 	}
@@ -1645,3 +1647,157 @@ func TestMySQL_formatTableName(t *testing.T) {
 		t.Fatalf("formatTableName failed: got %q, expected %q", tableName, expectedTableName)
 	}
 }
+
+func TestMySQL_GetTableDefinition(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mysql := &MySQL{Connection: db}
+
+	rows := sqlmock.NewRows([]string{"Table", "Create Table"}).
+		AddRow(testDBTableNameMySQL, "CREATE TABLE `test_table` (\n  `id` int NOT NULL\n)")
+	mock.ExpectQuery(fmt.Sprintf("SHOW CREATE TABLE %s", mysql.formatTableName(testDBNameMySQL, testDBTableNameMySQL))).WillReturnRows(rows)
+
+	definition, err := mysql.GetTableDefinition(context.Background(), testDBNameMySQL, testDBTableNameMySQL)
+	if err != nil {
+		t.Fatalf("GetTableDefinition failed: %v", err)
+	}
+
+	expected := "CREATE TABLE `test_table` (\n  `id` int NOT NULL\n);"
+	if definition != expected {
+		t.Fatalf("GetTableDefinition failed: got %q, expected %q", definition, expected)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestMySQL_GetFunctions(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mysql := &MySQL{Connection: db}
+
+	rows := sqlmock.NewRows([]string{"ROUTINE_NAME"}).AddRow("add_user").AddRow("total")
+	mock.ExpectQuery("SELECT ROUTINE_NAME FROM information_schema.ROUTINES").
+		WithArgs(testDBNameMySQL, "FUNCTION").
+		WillReturnRows(rows)
+
+	functions, err := mysql.GetFunctions(context.Background(), testDBNameMySQL)
+	if err != nil {
+		t.Fatalf("GetFunctions failed: %v", err)
+	}
+
+	expected := map[string][]string{testDBNameMySQL: {"add_user", "total"}}
+	if !reflect.DeepEqual(functions, expected) {
+		t.Fatalf("GetFunctions failed: got %v, expected %v", functions, expected)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestMySQL_GetProcedureDefinition(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mysql := &MySQL{Connection: db}
+
+	columns := []string{"Procedure", "sql_mode", "Create Procedure", "character_set_client", "collation_connection", "Database Collation"}
+
+	rows := sqlmock.NewRows(columns).
+		AddRow("cleanup", "", "CREATE PROCEDURE `cleanup`() BEGIN DELETE FROM logs; END", "utf8mb4", "utf8mb4_general_ci", "utf8mb4_general_ci")
+	mock.ExpectQuery(fmt.Sprintf("SHOW CREATE PROCEDURE %s", mysql.formatTableName(testDBNameMySQL, "cleanup"))).WillReturnRows(rows)
+
+	definition, err := mysql.GetProcedureDefinition(context.Background(), testDBNameMySQL, "cleanup")
+	if err != nil {
+		t.Fatalf("GetProcedureDefinition failed: %v", err)
+	}
+	if expected := "CREATE PROCEDURE `cleanup`() BEGIN DELETE FROM logs; END;"; definition != expected {
+		t.Fatalf("GetProcedureDefinition failed: got %q, expected %q", definition, expected)
+	}
+
+	// The body of the routines of other users is NULL without the privilege
+	// to see it.
+	rows = sqlmock.NewRows(columns).AddRow("cleanup", "", nil, "utf8mb4", "utf8mb4_general_ci", "utf8mb4_general_ci")
+	mock.ExpectQuery(fmt.Sprintf("SHOW CREATE PROCEDURE %s", mysql.formatTableName(testDBNameMySQL, "cleanup"))).WillReturnRows(rows)
+
+	if _, err := mysql.GetProcedureDefinition(context.Background(), testDBNameMySQL, "cleanup"); err == nil {
+		t.Fatal("Expected an error for a hidden definition")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestMySQL_GetTriggerDefinition(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mysql := &MySQL{Connection: db}
+
+	rows := sqlmock.NewRows([]string{"Trigger", "sql_mode", "SQL Original Statement", "character_set_client", "collation_connection", "Database Collation", "Created"}).
+		AddRow("users_touch", "", "CREATE TRIGGER `users_touch` BEFORE UPDATE ON `users` FOR EACH ROW SET NEW.updated_at = NOW()", "utf8mb4", "utf8mb4_general_ci", "utf8mb4_general_ci", nil)
+	mock.ExpectQuery(fmt.Sprintf("SHOW CREATE TRIGGER %s", mysql.formatTableName(testDBNameMySQL, "users_touch"))).WillReturnRows(rows)
+
+	definition, err := mysql.GetTriggerDefinition(context.Background(), testDBNameMySQL, "users_touch")
+	if err != nil {
+		t.Fatalf("GetTriggerDefinition failed: %v", err)
+	}
+	if expected := "CREATE TRIGGER `users_touch` BEFORE UPDATE ON `users` FOR EACH ROW SET NEW.updated_at = NOW();"; definition != expected {
+		t.Fatalf("GetTriggerDefinition failed: got %q, expected %q", definition, expected)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestMySQL_GetReferencingForeignKeys(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mysql := &MySQL{Connection: db}
+
+	rows := sqlmock.NewRows([]string{"TABLE_NAME", "CONSTRAINT_NAME", "CONSTRAINT_NAME", "COLUMN_NAME", "REFERENCED_COLUMN_NAME"}).
+		AddRow("order_lines", "order_lines_order_fk", "order_lines_order_fk", "order_id", "id").
+		AddRow("order_lines", "order_lines_order_fk", "order_lines_order_fk", "shop_id", "shop_id").
+		AddRow("refunds", "refunds_order_fk", "refunds_order_fk", "order_id", "id")
+	mock.ExpectQuery("FROM information_schema.KEY_COLUMN_USAGE").
+		WithArgs(testDBNameMySQL, "orders", testDBNameMySQL).
+		WillReturnRows(rows)
+
+	keys, err := mysql.GetReferencingForeignKeys(context.Background(), testDBNameMySQL, "orders")
+	if err != nil {
+		t.Fatalf("GetReferencingForeignKeys failed: %v", err)
+	}
+	expected := []models.ReferencingForeignKey{
+		{Table: "order_lines", ForeignKeySchema: models.ForeignKeySchema{Name: "order_lines_order_fk", Columns: []string{"order_id", "shop_id"}, ReferencedColumns: []string{"id", "shop_id"}}},
+		{Table: "refunds", ForeignKeySchema: models.ForeignKeySchema{Name: "refunds_order_fk", Columns: []string{"order_id"}, ReferencedColumns: []string{"id"}}},
+	}
+	if !reflect.DeepEqual(keys, expected) {
+		t.Fatalf("GetReferencingForeignKeys failed: got %+v, expected %+v", keys, expected)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,101 @@
+package drivers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+// sequenceSchema is a sequence as read from the catalog, its values as
+// text.
+type sequenceSchema struct {
+	Name      string
+	Type      string
+	Start     string
+	Increment string
+	MinValue  string
+	MaxValue  string
+	Cached    bool
+	// Cache is the number of values cached, empty for the default.
+	Cache string
+	Cycle bool
+}
+
+func (w *ddlWriter) createSequence(sequence sequenceSchema) {
+	options := []string{
+		"AS " + sequence.Type,
+		"START WITH " + sequence.Start,
+		"INCREMENT BY " + sequence.Increment,
+		"MINVALUE " + sequence.MinValue,
+		"MAXVALUE " + sequence.MaxValue,
+	}
+	switch {
+	case !sequence.Cached:
+		options = append(options, "NO CACHE")
+	case sequence.Cache != "":
+		options = append(options, "CACHE "+sequence.Cache)
+	default:
+		options = append(options, "CACHE")
+	}
+	if sequence.Cycle {
+		options = append(options, "CYCLE")
+	} else {
+		options = append(options, "NO CYCLE")
+	}
+
+	w.add("CREATE SEQUENCE %s\n  %s;", w.table(sequence.Name), strings.Join(options, "\n  "))
+}
+
+func (w *ddlWriter) createEnum(name string, labels []string) {
+	quoted := make([]string, len(labels))
+	for i, label := range labels {
+		quoted[i] = "'" + strings.ReplaceAll(label, "'", "''") + "'"
+	}
+
+	w.add("CREATE TYPE %s AS ENUM (\n  %s\n);", w.table(name), strings.Join(quoted, ",\n  "))
+}
+
+// createCompositeType writes the CREATE TYPE statement of a type made of
+// columns: a composite type on Postgres, a table type on MSSQL.
+func (w *ddlWriter) createCompositeType(name string, columns []models.ColumnSchema) {
+	definitions := make([]string, len(columns))
+	for i, column := range columns {
+		definitions[i] = w.columnDefinition(column)
+	}
+
+	as := "AS"
+	if w.provider == DriverMSSQL {
+		as = "AS TABLE"
+	}
+	w.add("CREATE TYPE %s %s (\n  %s\n);", w.table(name), as, strings.Join(definitions, ",\n  "))
+}
+
+// createDomain writes the CREATE DOMAIN statement of a Postgres domain,
+// constraints being its CHECK constraints as e.g. "CONSTRAINT positive
+// CHECK (VALUE > 0)".
+func (w *ddlWriter) createDomain(name, baseType string, notNull bool, defaultValue string, constraints []string) {
+	definition := fmt.Sprintf("CREATE DOMAIN %s AS %s", w.table(name), baseType)
+	if notNull {
+		definition += "\n  NOT NULL"
+	}
+	if defaultValue != "" {
+		definition += "\n  DEFAULT " + defaultValue
+	}
+	for _, constraint := range constraints {
+		definition += "\n  " + constraint
+	}
+
+	w.add("%s;", definition)
+}
+
+// createAliasType writes the CREATE TYPE statement of a MSSQL alias type of
+// baseType.
+func (w *ddlWriter) createAliasType(name, baseType string, nullable bool) {
+	null := " NULL"
+	if !nullable {
+		null = " NOT NULL"
+	}
+
+	w.add("CREATE TYPE %s FROM %s%s;", w.table(name), baseType, null)
+}
@@ -0,0 +1,153 @@
+package drivers
+
+import (
+	"testing"
+
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+func TestCreateSequence(t *testing.T) {
+	sequence := sequenceSchema{
+		Name:      "sales.order_number",
+		Type:      "bigint",
+		Start:     "1000",
+		Increment: "1",
+		MinValue:  "1",
+		MaxValue:  "9223372036854775807",
+	}
+
+	tests := []struct {
+		name     string
+		db       Driver
+		cached   bool
+		cache    string
+		expected string
+	}{
+		{
+			name:   "Postgres",
+			db:     &Postgres{Provider: DriverPostgres},
+			cached: true,
+			cache:  "1",
+			expected: `CREATE SEQUENCE "sales"."order_number"` + "\n" +
+				"  AS bigint\n  START WITH 1000\n  INCREMENT BY 1\n  MINVALUE 1\n  MAXVALUE 9223372036854775807\n  CACHE 1\n  NO CYCLE;",
+		},
+		{
+			name:   "MSSQL with the default cache",
+			db:     &MSSQL{Provider: DriverMSSQL},
+			cached: true,
+			expected: "CREATE SEQUENCE [sales].[order_number]\n" +
+				"  AS bigint\n  START WITH 1000\n  INCREMENT BY 1\n  MINVALUE 1\n  MAXVALUE 9223372036854775807\n  CACHE\n  NO CYCLE;",
+		},
+		{
+			name: "MSSQL without cache",
+			db:   &MSSQL{Provider: DriverMSSQL},
+			expected: "CREATE SEQUENCE [sales].[order_number]\n" +
+				"  AS bigint\n  START WITH 1000\n  INCREMENT BY 1\n  MINVALUE 1\n  MAXVALUE 9223372036854775807\n  NO CACHE\n  NO CYCLE;",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sequence := sequence
+			sequence.Cached, sequence.Cache = tt.cached, tt.cache
+
+			w := &ddlWriter{provider: tt.db.GetProvider(), reference: tt.db.FormatReference}
+			w.createSequence(sequence)
+			if w.statements[0] != tt.expected {
+				t.Fatalf("Statement mismatch:\nexpected: %s\ngot: %s", tt.expected, w.statements[0])
+			}
+		})
+	}
+}
+
+func TestCreateTypes(t *testing.T) {
+	postgres := &Postgres{Provider: DriverPostgres}
+	mssql := &MSSQL{Provider: DriverMSSQL}
+
+	tests := []struct {
+		name     string
+		db       Driver
+		write    func(w *ddlWriter)
+		expected string
+	}{
+		{
+			name: "Postgres composite type",
+			db:   postgres,
+			write: func(w *ddlWriter) {
+				w.createCompositeType("public.address", []models.ColumnSchema{
+					{Name: "street", Type: "text", Nullable: true},
+					{Name: "zip", Type: "character varying(10)", Nullable: true},
+				})
+			},
+			expected: `CREATE TYPE "public"."address" AS (` + "\n" +
+				`  "street" text,` + "\n" +
+				`  "zip" character varying(10)` + "\n" +
+				");",
+		},
+		{
+			name: "Postgres domain",
+			db:   postgres,
+			write: func(w *ddlWriter) {
+				w.createDomain("public.price", "numeric(10,2)", true, "0", []string{"CONSTRAINT price_check CHECK (VALUE >= 0)"})
+			},
+			expected: `CREATE DOMAIN "public"."price" AS numeric(10,2)` + "\n" +
+				"  NOT NULL\n" +
+				"  DEFAULT 0\n" +
+				"  CONSTRAINT price_check CHECK (VALUE >= 0);",
+		},
+		{
+			name: "MSSQL table type",
+			db:   mssql,
+			write: func(w *ddlWriter) {
+				w.createCompositeType("dbo.order_lines", []models.ColumnSchema{
+					{Name: "product_id", Type: "int"},
+					{Name: "note", Type: "nvarchar(200)", Nullable: true},
+				})
+			},
+			expected: "CREATE TYPE [dbo].[order_lines] AS TABLE (\n" +
+				"  [product_id] int NOT NULL,\n" +
+				"  [note] nvarchar(200)\n" +
+				");",
+		},
+		{
+			name: "MSSQL alias type",
+			db:   mssql,
+			write: func(w *ddlWriter) {
+				w.createAliasType("dbo.phone", "varchar(20)", false)
+			},
+			expected: "CREATE TYPE [dbo].[phone] FROM varchar(20) NOT NULL;",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &ddlWriter{provider: tt.db.GetProvider(), reference: tt.db.FormatReference}
+			tt.write(w)
+			if w.statements[0] != tt.expected {
+				t.Fatalf("Statement mismatch:\nexpected: %s\ngot: %s", tt.expected, w.statements[0])
+			}
+		})
+	}
+}
+
+func TestMSSQLColumnType(t *testing.T) {
+	tests := []struct {
+		typeName  string
+		maxLength int
+		precision int
+		scale     int
+		expected  string
+	}{
+		{"nvarchar", 100, 0, 0, "nvarchar(50)"},
+		{"varchar", -1, 0, 0, "varchar(max)"},
+		{"decimal", 9, 10, 2, "decimal(10, 2)"},
+		{"datetime2", 8, 27, 7, "datetime2(7)"},
+		{"int", 4, 10, 0, "int"},
+	}
+
+	for _, tt := range tests {
+		if got := mssqlColumnType(tt.typeName, tt.maxLength, tt.precision, tt.scale); got != tt.expected {
+			t.Errorf("mssqlColumnType(%q, %d, %d, %d) = %q, expected %q", tt.typeName, tt.maxLength, tt.precision, tt.scale, got, tt.expected)
+		}
+	}
+}
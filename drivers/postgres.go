@@ -1,11 +1,13 @@
 package drivers
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
 	"strconv"
 	"strings"
+	"sync"
 
 	// import postgresql driver
 	_ "github.com/lib/pq"
@@ -21,46 +23,69 @@ type Postgres struct {
 	CurrentDatabase  string
 	PreviousDatabase string
 	Urlstr           string
+	// InitStatements are run on every connection opened, including those
+	// to the other databases, see SessionStatements.
+	InitStatements []string
+
+	// mu guards Connection, replaced when connecting again while other
+	// goroutines use it.
+	mu sync.RWMutex
+	tx transaction
 }
 
-func (db *Postgres) TestConnection(urlstr string) error {
-	return db.Connect(urlstr)
+func (db *Postgres) TestConnection(ctx context.Context, urlstr string) error {
+	return db.Connect(ctx, urlstr)
 }
 
-func (db *Postgres) Connect(urlstr string) error {
+func (db *Postgres) Connect(ctx context.Context, urlstr string) error {
 	db.SetProvider(DriverPostgres)
 
-	connection, err := dburl.Open(urlstr)
+	connection, err := openDB(urlstr, db.InitStatements)
 	if err != nil {
 		return err
 	}
 
-	db.Connection = connection
-
-	err = db.Connection.Ping()
+	err = pingPool(ctx, connection)
 	if err != nil {
 		return err
 	}
 
-	db.Urlstr = urlstr
-
 	// Get the current database.
-	rows := db.Connection.QueryRow("SELECT current_database();")
+	rows := connection.QueryRowContext(ctx, "SELECT current_database();")
 
 	database := ""
 	err = rows.Scan(&database)
 	if err != nil {
-		return err
+		return errors.Join(err, connection.Close())
 	}
 
+	db.mu.Lock()
+	previous := db.Connection
+	db.Connection = connection
+	db.Urlstr = urlstr
 	db.CurrentDatabase = database
 	db.PreviousDatabase = database
+	db.mu.Unlock()
+
+	db.tx.replacePool(previous)
 
 	return nil
 }
 
-func (db *Postgres) GetDatabases() ([]string, error) {
-	rows, err := db.Connection.Query("SELECT datname FROM pg_database WHERE datallowconn AND has_database_privilege(current_user, datname, 'CONNECT');")
+// pool returns the connection pool, replaced when connecting again.
+func (db *Postgres) pool() *sql.DB {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	return db.Connection
+}
+
+func (db *Postgres) Ping(ctx context.Context) error {
+	return db.pool().PingContext(ctx)
+}
+
+func (db *Postgres) GetDatabases(ctx context.Context) ([]string, error) {
+	rows, err := db.pool().QueryContext(ctx, "SELECT datname FROM pg_database WHERE datallowconn AND has_database_privilege(current_user, datname, 'CONNECT');")
 	if err != nil {
 		return nil, err
 	}
@@ -82,7 +107,7 @@ func (db *Postgres) GetDatabases() ([]string, error) {
 	return databases, nil
 }
 
-func (db *Postgres) GetTables(database string) (map[string][]string, error) {
+func (db *Postgres) GetTables(ctx context.Context, database string) (map[string][]string, error) {
 	if database == "" {
 		return nil, errors.New("database name is required")
 	}
@@ -96,7 +121,7 @@ func (db *Postgres) GetTables(database string) (map[string][]string, error) {
 	}
 
 	query := "SELECT table_name, table_schema FROM information_schema.tables WHERE table_catalog = $1"
-	rows, err := conn.Query(query, database)
+	rows, err := conn.QueryContext(ctx, query, database)
 	if err != nil {
 		return nil, err
 	}
@@ -121,7 +146,7 @@ func (db *Postgres) GetTables(database string) (map[string][]string, error) {
 	return tables, nil
 }
 
-func (db *Postgres) GetTableColumns(database, table string) ([][]string, error) {
+func (db *Postgres) GetTableColumns(ctx context.Context, database, table string) ([][]string, error) {
 	if database == "" {
 		return nil, errors.New("database name is required")
 	}
@@ -146,9 +171,12 @@ func (db *Postgres) GetTableColumns(database, table string) ([][]string, error)
 	tableSchema := splitTableString[0]
 	tableName := splitTableString[1]
 
-	query := "SELECT c.column_name, c.data_type, c.is_nullable, c.column_default, COALESCE(pd.description, '') as comment FROM information_schema.columns c LEFT JOIN pg_class pc ON pc.relname = c.table_name AND pc.relnamespace = (SELECT oid FROM pg_namespace WHERE nspname = c.table_schema) LEFT JOIN pg_namespace pn ON pn.nspname = c.table_schema AND pn.oid = pc.relnamespace LEFT JOIN pg_description pd ON pd.objoid = pc.oid AND pd.objsubid = c.ordinal_position WHERE c.table_catalog = $1 AND c.table_schema = $2 AND c.table_name = $3 ORDER by c.ordinal_position"
+	// The type is read from format_type, with its length, precision, array
+	// dimensions or enum name, as the DDL written from it recreates the
+	// column.
+	query := "SELECT c.column_name, format_type(a.atttypid, a.atttypmod) AS data_type, c.is_nullable, c.column_default, COALESCE(pd.description, '') as comment FROM information_schema.columns c JOIN pg_namespace pn ON pn.nspname = c.table_schema JOIN pg_class pc ON pc.relname = c.table_name AND pc.relnamespace = pn.oid JOIN pg_attribute a ON a.attrelid = pc.oid AND a.attname = c.column_name LEFT JOIN pg_description pd ON pd.objoid = pc.oid AND pd.objsubid = a.attnum WHERE c.table_catalog = $1 AND c.table_schema = $2 AND c.table_name = $3 ORDER by c.ordinal_position"
 
-	rows, err := conn.Query(query, database, tableSchema, tableName)
+	rows, err := conn.QueryContext(ctx, query, database, tableSchema, tableName)
 	if err != nil {
 		return nil, err
 	}
@@ -186,7 +214,7 @@ func (db *Postgres) GetTableColumns(database, table string) ([][]string, error)
 	return results, nil
 }
 
-func (db *Postgres) GetConstraints(database, table string) ([][]string, error) {
+func (db *Postgres) GetConstraints(ctx context.Context, database, table string) ([][]string, error) {
 	if database == "" {
 		return nil, errors.New("database name is required")
 	}
@@ -210,7 +238,7 @@ func (db *Postgres) GetConstraints(database, table string) ([][]string, error) {
 	tableSchema := splitTableString[0]
 	tableName := splitTableString[1]
 
-	rows, err := conn.Query(fmt.Sprintf(`
+	rows, err := conn.QueryContext(ctx, fmt.Sprintf(`
         SELECT
             tc.constraint_name,
             kcu.column_name,
@@ -261,7 +289,7 @@ func (db *Postgres) GetConstraints(database, table string) ([][]string, error) {
 	return constraints, nil
 }
 
-func (db *Postgres) GetForeignKeys(database, table string) ([][]string, error) {
+func (db *Postgres) GetForeignKeys(ctx context.Context, database, table string) ([][]string, error) {
 	if database == "" {
 		return nil, errors.New("database name is required")
 	}
@@ -285,7 +313,7 @@ func (db *Postgres) GetForeignKeys(database, table string) ([][]string, error) {
 	tableSchema := splitTableString[0]
 	tableName := splitTableString[1]
 
-	rows, err := conn.Query(fmt.Sprintf(`
+	rows, err := conn.QueryContext(ctx, fmt.Sprintf(`
         SELECT
             con.conname AS constraint_name,
             src_att.attname AS column_name,
@@ -340,7 +368,42 @@ func (db *Postgres) GetForeignKeys(database, table string) ([][]string, error) {
 	return foreignKeys, nil
 }
 
-func (db *Postgres) GetIndexes(database, table string) ([][]string, error) {
+func (db *Postgres) GetReferencingForeignKeys(ctx context.Context, database, table string) ([]models.ReferencingForeignKey, error) {
+	schema, tableName, err := splitSchemaName(database, table, "table")
+	if err != nil {
+		return nil, err
+	}
+
+	conn, needsClose, err := db.connectionFor(database)
+	if err != nil {
+		return nil, err
+	}
+	if needsClose {
+		defer conn.Close()
+	}
+
+	rows, err := conn.QueryContext(ctx, `
+		SELECT src_ns.nspname || '.' || src_cls.relname, con.conname, con.conname, src_att.attname, ref_att.attname
+		FROM pg_catalog.pg_constraint con
+		JOIN pg_catalog.pg_class src_cls ON src_cls.oid = con.conrelid
+		JOIN pg_catalog.pg_namespace src_ns ON src_ns.oid = src_cls.relnamespace
+		JOIN pg_catalog.pg_class ref_cls ON ref_cls.oid = con.confrelid
+		JOIN pg_catalog.pg_namespace ref_ns ON ref_ns.oid = ref_cls.relnamespace
+		CROSS JOIN LATERAL unnest(con.conkey, con.confkey) WITH ORDINALITY AS k(attnum, refattnum, position)
+		JOIN pg_catalog.pg_attribute src_att ON src_att.attrelid = con.conrelid AND src_att.attnum = k.attnum
+		JOIN pg_catalog.pg_attribute ref_att ON ref_att.attrelid = con.confrelid AND ref_att.attnum = k.refattnum
+		WHERE con.contype = 'f' AND ref_ns.nspname = $1 AND ref_cls.relname = $2
+		ORDER BY src_ns.nspname, src_cls.relname, con.conname, k.position
+	`, schema, tableName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanReferencingForeignKeys(rows)
+}
+
+func (db *Postgres) GetIndexes(ctx context.Context, database, table string) ([][]string, error) {
 	if database == "" {
 		return nil, errors.New("database name is required")
 	}
@@ -364,7 +427,7 @@ func (db *Postgres) GetIndexes(database, table string) ([][]string, error) {
 	tableSchema := splitTableString[0]
 	tableName := splitTableString[1]
 
-	rows, err := conn.Query(fmt.Sprintf(`
+	rows, err := conn.QueryContext(ctx, fmt.Sprintf(`
         SELECT
             i.relname AS index_name,
             a.attname AS column_name,
@@ -425,7 +488,7 @@ func (db *Postgres) GetIndexes(database, table string) ([][]string, error) {
 	return indexes, nil
 }
 
-func (db *Postgres) GetRecords(database, table, where, sort string, offset, limit int) (records [][]string, totalRecords int, queryString string, err error) {
+func (db *Postgres) GetRecords(ctx context.Context, database, table, where, sort string, offset, limit int) (records []models.Record, totalRecords int, queryString string, err error) {
 	if database == "" {
 		return nil, 0, "", errors.New("database name is required")
 	}
@@ -463,51 +526,15 @@ func (db *Postgres) GetRecords(database, table, where, sort string, offset, limi
 		limit = DefaultRowLimit
 	}
 
-	paginatedRows, err := conn.Query(queryString, limit, offset)
+	paginatedRows, err := db.tx.on(conn).QueryContext(ctx, queryString, limit, offset)
 	if err != nil {
 		return nil, 0, queryString, err
 	}
 	defer paginatedRows.Close()
 
-	columns, columnsError := paginatedRows.Columns()
-	if columnsError != nil {
-		return nil, 0, queryString, columnsError
-	}
-
-	records = [][]string{columns}
-	for paginatedRows.Next() {
-		nullStringSlice := make([]sql.NullString, len(columns))
-
-		rowValues := make([]any, len(columns))
-		for i := range nullStringSlice {
-			rowValues[i] = &nullStringSlice[i]
-		}
-
-		if err := paginatedRows.Scan(rowValues...); err != nil {
-			return nil, 0, queryString, err
-		}
-
-		var row []string
-		for _, col := range nullStringSlice {
-			if col.Valid {
-				if col.String == "" {
-					row = append(row, "EMPTY&")
-				} else {
-					row = append(row, col.String)
-				}
-			} else {
-				row = append(row, "NULL&")
-			}
-		}
-
-		records = append(records, row)
-	}
-
-	if err := paginatedRows.Err(); err != nil {
-		return nil, 0, queryString, err
-	}
-	// close to release the connection
-	if err := paginatedRows.Close(); err != nil {
+	// reading the records closes the rows to release the connection
+	records, err = readRecords(paginatedRows)
+	if err != nil {
 		return nil, 0, queryString, err
 	}
 
@@ -518,7 +545,7 @@ func (db *Postgres) GetRecords(database, table, where, sort string, offset, limi
 		countQuery += fmt.Sprintf(" %s", where)
 	}
 
-	countRow := conn.QueryRow(countQuery)
+	countRow := db.tx.on(conn).QueryRowContext(ctx, countQuery)
 
 	if err := countRow.Scan(&totalRecords); err != nil {
 		return records, 0, queryString, err
@@ -531,7 +558,7 @@ func (db *Postgres) GetRecords(database, table, where, sort string, offset, limi
 	return records, totalRecords, queryString, nil
 }
 
-func (db *Postgres) UpdateRecord(database, table, column, value, primaryKeyColumnName, primaryKeyValue string) error {
+func (db *Postgres) UpdateRecord(ctx context.Context, database, table, column, value, primaryKeyColumnName, primaryKeyValue string) error {
 	if database == "" {
 		return errors.New("database name is required")
 	}
@@ -569,11 +596,15 @@ func (db *Postgres) UpdateRecord(database, table, column, value, primaryKeyColum
 	query += formattedTableName
 	query += fmt.Sprintf(" SET \"%s\" = $1 WHERE \"%s\" = $2", column, primaryKeyColumnName)
 
-	_, err = conn.Exec(query, value, primaryKeyValue)
+	q, err := db.tx.writer(conn)
+	if err != nil {
+		return err
+	}
+	_, err = q.ExecContext(ctx, query, value, primaryKeyValue)
 	return err
 }
 
-func (db *Postgres) DeleteRecord(database, table, primaryKeyColumnName, primaryKeyValue string) error {
+func (db *Postgres) DeleteRecord(ctx context.Context, database, table, primaryKeyColumnName, primaryKeyValue string) error {
 	if database == "" {
 		return errors.New("database name is required")
 	}
@@ -604,12 +635,20 @@ func (db *Postgres) DeleteRecord(database, table, primaryKeyColumnName, primaryK
 	query += formattedTableName
 	query += fmt.Sprintf(" WHERE \"%s\" = $1", primaryKeyColumnName)
 
-	_, err = conn.Exec(query, primaryKeyValue)
+	q, err := db.tx.writer(conn)
+	if err != nil {
+		return err
+	}
+	_, err = q.ExecContext(ctx, query, primaryKeyValue)
 	return err
 }
 
-func (db *Postgres) ExecuteDMLStatement(query string) (result string, err error) {
-	res, err := db.Connection.Exec(query)
+func (db *Postgres) ExecuteDMLStatement(ctx context.Context, query string, args ...any) (result string, err error) {
+	q, err := db.tx.writer(db.pool())
+	if err != nil {
+		return "", err
+	}
+	res, err := q.ExecContext(ctx, query, args...)
 	if err != nil {
 		return result, err
 	}
@@ -620,48 +659,35 @@ func (db *Postgres) ExecuteDMLStatement(query string) (result string, err error)
 	return fmt.Sprintf("%d rows affected", rowsAffected), nil
 }
 
-func (db *Postgres) ExecuteQuery(query string) ([][]string, int, error) {
-	rows, err := db.Connection.Query(query)
+func (db *Postgres) ExecuteQuery(ctx context.Context, query string, args ...any) ([]models.Record, int, error) {
+	rows, err := db.QueryRows(ctx, query, args...)
 	if err != nil {
 		return nil, 0, err
 	}
-	defer rows.Close()
 
-	columns, err := rows.Columns()
+	return readAllRows(rows)
+}
+
+func (db *Postgres) QueryRows(ctx context.Context, query string, args ...any) (Rows, error) {
+	rows, err := db.tx.on(db.pool()).QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 
-	records := make([][]string, 0)
-	for rows.Next() {
-		rowValues := make([]any, len(columns))
-		for i := range columns {
-			rowValues[i] = new(sql.RawBytes)
-		}
-
-		err = rows.Scan(rowValues...)
-		if err != nil {
-			return nil, 0, err
-		}
-
-		var row []string
-		for _, col := range rowValues {
-			row = append(row, string(*col.(*sql.RawBytes)))
-		}
+	return newSQLRows(rows)
+}
 
-		records = append(records, row)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, 0, err
+func (db *Postgres) ExplainQuery(ctx context.Context, query string, args ...any) (*models.PlanNode, error) {
+	var plan string
+	err := db.tx.on(db.pool()).QueryRowContext(ctx, "EXPLAIN (FORMAT JSON) "+query, args...).Scan(&plan)
+	if err != nil {
+		return nil, err
 	}
 
-	// Prepend the columns to the records.
-	results := append([][]string{columns}, records...)
-
-	return results, len(records), nil
+	return parsePostgresPlan([]byte(plan))
 }
 
-func (db *Postgres) ExecutePendingChanges(changes []models.DBDMLChange) error {
+func (db *Postgres) ExecutePendingChanges(ctx context.Context, changes []models.DBDMLChange) error {
 	var queries []models.Query
 
 	for _, change := range changes {
@@ -682,10 +708,10 @@ func (db *Postgres) ExecutePendingChanges(changes []models.DBDMLChange) error {
 		}
 	}
 
-	return queriesInTransaction(db.Connection, queries)
+	return db.tx.execQueries(ctx, db.pool(), queries)
 }
 
-func (db *Postgres) GetPrimaryKeyColumnNames(database, table string) ([]string, error) {
+func (db *Postgres) GetPrimaryKeyColumnNames(ctx context.Context, database, table string) ([]string, error) {
 	if database == "" {
 		return nil, errors.New("database name is required")
 	}
@@ -709,7 +735,7 @@ func (db *Postgres) GetPrimaryKeyColumnNames(database, table string) ([]string,
 		defer conn.Close()
 	}
 
-	row, err := conn.Query(`
+	row, err := conn.QueryContext(ctx, `
 		SELECT
 			a.attname AS column_name
 		FROM
@@ -758,6 +784,22 @@ func (db *Postgres) GetProvider() string {
 	return db.Provider
 }
 
+func (db *Postgres) BeginTransaction(ctx context.Context) error {
+	return db.tx.begin(ctx, db.pool())
+}
+
+func (db *Postgres) CommitTransaction() error {
+	return db.tx.commit()
+}
+
+func (db *Postgres) RollbackTransaction() error {
+	return db.tx.rollback()
+}
+
+func (db *Postgres) InTransaction() bool {
+	return db.tx.active()
+}
+
 func dsnValue(dsn, key string) string {
 	prefix := key + "="
 	for _, part := range strings.Split(dsn, " ") {
@@ -790,11 +832,13 @@ func buildReconnectURL(urlstr, newDB string) (string, error) {
 // connectToDatabase opens a new connection to the given database without
 // mutating the receiver. The caller must close the returned connection.
 func (db *Postgres) connectToDatabase(database string) (*sql.DB, error) {
+	db.mu.RLock()
 	urlstr, err := buildReconnectURL(db.Urlstr, database)
+	db.mu.RUnlock()
 	if err != nil {
 		return nil, err
 	}
-	return dburl.Open(urlstr)
+	return openDB(urlstr, db.InitStatements)
 }
 
 // connectionFor returns a connection to the given database. If it matches
@@ -802,8 +846,13 @@ func (db *Postgres) connectToDatabase(database string) (*sql.DB, error) {
 // close it). Otherwise a new temporary connection is opened and returned
 // (caller MUST close it).
 func (db *Postgres) connectionFor(database string) (conn *sql.DB, needsClose bool, err error) {
-	if database == db.CurrentDatabase {
-		return db.Connection, false, nil
+	db.mu.RLock()
+	current := db.Connection
+	currentDatabase := db.CurrentDatabase
+	db.mu.RUnlock()
+
+	if database == currentDatabase {
+		return current, false, nil
 	}
 	conn, err = db.connectToDatabase(database)
 	if err != nil {
@@ -813,11 +862,18 @@ func (db *Postgres) connectionFor(database string) (conn *sql.DB, needsClose boo
 }
 
 func (db *Postgres) SwitchDatabase(database string) error {
+	if db.tx.active() {
+		return ErrTransactionOpen
+	}
+
 	conn, err := db.connectToDatabase(database)
 	if err != nil {
 		return err
 	}
 
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
 	err = db.Connection.Close()
 	if err != nil {
 		if closeErr := conn.Close(); closeErr != nil {
@@ -936,7 +992,7 @@ func (db *Postgres) DMLChangeToQueryString(change models.DBDMLChange) (string, e
 	return queryStr, nil
 }
 
-func (db *Postgres) GetFunctions(database string) (map[string][]string, error) {
+func (db *Postgres) GetFunctions(ctx context.Context, database string) (map[string][]string, error) {
 	if database == "" {
 		return nil, errors.New("database name is required")
 	}
@@ -949,7 +1005,7 @@ func (db *Postgres) GetFunctions(database string) (map[string][]string, error) {
 		defer conn.Close()
 	}
 
-	rows, err := conn.Query(`
+	rows, err := conn.QueryContext(ctx, `
 		SELECT n.nspname || '.' || p.proname
 		FROM pg_catalog.pg_proc p
 		JOIN pg_catalog.pg_namespace n ON n.oid = p.pronamespace
@@ -977,7 +1033,7 @@ func (db *Postgres) GetFunctions(database string) (map[string][]string, error) {
 	return functions, nil
 }
 
-func (db *Postgres) GetProcedures(database string) (map[string][]string, error) {
+func (db *Postgres) GetProcedures(ctx context.Context, database string) (map[string][]string, error) {
 	if database == "" {
 		return nil, errors.New("database name is required")
 	}
@@ -990,7 +1046,7 @@ func (db *Postgres) GetProcedures(database string) (map[string][]string, error)
 		defer conn.Close()
 	}
 
-	rows, err := conn.Query(`
+	rows, err := conn.QueryContext(ctx, `
 		SELECT n.nspname || '.' || p.proname
 		FROM pg_catalog.pg_proc p
 		JOIN pg_catalog.pg_namespace n ON n.oid = p.pronamespace
@@ -1018,7 +1074,7 @@ func (db *Postgres) GetProcedures(database string) (map[string][]string, error)
 	return procedures, nil
 }
 
-func (db *Postgres) GetViews(database string) (map[string][]string, error) {
+func (db *Postgres) GetViews(ctx context.Context, database string) (map[string][]string, error) {
 	if database == "" {
 		return nil, errors.New("database name is required")
 	}
@@ -1031,7 +1087,7 @@ func (db *Postgres) GetViews(database string) (map[string][]string, error) {
 		defer conn.Close()
 	}
 
-	rows, err := conn.Query(`
+	rows, err := conn.QueryContext(ctx, `
 		SELECT table_schema || '.' || table_name
 		FROM information_schema.views
 		WHERE table_catalog = $1
@@ -1066,7 +1122,7 @@ func (db *Postgres) UseSchemas() bool {
 	return true
 }
 
-func (db *Postgres) GetFunctionDefinition(database, name string) (string, error) {
+func (db *Postgres) GetFunctionDefinition(ctx context.Context, database, name string) (string, error) {
 	if database == "" {
 		return "", errors.New("database name is required")
 	}
@@ -1088,7 +1144,7 @@ func (db *Postgres) GetFunctionDefinition(database, name string) (string, error)
 	}
 
 	var result string
-	row := conn.QueryRow(`
+	row := conn.QueryRowContext(ctx, `
 		SELECT pg_get_functiondef(p.oid)
 		FROM pg_catalog.pg_proc p
 		JOIN pg_catalog.pg_namespace n ON n.oid = p.pronamespace
@@ -1102,7 +1158,7 @@ func (db *Postgres) GetFunctionDefinition(database, name string) (string, error)
 	return result, nil
 }
 
-func (db *Postgres) GetProcedureDefinition(database, name string) (string, error) {
+func (db *Postgres) GetProcedureDefinition(ctx context.Context, database, name string) (string, error) {
 	if database == "" {
 		return "", errors.New("database name is required")
 	}
@@ -1124,7 +1180,7 @@ func (db *Postgres) GetProcedureDefinition(database, name string) (string, error
 	}
 
 	var result string
-	row := conn.QueryRow(`
+	row := conn.QueryRowContext(ctx, `
 		SELECT pg_get_functiondef(p.oid)
 		FROM pg_catalog.pg_proc p
 		JOIN pg_catalog.pg_namespace n ON n.oid = p.pronamespace
@@ -1139,7 +1195,20 @@ func (db *Postgres) GetProcedureDefinition(database, name string) (string, error
 	return result, nil
 }
 
-func (db *Postgres) GetViewDefinition(database, name string) (string, error) {
+// GetTableDefinition rebuilds the statements that create table from the
+// catalog, Postgres has no statement returning them.
+func (db *Postgres) GetTableDefinition(ctx context.Context, database, table string) (string, error) {
+	if database == "" {
+		return "", errors.New("database name is required")
+	}
+	if len(strings.Split(table, ".")) != 2 {
+		return "", errors.New("table must be in the format schema.table")
+	}
+
+	return tableDefinition(ctx, db, database, table)
+}
+
+func (db *Postgres) GetViewDefinition(ctx context.Context, database, name string) (string, error) {
 	if database == "" {
 		return "", errors.New("database name is required")
 	}
@@ -1161,7 +1230,7 @@ func (db *Postgres) GetViewDefinition(database, name string) (string, error) {
 	}
 
 	var result string
-	row := conn.QueryRow(`
+	row := conn.QueryRowContext(ctx, `
 		SELECT definition
 		FROM pg_catalog.pg_views
 		WHERE schemaname = $1 AND viewname = $2
@@ -1172,3 +1241,341 @@ func (db *Postgres) GetViewDefinition(database, name string) (string, error) {
 
 	return result, nil
 }
+
+func (db *Postgres) GetTriggers(ctx context.Context, database string) (map[string][]string, error) {
+	return db.getObjectNames(ctx, database, `
+		SELECT DISTINCT n.nspname || '.' || t.tgname
+		FROM pg_catalog.pg_trigger t
+		JOIN pg_catalog.pg_class c ON c.oid = t.tgrelid
+		JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+		WHERE NOT t.tgisinternal
+		AND n.nspname NOT IN ('pg_catalog', 'information_schema')
+		ORDER BY 1
+	`)
+}
+
+func (db *Postgres) GetSequences(ctx context.Context, database string) (map[string][]string, error) {
+	return db.getObjectNames(ctx, database, `
+		SELECT schemaname || '.' || sequencename
+		FROM pg_catalog.pg_sequences
+		WHERE schemaname NOT IN ('pg_catalog', 'information_schema')
+		ORDER BY schemaname, sequencename
+	`)
+}
+
+func (db *Postgres) GetMaterializedViews(ctx context.Context, database string) (map[string][]string, error) {
+	return db.getObjectNames(ctx, database, `
+		SELECT schemaname || '.' || matviewname
+		FROM pg_catalog.pg_matviews
+		WHERE schemaname NOT IN ('pg_catalog', 'information_schema')
+		ORDER BY schemaname, matviewname
+	`)
+}
+
+// GetTypes lists the enums, the domains and the composite types created
+// with CREATE TYPE, not those of the tables.
+func (db *Postgres) GetTypes(ctx context.Context, database string) (map[string][]string, error) {
+	return db.getObjectNames(ctx, database, `
+		SELECT n.nspname || '.' || t.typname
+		FROM pg_catalog.pg_type t
+		JOIN pg_catalog.pg_namespace n ON n.oid = t.typnamespace
+		LEFT JOIN pg_catalog.pg_class c ON c.oid = t.typrelid
+		WHERE t.typtype IN ('e', 'c', 'd')
+		AND (t.typtype <> 'c' OR c.relkind = 'c')
+		AND n.nspname NOT IN ('pg_catalog', 'information_schema')
+		AND n.nspname NOT LIKE 'pg_toast%'
+		ORDER BY 1
+	`)
+}
+
+// getObjectNames returns the schema-qualified names query returns in
+// database, keyed by database.
+func (db *Postgres) getObjectNames(ctx context.Context, database, query string) (map[string][]string, error) {
+	if database == "" {
+		return nil, errors.New("database name is required")
+	}
+
+	conn, needsClose, err := db.connectionFor(database)
+	if err != nil {
+		return nil, err
+	}
+	if needsClose {
+		defer conn.Close()
+	}
+
+	rows, err := conn.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	names := make(map[string][]string)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names[database] = append(names[database], name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return names, nil
+}
+
+// GetTriggerDefinition returns the CREATE TRIGGER statements of the
+// triggers named name of the schema, one per table having one.
+func (db *Postgres) GetTriggerDefinition(ctx context.Context, database, name string) (string, error) {
+	schema, trigger, err := splitSchemaName(database, name, "trigger")
+	if err != nil {
+		return "", err
+	}
+
+	conn, needsClose, err := db.connectionFor(database)
+	if err != nil {
+		return "", err
+	}
+	if needsClose {
+		defer conn.Close()
+	}
+
+	rows, err := conn.QueryContext(ctx, `
+		SELECT pg_catalog.pg_get_triggerdef(t.oid, true)
+		FROM pg_catalog.pg_trigger t
+		JOIN pg_catalog.pg_class c ON c.oid = t.tgrelid
+		JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = $1 AND t.tgname = $2 AND NOT t.tgisinternal
+		ORDER BY c.relname
+	`, schema, trigger)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	var statements []string
+	for rows.Next() {
+		var statement string
+		if err := rows.Scan(&statement); err != nil {
+			return "", err
+		}
+		statements = append(statements, statement+";")
+	}
+	if err := rows.Err(); err != nil {
+		return "", err
+	}
+	if len(statements) == 0 {
+		return "", fmt.Errorf("trigger %s not found", name)
+	}
+
+	return strings.Join(statements, "\n\n"), nil
+}
+
+func (db *Postgres) GetSequenceDefinition(ctx context.Context, database, name string) (string, error) {
+	schema, sequenceName, err := splitSchemaName(database, name, "sequence")
+	if err != nil {
+		return "", err
+	}
+
+	conn, needsClose, err := db.connectionFor(database)
+	if err != nil {
+		return "", err
+	}
+	if needsClose {
+		defer conn.Close()
+	}
+
+	sequence := sequenceSchema{Name: name, Cached: true}
+	row := conn.QueryRowContext(ctx, `
+		SELECT data_type::text, start_value::text, increment_by::text, min_value::text, max_value::text, cache_size::text, cycle
+		FROM pg_catalog.pg_sequences
+		WHERE schemaname = $1 AND sequencename = $2
+	`, schema, sequenceName)
+	if err := row.Scan(&sequence.Type, &sequence.Start, &sequence.Increment, &sequence.MinValue, &sequence.MaxValue, &sequence.Cache, &sequence.Cycle); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", fmt.Errorf("sequence %s not found", name)
+		}
+		return "", err
+	}
+
+	w := &ddlWriter{provider: db.GetProvider(), reference: db.FormatReference}
+	w.createSequence(sequence)
+
+	return w.statements[0], nil
+}
+
+func (db *Postgres) GetMaterializedViewDefinition(ctx context.Context, database, name string) (string, error) {
+	schema, view, err := splitSchemaName(database, name, "materialized view")
+	if err != nil {
+		return "", err
+	}
+
+	conn, needsClose, err := db.connectionFor(database)
+	if err != nil {
+		return "", err
+	}
+	if needsClose {
+		defer conn.Close()
+	}
+
+	var definition string
+	row := conn.QueryRowContext(ctx, `
+		SELECT definition
+		FROM pg_catalog.pg_matviews
+		WHERE schemaname = $1 AND matviewname = $2
+	`, schema, view)
+	if err := row.Scan(&definition); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", fmt.Errorf("materialized view %s not found", name)
+		}
+		return "", err
+	}
+
+	w := &ddlWriter{provider: db.GetProvider(), reference: db.FormatReference}
+	return fmt.Sprintf("CREATE MATERIALIZED VIEW %s AS\n%s;", w.table(name), strings.TrimSuffix(strings.TrimSpace(definition), ";")), nil
+}
+
+// GetTypeDefinition returns the CREATE TYPE statement of an enum or a
+// composite type, or the CREATE DOMAIN statement of a domain.
+func (db *Postgres) GetTypeDefinition(ctx context.Context, database, name string) (string, error) {
+	schema, typeName, err := splitSchemaName(database, name, "type")
+	if err != nil {
+		return "", err
+	}
+
+	conn, needsClose, err := db.connectionFor(database)
+	if err != nil {
+		return "", err
+	}
+	if needsClose {
+		defer conn.Close()
+	}
+
+	var (
+		oid          int64
+		kind         string
+		baseType     string
+		notNull      bool
+		defaultValue sql.NullString
+	)
+	row := conn.QueryRowContext(ctx, `
+		SELECT t.oid::bigint, t.typtype::text, pg_catalog.format_type(t.typbasetype, t.typtypmod), t.typnotnull, t.typdefault
+		FROM pg_catalog.pg_type t
+		JOIN pg_catalog.pg_namespace n ON n.oid = t.typnamespace
+		WHERE n.nspname = $1 AND t.typname = $2
+		AND t.typtype IN ('e', 'c', 'd')
+	`, schema, typeName)
+	if err := row.Scan(&oid, &kind, &baseType, &notNull, &defaultValue); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", fmt.Errorf("type %s not found", name)
+		}
+		return "", err
+	}
+
+	w := &ddlWriter{provider: db.GetProvider(), reference: db.FormatReference}
+
+	var query string
+	switch kind {
+	case "e":
+		query = `
+			SELECT enumlabel
+			FROM pg_catalog.pg_enum
+			WHERE enumtypid = $1
+			ORDER BY enumsortorder
+		`
+	case "c":
+		query = `
+			SELECT a.attname, pg_catalog.format_type(a.atttypid, a.atttypmod)
+			FROM pg_catalog.pg_attribute a
+			JOIN pg_catalog.pg_type t ON t.typrelid = a.attrelid
+			WHERE t.oid = $1 AND a.attnum > 0 AND NOT a.attisdropped
+			ORDER BY a.attnum
+		`
+	default:
+		query = `
+			SELECT 'CONSTRAINT ' || pg_catalog.quote_ident(conname) || ' ' || pg_catalog.pg_get_constraintdef(oid)
+			FROM pg_catalog.pg_constraint
+			WHERE contypid = $1
+			ORDER BY conname
+		`
+	}
+
+	rows, err := conn.QueryContext(ctx, query, oid)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	var values []string
+	var columns []models.ColumnSchema
+	for rows.Next() {
+		if kind == "c" {
+			column := models.ColumnSchema{Nullable: true}
+			if err := rows.Scan(&column.Name, &column.Type); err != nil {
+				return "", err
+			}
+			columns = append(columns, column)
+			continue
+		}
+
+		var value string
+		if err := rows.Scan(&value); err != nil {
+			return "", err
+		}
+		values = append(values, value)
+	}
+	if err := rows.Err(); err != nil {
+		return "", err
+	}
+
+	switch kind {
+	case "e":
+		w.createEnum(name, values)
+	case "c":
+		w.createCompositeType(name, columns)
+	default:
+		w.createDomain(name, baseType, notNull, defaultValue.String, values)
+	}
+
+	return w.statements[0], nil
+}
+
+func (db *Postgres) RefreshMaterializedView(ctx context.Context, database, name string) error {
+	if _, _, err := splitSchemaName(database, name, "materialized view"); err != nil {
+		return err
+	}
+
+	conn, needsClose, err := db.connectionFor(database)
+	if err != nil {
+		return err
+	}
+	if needsClose {
+		defer conn.Close()
+	}
+
+	w := &ddlWriter{provider: db.GetProvider(), reference: db.FormatReference}
+	q, err := db.tx.writer(conn)
+	if err != nil {
+		return err
+	}
+	_, err = q.ExecContext(ctx, "REFRESH MATERIALIZED VIEW "+w.table(name))
+	return err
+}
+
+// splitSchemaName splits name, the schema-qualified name of an object of
+// kind of database, into its schema and its name.
+func splitSchemaName(database, name, kind string) (schema, object string, err error) {
+	if database == "" {
+		return "", "", errors.New("database name is required")
+	}
+	if name == "" {
+		return "", "", fmt.Errorf("%s name is required", kind)
+	}
+
+	schema, object, ok := strings.Cut(name, ".")
+	if !ok {
+		return "", "", fmt.Errorf("%s name must be in format schema.name", kind)
+	}
+
+	return schema, object, nil
+}
@@ -1,6 +1,7 @@
 package drivers
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"reflect"
@@ -207,7 +208,7 @@ func TestPostgres_ErrorScenarios(t *testing.T) {
 					WillReturnError(errors.New("query error"))
 			},
 			testFunc: func(db *Postgres) error {
-				_, err := db.GetTables(schemaPostgres)
+				_, err := db.GetTables(context.Background(), schemaPostgres)
 				return err
 			},
 		},
@@ -260,23 +261,23 @@ func TestPostgres_GetTableColumns(t *testing.T) {
 		"Primary key identifier",
 	).AddRow(
 		"name",
-		"character varying",
+		"character varying(255)",
 		"YES",
 		"",
 		"User name field",
 	).AddRow(
 		"email",
-		"character varying",
+		"text[]",
 		"YES",
 		"",
 		"", // Empty comment
 	)
 
-	mock.ExpectQuery("SELECT c.column_name, c.data_type, c.is_nullable, c.column_default, COALESCE(pd.description, '') as comment FROM information_schema.columns c LEFT JOIN pg_class pc ON pc.relname = c.table_name AND pc.relnamespace = (SELECT oid FROM pg_namespace WHERE nspname = c.table_schema) LEFT JOIN pg_namespace pn ON pn.nspname = c.table_schema AND pn.oid = pc.relnamespace LEFT JOIN pg_description pd ON pd.objoid = pc.oid AND pd.objsubid = c.ordinal_position WHERE c.table_catalog = $1 AND c.table_schema = $2 AND c.table_name = $3 ORDER by c.ordinal_position").
+	mock.ExpectQuery("SELECT c.column_name, format_type(a.atttypid, a.atttypmod) AS data_type, c.is_nullable, c.column_default, COALESCE(pd.description, '') as comment FROM information_schema.columns c JOIN pg_namespace pn ON pn.nspname = c.table_schema JOIN pg_class pc ON pc.relname = c.table_name AND pc.relnamespace = pn.oid JOIN pg_attribute a ON a.attrelid = pc.oid AND a.attname = c.column_name LEFT JOIN pg_description pd ON pd.objoid = pc.oid AND pd.objsubid = a.attnum WHERE c.table_catalog = $1 AND c.table_schema = $2 AND c.table_name = $3 ORDER by c.ordinal_position").
 		WithArgs(DBNamePostgres, schemaPostgres, tableNamePostgres).
 		WillReturnRows(rows)
 
-	columns, err := pg.GetTableColumns(DBNamePostgres, schemaAndTablePostgres)
+	columns, err := pg.GetTableColumns(context.Background(), DBNamePostgres, schemaAndTablePostgres)
 	if err != nil {
 		t.Fatalf("GetTableColumns failed: %v", err)
 	}
@@ -284,8 +285,8 @@ func TestPostgres_GetTableColumns(t *testing.T) {
 	expected := [][]string{
 		{"column_name", "data_type", "is_nullable", "column_default", "comment"},
 		{"id", "integer", "NO", "nextval('test_table_id_seq'::regclass)", "Primary key identifier"},
-		{"name", "character varying", "YES", "", "User name field"},
-		{"email", "character varying", "YES", "", ""},
+		{"name", "character varying(255)", "YES", "", "User name field"},
+		{"email", "text[]", "YES", "", ""},
 	}
 
 	if !reflect.DeepEqual(columns, expected) {
@@ -305,10 +306,10 @@ func TestPostgres_GetTableColumns_Error(t *testing.T) {
 	defer db.Close()
 
 	pg := &Postgres{Connection: db, CurrentDatabase: DBNamePostgres}
-	mock.ExpectQuery("SELECT c.column_name, c.data_type, c.is_nullable, c.column_default, COALESCE\\(pd.description, ''\\) as comment FROM information_schema.columns c LEFT JOIN pg_class pc ON pc.relname = c.table_name AND pc.relnamespace = \\(SELECT oid FROM pg_namespace WHERE nspname = c.table_schema\\) LEFT JOIN pg_namespace pn ON pn.nspname = c.table_schema AND pn.oid = pc.relnamespace LEFT JOIN pg_description pd ON pd.objoid = pc.oid AND pd.objsubid = c.ordinal_position WHERE c.table_catalog = \\$1 AND c.table_schema = \\$2 AND c.table_name = \\$3 ORDER by c.ordinal_position").WithArgs(DBNamePostgres, schemaPostgres, tableNamePostgres).
+	mock.ExpectQuery("SELECT c.column_name, format_type\\(a.atttypid, a.atttypmod\\) AS data_type, c.is_nullable, c.column_default, COALESCE\\(pd.description, ''\\) as comment FROM information_schema.columns c JOIN pg_namespace pn ON pn.nspname = c.table_schema JOIN pg_class pc ON pc.relname = c.table_name AND pc.relnamespace = pn.oid JOIN pg_attribute a ON a.attrelid = pc.oid AND a.attname = c.column_name LEFT JOIN pg_description pd ON pd.objoid = pc.oid AND pd.objsubid = a.attnum WHERE c.table_catalog = \\$1 AND c.table_schema = \\$2 AND c.table_name = \\$3 ORDER by c.ordinal_position").WithArgs(DBNamePostgres, schemaPostgres, tableNamePostgres).
 		WillReturnError(errors.New("query error"))
 
-	_, err = pg.GetTableColumns(DBNamePostgres, schemaAndTablePostgres)
+	_, err = pg.GetTableColumns(context.Background(), DBNamePostgres, schemaAndTablePostgres)
 	if err == nil {
 		t.Fatal("Expected error but got nil")
 	}
@@ -336,7 +337,7 @@ func TestPostgres_GetRecords(t *testing.T) {
 
 	mock.ExpectQuery(fmt.Sprintf(`SELECT COUNT\(\*\) FROM "%s"."%s"`, schemaPostgres, tableNamePostgres)).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
 
-	records, total, _, err := pg.GetRecords(DBNamePostgres, schemaAndTablePostgres, "", "", 0, DefaultRowLimit)
+	records, total, _, err := pg.GetRecords(context.Background(), DBNamePostgres, schemaAndTablePostgres, "", "", 0, DefaultRowLimit)
 	if err != nil {
 		t.Fatalf("GetRecords failed: %v", err)
 	}
@@ -347,7 +348,7 @@ func TestPostgres_GetRecords(t *testing.T) {
 		{"2", "Bob"},
 	}
 
-	if !reflect.DeepEqual(records, expected) {
+	if !reflect.DeepEqual(models.RecordStrings(records), expected) {
 		t.Fatalf("Expected %v, got %v", expected, records)
 	}
 
@@ -400,7 +401,7 @@ func TestPostgres_GetForeignKeys(t *testing.T) {
         ORDER BY con.conname, src_att.attnum
   `, schemaPostgres, tableNamePostgres)).WillReturnRows(rows)
 
-	constraints, err := pg.GetForeignKeys(DBNamePostgres, schemaAndTablePostgres)
+	constraints, err := pg.GetForeignKeys(context.Background(), DBNamePostgres, schemaAndTablePostgres)
 	if err != nil {
 		t.Fatalf("GetForeignKeys failed: %v", err)
 	}
@@ -458,7 +459,7 @@ func TestPostgres_GetIndexes(t *testing.T) {
             i.relname
   `, schemaPostgres, tableNamePostgres)).WillReturnRows(rows)
 
-	indexes, err := pg.GetIndexes(DBNamePostgres, schemaAndTablePostgres)
+	indexes, err := pg.GetIndexes(context.Background(), DBNamePostgres, schemaAndTablePostgres)
 	if err != nil {
 		t.Fatalf("GetIndexes failed: %v", err)
 	}
@@ -539,7 +540,7 @@ func TestPostgres_ExecutePendingChanges(t *testing.T) {
 		WillReturnResult(sqlmock.NewResult(0, 1))
 	mock.ExpectCommit()
 
-	err = pg.ExecutePendingChanges(changes)
+	err = pg.ExecutePendingChanges(context.Background(), changes)
 	if err != nil {
 		t.Fatalf("ExecutePendingChanges failed: %v", err)
 	}
@@ -574,7 +575,7 @@ func TestPostgres_GetPrimaryKeyColumnNames(t *testing.T) {
 			relname = \$2 AND nspname = \$1 AND indisprimary
 	`).WithArgs(schemaPostgres, tableNamePostgres).WillReturnRows(rows)
 
-	keys, err := pg.GetPrimaryKeyColumnNames(DBNamePostgres, schemaAndTablePostgres)
+	keys, err := pg.GetPrimaryKeyColumnNames(context.Background(), DBNamePostgres, schemaAndTablePostgres)
 	if err != nil {
 		t.Fatalf("GetPrimaryKeyColumnNames failed: %v", err)
 	}
@@ -737,3 +738,62 @@ func TestBuildReconnectURL(t *testing.T) {
 		})
 	}
 }
+
+func TestPostgres_GetTypeDefinition(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error creating mock: %v", err)
+	}
+	defer db.Close()
+
+	pg := &Postgres{Connection: db, Provider: DriverPostgres, CurrentDatabase: DBNamePostgres}
+
+	mock.ExpectQuery(`FROM pg_catalog.pg_type t`).
+		WithArgs("public", "mood").
+		WillReturnRows(sqlmock.NewRows([]string{"oid", "typtype", "format_type", "typnotnull", "typdefault"}).
+			AddRow(16385, "e", "-", false, nil))
+	mock.ExpectQuery(`FROM pg_catalog.pg_enum`).
+		WithArgs(int64(16385)).
+		WillReturnRows(sqlmock.NewRows([]string{"enumlabel"}).AddRow("sad").AddRow("ok").AddRow("it's fine"))
+
+	definition, err := pg.GetTypeDefinition(context.Background(), DBNamePostgres, "public.mood")
+	if err != nil {
+		t.Fatalf("GetTypeDefinition failed: %v", err)
+	}
+	expected := `CREATE TYPE "public"."mood" AS ENUM (` + "\n" +
+		"  'sad',\n" +
+		"  'ok',\n" +
+		"  'it''s fine'\n" +
+		");"
+	if definition != expected {
+		t.Fatalf("GetTypeDefinition failed:\nexpected: %s\ngot: %s", expected, definition)
+	}
+
+	if _, err := pg.GetTypeDefinition(context.Background(), DBNamePostgres, "mood"); err == nil {
+		t.Fatal("Expected an error for a type name without a schema")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPostgres_RefreshMaterializedView(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("Error creating mock: %v", err)
+	}
+	defer db.Close()
+
+	pg := &Postgres{Connection: db, Provider: DriverPostgres, CurrentDatabase: DBNamePostgres}
+
+	mock.ExpectExec(`REFRESH MATERIALIZED VIEW "reports"."daily_sales"`).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	if err := pg.RefreshMaterializedView(context.Background(), DBNamePostgres, "reports.daily_sales"); err != nil {
+		t.Fatalf("RefreshMaterializedView failed: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
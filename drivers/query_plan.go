@@ -0,0 +1,299 @@
+package drivers
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+var errEmptyPlan = errors.New("the database returned an empty query plan")
+
+// parsePostgresPlan parses the output of EXPLAIN (FORMAT JSON).
+func parsePostgresPlan(data []byte) (*models.PlanNode, error) {
+	var plans []struct {
+		Plan map[string]any `json:"Plan"`
+	}
+	if err := json.Unmarshal(data, &plans); err != nil {
+		return nil, fmt.Errorf("failed to parse query plan: %w", err)
+	}
+	if len(plans) == 0 || plans[0].Plan == nil {
+		return nil, errEmptyPlan
+	}
+
+	return postgresPlanNode(plans[0].Plan), nil
+}
+
+func postgresPlanNode(plan map[string]any) *models.PlanNode {
+	node := &models.PlanNode{
+		Type: jsonString(plan["Node Type"]),
+		Cost: jsonNumber(plan["Total Cost"]),
+		Rows: jsonNumber(plan["Plan Rows"]),
+	}
+	node.FullScan = node.Type == "Seq Scan"
+
+	details := []string{}
+	if relation := jsonString(plan["Relation Name"]); relation != "" {
+		details = append(details, "on "+relation)
+	}
+	if index := jsonString(plan["Index Name"]); index != "" {
+		details = append(details, "using "+index)
+	}
+	for _, key := range []string{"Hash Cond", "Merge Cond", "Index Cond", "Join Filter", "Filter"} {
+		if condition := jsonString(plan[key]); condition != "" {
+			details = append(details, condition)
+			break
+		}
+	}
+	node.Detail = strings.Join(details, " ")
+
+	children, _ := plan["Plans"].([]any)
+	for _, child := range children {
+		if childPlan, ok := child.(map[string]any); ok {
+			node.Children = append(node.Children, postgresPlanNode(childPlan))
+		}
+	}
+
+	return node
+}
+
+// parseMySQLPlan parses the output of EXPLAIN FORMAT=JSON. Every object of
+// the plan that describes an operation (query_block, nested_loop, table,
+// ordering_operation, ...) becomes a node named after its key.
+func parseMySQLPlan(data []byte) (*models.PlanNode, error) {
+	var plan map[string]any
+	if err := json.Unmarshal(data, &plan); err != nil {
+		return nil, fmt.Errorf("failed to parse query plan: %w", err)
+	}
+
+	queryBlock, ok := plan["query_block"]
+	if !ok {
+		return nil, errEmptyPlan
+	}
+
+	return mysqlPlanNode("query_block", queryBlock), nil
+}
+
+func mysqlPlanNode(key string, value any) *models.PlanNode {
+	node := &models.PlanNode{Type: key}
+
+	switch value := value.(type) {
+	case map[string]any:
+		if costInfo, ok := value["cost_info"].(map[string]any); ok {
+			node.Cost = jsonNumber(costInfo["query_cost"])
+			if node.Cost == 0 {
+				node.Cost = jsonNumber(costInfo["prefix_cost"])
+			}
+		}
+
+		node.Rows = jsonNumber(value["rows_produced_per_join"])
+		if node.Rows == 0 {
+			node.Rows = jsonNumber(value["rows_examined_per_scan"])
+		}
+
+		if table := jsonString(value["table_name"]); table != "" {
+			accessType := jsonString(value["access_type"])
+			node.Detail = fmt.Sprintf("on %s (%s)", table, accessType)
+			if index := jsonString(value["key"]); index != "" {
+				node.Detail += " using " + index
+			}
+			node.FullScan = accessType == "ALL"
+		}
+
+		node.Children = mysqlPlanChildren(value)
+	case []any:
+		for _, element := range value {
+			if object, ok := element.(map[string]any); ok {
+				node.Children = append(node.Children, mysqlPlanChildren(object)...)
+			}
+		}
+	}
+
+	return node
+}
+
+// mysqlPlanChildren returns a node for every nested operation of object.
+// encoding/json does not keep the key order, so keys are visited sorted.
+func mysqlPlanChildren(object map[string]any) []*models.PlanNode {
+	keys := make([]string, 0, len(object))
+	for key, value := range object {
+		switch value.(type) {
+		case map[string]any, []any:
+			if key != "cost_info" && key != "used_columns" && key != "possible_keys" && key != "used_key_parts" && key != "ref" {
+				keys = append(keys, key)
+			}
+		}
+	}
+	slices.Sort(keys)
+
+	children := []*models.PlanNode{}
+	for _, key := range keys {
+		children = append(children, mysqlPlanNode(key, object[key]))
+	}
+	return children
+}
+
+// sqlitePlanRow is one row of EXPLAIN QUERY PLAN.
+type sqlitePlanRow struct {
+	ID     int
+	Parent int
+	Detail string
+}
+
+// parseSQLitePlan builds the plan tree of the rows of EXPLAIN QUERY PLAN,
+// which link to their parent row by id.
+func parseSQLitePlan(rows []sqlitePlanRow) (*models.PlanNode, error) {
+	if len(rows) == 0 {
+		return nil, errEmptyPlan
+	}
+
+	root := &models.PlanNode{Type: "QUERY PLAN"}
+	nodes := map[int]*models.PlanNode{0: root}
+
+	for _, row := range rows {
+		node := &models.PlanNode{
+			Type:     row.Detail,
+			FullScan: strings.HasPrefix(row.Detail, "SCAN ") && !strings.Contains(row.Detail, " INDEX "),
+		}
+		nodes[row.ID] = node
+
+		parent, ok := nodes[row.Parent]
+		if !ok {
+			parent = root
+		}
+		parent.Children = append(parent.Children, node)
+	}
+
+	return root, nil
+}
+
+// mssqlPlanElement is any element of a SHOWPLAN_XML document.
+type mssqlPlanElement struct {
+	XMLName  xml.Name
+	Attrs    []xml.Attr         `xml:",any,attr"`
+	Children []mssqlPlanElement `xml:",any"`
+}
+
+func (e mssqlPlanElement) attr(name string) string {
+	for _, attr := range e.Attrs {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+// parseMSSQLPlan parses the output of SET SHOWPLAN_XML ON. Every statement
+// becomes a node whose children are the RelOp operators of its plan.
+func parseMSSQLPlan(data []byte) (*models.PlanNode, error) {
+	var document mssqlPlanElement
+	if err := xml.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("failed to parse query plan: %w", err)
+	}
+
+	statements := []*models.PlanNode{}
+	var findStatements func(element mssqlPlanElement)
+	findStatements = func(element mssqlPlanElement) {
+		if element.XMLName.Local == "StmtSimple" {
+			statement := &models.PlanNode{
+				Type:     element.attr("StatementType"),
+				Cost:     xmlNumber(element.attr("StatementSubTreeCost")),
+				Rows:     xmlNumber(element.attr("StatementEstRows")),
+				Children: mssqlRelOps(element),
+			}
+			statements = append(statements, statement)
+			return
+		}
+		for _, child := range element.Children {
+			findStatements(child)
+		}
+	}
+	findStatements(document)
+
+	switch len(statements) {
+	case 0:
+		return nil, errEmptyPlan
+	case 1:
+		return statements[0], nil
+	default:
+		return &models.PlanNode{Type: "BATCH", Children: statements}, nil
+	}
+}
+
+// mssqlRelOps returns a node for every RelOp below element that is not
+// nested in another RelOp.
+func mssqlRelOps(element mssqlPlanElement) []*models.PlanNode {
+	nodes := []*models.PlanNode{}
+
+	for _, child := range element.Children {
+		if child.XMLName.Local != "RelOp" {
+			nodes = append(nodes, mssqlRelOps(child)...)
+			continue
+		}
+
+		physicalOp := child.attr("PhysicalOp")
+		node := &models.PlanNode{
+			Type:     physicalOp,
+			Cost:     xmlNumber(child.attr("EstimatedTotalSubtreeCost")),
+			Rows:     xmlNumber(child.attr("EstimateRows")),
+			FullScan: physicalOp == "Table Scan" || physicalOp == "Clustered Index Scan",
+			Children: mssqlRelOps(child),
+		}
+
+		if object, ok := mssqlPlanObject(child); ok {
+			node.Detail = "on " + object.attr("Table")
+			if index := object.attr("Index"); index != "" {
+				node.Detail += " using " + index
+			}
+		}
+
+		nodes = append(nodes, node)
+	}
+
+	return nodes
+}
+
+// mssqlPlanObject returns the Object element naming the table an operator
+// reads, without looking into nested operators.
+func mssqlPlanObject(element mssqlPlanElement) (mssqlPlanElement, bool) {
+	for _, child := range element.Children {
+		switch child.XMLName.Local {
+		case "Object":
+			return child, true
+		case "RelOp":
+			continue
+		default:
+			if object, ok := mssqlPlanObject(child); ok {
+				return object, true
+			}
+		}
+	}
+	return mssqlPlanElement{}, false
+}
+
+func jsonString(value any) string {
+	s, _ := value.(string)
+	return s
+}
+
+// jsonNumber returns value as a number. MySQL reports costs as strings.
+func jsonNumber(value any) float64 {
+	switch value := value.(type) {
+	case float64:
+		return value
+	case string:
+		number, _ := strconv.ParseFloat(value, 64)
+		return number
+	}
+	return 0
+}
+
+func xmlNumber(value string) float64 {
+	number, _ := strconv.ParseFloat(value, 64)
+	return number
+}
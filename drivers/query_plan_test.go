@@ -0,0 +1,182 @@
+package drivers
+
+import (
+	"context"
+	"testing"
+
+	gomock "github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+// planSummary flattens a plan into "depth:type" entries for comparison.
+func planSummary(node *models.PlanNode, depth int) []string {
+	summary := []string{string(rune('0'+depth)) + ":" + node.Type}
+	for _, child := range node.Children {
+		summary = append(summary, planSummary(child, depth+1)...)
+	}
+	return summary
+}
+
+func assertPlan(t *testing.T, plan *models.PlanNode, want []string) {
+	t.Helper()
+
+	got := planSummary(plan, 0)
+	if len(got) != len(want) {
+		t.Fatalf("expected plan %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected plan %v, got %v", want, got)
+		}
+	}
+}
+
+func Test_parsePostgresPlan(t *testing.T) {
+	data := `[{"Plan": {"Node Type": "Hash Join", "Total Cost": 35.5, "Plan Rows": 120, "Hash Cond": "(o.user_id = u.id)",
+		"Plans": [
+			{"Node Type": "Seq Scan", "Relation Name": "orders", "Total Cost": 20.1, "Plan Rows": 1000},
+			{"Node Type": "Hash", "Total Cost": 8.2, "Plan Rows": 10, "Plans": [
+				{"Node Type": "Index Scan", "Relation Name": "users", "Index Name": "users_pkey", "Total Cost": 8.2, "Plan Rows": 10}
+			]}
+		]}}]`
+
+	plan, err := parsePostgresPlan([]byte(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	assertPlan(t, plan, []string{"0:Hash Join", "1:Seq Scan", "1:Hash", "2:Index Scan"})
+
+	if plan.Cost != 35.5 || plan.Rows != 120 || plan.Detail != "(o.user_id = u.id)" {
+		t.Errorf("unexpected root node %+v", plan)
+	}
+	if scan := plan.Children[0]; !scan.FullScan || scan.Detail != "on orders" {
+		t.Errorf("expected a full scan on orders, got %+v", scan)
+	}
+	if index := plan.Children[1].Children[0]; index.FullScan || index.Detail != "on users using users_pkey" {
+		t.Errorf("unexpected index scan %+v", index)
+	}
+
+	if _, err := parsePostgresPlan([]byte(`[]`)); err == nil {
+		t.Error("expected an error for an empty plan")
+	}
+}
+
+func Test_parseMySQLPlan(t *testing.T) {
+	data := `{"query_block": {"select_id": 1, "cost_info": {"query_cost": "12.50"},
+		"ordering_operation": {"using_filesort": true,
+			"nested_loop": [
+				{"table": {"table_name": "o", "access_type": "ALL", "rows_examined_per_scan": 100, "cost_info": {"prefix_cost": "10.25"}}},
+				{"table": {"table_name": "u", "access_type": "eq_ref", "key": "PRIMARY", "used_key_parts": ["id"], "rows_produced_per_join": 100}}
+			]}}}`
+
+	plan, err := parseMySQLPlan([]byte(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	assertPlan(t, plan, []string{"0:query_block", "1:ordering_operation", "2:nested_loop", "3:table", "3:table"})
+
+	if plan.Cost != 12.5 {
+		t.Errorf("expected query cost 12.5, got %v", plan.Cost)
+	}
+
+	tables := plan.Children[0].Children[0].Children
+	if !tables[0].FullScan || tables[0].Cost != 10.25 || tables[0].Rows != 100 || tables[0].Detail != "on o (ALL)" {
+		t.Errorf("unexpected first table %+v", tables[0])
+	}
+	if tables[1].FullScan || tables[1].Detail != "on u (eq_ref) using PRIMARY" {
+		t.Errorf("unexpected second table %+v", tables[1])
+	}
+}
+
+func Test_parseSQLitePlan(t *testing.T) {
+	plan, err := parseSQLitePlan([]sqlitePlanRow{
+		{ID: 2, Parent: 0, Detail: "SCAN orders"},
+		{ID: 5, Parent: 0, Detail: "SEARCH users USING INTEGER PRIMARY KEY (rowid=?)"},
+		{ID: 9, Parent: 0, Detail: "CORRELATED SCALAR SUBQUERY 1"},
+		{ID: 12, Parent: 9, Detail: "SCAN items USING COVERING INDEX items_idx"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	assertPlan(t, plan, []string{
+		"0:QUERY PLAN",
+		"1:SCAN orders",
+		"1:SEARCH users USING INTEGER PRIMARY KEY (rowid=?)",
+		"1:CORRELATED SCALAR SUBQUERY 1",
+		"2:SCAN items USING COVERING INDEX items_idx",
+	})
+
+	if !plan.Children[0].FullScan || plan.Children[2].Children[0].FullScan {
+		t.Error("expected only the table scan to be a full scan")
+	}
+}
+
+func Test_parseMSSQLPlan(t *testing.T) {
+	data := `<ShowPlanXML xmlns="http://schemas.microsoft.com/sqlserver/2004/07/showplan"><BatchSequence><Batch><Statements>
+		<StmtSimple StatementType="SELECT" StatementSubTreeCost="0.52" StatementEstRows="42"><QueryPlan>
+			<RelOp NodeId="0" PhysicalOp="Nested Loops" LogicalOp="Inner Join" EstimateRows="42" EstimatedTotalSubtreeCost="0.52">
+				<NestedLoops>
+					<RelOp NodeId="1" PhysicalOp="Clustered Index Scan" LogicalOp="Clustered Index Scan" EstimateRows="100" EstimatedTotalSubtreeCost="0.4">
+						<IndexScan><Object Database="[db]" Schema="[dbo]" Table="[orders]" Index="[PK_orders]"/></IndexScan>
+					</RelOp>
+					<RelOp NodeId="2" PhysicalOp="Index Seek" LogicalOp="Index Seek" EstimateRows="1" EstimatedTotalSubtreeCost="0.1">
+						<IndexScan><Object Table="[users]" Index="[IX_users]"/></IndexScan>
+					</RelOp>
+				</NestedLoops>
+			</RelOp>
+		</QueryPlan></StmtSimple>
+	</Statements></Batch></BatchSequence></ShowPlanXML>`
+
+	plan, err := parseMSSQLPlan([]byte(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	assertPlan(t, plan, []string{"0:SELECT", "1:Nested Loops", "2:Clustered Index Scan", "2:Index Seek"})
+
+	if plan.Cost != 0.52 || plan.Rows != 42 {
+		t.Errorf("unexpected statement node %+v", plan)
+	}
+
+	join := plan.Children[0]
+	if join.Detail != "" {
+		t.Errorf("expected the join to have no object, got %q", join.Detail)
+	}
+	if scan := join.Children[0]; !scan.FullScan || scan.Detail != "on [orders] using [PK_orders]" {
+		t.Errorf("unexpected scan %+v", scan)
+	}
+	if seek := join.Children[1]; seek.FullScan || seek.Detail != "on [users] using [IX_users]" {
+		t.Errorf("unexpected seek %+v", seek)
+	}
+}
+
+func TestPostgres_ExplainQuery(t *testing.T) {
+	db, mock, err := gomock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery(`EXPLAIN \(FORMAT JSON\) SELECT \* FROM users WHERE id = \$1`).
+		WithArgs(int64(7)).
+		WillReturnRows(gomock.NewRows([]string{"QUERY PLAN"}).
+			AddRow(`[{"Plan": {"Node Type": "Index Scan", "Relation Name": "users", "Total Cost": 8.1, "Plan Rows": 1}}]`))
+
+	pg := &Postgres{Connection: db}
+	plan, err := pg.ExplainQuery(context.Background(), "SELECT * FROM users WHERE id = $1", int64(7))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if plan.Type != "Index Scan" || plan.Detail != "on users" {
+		t.Errorf("unexpected plan %+v", plan)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,316 @@
+package drivers
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"syscall"
+
+	"github.com/go-sql-driver/mysql"
+
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+// Reconnecting is a Driver that, when an operation fails because the
+// connection to the database was lost, reconnects and runs it once more.
+// Inside a transaction, lost with the connection, operations are not run
+// again.
+type Reconnecting struct {
+	Driver
+	// Reconnect connects the driver again, e.g. after running the commands
+	// of the connection.
+	Reconnect func(ctx context.Context) error
+}
+
+// IsConnectionError reports whether err is caused by the connection to the
+// database being lost or refused.
+func IsConnectionError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var netErr net.Error
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, mysql.ErrInvalidConn) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.EPIPE) ||
+		errors.As(err, &netErr) {
+		return true
+	}
+
+	// Some drivers only keep the message of the network error.
+	message := strings.ToLower(err.Error())
+	for _, lost := range []string{"bad connection", "broken pipe", "connection refused", "connection reset", "use of closed network connection"} {
+		if strings.Contains(message, lost) {
+			return true
+		}
+	}
+	return false
+}
+
+// retry runs operation, which only reads the database, and once more after
+// reconnecting if it failed because the connection was lost.
+func retry[T any](r *Reconnecting, ctx context.Context, operation func() (T, error)) (T, error) {
+	result, err := operation()
+	if !IsConnectionError(err) || r.Reconnect == nil || r.InTransaction() {
+		return result, err
+	}
+
+	if reconnectErr := r.Reconnect(ctx); reconnectErr != nil {
+		return result, errors.Join(err, reconnectErr)
+	}
+	return operation()
+}
+
+// retryWrite runs operation, which may change the database, and reconnects
+// if it failed because the connection was lost. It is only run once more on
+// driver.ErrBadConn, returned before the statement is sent: the connection
+// may have been lost after the server ran it, and running it again would
+// e.g. insert its rows twice.
+func retryWrite[T any](r *Reconnecting, ctx context.Context, operation func() (T, error)) (T, error) {
+	result, err := operation()
+	if !IsConnectionError(err) || r.Reconnect == nil || r.InTransaction() {
+		return result, err
+	}
+
+	if reconnectErr := r.Reconnect(ctx); reconnectErr != nil {
+		return result, errors.Join(err, reconnectErr)
+	}
+	if !errors.Is(err, driver.ErrBadConn) {
+		return result, fmt.Errorf("the connection was lost, the statement may have run: %w", err)
+	}
+	return operation()
+}
+
+// retryErr is retry for the operations that only return an error.
+func retryErr(r *Reconnecting, ctx context.Context, operation func() error) error {
+	_, err := retry(r, ctx, func() (struct{}, error) {
+		return struct{}{}, operation()
+	})
+	return err
+}
+
+// retryWriteErr is retryWrite for the operations that only return an error.
+func retryWriteErr(r *Reconnecting, ctx context.Context, operation func() error) error {
+	_, err := retryWrite(r, ctx, func() (struct{}, error) {
+		return struct{}{}, operation()
+	})
+	return err
+}
+
+// retryQuery is retry for the reads and retryWrite for the mutations, e.g.
+// an INSERT ... RETURNING run by ExecuteQuery.
+func retryQuery[T any](r *Reconnecting, ctx context.Context, query string, operation func() (T, error)) (T, error) {
+	if IsQueryMutation(query) {
+		return retryWrite(r, ctx, operation)
+	}
+	return retry(r, ctx, operation)
+}
+
+func (r *Reconnecting) GetDatabases(ctx context.Context) ([]string, error) {
+	return retry(r, ctx, func() ([]string, error) {
+		return r.Driver.GetDatabases(ctx)
+	})
+}
+
+func (r *Reconnecting) GetTables(ctx context.Context, database string) (map[string][]string, error) {
+	return retry(r, ctx, func() (map[string][]string, error) {
+		return r.Driver.GetTables(ctx, database)
+	})
+}
+
+func (r *Reconnecting) GetTableColumns(ctx context.Context, database, table string) ([][]string, error) {
+	return retry(r, ctx, func() ([][]string, error) {
+		return r.Driver.GetTableColumns(ctx, database, table)
+	})
+}
+
+func (r *Reconnecting) GetConstraints(ctx context.Context, database, table string) ([][]string, error) {
+	return retry(r, ctx, func() ([][]string, error) {
+		return r.Driver.GetConstraints(ctx, database, table)
+	})
+}
+
+func (r *Reconnecting) GetForeignKeys(ctx context.Context, database, table string) ([][]string, error) {
+	return retry(r, ctx, func() ([][]string, error) {
+		return r.Driver.GetForeignKeys(ctx, database, table)
+	})
+}
+
+func (r *Reconnecting) GetReferencingForeignKeys(ctx context.Context, database, table string) ([]models.ReferencingForeignKey, error) {
+	return retry(r, ctx, func() ([]models.ReferencingForeignKey, error) {
+		return r.Driver.GetReferencingForeignKeys(ctx, database, table)
+	})
+}
+
+func (r *Reconnecting) GetIndexes(ctx context.Context, database, table string) ([][]string, error) {
+	return retry(r, ctx, func() ([][]string, error) {
+		return r.Driver.GetIndexes(ctx, database, table)
+	})
+}
+
+func (r *Reconnecting) GetRecords(ctx context.Context, database, table, where, sort string, offset, limit int) ([]models.Record, int, string, error) {
+	var count int
+	var query string
+	records, err := retry(r, ctx, func() (records []models.Record, err error) {
+		records, count, query, err = r.Driver.GetRecords(ctx, database, table, where, sort, offset, limit)
+		return records, err
+	})
+	return records, count, query, err
+}
+
+func (r *Reconnecting) UpdateRecord(ctx context.Context, database, table, column, value, primaryKeyColumnName, primaryKeyValue string) error {
+	return retryWriteErr(r, ctx, func() error {
+		return r.Driver.UpdateRecord(ctx, database, table, column, value, primaryKeyColumnName, primaryKeyValue)
+	})
+}
+
+func (r *Reconnecting) DeleteRecord(ctx context.Context, database, table, primaryKeyColumnName, primaryKeyValue string) error {
+	return retryWriteErr(r, ctx, func() error {
+		return r.Driver.DeleteRecord(ctx, database, table, primaryKeyColumnName, primaryKeyValue)
+	})
+}
+
+func (r *Reconnecting) ExecuteDMLStatement(ctx context.Context, query string, args ...any) (string, error) {
+	return retryWrite(r, ctx, func() (string, error) {
+		return r.Driver.ExecuteDMLStatement(ctx, query, args...)
+	})
+}
+
+func (r *Reconnecting) ExecuteQuery(ctx context.Context, query string, args ...any) ([]models.Record, int, error) {
+	var count int
+	records, err := retryQuery(r, ctx, query, func() (records []models.Record, err error) {
+		records, count, err = r.Driver.ExecuteQuery(ctx, query, args...)
+		return records, err
+	})
+	return records, count, err
+}
+
+func (r *Reconnecting) QueryRows(ctx context.Context, query string, args ...any) (Rows, error) {
+	return retryQuery(r, ctx, query, func() (Rows, error) {
+		return r.Driver.QueryRows(ctx, query, args...)
+	})
+}
+
+func (r *Reconnecting) ExecutePendingChanges(ctx context.Context, changes []models.DBDMLChange) error {
+	return retryWriteErr(r, ctx, func() error {
+		return r.Driver.ExecutePendingChanges(ctx, changes)
+	})
+}
+
+func (r *Reconnecting) GetPrimaryKeyColumnNames(ctx context.Context, database, table string) ([]string, error) {
+	return retry(r, ctx, func() ([]string, error) {
+		return r.Driver.GetPrimaryKeyColumnNames(ctx, database, table)
+	})
+}
+
+func (r *Reconnecting) ExplainQuery(ctx context.Context, query string, args ...any) (*models.PlanNode, error) {
+	return retry(r, ctx, func() (*models.PlanNode, error) {
+		return r.Driver.ExplainQuery(ctx, query, args...)
+	})
+}
+
+func (r *Reconnecting) BeginTransaction(ctx context.Context) error {
+	return retryWriteErr(r, ctx, func() error {
+		return r.Driver.BeginTransaction(ctx)
+	})
+}
+
+func (r *Reconnecting) GetFunctions(ctx context.Context, database string) (map[string][]string, error) {
+	return retry(r, ctx, func() (map[string][]string, error) {
+		return r.Driver.GetFunctions(ctx, database)
+	})
+}
+
+func (r *Reconnecting) GetProcedures(ctx context.Context, database string) (map[string][]string, error) {
+	return retry(r, ctx, func() (map[string][]string, error) {
+		return r.Driver.GetProcedures(ctx, database)
+	})
+}
+
+func (r *Reconnecting) GetViews(ctx context.Context, database string) (map[string][]string, error) {
+	return retry(r, ctx, func() (map[string][]string, error) {
+		return r.Driver.GetViews(ctx, database)
+	})
+}
+
+func (r *Reconnecting) GetTriggers(ctx context.Context, database string) (map[string][]string, error) {
+	return retry(r, ctx, func() (map[string][]string, error) {
+		return r.Driver.GetTriggers(ctx, database)
+	})
+}
+
+func (r *Reconnecting) GetSequences(ctx context.Context, database string) (map[string][]string, error) {
+	return retry(r, ctx, func() (map[string][]string, error) {
+		return r.Driver.GetSequences(ctx, database)
+	})
+}
+
+func (r *Reconnecting) GetMaterializedViews(ctx context.Context, database string) (map[string][]string, error) {
+	return retry(r, ctx, func() (map[string][]string, error) {
+		return r.Driver.GetMaterializedViews(ctx, database)
+	})
+}
+
+func (r *Reconnecting) GetTypes(ctx context.Context, database string) (map[string][]string, error) {
+	return retry(r, ctx, func() (map[string][]string, error) {
+		return r.Driver.GetTypes(ctx, database)
+	})
+}
+
+func (r *Reconnecting) GetFunctionDefinition(ctx context.Context, database string, name string) (string, error) {
+	return retry(r, ctx, func() (string, error) {
+		return r.Driver.GetFunctionDefinition(ctx, database, name)
+	})
+}
+
+func (r *Reconnecting) GetProcedureDefinition(ctx context.Context, database string, name string) (string, error) {
+	return retry(r, ctx, func() (string, error) {
+		return r.Driver.GetProcedureDefinition(ctx, database, name)
+	})
+}
+
+func (r *Reconnecting) GetViewDefinition(ctx context.Context, database string, name string) (string, error) {
+	return retry(r, ctx, func() (string, error) {
+		return r.Driver.GetViewDefinition(ctx, database, name)
+	})
+}
+
+func (r *Reconnecting) GetTableDefinition(ctx context.Context, database string, table string) (string, error) {
+	return retry(r, ctx, func() (string, error) {
+		return r.Driver.GetTableDefinition(ctx, database, table)
+	})
+}
+
+func (r *Reconnecting) GetTriggerDefinition(ctx context.Context, database string, name string) (string, error) {
+	return retry(r, ctx, func() (string, error) {
+		return r.Driver.GetTriggerDefinition(ctx, database, name)
+	})
+}
+
+func (r *Reconnecting) GetSequenceDefinition(ctx context.Context, database string, name string) (string, error) {
+	return retry(r, ctx, func() (string, error) {
+		return r.Driver.GetSequenceDefinition(ctx, database, name)
+	})
+}
+
+func (r *Reconnecting) GetMaterializedViewDefinition(ctx context.Context, database string, name string) (string, error) {
+	return retry(r, ctx, func() (string, error) {
+		return r.Driver.GetMaterializedViewDefinition(ctx, database, name)
+	})
+}
+
+func (r *Reconnecting) GetTypeDefinition(ctx context.Context, database string, name string) (string, error) {
+	return retry(r, ctx, func() (string, error) {
+		return r.Driver.GetTypeDefinition(ctx, database, name)
+	})
+}
+
+func (r *Reconnecting) RefreshMaterializedView(ctx context.Context, database string, name string) error {
+	return retryWriteErr(r, ctx, func() error {
+		return r.Driver.RefreshMaterializedView(ctx, database, name)
+	})
+}
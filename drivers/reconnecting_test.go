@@ -0,0 +1,155 @@
+package drivers
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+// flakyDriver fails its queries with the errors of failures, one per query.
+type flakyDriver struct {
+	mockDriver
+	failures      []error
+	inTransaction bool
+	writes        int
+}
+
+func (d *flakyDriver) ExecuteQuery(context.Context, string, ...any) ([]models.Record, int, error) {
+	if len(d.failures) > 0 {
+		err := d.failures[0]
+		d.failures = d.failures[1:]
+		return nil, 0, err
+	}
+	return []models.Record{{{Raw: []byte("1")}}}, 1, nil
+}
+
+func (d *flakyDriver) ExecuteDMLStatement(context.Context, string, ...any) (string, error) {
+	d.writes++
+	if len(d.failures) > 0 {
+		err := d.failures[0]
+		d.failures = d.failures[1:]
+		return "", err
+	}
+	return "1 row affected", nil
+}
+
+func (d *flakyDriver) InTransaction() bool { return d.inTransaction }
+
+func TestReconnecting(t *testing.T) {
+	tests := []struct {
+		name           string
+		failure        error
+		inTransaction  bool
+		wantReconnects int
+		wantErr        bool
+	}{
+		{name: "lost connection", failure: fmt.Errorf("query: %w", driver.ErrBadConn), wantReconnects: 1},
+		{name: "refused connection", failure: errors.New("dial tcp 127.0.0.1:5432: connect: connection refused"), wantReconnects: 1},
+		{name: "other error", failure: errors.New("syntax error"), wantErr: true},
+		{name: "in transaction", failure: driver.ErrBadConn, inTransaction: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flaky := &flakyDriver{failures: []error{tt.failure}, inTransaction: tt.inTransaction}
+			reconnects := 0
+			r := &Reconnecting{Driver: flaky, Reconnect: func(context.Context) error {
+				reconnects++
+				return nil
+			}}
+
+			records, count, err := r.ExecuteQuery(context.Background(), "SELECT 1")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ExecuteQuery() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if reconnects != tt.wantReconnects {
+				t.Errorf("got %d reconnects, want %d", reconnects, tt.wantReconnects)
+			}
+			if !tt.wantErr && (count != 1 || len(records) != 1) {
+				t.Errorf("ExecuteQuery() = %v, %d", records, count)
+			}
+		})
+	}
+}
+
+func TestReconnecting_Writes(t *testing.T) {
+	tests := []struct {
+		name       string
+		failure    error
+		wantWrites int
+		wantErr    bool
+	}{
+		// The server may have run the statement before the connection was
+		// lost: it must not run twice.
+		{name: "lost after sending", failure: io.EOF, wantWrites: 1, wantErr: true},
+		{name: "reset after sending", failure: syscall.ECONNRESET, wantWrites: 1, wantErr: true},
+		// database/sql returns ErrBadConn before sending the statement.
+		{name: "bad connection", failure: driver.ErrBadConn, wantWrites: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flaky := &flakyDriver{failures: []error{tt.failure}}
+			reconnects := 0
+			r := &Reconnecting{Driver: flaky, Reconnect: func(context.Context) error {
+				reconnects++
+				return nil
+			}}
+
+			_, err := r.ExecuteDMLStatement(context.Background(), "INSERT INTO users (name) VALUES ('a')")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ExecuteDMLStatement() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if flaky.writes != tt.wantWrites {
+				t.Errorf("the statement ran %d times, want %d", flaky.writes, tt.wantWrites)
+			}
+			if reconnects != 1 {
+				t.Errorf("got %d reconnects, want 1", reconnects)
+			}
+		})
+	}
+}
+
+func TestSQLite_Reconnect(t *testing.T) {
+	db := &SQLite{}
+	urlstr := filepath.Join(t.TempDir(), "test.db")
+
+	if err := db.Connect(context.Background(), urlstr); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.BeginTransaction(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	previous := db.Connection
+
+	// Connecting again replaces the connection, losing its transaction.
+	if err := db.Connect(context.Background(), urlstr); err != nil {
+		t.Fatal(err)
+	}
+	if db.Connection == previous || db.InTransaction() {
+		t.Fatalf("Connect() kept the previous connection or its transaction")
+	}
+	if err := db.Ping(context.Background()); err != nil {
+		t.Fatalf("Ping() error = %v", err)
+	}
+
+	// Failing to connect again keeps the current connection.
+	current := db.Connection
+	db.InitStatements = []string{"SELECT * FROM missing"}
+	if err := db.Connect(context.Background(), urlstr); err == nil {
+		t.Fatal("Connect() ran a failing init statement without error")
+	}
+	if db.Connection != current {
+		t.Fatal("a failed Connect() replaced the connection")
+	}
+	if err := db.Ping(context.Background()); err != nil {
+		t.Fatalf("Ping() error = %v", err)
+	}
+}
@@ -0,0 +1,142 @@
+package drivers
+
+import (
+	"database/sql"
+
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+// Rows is a cursor over the result of a query. Rows are read from the
+// database as Next is called instead of being loaded up front, so a result
+// does not have to fit in memory. Rows must be closed.
+type Rows interface {
+	Columns() []models.ResultColumn
+	// Next advances to the next row. It returns false at the end of the
+	// result or on error, see Err.
+	Next() bool
+	// Values returns the values of the current row. The record is not
+	// reused by later calls to Next.
+	Values() models.Record
+	Err() error
+	Close() error
+}
+
+type sqlRows struct {
+	rows    *sql.Rows
+	columns []models.ResultColumn
+	scanned []any
+	values  models.Record
+	err     error
+}
+
+// newSQLRows wraps rows. rows is closed if its columns can't be read.
+func newSQLRows(rows *sql.Rows) (Rows, error) {
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		rows.Close()
+		return nil, err
+	}
+
+	columns := make([]models.ResultColumn, len(columnTypes))
+	scanned := make([]any, len(columnTypes))
+	for i, columnType := range columnTypes {
+		columns[i] = models.ResultColumn{Name: columnType.Name(), Type: columnType.DatabaseTypeName()}
+		scanned[i] = new(sql.RawBytes)
+	}
+
+	return &sqlRows{rows: rows, columns: columns, scanned: scanned}, nil
+}
+
+func (r *sqlRows) Columns() []models.ResultColumn {
+	return r.columns
+}
+
+func (r *sqlRows) Next() bool {
+	if r.err != nil || !r.rows.Next() {
+		return false
+	}
+
+	if err := r.rows.Scan(r.scanned...); err != nil {
+		r.err = err
+		return false
+	}
+
+	r.values = make(models.Record, len(r.scanned))
+	for i, value := range r.scanned {
+		r.values[i] = newValue(r.columns[i].Type, *value.(*sql.RawBytes))
+	}
+
+	return true
+}
+
+// newValue copies raw, which database/sql sets to nil for NULL.
+func newValue(typeName string, raw sql.RawBytes) models.Value {
+	if raw == nil {
+		return models.Value{Type: typeName, Null: true}
+	}
+	return models.Value{Type: typeName, Raw: append([]byte{}, raw...)}
+}
+
+func (r *sqlRows) Values() models.Record {
+	return r.values
+}
+
+func (r *sqlRows) Err() error {
+	if r.err != nil {
+		return r.err
+	}
+	return r.rows.Err()
+}
+
+func (r *sqlRows) Close() error {
+	return r.rows.Close()
+}
+
+// ReadRows reads up to limit rows from rows, all of them if limit is not
+// positive. done reports whether the end of the result was reached.
+func ReadRows(rows Rows, limit int) (records []models.Record, done bool, err error) {
+	records = []models.Record{}
+
+	for limit <= 0 || len(records) < limit {
+		if !rows.Next() {
+			return records, true, rows.Err()
+		}
+		records = append(records, rows.Values())
+	}
+
+	return records, false, nil
+}
+
+// ColumnNames returns the names of columns.
+func ColumnNames(columns []models.ResultColumn) []string {
+	names := make([]string, len(columns))
+	for i, column := range columns {
+		names[i] = column.Name
+	}
+	return names
+}
+
+// readAllRows reads and closes rows. It returns the header record followed
+// by the records, and the number of records.
+func readAllRows(rows Rows) ([]models.Record, int, error) {
+	defer rows.Close()
+
+	records, _, err := ReadRows(rows, 0)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return append([]models.Record{models.HeaderRecord(rows.Columns())}, records...), len(records), nil
+}
+
+// readRecords reads and closes rows of a table. It returns the header record
+// followed by the records.
+func readRecords(rows *sql.Rows) ([]models.Record, error) {
+	wrapped, err := newSQLRows(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	records, _, err := readAllRows(wrapped)
+	return records, err
+}
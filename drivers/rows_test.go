@@ -0,0 +1,131 @@
+package drivers
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	gomock "github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+func TestReadRows(t *testing.T) {
+	db, mock, err := gomock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT id, name FROM users").
+		WillReturnRows(gomock.NewRowsWithColumnDefinition(
+			gomock.NewColumn("id").OfType("INT4", int64(0)),
+			gomock.NewColumn("name").OfType("VARCHAR", ""),
+		).AddRow(1, "a").AddRow(2, "b").AddRow(3, "c"))
+
+	sqlRows, err := db.QueryContext(context.Background(), "SELECT id, name FROM users")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rows, err := newSQLRows(sqlRows)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer rows.Close()
+
+	wantColumns := []models.ResultColumn{{Name: "id", Type: "INT4"}, {Name: "name", Type: "VARCHAR"}}
+	if !reflect.DeepEqual(rows.Columns(), wantColumns) {
+		t.Errorf("expected columns %v, got %v", wantColumns, rows.Columns())
+	}
+
+	batch, done, err := ReadRows(rows, 2)
+	if err != nil || done {
+		t.Fatalf("expected a first batch that is not done, got done=%v err=%v", done, err)
+	}
+	if want := [][]string{{"1", "a"}, {"2", "b"}}; !reflect.DeepEqual(models.RecordStrings(batch), want) {
+		t.Errorf("expected %v, got %v", want, batch)
+	}
+
+	batch, done, err = ReadRows(rows, 2)
+	if err != nil || !done {
+		t.Fatalf("expected the last batch to be done, got done=%v err=%v", done, err)
+	}
+	if want := [][]string{{"3", "c"}}; !reflect.DeepEqual(models.RecordStrings(batch), want) {
+		t.Errorf("expected %v, got %v", want, batch)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestSQLite_ExecuteQuery_readsAllRows(t *testing.T) {
+	db, mock, err := gomock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT name FROM users").
+		WillReturnRows(gomock.NewRows([]string{"name"}).AddRow("a").AddRow("b"))
+
+	sqlite := &SQLite{Connection: db}
+	records, count, err := sqlite.ExecuteQuery(context.Background(), "SELECT name FROM users")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if want := [][]string{{"name"}, {"a"}, {"b"}}; !reflect.DeepEqual(models.RecordStrings(records), want) || count != 2 {
+		t.Errorf("expected %v with 2 records, got %v with %d", want, records, count)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestMySQL_GetRecords_typedValues(t *testing.T) {
+	db, mock, err := gomock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT \\* FROM `test_db`.`test_table` LIMIT \\?, \\?").
+		WithArgs(0, DefaultRowLimit).
+		WillReturnRows(gomock.NewRowsWithColumnDefinition(
+			gomock.NewColumn("id").OfType("INT", int64(0)),
+			gomock.NewColumn("note").OfType("VARCHAR", ""),
+		).AddRow(1, nil).AddRow(2, "").AddRow(3, "NULL&"))
+
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM `test_db`.`test_table`").
+		WillReturnRows(gomock.NewRows([]string{"count"}).AddRow(3))
+
+	mysql := &MySQL{Connection: db}
+	records, _, _, err := mysql.GetRecords(context.Background(), "test_db", "test_table", "", "", 0, DefaultRowLimit)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(records) != 4 {
+		t.Fatalf("expected a header and 3 records, got %v", records)
+	}
+
+	if header := records[0]; header[0].String() != "id" || !header[0].IsNumeric() || header[1].IsNumeric() {
+		t.Errorf("unexpected header %+v", header)
+	}
+	if note := records[1][1]; !note.Null || note.Type != "VARCHAR" {
+		t.Errorf("expected NULL, got %+v", note)
+	}
+	if note := records[2][1]; note.Null || !note.IsEmpty() {
+		t.Errorf("expected an empty string, got %+v", note)
+	}
+	if note := records[3][1]; note.Null || note.String() != "NULL&" {
+		t.Errorf("expected the text NULL&, got %+v", note)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
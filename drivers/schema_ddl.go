@@ -0,0 +1,405 @@
+package drivers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+// ddlWriter writes the statements of a schema diff in the dialect of a
+// provider.
+type ddlWriter struct {
+	provider   string
+	reference  func(string) string
+	statements []string
+}
+
+// DDL returns the statements that make the target of the diff match its
+// source, in the dialect of db, the driver of the target. What the dialect
+// can't change in place, e.g. a column of a SQLite table, is written as a
+// comment.
+//
+// Foreign keys and indexes are dropped first and created last, so that the
+// columns they use can be changed in between.
+func (d *SchemaDiff) DDL(db Driver) []string {
+	w := &ddlWriter{provider: db.GetProvider(), reference: db.FormatReference}
+
+	for _, change := range d.Changes {
+		if change.Kind == models.SchemaForeignKey && change.Type != models.SchemaAdded {
+			w.dropForeignKey(change.Table, findForeignKey(d.Target[change.Table], change.Name))
+		}
+	}
+	for _, change := range d.Changes {
+		if change.Kind == models.SchemaIndex && change.Type != models.SchemaAdded {
+			w.dropIndex(change.Table, findIndex(d.Target[change.Table], change.Name))
+		}
+	}
+	for _, change := range d.Changes {
+		switch {
+		case change.Kind == models.SchemaTable && change.Type == models.SchemaAdded:
+			w.createTable(d.Source[change.Table])
+		case change.Kind == models.SchemaColumn && change.Type == models.SchemaAdded:
+			w.addColumn(change.Table, findColumn(d.Source[change.Table], change.Name))
+		case change.Kind == models.SchemaColumn && change.Type == models.SchemaChanged:
+			w.alterColumn(change.Table, findColumn(d.Source[change.Table], change.Name), findColumn(d.Target[change.Table], change.Name))
+		}
+	}
+	for _, change := range d.Changes {
+		if change.Kind == models.SchemaPrimaryKey {
+			w.alterPrimaryKey(change.Table, d.Source[change.Table].PrimaryKey, d.Target[change.Table].PrimaryKey)
+		}
+	}
+	for _, change := range d.Changes {
+		if change.Kind == models.SchemaColumn && change.Type == models.SchemaRemoved {
+			w.add("ALTER TABLE %s DROP COLUMN %s;", w.table(change.Table), w.reference(change.Name))
+		}
+	}
+	for _, change := range d.Changes {
+		if change.Kind == models.SchemaIndex && change.Type != models.SchemaRemoved {
+			w.createIndex(change.Table, findIndex(d.Source[change.Table], change.Name))
+		}
+	}
+	for _, change := range d.Changes {
+		if change.Kind == models.SchemaForeignKey && change.Type != models.SchemaRemoved {
+			w.addForeignKey(change.Table, findForeignKey(d.Source[change.Table], change.Name))
+		}
+	}
+	for _, change := range d.Changes {
+		if change.Kind == models.SchemaTable && change.Type == models.SchemaRemoved {
+			w.add("DROP TABLE %s;", w.table(change.Table))
+		}
+	}
+
+	return w.statements
+}
+
+func (w *ddlWriter) add(format string, args ...any) {
+	w.statements = append(w.statements, fmt.Sprintf(format, args...))
+}
+
+func (w *ddlWriter) comment(format string, args ...any) {
+	w.add("-- "+format, args...)
+}
+
+// table quotes each part of e.g. "schema.table".
+func (w *ddlWriter) table(name string) string {
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		parts[i] = w.reference(part)
+	}
+	return strings.Join(parts, ".")
+}
+
+func (w *ddlWriter) columns(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = w.reference(name)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+func (w *ddlWriter) columnDefinition(column models.ColumnSchema) string {
+	definition := w.reference(column.Name) + " " + column.Type
+	if !column.Nullable {
+		definition += " NOT NULL"
+	}
+	if column.Default != "" {
+		definition += " DEFAULT " + column.Default
+	}
+	return definition
+}
+
+// tableDefinitions returns the definitions of the columns and of the primary
+// key of table, for its CREATE TABLE statement.
+func (w *ddlWriter) tableDefinitions(table models.TableSchema) []string {
+	definitions := make([]string, 0, len(table.Columns)+1+len(table.ForeignKeys))
+	for _, column := range table.Columns {
+		definitions = append(definitions, w.columnDefinition(column))
+	}
+	if table.PrimaryKey != nil && len(table.PrimaryKey.Columns) > 0 {
+		definitions = append(definitions, "PRIMARY KEY ("+w.columns(table.PrimaryKey.Columns)+")")
+	}
+	return definitions
+}
+
+func (w *ddlWriter) createTable(table models.TableSchema) {
+	definitions := w.tableDefinitions(table)
+	// SQLite can't add foreign keys to an existing table.
+	if w.provider == DriverSqlite {
+		for _, key := range table.ForeignKeys {
+			definitions = append(definitions, w.foreignKeyDefinition(key))
+		}
+	}
+
+	w.add("CREATE TABLE %s (\n  %s\n);", w.table(table.Name), strings.Join(definitions, ",\n  "))
+
+	for _, index := range table.Indexes {
+		w.createIndex(table.Name, index)
+	}
+	if w.provider != DriverSqlite {
+		for _, key := range table.ForeignKeys {
+			w.addForeignKey(table.Name, key)
+		}
+	}
+}
+
+func (w *ddlWriter) addColumn(table string, column models.ColumnSchema) {
+	if w.provider == DriverMSSQL {
+		w.add("ALTER TABLE %s ADD %s;", w.table(table), w.columnDefinition(column))
+		return
+	}
+	w.add("ALTER TABLE %s ADD COLUMN %s;", w.table(table), w.columnDefinition(column))
+}
+
+func (w *ddlWriter) dropColumn(table, column string) {
+	w.add("ALTER TABLE %s DROP COLUMN %s;", w.table(table), w.reference(column))
+}
+
+func (w *ddlWriter) renameColumn(table, oldName, newName string) {
+	if w.provider == DriverMSSQL {
+		// sp_rename takes the current name unquoted, as table.column.
+		w.add("EXEC sp_rename '%s', '%s', 'COLUMN';", strings.ReplaceAll(table+"."+oldName, "'", "''"), strings.ReplaceAll(newName, "'", "''"))
+		return
+	}
+	w.add("ALTER TABLE %s RENAME COLUMN %s TO %s;", w.table(table), w.reference(oldName), w.reference(newName))
+}
+
+func (w *ddlWriter) alterColumn(table string, source, target models.ColumnSchema) {
+	tableName := w.table(table)
+	column := w.reference(source.Name)
+
+	typeChanged := !strings.EqualFold(source.Type, target.Type)
+	nullableChanged := source.Nullable != target.Nullable
+	defaultChanged := !strings.EqualFold(source.Default, target.Default)
+
+	switch w.provider {
+	case DriverMySQL:
+		w.add("ALTER TABLE %s MODIFY COLUMN %s;", tableName, w.columnDefinition(source))
+	case DriverPostgres:
+		if typeChanged {
+			w.add("ALTER TABLE %s ALTER COLUMN %s TYPE %s;", tableName, column, source.Type)
+		}
+		if nullableChanged && source.Nullable {
+			w.add("ALTER TABLE %s ALTER COLUMN %s DROP NOT NULL;", tableName, column)
+		} else if nullableChanged {
+			w.add("ALTER TABLE %s ALTER COLUMN %s SET NOT NULL;", tableName, column)
+		}
+		if defaultChanged && source.Default == "" {
+			w.add("ALTER TABLE %s ALTER COLUMN %s DROP DEFAULT;", tableName, column)
+		} else if defaultChanged {
+			w.add("ALTER TABLE %s ALTER COLUMN %s SET DEFAULT %s;", tableName, column, source.Default)
+		}
+	case DriverMSSQL:
+		if typeChanged || nullableChanged {
+			nullability := "NULL"
+			if !source.Nullable {
+				nullability = "NOT NULL"
+			}
+			w.add("ALTER TABLE %s ALTER COLUMN %s %s %s;", tableName, column, source.Type, nullability)
+		}
+		if defaultChanged {
+			w.comment("replace the default constraint of %s.%s with DEFAULT %s", table, source.Name, source.Default)
+		}
+	default:
+		w.comment("SQLite can't alter column %s of %s to %s, the table has to be rebuilt", source.Name, table, describeColumn(source))
+	}
+}
+
+func (w *ddlWriter) alterPrimaryKey(table string, source, target *models.ConstraintSchema) {
+	tableName := w.table(table)
+
+	switch w.provider {
+	case DriverSqlite:
+		w.comment("SQLite can't change the primary key of %s to %s, the table has to be rebuilt", table, describePrimaryKey(source))
+		return
+	case DriverMySQL:
+		if target != nil {
+			w.add("ALTER TABLE %s DROP PRIMARY KEY;", tableName)
+		}
+		if source != nil {
+			w.add("ALTER TABLE %s ADD PRIMARY KEY (%s);", tableName, w.columns(source.Columns))
+		}
+		return
+	}
+
+	if target != nil && target.Name != "" {
+		w.add("ALTER TABLE %s DROP CONSTRAINT %s;", tableName, w.reference(target.Name))
+	}
+	if source == nil {
+		return
+	}
+	if source.Name != "" {
+		w.add("ALTER TABLE %s ADD CONSTRAINT %s PRIMARY KEY (%s);", tableName, w.reference(source.Name), w.columns(source.Columns))
+	} else {
+		w.add("ALTER TABLE %s ADD PRIMARY KEY (%s);", tableName, w.columns(source.Columns))
+	}
+}
+
+func (w *ddlWriter) createIndex(table string, index models.IndexSchema) {
+	if len(index.Columns) == 0 {
+		w.comment("create index %s of %s as in the source", index.Name, table)
+		return
+	}
+
+	if index.Constraint && (w.provider == DriverPostgres || w.provider == DriverMSSQL) {
+		w.add("ALTER TABLE %s ADD CONSTRAINT %s UNIQUE (%s);", w.table(table), w.reference(index.Name), w.columns(index.Columns))
+		return
+	}
+
+	unique := ""
+	if index.Unique {
+		unique = "UNIQUE "
+	}
+	w.add("CREATE %sINDEX %s ON %s (%s);", unique, w.reference(index.Name), w.table(table), w.columns(index.Columns))
+}
+
+func (w *ddlWriter) dropIndex(table string, index models.IndexSchema) {
+	switch {
+	case index.Constraint && (w.provider == DriverPostgres || w.provider == DriverMSSQL):
+		w.add("ALTER TABLE %s DROP CONSTRAINT %s;", w.table(table), w.reference(index.Name))
+	case w.provider == DriverPostgres:
+		// Indexes belong to the schema of their table.
+		name := w.reference(index.Name)
+		if schema, _, ok := strings.Cut(table, "."); ok {
+			name = w.reference(schema) + "." + name
+		}
+		w.add("DROP INDEX %s;", name)
+	case w.provider == DriverSqlite:
+		w.add("DROP INDEX %s;", w.reference(index.Name))
+	default:
+		w.add("DROP INDEX %s ON %s;", w.reference(index.Name), w.table(table))
+	}
+}
+
+func (w *ddlWriter) foreignKeyDefinition(key models.ForeignKeySchema) string {
+	return fmt.Sprintf("FOREIGN KEY (%s) REFERENCES %s (%s)", w.columns(key.Columns), w.table(key.ReferencedTable), w.columns(key.ReferencedColumns))
+}
+
+func (w *ddlWriter) addForeignKey(table string, key models.ForeignKeySchema) {
+	if w.provider == DriverSqlite {
+		w.comment("SQLite can't add the foreign key %s to %s, the table has to be rebuilt", describeForeignKey(key), table)
+		return
+	}
+	w.add("ALTER TABLE %s ADD CONSTRAINT %s %s;", w.table(table), w.reference(key.Name), w.foreignKeyDefinition(key))
+}
+
+func (w *ddlWriter) dropForeignKey(table string, key models.ForeignKeySchema) {
+	switch w.provider {
+	case DriverSqlite:
+		w.comment("SQLite can't drop the foreign key %s of %s, the table has to be rebuilt", describeForeignKey(key), table)
+	case DriverMySQL:
+		w.add("ALTER TABLE %s DROP FOREIGN KEY %s;", w.table(table), w.reference(key.Name))
+	default:
+		w.add("ALTER TABLE %s DROP CONSTRAINT %s;", w.table(table), w.reference(key.Name))
+	}
+}
+
+// tableDefinition rebuilds the statements that create table from its
+// columns, constraints, indexes and foreign keys, for the databases that
+// can't return them.
+func tableDefinition(ctx context.Context, db Driver, database, table string) (string, error) {
+	schema, err := loadTableSchema(ctx, db, database, table)
+	if err != nil {
+		return "", err
+	}
+	if len(schema.Columns) == 0 {
+		return "", fmt.Errorf("table %s not found", table)
+	}
+
+	w := &ddlWriter{provider: db.GetProvider(), reference: db.FormatReference}
+	w.createTable(schema)
+
+	return strings.Join(w.statements, "\n\n"), nil
+}
+
+// DDLChangeQueries returns the statements of a staged DDL change in the
+// dialect of db. It fails for the changes the dialect can't make in place,
+// e.g. altering a column of a SQLite table.
+func DDLChangeQueries(db Driver, change models.DBDDLChange) ([]string, error) {
+	w := &ddlWriter{provider: db.GetProvider(), reference: db.FormatReference}
+
+	table := change.Table
+	if w.provider == DriverMySQL && change.Database != "" {
+		table = change.Database + "." + change.Table
+	}
+
+	switch change.Type {
+	case models.DDLAddColumn:
+		w.addColumn(table, change.Column)
+	case models.DDLDropColumn:
+		w.dropColumn(table, change.Column.Name)
+	case models.DDLRenameColumn:
+		w.renameColumn(table, change.OldColumn.Name, change.Column.Name)
+	case models.DDLAlterColumn:
+		w.alterColumn(table, change.Column, change.OldColumn)
+	case models.DDLCreateIndex:
+		w.createIndex(table, change.Index)
+	case models.DDLDropIndex:
+		w.dropIndex(table, change.Index)
+	}
+
+	for _, statement := range w.statements {
+		if comment, ok := strings.CutPrefix(statement, "-- "); ok {
+			return nil, errors.New(comment)
+		}
+	}
+
+	return w.statements, nil
+}
+
+// SupportsTransactionalDDL reports whether the statements that change the
+// structure of tables can be rolled back in provider. MySQL commits the open
+// transaction before each of them.
+func SupportsTransactionalDDL(provider string) bool {
+	return provider != DriverMySQL
+}
+
+// ExecuteStructureChanges runs the staged DDL changes and then the DML
+// changes. Where DDL is transactional, they all run in one transaction, or
+// inside the open one. Elsewhere the DDL statements run one by one, and the
+// DML changes in a transaction of their own.
+func ExecuteStructureChanges(ctx context.Context, db Driver, ddlChanges []models.DBDDLChange, dmlChanges []models.DBDMLChange) error {
+	var queries []string
+	for _, change := range ddlChanges {
+		changeQueries, err := DDLChangeQueries(db, change)
+		if err != nil {
+			return err
+		}
+		queries = append(queries, changeQueries...)
+	}
+
+	transactional := SupportsTransactionalDDL(db.GetProvider())
+	if len(queries) > 0 && !transactional && db.InTransaction() {
+		return errors.New("the open transaction would be committed by the DDL statements, commit or roll it back first")
+	}
+
+	ownTransaction := len(queries) > 0 && transactional && !db.InTransaction()
+	if ownTransaction {
+		if err := db.BeginTransaction(ctx); err != nil {
+			return err
+		}
+	}
+
+	err := func() error {
+		for _, query := range queries {
+			if _, err := db.ExecuteDMLStatement(ctx, query); err != nil {
+				return fmt.Errorf("%s: %w", query, err)
+			}
+		}
+		if len(dmlChanges) > 0 {
+			return db.ExecutePendingChanges(ctx, dmlChanges)
+		}
+		return nil
+	}()
+
+	if !ownTransaction {
+		return err
+	}
+	if err != nil {
+		return errors.Join(err, db.RollbackTransaction())
+	}
+	return db.CommitTransaction()
+}
@@ -0,0 +1,181 @@
+package drivers
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+func TestDDLChangeQueries(t *testing.T) {
+	email := models.ColumnSchema{Name: "email", Type: "varchar(100)", Nullable: true}
+	changedEmail := models.ColumnSchema{Name: "email", Type: "varchar(255)"}
+
+	tests := []struct {
+		name     string
+		db       Driver
+		change   models.DBDDLChange
+		expected []string
+	}{
+		{
+			name:     "MySQL add column",
+			db:       &MySQL{Provider: DriverMySQL},
+			change:   models.DBDDLChange{Database: "app", Table: "users", Type: models.DDLAddColumn, Column: email},
+			expected: []string{"ALTER TABLE `app`.`users` ADD COLUMN `email` varchar(100);"},
+		},
+		{
+			name:     "MySQL rename column",
+			db:       &MySQL{Provider: DriverMySQL},
+			change:   models.DBDDLChange{Database: "app", Table: "users", Type: models.DDLRenameColumn, Column: models.ColumnSchema{Name: "mail"}, OldColumn: email},
+			expected: []string{"ALTER TABLE `app`.`users` RENAME COLUMN `email` TO `mail`;"},
+		},
+		{
+			name:   "Postgres alter column",
+			db:     &Postgres{Provider: DriverPostgres},
+			change: models.DBDDLChange{Database: "app", Table: "public.users", Type: models.DDLAlterColumn, Column: changedEmail, OldColumn: email},
+			expected: []string{
+				`ALTER TABLE "public"."users" ALTER COLUMN "email" TYPE varchar(255);`,
+				`ALTER TABLE "public"."users" ALTER COLUMN "email" SET NOT NULL;`,
+			},
+		},
+		{
+			name:     "Postgres drop unique constraint",
+			db:       &Postgres{Provider: DriverPostgres},
+			change:   models.DBDDLChange{Database: "app", Table: "public.users", Type: models.DDLDropIndex, Index: models.IndexSchema{Name: "users_email_key", Columns: []string{"email"}, Unique: true, Constraint: true}},
+			expected: []string{`ALTER TABLE "public"."users" DROP CONSTRAINT "users_email_key";`},
+		},
+		{
+			name:     "MSSQL rename column",
+			db:       &MSSQL{Provider: DriverMSSQL},
+			change:   models.DBDDLChange{Database: "app", Table: "users", Type: models.DDLRenameColumn, Column: models.ColumnSchema{Name: "mail"}, OldColumn: email},
+			expected: []string{"EXEC sp_rename 'users.email', 'mail', 'COLUMN';"},
+		},
+		{
+			name:     "SQLite create index",
+			db:       &SQLite{Provider: DriverSqlite},
+			change:   models.DBDDLChange{Database: "main", Table: "users", Type: models.DDLCreateIndex, Index: models.IndexSchema{Name: "users_email", Columns: []string{"email"}, Unique: true}},
+			expected: []string{"CREATE UNIQUE INDEX `users_email` ON `users` (`email`);"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			queries, err := DDLChangeQueries(tt.db, tt.change)
+			if err != nil {
+				t.Fatalf("DDLChangeQueries failed: %v", err)
+			}
+			if !reflect.DeepEqual(queries, tt.expected) {
+				t.Fatalf("Queries mismatch:\nexpected: %q\ngot: %q", tt.expected, queries)
+			}
+		})
+	}
+}
+
+func TestDDLChangeQueries_SQLiteAlterColumn(t *testing.T) {
+	change := models.DBDDLChange{
+		Table:     "users",
+		Type:      models.DDLAlterColumn,
+		Column:    models.ColumnSchema{Name: "email", Type: "TEXT"},
+		OldColumn: models.ColumnSchema{Name: "email", Type: "TEXT", Nullable: true},
+	}
+
+	_, err := DDLChangeQueries(&SQLite{Provider: DriverSqlite}, change)
+	if err == nil || !strings.Contains(err.Error(), "SQLite can't alter column email") {
+		t.Fatalf("Expected an error for altering a SQLite column, got %v", err)
+	}
+}
+
+func TestExecuteStructureChanges_SQLite(t *testing.T) {
+	ctx := context.Background()
+
+	db := &SQLite{}
+	if err := db.Connect(ctx, filepath.Join(t.TempDir(), "test.db")); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	t.Cleanup(func() { db.Connection.Close() })
+	if _, err := db.Connection.ExecContext(ctx, "CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT)"); err != nil {
+		t.Fatalf("CREATE TABLE failed: %v", err)
+	}
+
+	columnCount := func() int {
+		t.Helper()
+
+		columns, err := db.GetTableColumns(ctx, "main", "users")
+		if err != nil {
+			t.Fatalf("GetTableColumns failed: %v", err)
+		}
+		return len(columns) - 1
+	}
+
+	addEmail := models.DBDDLChange{Table: "users", Type: models.DDLAddColumn, Column: models.ColumnSchema{Name: "email", Type: "TEXT", Nullable: true}}
+	insert := models.DBDMLChange{
+		Table: "users",
+		Type:  models.DMLInsertType,
+		Values: []models.CellValue{
+			{Column: "name", Value: "Alice", Type: models.String},
+			{Column: "email", Value: "alice@example.com", Type: models.String},
+		},
+	}
+	failing := models.DBDDLChange{Table: "users", Type: models.DDLCreateIndex, Index: models.IndexSchema{Name: "users_missing", Columns: []string{"missing"}}}
+
+	// A failing statement rolls back the column added before it.
+	if err := ExecuteStructureChanges(ctx, db, []models.DBDDLChange{addEmail, failing}, nil); err == nil {
+		t.Fatal("Expected an error for an index on a missing column")
+	}
+	if count := columnCount(); count != 2 {
+		t.Fatalf("Expected the added column to be rolled back, got %d columns", count)
+	}
+	if db.InTransaction() {
+		t.Fatal("Expected no transaction to be left open")
+	}
+
+	// The DML changes run after the DDL changes, and may use what they add.
+	if err := ExecuteStructureChanges(ctx, db, []models.DBDDLChange{addEmail}, []models.DBDMLChange{insert}); err != nil {
+		t.Fatalf("ExecuteStructureChanges failed: %v", err)
+	}
+	if count := columnCount(); count != 3 {
+		t.Fatalf("Expected 3 columns, got %d", count)
+	}
+
+	var email string
+	if err := db.Connection.QueryRowContext(ctx, "SELECT email FROM users WHERE name = 'Alice'").Scan(&email); err != nil {
+		t.Fatalf("Reading the inserted row failed: %v", err)
+	}
+	if email != "alice@example.com" {
+		t.Fatalf("Expected alice@example.com, got %q", email)
+	}
+}
+
+func TestTableDefinition_SQLite(t *testing.T) {
+	ctx := context.Background()
+
+	db := &SQLite{}
+	if err := db.Connect(ctx, filepath.Join(t.TempDir(), "test.db")); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	t.Cleanup(func() { db.Connection.Close() })
+
+	for _, statement := range []string{
+		"CREATE TABLE users (id INTEGER PRIMARY KEY)",
+		"CREATE TABLE orders (id INTEGER PRIMARY KEY, user_id INTEGER NOT NULL REFERENCES users (id))",
+	} {
+		if _, err := db.Connection.ExecContext(ctx, statement); err != nil {
+			t.Fatalf("%s failed: %v", statement, err)
+		}
+	}
+
+	// The definition rebuilt from the catalog, as for Postgres and MSSQL.
+	definition, err := tableDefinition(ctx, db, "main", "orders")
+	if err != nil {
+		t.Fatalf("tableDefinition failed: %v", err)
+	}
+
+	expected := "CREATE TABLE `orders` (\n  `id` INTEGER,\n  `user_id` INTEGER NOT NULL,\n  PRIMARY KEY (`id`),\n" +
+		"  FOREIGN KEY (`user_id`) REFERENCES `users` (`id`)\n);"
+	if definition != expected {
+		t.Fatalf("Definition mismatch:\nexpected: %s\ngot: %s", expected, definition)
+	}
+}
@@ -0,0 +1,524 @@
+package drivers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+// systemSchemas are the schemas of the database itself, left out of a
+// schema diff.
+var systemSchemas = map[string]bool{
+	"information_schema": true,
+	"pg_catalog":         true,
+	"pg_toast":           true,
+	"sys":                true,
+}
+
+// LoadSchema reads the structure of every table of database through
+// GetTables, GetTableColumns, GetConstraints, GetIndexes and GetForeignKeys.
+func LoadSchema(ctx context.Context, db Driver, database string) ([]models.TableSchema, error) {
+	tables, err := db.GetTables(ctx, database)
+	if err != nil {
+		return nil, err
+	}
+
+	var tableNames []string
+	for schema, names := range tables {
+		if systemSchemas[schema] {
+			continue
+		}
+		for _, name := range names {
+			if db.UseSchemas() {
+				name = schema + "." + name
+			}
+			tableNames = append(tableNames, name)
+		}
+	}
+	sort.Strings(tableNames)
+
+	schemas := make([]models.TableSchema, 0, len(tableNames))
+	for _, tableName := range tableNames {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		table, err := loadTableSchema(ctx, db, database, tableName)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", tableName, err)
+		}
+		schemas = append(schemas, table)
+	}
+
+	return schemas, nil
+}
+
+// loadTableSchema reads the structure of a table. The column types are the
+// full types returned by GetTableColumns, e.g. varchar(255), so that a change
+// of length or precision is a change of the column.
+func loadTableSchema(ctx context.Context, db Driver, database, tableName string) (models.TableSchema, error) {
+	table := models.TableSchema{Name: tableName}
+
+	columns, err := db.GetTableColumns(ctx, database, tableName)
+	if err != nil {
+		return table, err
+	}
+	constraints, err := db.GetConstraints(ctx, database, tableName)
+	if err != nil {
+		return table, err
+	}
+	indexes, err := db.GetIndexes(ctx, database, tableName)
+	if err != nil {
+		return table, err
+	}
+
+	// The foreign keys of MySQL are read from its constraints, GetForeignKeys
+	// returns the keys referencing the table.
+	var foreignKeys [][]string
+	if db.GetProvider() != DriverMySQL {
+		foreignKeys, err = db.GetForeignKeys(ctx, database, tableName)
+		if err != nil {
+			return table, err
+		}
+	}
+
+	table.Columns, table.PrimaryKey = parseSchemaColumns(columns)
+
+	primaryKey, uniqueConstraints, constraintKeys := parseSchemaConstraints(constraints)
+	if primaryKey != nil {
+		table.PrimaryKey = primaryKey
+	}
+
+	var indexPrimaryKey *models.ConstraintSchema
+	table.Indexes, indexPrimaryKey = parseSchemaIndexes(indexes, table.PrimaryKey, uniqueConstraints)
+	if table.PrimaryKey == nil {
+		table.PrimaryKey = indexPrimaryKey
+	}
+
+	table.ForeignKeys = append(constraintKeys, parseSchemaForeignKeys(foreignKeys)...)
+
+	return table, nil
+}
+
+// schemaRows reads the results of GetTableColumns and the like, whose first
+// row holds the column names, by column name since each driver returns
+// different columns.
+type schemaRows struct {
+	header map[string]int
+	rows   [][]string
+}
+
+func newSchemaRows(results [][]string) schemaRows {
+	r := schemaRows{header: map[string]int{}}
+	if len(results) == 0 {
+		return r
+	}
+	for i, name := range results[0] {
+		r.header[strings.ToLower(name)] = i
+	}
+	r.rows = results[1:]
+	return r
+}
+
+func (r schemaRows) has(name string) bool {
+	_, ok := r.header[name]
+	return ok
+}
+
+// value returns the value of the first of names the rows have.
+func (r schemaRows) value(row []string, names ...string) string {
+	for _, name := range names {
+		if i, ok := r.header[name]; ok && i < len(row) {
+			return row[i]
+		}
+	}
+	return ""
+}
+
+// ParseTableColumns reads the results of GetTableColumns, a column for each
+// of its rows.
+func ParseTableColumns(results [][]string) []models.ColumnSchema {
+	columns, _ := parseSchemaColumns(results)
+	return columns
+}
+
+// ParseTableIndexes reads the results of GetIndexes, with GetConstraints
+// to tell the UNIQUE constraints apart. The index of the primary key is
+// left out.
+func ParseTableIndexes(indexes, constraints [][]string) []models.IndexSchema {
+	primaryKey, uniqueConstraints, _ := parseSchemaConstraints(constraints)
+	parsed, _ := parseSchemaIndexes(indexes, primaryKey, uniqueConstraints)
+	return parsed
+}
+
+// SchemaObjectName returns the name of the index or constraint at row of
+// the results of GetIndexes or GetConstraints, the first row being the
+// column names.
+func SchemaObjectName(results [][]string, row int) string {
+	r := newSchemaRows(results)
+	if row < 1 || row > len(r.rows) {
+		return ""
+	}
+	return r.value(r.rows[row-1], "key_name", "index_name", "constraint_name", "name")
+}
+
+func isTrue(value string) bool {
+	switch strings.ToLower(value) {
+	case "1", "true", "yes":
+		return true
+	}
+	return false
+}
+
+func parseSchemaColumns(results [][]string) ([]models.ColumnSchema, *models.ConstraintSchema) {
+	r := newSchemaRows(results)
+	columns := make([]models.ColumnSchema, 0, len(r.rows))
+
+	// SQLite reports the position of each column in the primary key.
+	var primaryKeyColumns []string
+	var primaryKeyPositions []int
+
+	for _, row := range r.rows {
+		column := models.ColumnSchema{
+			Name:    r.value(row, "field", "column_name", "name"),
+			Type:    r.value(row, "type", "data_type"),
+			Default: r.value(row, "default", "column_default", "dflt_value"),
+		}
+
+		switch {
+		case r.has("notnull"):
+			column.Nullable = !isTrue(r.value(row, "notnull"))
+		default:
+			column.Nullable = isTrue(r.value(row, "null", "is_nullable"))
+		}
+
+		position, err := strconv.Atoi(r.value(row, "pk"))
+		if err != nil && r.value(row, "key") == "PRI" {
+			// MySQL only flags the columns of the primary key, taken in
+			// the order of the columns.
+			position, err = len(primaryKeyColumns)+1, nil
+		}
+		if err == nil && position > 0 {
+			primaryKeyColumns = append(primaryKeyColumns, column.Name)
+			primaryKeyPositions = append(primaryKeyPositions, position)
+		}
+
+		columns = append(columns, column)
+	}
+
+	if len(primaryKeyColumns) == 0 {
+		return columns, nil
+	}
+
+	ordered := make([]string, len(primaryKeyColumns))
+	for i, position := range primaryKeyPositions {
+		if position <= len(ordered) {
+			ordered[position-1] = primaryKeyColumns[i]
+		}
+	}
+	return columns, &models.ConstraintSchema{Columns: ordered}
+}
+
+// parseSchemaConstraints returns the primary key, the names of the UNIQUE
+// constraints and, for MySQL, the foreign keys.
+func parseSchemaConstraints(results [][]string) (*models.ConstraintSchema, map[string]bool, []models.ForeignKeySchema) {
+	r := newSchemaRows(results)
+	if !r.has("constraint_name") {
+		// e.g. SQLite returns the statement that created the table.
+		return nil, nil, nil
+	}
+
+	var primaryKey *models.ConstraintSchema
+	unique := map[string]bool{}
+	var foreignKeys []models.ForeignKeySchema
+
+	for _, row := range r.rows {
+		name := r.value(row, "constraint_name")
+		column := r.value(row, "column_name")
+		constraintType := strings.ToUpper(r.value(row, "constraint_type"))
+
+		switch {
+		case strings.Contains(constraintType, "PRIMARY") || (constraintType == "" && name == "PRIMARY"):
+			if primaryKey == nil {
+				primaryKey = &models.ConstraintSchema{Name: name}
+			}
+			primaryKey.Columns = appendUnique(primaryKey.Columns, column)
+		case strings.Contains(constraintType, "UNIQUE"):
+			unique[name] = true
+		case r.value(row, "referenced_table_name") != "":
+			foreignKeys = addForeignKeyColumn(foreignKeys, name, r.value(row, "referenced_table_name"), column, r.value(row, "referenced_column_name"))
+		}
+	}
+
+	return primaryKey, unique, foreignKeys
+}
+
+// parseSchemaIndexes returns the indexes other than the one of the primary
+// key, which is returned on its own.
+func parseSchemaIndexes(results [][]string, primaryKey *models.ConstraintSchema, uniqueConstraints map[string]bool) ([]models.IndexSchema, *models.ConstraintSchema) {
+	r := newSchemaRows(results)
+
+	var indexes []models.IndexSchema
+	var indexPrimaryKey *models.ConstraintSchema
+
+	for _, row := range r.rows {
+		name := r.value(row, "key_name", "index_name", "name")
+		column := r.value(row, "column_name")
+
+		isPrimary := name == "PRIMARY" || isTrue(r.value(row, "is_primary_key")) || r.value(row, "origin") == "pk" ||
+			(primaryKey != nil && primaryKey.Name != "" && name == primaryKey.Name)
+		if isPrimary {
+			if indexPrimaryKey == nil {
+				indexPrimaryKey = &models.ConstraintSchema{Name: name}
+			}
+			if column != "" {
+				indexPrimaryKey.Columns = appendUnique(indexPrimaryKey.Columns, column)
+			}
+			continue
+		}
+
+		i := slices.IndexFunc(indexes, func(index models.IndexSchema) bool { return index.Name == name })
+		if i < 0 {
+			unique := isTrue(r.value(row, "is_unique", "unique")) ||
+				(r.has("non_unique") && !isTrue(r.value(row, "non_unique"))) ||
+				uniqueConstraints[name]
+			indexes = append(indexes, models.IndexSchema{
+				Name:       name,
+				Unique:     unique,
+				Constraint: uniqueConstraints[name] || r.value(row, "origin") == "u",
+			})
+			i = len(indexes) - 1
+		}
+		if column != "" {
+			indexes[i].Columns = appendUnique(indexes[i].Columns, column)
+		}
+	}
+
+	return indexes, indexPrimaryKey
+}
+
+func parseSchemaForeignKeys(results [][]string) []models.ForeignKeySchema {
+	r := newSchemaRows(results)
+
+	var foreignKeys []models.ForeignKeySchema
+	for _, row := range r.rows {
+		name := r.value(row, "constraint_name", "id")
+
+		referencedTable := r.value(row, "referenced_table", "table")
+		if r.has("foreign_table_name") {
+			referencedTable = r.value(row, "foreign_table_schema") + "." + r.value(row, "foreign_table_name")
+		}
+
+		foreignKeys = addForeignKeyColumn(foreignKeys, name, referencedTable,
+			r.value(row, "column_name", "from"),
+			r.value(row, "referenced_column", "foreign_column_name", "to"))
+	}
+
+	return foreignKeys
+}
+
+func addForeignKeyColumn(foreignKeys []models.ForeignKeySchema, name, referencedTable, column, referencedColumn string) []models.ForeignKeySchema {
+	i := slices.IndexFunc(foreignKeys, func(key models.ForeignKeySchema) bool { return key.Name == name })
+	if i < 0 {
+		foreignKeys = append(foreignKeys, models.ForeignKeySchema{Name: name, ReferencedTable: referencedTable})
+		i = len(foreignKeys) - 1
+	}
+	foreignKeys[i].Columns = append(foreignKeys[i].Columns, column)
+	foreignKeys[i].ReferencedColumns = append(foreignKeys[i].ReferencedColumns, referencedColumn)
+	return foreignKeys
+}
+
+func appendUnique(values []string, value string) []string {
+	if slices.Contains(values, value) {
+		return values
+	}
+	return append(values, value)
+}
+
+// SchemaDiff holds the differences between a source and a target schema.
+type SchemaDiff struct {
+	Source  map[string]models.TableSchema
+	Target  map[string]models.TableSchema
+	Changes []models.SchemaChange
+}
+
+// DiffSchemas compares the tables of source and target, by table name and
+// then by column, index and foreign key name.
+func DiffSchemas(source, target []models.TableSchema) *SchemaDiff {
+	diff := &SchemaDiff{
+		Source: make(map[string]models.TableSchema, len(source)),
+		Target: make(map[string]models.TableSchema, len(target)),
+	}
+
+	var tableNames []string
+	for _, table := range source {
+		diff.Source[table.Name] = table
+		tableNames = append(tableNames, table.Name)
+	}
+	for _, table := range target {
+		diff.Target[table.Name] = table
+		if _, ok := diff.Source[table.Name]; !ok {
+			tableNames = append(tableNames, table.Name)
+		}
+	}
+	sort.Strings(tableNames)
+
+	for _, tableName := range tableNames {
+		sourceTable, inSource := diff.Source[tableName]
+		targetTable, inTarget := diff.Target[tableName]
+
+		switch {
+		case !inTarget:
+			diff.add(models.SchemaAdded, models.SchemaTable, tableName, "", describeTable(sourceTable), "")
+		case !inSource:
+			diff.add(models.SchemaRemoved, models.SchemaTable, tableName, "", "", describeTable(targetTable))
+		default:
+			diff.diffTables(sourceTable, targetTable)
+		}
+	}
+
+	return diff
+}
+
+func (d *SchemaDiff) add(changeType models.SchemaChangeType, kind models.SchemaObjectKind, table, name, source, target string) {
+	d.Changes = append(d.Changes, models.SchemaChange{
+		Type:   changeType,
+		Kind:   kind,
+		Table:  table,
+		Name:   name,
+		Source: source,
+		Target: target,
+	})
+}
+
+// diffObjects compares the objects of a kind of both sides by name.
+func (d *SchemaDiff) diffObjects(kind models.SchemaObjectKind, table string, sourceNames, targetNames []string, describe func(name string, source bool) string) {
+	for _, name := range sourceNames {
+		sourceText := describe(name, true)
+		if !slices.Contains(targetNames, name) {
+			d.add(models.SchemaAdded, kind, table, name, sourceText, "")
+		} else if targetText := describe(name, false); !strings.EqualFold(sourceText, targetText) {
+			d.add(models.SchemaChanged, kind, table, name, sourceText, targetText)
+		}
+	}
+	for _, name := range targetNames {
+		if !slices.Contains(sourceNames, name) {
+			d.add(models.SchemaRemoved, kind, table, name, "", describe(name, false))
+		}
+	}
+}
+
+func (d *SchemaDiff) diffTables(source, target models.TableSchema) {
+	d.diffObjects(models.SchemaColumn, source.Name, columnNames(source), columnNames(target), func(name string, inSource bool) string {
+		return describeColumn(findColumn(pick(inSource, source, target), name))
+	})
+
+	sourcePrimaryKey, targetPrimaryKey := describePrimaryKey(source.PrimaryKey), describePrimaryKey(target.PrimaryKey)
+	switch {
+	case sourcePrimaryKey == targetPrimaryKey:
+	case targetPrimaryKey == "":
+		d.add(models.SchemaAdded, models.SchemaPrimaryKey, source.Name, "", sourcePrimaryKey, "")
+	case sourcePrimaryKey == "":
+		d.add(models.SchemaRemoved, models.SchemaPrimaryKey, source.Name, "", "", targetPrimaryKey)
+	default:
+		d.add(models.SchemaChanged, models.SchemaPrimaryKey, source.Name, "", sourcePrimaryKey, targetPrimaryKey)
+	}
+
+	d.diffObjects(models.SchemaIndex, source.Name, indexNames(source), indexNames(target), func(name string, inSource bool) string {
+		return describeIndex(findIndex(pick(inSource, source, target), name))
+	})
+
+	d.diffObjects(models.SchemaForeignKey, source.Name, foreignKeyNames(source), foreignKeyNames(target), func(name string, inSource bool) string {
+		return describeForeignKey(findForeignKey(pick(inSource, source, target), name))
+	})
+}
+
+func pick(first bool, a, b models.TableSchema) models.TableSchema {
+	if first {
+		return a
+	}
+	return b
+}
+
+func columnNames(table models.TableSchema) []string {
+	names := make([]string, len(table.Columns))
+	for i, column := range table.Columns {
+		names[i] = column.Name
+	}
+	return names
+}
+
+func indexNames(table models.TableSchema) []string {
+	names := make([]string, len(table.Indexes))
+	for i, index := range table.Indexes {
+		names[i] = index.Name
+	}
+	return names
+}
+
+func foreignKeyNames(table models.TableSchema) []string {
+	names := make([]string, len(table.ForeignKeys))
+	for i, key := range table.ForeignKeys {
+		names[i] = key.Name
+	}
+	return names
+}
+
+func findColumn(table models.TableSchema, name string) models.ColumnSchema {
+	i := slices.IndexFunc(table.Columns, func(column models.ColumnSchema) bool { return column.Name == name })
+	return table.Columns[i]
+}
+
+func findIndex(table models.TableSchema, name string) models.IndexSchema {
+	i := slices.IndexFunc(table.Indexes, func(index models.IndexSchema) bool { return index.Name == name })
+	return table.Indexes[i]
+}
+
+func findForeignKey(table models.TableSchema, name string) models.ForeignKeySchema {
+	i := slices.IndexFunc(table.ForeignKeys, func(key models.ForeignKeySchema) bool { return key.Name == name })
+	return table.ForeignKeys[i]
+}
+
+func describeTable(table models.TableSchema) string {
+	names := columnNames(table)
+	return fmt.Sprintf("%d columns: %s", len(names), strings.Join(names, ", "))
+}
+
+func describeColumn(column models.ColumnSchema) string {
+	text := column.Type
+	if !column.Nullable {
+		text += " NOT NULL"
+	}
+	if column.Default != "" {
+		text += " DEFAULT " + column.Default
+	}
+	return text
+}
+
+func describePrimaryKey(primaryKey *models.ConstraintSchema) string {
+	if primaryKey == nil || len(primaryKey.Columns) == 0 {
+		return ""
+	}
+	return "(" + strings.Join(primaryKey.Columns, ", ") + ")"
+}
+
+func describeIndex(index models.IndexSchema) string {
+	// SQLite doesn't list the columns of its indexes.
+	text := "index"
+	if len(index.Columns) > 0 {
+		text = "(" + strings.Join(index.Columns, ", ") + ")"
+	}
+	if index.Unique {
+		text = "UNIQUE " + text
+	}
+	return text
+}
+
+func describeForeignKey(key models.ForeignKeySchema) string {
+	return fmt.Sprintf("(%s) REFERENCES %s (%s)", strings.Join(key.Columns, ", "), key.ReferencedTable, strings.Join(key.ReferencedColumns, ", "))
+}
@@ -0,0 +1,204 @@
+package drivers
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+func TestLoadSchema_MySQLResults(t *testing.T) {
+	columns := [][]string{
+		{"Field", "Type", "Collation", "Null", "Key", "Default", "Extra", "Privileges", "Comment"},
+		{"id", "int", "", "NO", "PRI", "", "auto_increment", "", ""},
+		{"email", "varchar(255)", "utf8mb4_general_ci", "YES", "UNI", "", "", "", ""},
+		{"team_id", "int", "", "NO", "MUL", "0", "", "", ""},
+	}
+	constraints := [][]string{
+		{"CONSTRAINT_NAME", "COLUMN_NAME", "REFERENCED_TABLE_NAME", "REFERENCED_COLUMN_NAME"},
+		{"PRIMARY", "id", "", ""},
+		{"email", "email", "", ""},
+		{"users_team_fk", "team_id", "teams", "id"},
+	}
+	indexes := [][]string{
+		{"Table", "Non_unique", "Key_name", "Seq_in_index", "Column_name", "Index_type"},
+		{"users", "0", "PRIMARY", "1", "id", "BTREE"},
+		{"users", "0", "email", "1", "email", "BTREE"},
+		{"users", "1", "users_team_fk", "1", "team_id", "BTREE"},
+	}
+
+	table := models.TableSchema{Name: "users"}
+	table.Columns, table.PrimaryKey = parseSchemaColumns(columns)
+	primaryKey, unique, foreignKeys := parseSchemaConstraints(constraints)
+	table.PrimaryKey = primaryKey
+	table.Indexes, _ = parseSchemaIndexes(indexes, table.PrimaryKey, unique)
+	table.ForeignKeys = foreignKeys
+
+	expected := models.TableSchema{
+		Name: "users",
+		Columns: []models.ColumnSchema{
+			{Name: "id", Type: "int"},
+			{Name: "email", Type: "varchar(255)", Nullable: true},
+			{Name: "team_id", Type: "int", Default: "0"},
+		},
+		PrimaryKey: &models.ConstraintSchema{Name: "PRIMARY", Columns: []string{"id"}},
+		Indexes: []models.IndexSchema{
+			{Name: "email", Columns: []string{"email"}, Unique: true},
+			{Name: "users_team_fk", Columns: []string{"team_id"}},
+		},
+		ForeignKeys: []models.ForeignKeySchema{
+			{Name: "users_team_fk", Columns: []string{"team_id"}, ReferencedTable: "teams", ReferencedColumns: []string{"id"}},
+		},
+	}
+
+	if !reflect.DeepEqual(table, expected) {
+		t.Fatalf("Schema mismatch:\nexpected: %+v\ngot: %+v", expected, table)
+	}
+}
+
+func TestLoadSchema_SQLite(t *testing.T) {
+	ctx := context.Background()
+
+	open := func(statements ...string) *SQLite {
+		t.Helper()
+
+		db := &SQLite{}
+		if err := db.Connect(ctx, filepath.Join(t.TempDir(), "test.db")); err != nil {
+			t.Fatalf("Connect failed: %v", err)
+		}
+		t.Cleanup(func() { db.Connection.Close() })
+
+		for _, statement := range statements {
+			if _, err := db.Connection.ExecContext(ctx, statement); err != nil {
+				t.Fatalf("%s: %v", statement, err)
+			}
+		}
+		return db
+	}
+
+	source := open(
+		"CREATE TABLE teams (id INTEGER PRIMARY KEY, name TEXT NOT NULL)",
+		"CREATE TABLE users (id INTEGER PRIMARY KEY, email TEXT NOT NULL, team_id INTEGER REFERENCES teams (id))",
+		"CREATE INDEX users_email ON users (email)",
+	)
+	target := open(
+		"CREATE TABLE users (id INTEGER PRIMARY KEY, email TEXT, nickname TEXT)",
+		"CREATE TABLE legacy (id INTEGER)",
+	)
+
+	sourceSchema, err := LoadSchema(ctx, source, "test")
+	if err != nil {
+		t.Fatalf("LoadSchema of the source failed: %v", err)
+	}
+	targetSchema, err := LoadSchema(ctx, target, "test")
+	if err != nil {
+		t.Fatalf("LoadSchema of the target failed: %v", err)
+	}
+
+	diff := DiffSchemas(sourceSchema, targetSchema)
+
+	expectedChanges := []models.SchemaChange{
+		{Type: models.SchemaRemoved, Kind: models.SchemaTable, Table: "legacy", Target: "1 columns: id"},
+		{Type: models.SchemaAdded, Kind: models.SchemaTable, Table: "teams", Source: "2 columns: id, name"},
+		{Type: models.SchemaChanged, Kind: models.SchemaColumn, Table: "users", Name: "email", Source: "TEXT NOT NULL", Target: "TEXT"},
+		{Type: models.SchemaAdded, Kind: models.SchemaColumn, Table: "users", Name: "team_id", Source: "INTEGER"},
+		{Type: models.SchemaRemoved, Kind: models.SchemaColumn, Table: "users", Name: "nickname", Target: "TEXT"},
+		{Type: models.SchemaAdded, Kind: models.SchemaIndex, Table: "users", Name: "users_email", Source: "index"},
+		{Type: models.SchemaAdded, Kind: models.SchemaForeignKey, Table: "users", Name: "0", Source: "(team_id) REFERENCES teams (id)"},
+	}
+	if !reflect.DeepEqual(diff.Changes, expectedChanges) {
+		t.Fatalf("Changes mismatch:\nexpected: %+v\ngot: %+v", expectedChanges, diff.Changes)
+	}
+
+	expectedDDL := []string{
+		"CREATE TABLE `teams` (\n  `id` INTEGER,\n  `name` TEXT NOT NULL,\n  PRIMARY KEY (`id`)\n);",
+		"-- SQLite can't alter column email of users to TEXT NOT NULL, the table has to be rebuilt",
+		"ALTER TABLE `users` ADD COLUMN `team_id` INTEGER;",
+		"ALTER TABLE `users` DROP COLUMN `nickname`;",
+		"-- create index users_email of users as in the source",
+		"-- SQLite can't add the foreign key (team_id) REFERENCES teams (id) to users, the table has to be rebuilt",
+		"DROP TABLE `legacy`;",
+	}
+	if ddl := diff.DDL(source); !reflect.DeepEqual(ddl, expectedDDL) {
+		t.Fatalf("DDL mismatch:\nexpected: %q\ngot: %q", expectedDDL, ddl)
+	}
+}
+
+func TestSchemaDiff_PostgresDDL(t *testing.T) {
+	source := []models.TableSchema{
+		{
+			Name: "public.users",
+			Columns: []models.ColumnSchema{
+				{Name: "id", Type: "integer"},
+				{Name: "email", Type: "text"},
+				{Name: "active", Type: "boolean", Default: "true"},
+			},
+			PrimaryKey: &models.ConstraintSchema{Name: "users_pkey", Columns: []string{"id"}},
+			Indexes: []models.IndexSchema{
+				{Name: "users_email_key", Columns: []string{"email"}, Unique: true, Constraint: true},
+				{Name: "users_active_idx", Columns: []string{"active", "email"}},
+			},
+		},
+	}
+	target := []models.TableSchema{
+		{
+			Name: "public.users",
+			Columns: []models.ColumnSchema{
+				{Name: "id", Type: "integer"},
+				{Name: "email", Type: "character varying", Nullable: true},
+			},
+			PrimaryKey: &models.ConstraintSchema{Name: "users_pkey", Columns: []string{"id"}},
+			Indexes: []models.IndexSchema{
+				{Name: "users_active_idx", Columns: []string{"email"}},
+			},
+			ForeignKeys: []models.ForeignKeySchema{
+				{Name: "users_team_fkey", Columns: []string{"team_id"}, ReferencedTable: "public.teams", ReferencedColumns: []string{"id"}},
+			},
+		},
+	}
+
+	ddl := DiffSchemas(source, target).DDL(&Postgres{Provider: DriverPostgres})
+
+	expected := []string{
+		`ALTER TABLE "public"."users" DROP CONSTRAINT "users_team_fkey";`,
+		`DROP INDEX "public"."users_active_idx";`,
+		`ALTER TABLE "public"."users" ALTER COLUMN "email" TYPE text;`,
+		`ALTER TABLE "public"."users" ALTER COLUMN "email" SET NOT NULL;`,
+		`ALTER TABLE "public"."users" ADD COLUMN "active" boolean NOT NULL DEFAULT true;`,
+		`ALTER TABLE "public"."users" ADD CONSTRAINT "users_email_key" UNIQUE ("email");`,
+		`CREATE INDEX "users_active_idx" ON "public"."users" ("active", "email");`,
+	}
+	if !reflect.DeepEqual(ddl, expected) {
+		t.Fatalf("DDL mismatch:\nexpected: %q\ngot: %q", expected, ddl)
+	}
+}
+
+func TestSchemaDiff_PostgresTypeChanges(t *testing.T) {
+	// GetTableColumns reads the types with their length, array dimensions
+	// or enum name.
+	load := func(columns [][]string) []models.TableSchema {
+		table := models.TableSchema{Name: "public.users"}
+		table.Columns, _ = parseSchemaColumns(append([][]string{{"column_name", "data_type", "is_nullable", "column_default", "comment"}}, columns...))
+		return []models.TableSchema{table}
+	}
+
+	source := load([][]string{
+		{"name", "character varying(255)", "NO", "", ""},
+		{"tags", "text[]", "YES", "", ""},
+		{"mood", "mood", "YES", "", ""},
+	})
+	target := load([][]string{
+		{"name", "character varying(50)", "NO", "", ""},
+		{"tags", "text[]", "YES", "", ""},
+		{"mood", "mood", "YES", "", ""},
+	})
+
+	ddl := DiffSchemas(source, target).DDL(&Postgres{Provider: DriverPostgres})
+
+	expected := []string{`ALTER TABLE "public"."users" ALTER COLUMN "name" TYPE character varying(255);`}
+	if !reflect.DeepEqual(ddl, expected) {
+		t.Fatalf("DDL mismatch:\nexpected: %q\ngot: %q", expected, ddl)
+	}
+}
@@ -0,0 +1,168 @@
+package drivers
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/xo/dburl"
+
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+// SessionStatements returns the statements setting the session options of
+// conn on provider, its StatementTimeout, Role and SearchPath, followed by
+// its InitSQL. They are run on every connection opened to the database.
+func SessionStatements(provider string, conn models.Connection) ([]string, error) {
+	var statements []string
+
+	if conn.StatementTimeout != "" {
+		timeout, err := time.ParseDuration(conn.StatementTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid statement timeout %q: %w", conn.StatementTimeout, err)
+		}
+
+		switch provider {
+		case DriverPostgres:
+			statements = append(statements, fmt.Sprintf("SET statement_timeout = %d", timeout.Milliseconds()))
+		case DriverMySQL:
+			statements = append(statements, fmt.Sprintf("SET SESSION max_execution_time = %d", timeout.Milliseconds()))
+		default:
+			return nil, fmt.Errorf("the statement timeout is not supported on %s", provider)
+		}
+	}
+
+	if conn.Role != "" {
+		switch provider {
+		case DriverPostgres:
+			statements = append(statements, "SET ROLE "+quoteIdentifier(conn.Role, '"'))
+		case DriverMySQL:
+			statements = append(statements, "SET ROLE "+quoteIdentifier(conn.Role, '`'))
+		default:
+			return nil, fmt.Errorf("the role is not supported on %s", provider)
+		}
+	}
+
+	if len(conn.SearchPath) > 0 {
+		if provider != DriverPostgres {
+			return nil, fmt.Errorf("the search path is not supported on %s", provider)
+		}
+
+		schemas := make([]string, len(conn.SearchPath))
+		for i, schema := range conn.SearchPath {
+			schemas[i] = quoteIdentifier(schema, '"')
+		}
+		statements = append(statements, "SET search_path TO "+strings.Join(schemas, ", "))
+	}
+
+	return append(statements, conn.InitSQL...), nil
+}
+
+// quoteIdentifier quotes identifier with quote, doubling the quotes it
+// holds.
+func quoteIdentifier(identifier string, quote rune) string {
+	q := string(quote)
+	return q + strings.ReplaceAll(identifier, q, q+q) + q
+}
+
+// openDB opens urlstr like dburl.Open. The statements are run on every
+// connection of the pool, as soon as it is opened.
+func openDB(urlstr string, statements []string) (*sql.DB, error) {
+	if len(statements) == 0 {
+		return dburl.Open(urlstr)
+	}
+
+	u, err := dburl.Parse(urlstr)
+	if err != nil {
+		return nil, err
+	}
+
+	driverName := u.Driver
+	if u.GoDriver != "" {
+		driverName = u.GoDriver
+	}
+	return openSessionDB(driverName, u.DSN, statements)
+}
+
+// openSessionDB opens dsn like sql.Open, running statements on every
+// connection of the pool.
+func openSessionDB(driverName, dsn string, statements []string) (*sql.DB, error) {
+	db, err := sql.Open(driverName, dsn)
+	if err != nil || len(statements) == 0 {
+		return db, err
+	}
+
+	base := db.Driver()
+	_ = db.Close()
+
+	var connector driver.Connector = dsnConnector{dsn: dsn, driver: base}
+	if driverContext, ok := base.(driver.DriverContext); ok {
+		if connector, err = driverContext.OpenConnector(dsn); err != nil {
+			return nil, err
+		}
+	}
+
+	return sql.OpenDB(&sessionConnector{Connector: connector, statements: statements}), nil
+}
+
+// dsnConnector opens connections to dsn with a driver which has no
+// connector of its own.
+type dsnConnector struct {
+	dsn    string
+	driver driver.Driver
+}
+
+func (c dsnConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.dsn)
+}
+
+func (c dsnConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+// sessionConnector runs statements on every connection it opens.
+type sessionConnector struct {
+	driver.Connector
+	statements []string
+}
+
+func (c *sessionConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, statement := range c.statements {
+		if err := execSessionStatement(ctx, conn, statement); err != nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("failed to run %q: %w", statement, err)
+		}
+	}
+	return conn, nil
+}
+
+func execSessionStatement(ctx context.Context, conn driver.Conn, statement string) error {
+	if execer, ok := conn.(driver.ExecerContext); ok {
+		_, err := execer.ExecContext(ctx, statement, nil)
+		if !errors.Is(err, driver.ErrSkip) {
+			return err
+		}
+	}
+
+	stmt, err := conn.Prepare(statement)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	if execer, ok := stmt.(driver.StmtExecContext); ok {
+		_, err = execer.ExecContext(ctx, nil)
+		return err
+	}
+	_, err = stmt.Exec(nil) //nolint:staticcheck // The drivers without StmtExecContext only have Exec.
+	return err
+}
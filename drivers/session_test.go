@@ -0,0 +1,91 @@
+package drivers
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+func TestSessionStatements(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider string
+		conn     models.Connection
+		expected []string
+		wantErr  bool
+	}{
+		{name: "none", provider: DriverPostgres, conn: models.Connection{}},
+		{
+			name:     "postgres",
+			provider: DriverPostgres,
+			conn: models.Connection{
+				StatementTimeout: "30s",
+				Role:             `read"only`,
+				SearchPath:       []string{"app", "public"},
+				InitSQL:          []string{"SET TIME ZONE 'UTC'"},
+			},
+			expected: []string{
+				"SET statement_timeout = 30000",
+				`SET ROLE "read""only"`,
+				`SET search_path TO "app", "public"`,
+				"SET TIME ZONE 'UTC'",
+			},
+		},
+		{
+			name:     "mysql",
+			provider: DriverMySQL,
+			conn:     models.Connection{StatementTimeout: "1m", Role: "reader"},
+			expected: []string{"SET SESSION max_execution_time = 60000", "SET ROLE `reader`"},
+		},
+		{
+			name:     "sqlite init sql",
+			provider: DriverSqlite,
+			conn:     models.Connection{InitSQL: []string{"PRAGMA foreign_keys = ON"}},
+			expected: []string{"PRAGMA foreign_keys = ON"},
+		},
+		{name: "invalid timeout", provider: DriverPostgres, conn: models.Connection{StatementTimeout: "30"}, wantErr: true},
+		{name: "unsupported timeout", provider: DriverSqlite, conn: models.Connection{StatementTimeout: "30s"}, wantErr: true},
+		{name: "unsupported role", provider: DriverMSSQL, conn: models.Connection{Role: "reader"}, wantErr: true},
+		{name: "unsupported search path", provider: DriverMySQL, conn: models.Connection{SearchPath: []string{"app"}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statements, err := SessionStatements(tt.provider, tt.conn)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SessionStatements() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(statements, tt.expected) {
+				t.Errorf("SessionStatements() = %q, want %q", statements, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSQLite_InitStatements(t *testing.T) {
+	db := &SQLite{InitStatements: []string{"CREATE TEMP TABLE session (id INTEGER)"}}
+	urlstr := filepath.Join(t.TempDir(), "test.db")
+
+	// The temporary table only exists on the connections which ran the
+	// statements, so it must be on every one of them, also after reconnecting.
+	for i := 0; i < 2; i++ {
+		if err := db.Connect(context.Background(), urlstr); err != nil {
+			t.Fatal(err)
+		}
+		db.Connection.SetMaxIdleConns(0)
+
+		for j := 0; j < 2; j++ {
+			if _, _, err := db.ExecuteQuery(context.Background(), "SELECT * FROM temp.session"); err != nil {
+				t.Fatalf("the init statements did not run: %v", err)
+			}
+		}
+	}
+
+	db.InitStatements = []string{"SELECT * FROM missing"}
+	if err := db.Connect(context.Background(), urlstr); err == nil {
+		t.Error("Connect() ran a failing init statement without error")
+	}
+}
@@ -1,10 +1,13 @@
 package drivers
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
+	"sync"
 
 	"github.com/jorgerojas26/lazysql/models"
 )
@@ -12,32 +15,59 @@ import (
 type SQLite struct {
 	Connection *sql.DB
 	Provider   string
+	// InitStatements are run on every connection opened, see
+	// SessionStatements.
+	InitStatements []string
+
+	// mu guards Connection, replaced when connecting again while other
+	// goroutines use it.
+	mu sync.RWMutex
+	tx transaction
 }
 
-func (db *SQLite) TestConnection(urlstr string) (err error) {
-	return db.Connect(urlstr)
+func (db *SQLite) TestConnection(ctx context.Context, urlstr string) (err error) {
+	return db.Connect(ctx, urlstr)
 }
 
-func (db *SQLite) Connect(urlstr string) (err error) {
+func (db *SQLite) Connect(ctx context.Context, urlstr string) (err error) {
 	db.SetProvider(DriverSqlite)
 
-	db.Connection, err = sql.Open("sqlite", urlstr)
+	connection, err := openSessionDB("sqlite", urlstr, db.InitStatements)
 	if err != nil {
 		return err
 	}
 
-	err = db.Connection.Ping()
+	err = pingPool(ctx, connection)
 	if err != nil {
 		return err
 	}
 
+	db.mu.Lock()
+	previous := db.Connection
+	db.Connection = connection
+	db.mu.Unlock()
+
+	db.tx.replacePool(previous)
+
 	return nil
 }
 
-func (db *SQLite) GetDatabases() ([]string, error) {
+// pool returns the connection pool, replaced when connecting again.
+func (db *SQLite) pool() *sql.DB {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	return db.Connection
+}
+
+func (db *SQLite) Ping(ctx context.Context) error {
+	return db.pool().PingContext(ctx)
+}
+
+func (db *SQLite) GetDatabases(ctx context.Context) ([]string, error) {
 	var databases []string
 
-	rows, err := db.Connection.Query("SELECT file FROM pragma_database_list WHERE name='main'")
+	rows, err := db.pool().QueryContext(ctx, "SELECT file FROM pragma_database_list WHERE name='main'")
 	if err != nil {
 		return nil, err
 	}
@@ -62,12 +92,12 @@ func (db *SQLite) GetDatabases() ([]string, error) {
 	return databases, nil
 }
 
-func (db *SQLite) GetTables(database string) (map[string][]string, error) {
+func (db *SQLite) GetTables(ctx context.Context, database string) (map[string][]string, error) {
 	if database == "" {
 		return nil, errors.New("database name is required")
 	}
 
-	rows, err := db.Connection.Query("SELECT name FROM sqlite_master WHERE type='table'")
+	rows, err := db.pool().QueryContext(ctx, "SELECT name FROM sqlite_master WHERE type='table'")
 	if err != nil {
 		return nil, err
 	}
@@ -91,12 +121,12 @@ func (db *SQLite) GetTables(database string) (map[string][]string, error) {
 	return tables, nil
 }
 
-func (db *SQLite) GetTableColumns(_, table string) (results [][]string, err error) {
+func (db *SQLite) GetTableColumns(ctx context.Context, _, table string) (results [][]string, err error) {
 	if table == "" {
 		return nil, errors.New("table name is required")
 	}
 
-	rows, err := db.Connection.Query(fmt.Sprintf("PRAGMA table_info(%s)", db.formatTableName(table)))
+	rows, err := db.pool().QueryContext(ctx, fmt.Sprintf("PRAGMA table_info(%s)", db.formatTableName(table)))
 	if err != nil {
 		return nil, err
 	}
@@ -139,7 +169,7 @@ func (db *SQLite) GetTableColumns(_, table string) (results [][]string, err erro
 	return results, nil
 }
 
-func (db *SQLite) GetConstraints(_, table string) (results [][]string, err error) {
+func (db *SQLite) GetConstraints(ctx context.Context, _, table string) (results [][]string, err error) {
 	if table == "" {
 		return nil, errors.New("table name is required")
 	}
@@ -147,7 +177,7 @@ func (db *SQLite) GetConstraints(_, table string) (results [][]string, err error
 	query := "SELECT sql FROM sqlite_master "
 	query += "WHERE type='table' AND name = ?"
 
-	rows, err := db.Connection.Query(query, table)
+	rows, err := db.pool().QueryContext(ctx, query, table)
 	if err != nil {
 		return nil, err
 	}
@@ -189,14 +219,14 @@ func (db *SQLite) GetConstraints(_, table string) (results [][]string, err error
 	return results, nil
 }
 
-func (db *SQLite) GetForeignKeys(_, table string) (results [][]string, err error) {
+func (db *SQLite) GetForeignKeys(ctx context.Context, _, table string) (results [][]string, err error) {
 	if table == "" {
 		return nil, errors.New("table name is required")
 	}
 
 	formattedTableName := db.formatTableName(table)
 
-	rows, err := db.Connection.Query("PRAGMA foreign_key_list(" + formattedTableName + ")")
+	rows, err := db.pool().QueryContext(ctx, "PRAGMA foreign_key_list("+formattedTableName+")")
 	if err != nil {
 		return nil, err
 	}
@@ -238,13 +268,53 @@ func (db *SQLite) GetForeignKeys(_, table string) (results [][]string, err error
 	return results, nil
 }
 
-func (db *SQLite) GetIndexes(_, table string) (results [][]string, err error) {
+// GetReferencingForeignKeys returns the foreign keys referencing table. The
+// keys naming no referenced column reference its primary key.
+func (db *SQLite) GetReferencingForeignKeys(ctx context.Context, database, table string) ([]models.ReferencingForeignKey, error) {
+	if table == "" {
+		return nil, errors.New("table name is required")
+	}
+
+	rows, err := db.pool().QueryContext(ctx, `
+		SELECT m.name, CAST(f.id AS TEXT), '', f."from", COALESCE(f."to", '')
+		FROM sqlite_master m
+		JOIN pragma_foreign_key_list(m.name) f
+		WHERE m.type = 'table' AND f."table" = ? COLLATE NOCASE
+		ORDER BY m.name, f.id, f.seq
+	`, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys, err := scanReferencingForeignKeys(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	var primaryKey []string
+	for i := range keys {
+		if !slices.Contains(keys[i].ReferencedColumns, "") {
+			continue
+		}
+		if primaryKey == nil {
+			if primaryKey, err = db.GetPrimaryKeyColumnNames(ctx, database, table); err != nil {
+				return nil, err
+			}
+		}
+		keys[i].ReferencedColumns = primaryKey
+	}
+
+	return keys, nil
+}
+
+func (db *SQLite) GetIndexes(ctx context.Context, _, table string) (results [][]string, err error) {
 	if table == "" {
 		return nil, errors.New("table name is required")
 	}
 
 	formattedTableName := db.formatTableName(table)
-	rows, err := db.Connection.Query("PRAGMA index_list(" + formattedTableName + ")")
+	rows, err := db.pool().QueryContext(ctx, "PRAGMA index_list("+formattedTableName+")")
 	if err != nil {
 		return nil, err
 	}
@@ -286,7 +356,7 @@ func (db *SQLite) GetIndexes(_, table string) (results [][]string, err error) {
 	return results, nil
 }
 
-func (db *SQLite) GetRecords(_, table, where, sort string, offset, limit int) (paginatedResults [][]string, totalRecords int, queryString string, err error) {
+func (db *SQLite) GetRecords(ctx context.Context, _, table, where, sort string, offset, limit int) (paginatedResults []models.Record, totalRecords int, queryString string, err error) {
 	if table == "" {
 		return nil, 0, "", errors.New("table name is required")
 	}
@@ -308,62 +378,24 @@ func (db *SQLite) GetRecords(_, table, where, sort string, offset, limit int) (p
 
 	queryString += " LIMIT ?, ?"
 
-	paginatedRows, err := db.Connection.Query(queryString, offset, limit)
+	paginatedRows, err := db.tx.on(db.pool()).QueryContext(ctx, queryString, offset, limit)
 	if err != nil {
 		return nil, 0, queryString, err
 	}
 	defer paginatedRows.Close()
 
-	columns, err := paginatedRows.Columns()
+	// reading the records closes the rows to release the connection
+	paginatedResults, err = readRecords(paginatedRows)
 	if err != nil {
 		return nil, 0, queryString, err
 	}
 
-	paginatedResults = append(paginatedResults, columns)
-
-	for paginatedRows.Next() {
-		nullStringSlice := make([]sql.NullString, len(columns))
-
-		rowValues := make([]any, len(columns))
-
-		for i := range nullStringSlice {
-			rowValues[i] = &nullStringSlice[i]
-		}
-
-		err = paginatedRows.Scan(rowValues...)
-		if err != nil {
-			return nil, 0, queryString, err
-		}
-
-		var row []string
-		for _, col := range nullStringSlice {
-			if col.Valid {
-				if col.String == "" {
-					row = append(row, "EMPTY&")
-				} else {
-					row = append(row, col.String)
-				}
-			} else {
-				row = append(row, "NULL&")
-			}
-		}
-
-		paginatedResults = append(paginatedResults, row)
-	}
-	if err := paginatedRows.Err(); err != nil {
-		return nil, 0, queryString, err
-	}
-	// close to release the connection
-	if err := paginatedRows.Close(); err != nil {
-		return nil, 0, queryString, err
-	}
-
 	countQuery := "SELECT COUNT(*) FROM "
 	countQuery += db.formatTableName(table)
 	if where != "" { // Add WHERE clause to count query as well if it exists
 		countQuery += fmt.Sprintf(" %s", where)
 	}
-	countRow := db.Connection.QueryRow(countQuery)
+	countRow := db.tx.on(db.pool()).QueryRowContext(ctx, countQuery)
 	if err := countRow.Scan(&totalRecords); err != nil {
 		return paginatedResults, 0, queryString, err
 	}
@@ -374,52 +406,48 @@ func (db *SQLite) GetRecords(_, table, where, sort string, offset, limit int) (p
 	return paginatedResults, totalRecords, queryString, nil
 }
 
-func (db *SQLite) ExecuteQuery(query string) ([][]string, int, error) {
-	rows, err := db.Connection.Query(query)
+func (db *SQLite) ExecuteQuery(ctx context.Context, query string, args ...any) ([]models.Record, int, error) {
+	rows, err := db.QueryRows(ctx, query, args...)
 	if err != nil {
 		return nil, 0, err
 	}
-	defer rows.Close()
 
-	columns, err := rows.Columns()
+	return readAllRows(rows)
+}
+
+func (db *SQLite) QueryRows(ctx context.Context, query string, args ...any) (Rows, error) {
+	rows, err := db.tx.on(db.pool()).QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 
-	records := make([][]string, 0)
-	for rows.Next() {
-		rowValues := make([]any, len(columns))
-		for i := range columns {
-			rowValues[i] = new(sql.RawBytes)
-		}
+	return newSQLRows(rows)
+}
 
-		err = rows.Scan(rowValues...)
-		if err != nil {
-			return nil, 0, err
-		}
+func (db *SQLite) ExplainQuery(ctx context.Context, query string, args ...any) (*models.PlanNode, error) {
+	rows, err := db.tx.on(db.pool()).QueryContext(ctx, "EXPLAIN QUERY PLAN "+query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
-		var row []string
-		for _, col := range rowValues {
-			if col == nil {
-				row = append(row, "NULL")
-			} else {
-				row = append(row, string(*col.(*sql.RawBytes)))
-			}
+	var planRows []sqlitePlanRow
+	for rows.Next() {
+		var row sqlitePlanRow
+		var notUsed int
+		if err := rows.Scan(&row.ID, &row.Parent, &notUsed, &row.Detail); err != nil {
+			return nil, err
 		}
-
-		records = append(records, row)
+		planRows = append(planRows, row)
 	}
 	if err := rows.Err(); err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 
-	// Prepend the columns to the records.
-	results := append([][]string{columns}, records...)
-
-	return results, len(records), nil
+	return parseSQLitePlan(planRows)
 }
 
-func (db *SQLite) UpdateRecord(_, table, column, value, primaryKeyColumnName, primaryKeyValue string) error {
+func (db *SQLite) UpdateRecord(ctx context.Context, _, table, column, value, primaryKeyColumnName, primaryKeyValue string) error {
 	if table == "" {
 		return errors.New("table name is required")
 	}
@@ -444,12 +472,16 @@ func (db *SQLite) UpdateRecord(_, table, column, value, primaryKeyColumnName, pr
 	query += db.formatTableName(table)
 	query += fmt.Sprintf(" SET %s = ? WHERE %s = ?", column, primaryKeyColumnName)
 
-	_, err := db.Connection.Exec(query, value, primaryKeyValue)
+	q, err := db.tx.writer(db.pool())
+	if err != nil {
+		return err
+	}
+	_, err = q.ExecContext(ctx, query, value, primaryKeyValue)
 
 	return err
 }
 
-func (db *SQLite) DeleteRecord(_, table, primaryKeyColumnName, primaryKeyValue string) error {
+func (db *SQLite) DeleteRecord(ctx context.Context, _, table, primaryKeyColumnName, primaryKeyValue string) error {
 	if table == "" {
 		return errors.New("table name is required")
 	}
@@ -466,13 +498,21 @@ func (db *SQLite) DeleteRecord(_, table, primaryKeyColumnName, primaryKeyValue s
 	query += db.formatTableName(table)
 	query += fmt.Sprintf(" WHERE %s = ?", primaryKeyColumnName)
 
-	_, err := db.Connection.Exec(query, primaryKeyValue)
+	q, err := db.tx.writer(db.pool())
+	if err != nil {
+		return err
+	}
+	_, err = q.ExecContext(ctx, query, primaryKeyValue)
 
 	return err
 }
 
-func (db *SQLite) ExecuteDMLStatement(query string) (result string, err error) {
-	res, err := db.Connection.Exec(query)
+func (db *SQLite) ExecuteDMLStatement(ctx context.Context, query string, args ...any) (result string, err error) {
+	q, err := db.tx.writer(db.pool())
+	if err != nil {
+		return "", err
+	}
+	res, err := q.ExecContext(ctx, query, args...)
 	if err != nil {
 		return "", err
 	}
@@ -485,7 +525,7 @@ func (db *SQLite) ExecuteDMLStatement(query string) (result string, err error) {
 	return fmt.Sprintf("%d rows affected", rowsAffected), nil
 }
 
-func (db *SQLite) ExecutePendingChanges(changes []models.DBDMLChange) error {
+func (db *SQLite) ExecutePendingChanges(ctx context.Context, changes []models.DBDMLChange) error {
 	var queries []models.Query
 
 	for _, change := range changes {
@@ -503,11 +543,11 @@ func (db *SQLite) ExecutePendingChanges(changes []models.DBDMLChange) error {
 		}
 	}
 
-	return queriesInTransaction(db.Connection, queries)
+	return db.tx.execQueries(ctx, db.pool(), queries)
 }
 
-func (db *SQLite) GetPrimaryKeyColumnNames(database, table string) (primaryKeyColumnName []string, err error) {
-	columns, err := db.GetTableColumns(database, table)
+func (db *SQLite) GetPrimaryKeyColumnNames(ctx context.Context, database, table string) (primaryKeyColumnName []string, err error) {
+	columns, err := db.GetTableColumns(ctx, database, table)
 	if err != nil {
 		return nil, err
 	}
@@ -537,6 +577,22 @@ func (db *SQLite) GetProvider() string {
 	return db.Provider
 }
 
+func (db *SQLite) BeginTransaction(ctx context.Context) error {
+	return db.tx.begin(ctx, db.pool())
+}
+
+func (db *SQLite) CommitTransaction() error {
+	return db.tx.commit()
+}
+
+func (db *SQLite) RollbackTransaction() error {
+	return db.tx.rollback()
+}
+
+func (db *SQLite) InTransaction() bool {
+	return db.tx.active()
+}
+
 func (db *SQLite) formatTableName(table string) string {
 	return fmt.Sprintf("`%s`", table)
 }
@@ -640,34 +696,180 @@ func (db *SQLite) DMLChangeToQueryString(change models.DBDMLChange) (string, err
 	return queryStr, nil
 }
 
-func (db *SQLite) GetFunctions(_ string) (map[string][]string, error) {
-	return nil, errors.New("not implemented")
+// GetFunctions returns no functions, SQLite has no stored functions.
+func (db *SQLite) GetFunctions(_ context.Context, _ string) (map[string][]string, error) {
+	return map[string][]string{}, nil
+}
+
+// GetProcedures returns no procedures, SQLite has no stored procedures.
+func (db *SQLite) GetProcedures(_ context.Context, _ string) (map[string][]string, error) {
+	return map[string][]string{}, nil
+}
+
+func (db *SQLite) GetViews(ctx context.Context, database string) (map[string][]string, error) {
+	if database == "" {
+		return nil, errors.New("database name is required")
+	}
+
+	names, err := db.getSchemaObjects(ctx, "view")
+	if err != nil {
+		return nil, err
+	}
+
+	views := make(map[string][]string)
+	if len(names) > 0 {
+		views[database] = names
+	}
+	return views, nil
 }
 
-func (db *SQLite) GetProcedures(_ string) (map[string][]string, error) {
-	return nil, errors.New("not implemented")
+// getSchemaObjects returns the names of the objects of objectType, e.g.
+// view or trigger, from sqlite_master.
+func (db *SQLite) getSchemaObjects(ctx context.Context, objectType string) ([]string, error) {
+	rows, err := db.pool().QueryContext(ctx, "SELECT name FROM sqlite_master WHERE type = ? ORDER BY name", objectType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return names, nil
 }
 
-func (db *SQLite) GetViews(_ string) (map[string][]string, error) {
-	return nil, errors.New("not implemented")
+// getSchemaObjectDefinition returns the statement that created the object
+// of objectType named name, as kept in sqlite_master.
+func (db *SQLite) getSchemaObjectDefinition(ctx context.Context, objectType, name string) (string, error) {
+	if name == "" {
+		return "", errors.New("name is required")
+	}
+
+	var definition string
+	row := db.pool().QueryRowContext(ctx, "SELECT sql FROM sqlite_master WHERE type = ? AND name = ?", objectType, name)
+	if err := row.Scan(&definition); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", fmt.Errorf("%s %s not found", objectType, name)
+		}
+		return "", err
+	}
+
+	return definition + ";", nil
 }
 
 func (db *SQLite) SupportsProgramming() bool {
-	return false
+	return true
 }
 
 func (db *SQLite) UseSchemas() bool {
 	return false
 }
 
-func (db *SQLite) GetFunctionDefinition(_ string, _ string) (string, error) {
-	return "", errors.New("not implemented")
+func (db *SQLite) GetFunctionDefinition(_ context.Context, _ string, _ string) (string, error) {
+	return "", errors.New("sqlite has no stored functions")
+}
+
+func (db *SQLite) GetProcedureDefinition(_ context.Context, _ string, _ string) (string, error) {
+	return "", errors.New("sqlite has no stored procedures")
 }
 
-func (db *SQLite) GetProcedureDefinition(_ string, _ string) (string, error) {
-	return "", errors.New("not implemented")
+func (db *SQLite) GetViewDefinition(ctx context.Context, _ string, name string) (string, error) {
+	return db.getSchemaObjectDefinition(ctx, "view", name)
 }
 
-func (db *SQLite) GetViewDefinition(_ string, _ string) (string, error) {
-	return "", errors.New("not implemented")
+func (db *SQLite) GetTriggers(ctx context.Context, database string) (map[string][]string, error) {
+	if database == "" {
+		return nil, errors.New("database name is required")
+	}
+
+	names, err := db.getSchemaObjects(ctx, "trigger")
+	if err != nil {
+		return nil, err
+	}
+
+	triggers := make(map[string][]string)
+	if len(names) > 0 {
+		triggers[database] = names
+	}
+	return triggers, nil
+}
+
+// GetSequences returns no sequences, SQLite has none.
+func (db *SQLite) GetSequences(_ context.Context, _ string) (map[string][]string, error) {
+	return map[string][]string{}, nil
+}
+
+// GetMaterializedViews returns no materialized views, SQLite has none.
+func (db *SQLite) GetMaterializedViews(_ context.Context, _ string) (map[string][]string, error) {
+	return map[string][]string{}, nil
+}
+
+// GetTypes returns no types, SQLite has no user-defined types.
+func (db *SQLite) GetTypes(_ context.Context, _ string) (map[string][]string, error) {
+	return map[string][]string{}, nil
+}
+
+func (db *SQLite) GetTriggerDefinition(ctx context.Context, _ string, name string) (string, error) {
+	return db.getSchemaObjectDefinition(ctx, "trigger", name)
+}
+
+func (db *SQLite) GetSequenceDefinition(_ context.Context, _ string, _ string) (string, error) {
+	return "", errors.New("sqlite has no sequences")
+}
+
+func (db *SQLite) GetMaterializedViewDefinition(_ context.Context, _ string, _ string) (string, error) {
+	return "", errors.New("sqlite has no materialized views")
+}
+
+func (db *SQLite) GetTypeDefinition(_ context.Context, _ string, _ string) (string, error) {
+	return "", errors.New("sqlite has no user-defined types")
+}
+
+func (db *SQLite) RefreshMaterializedView(_ context.Context, _ string, _ string) error {
+	return errors.New("sqlite has no materialized views")
+}
+
+func (db *SQLite) GetTableDefinition(ctx context.Context, _ string, table string) (string, error) {
+	if table == "" {
+		return "", errors.New("table name is required")
+	}
+
+	// The table first, then its indexes. The indexes SQLite creates for the
+	// constraints of the table have no sql.
+	rows, err := db.pool().QueryContext(ctx, `
+		SELECT sql
+		FROM sqlite_master
+		WHERE tbl_name = ? AND type IN ('table', 'index') AND sql IS NOT NULL
+		ORDER BY type = 'index', name
+	`, table)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	var statements []string
+	for rows.Next() {
+		var statement string
+		if err := rows.Scan(&statement); err != nil {
+			return "", err
+		}
+		statements = append(statements, statement+";")
+	}
+	if err := rows.Err(); err != nil {
+		return "", err
+	}
+	if len(statements) == 0 {
+		return "", fmt.Errorf("table %s not found", table)
+	}
+
+	return strings.Join(statements, "\n\n"), nil
 }
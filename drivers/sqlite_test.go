@@ -1,8 +1,10 @@
 package drivers
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"path/filepath"
 	"reflect"
 	"testing"
 
@@ -201,7 +203,7 @@ func TestSQLite_ErrorScenarios(t *testing.T) {
 					WillReturnError(errors.New("query error"))
 			},
 			testFunc: func(db *SQLite) error {
-				_, err := db.GetTables(testDBNameSQLite)
+				_, err := db.GetTables(context.Background(), testDBNameSQLite)
 				return err
 			},
 		},
@@ -241,7 +243,7 @@ func TestSQLite_GetTableColumns_Error(t *testing.T) {
 	mock.ExpectQuery(fmt.Sprintf("PRAGMA table_info\\(%s\\)", sqlite.formatTableName(testDBTableNameSQLite))).
 		WillReturnError(errors.New("query error"))
 
-	_, err = sqlite.GetTableColumns(testDBNameSQLite, testDBTableNameSQLite)
+	_, err = sqlite.GetTableColumns(context.Background(), testDBNameSQLite, testDBTableNameSQLite)
 	if err == nil {
 		t.Fatal("Expected error but got nil")
 	}
@@ -273,7 +275,7 @@ func TestSQLite_GetRecords(t *testing.T) {
 	mock.ExpectQuery(fmt.Sprintf("SELECT COUNT\\(\\*\\) FROM %s", sqlite.formatTableName(testDBTableNameSQLite))).
 		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
 
-	records, total, _, err := sqlite.GetRecords(testDBNameSQLite, testDBTableNameSQLite, "", "", 0, DefaultRowLimit)
+	records, total, _, err := sqlite.GetRecords(context.Background(), testDBNameSQLite, testDBTableNameSQLite, "", "", 0, DefaultRowLimit)
 	if err != nil {
 		t.Fatalf("GetRecords failed: %v", err)
 	}
@@ -284,7 +286,7 @@ func TestSQLite_GetRecords(t *testing.T) {
 		{"2", "Bob"},
 	}
 
-	if !reflect.DeepEqual(records, expected) {
+	if !reflect.DeepEqual(models.RecordStrings(records), expected) {
 		t.Fatalf("Expected %v, got %v", expected, records)
 	}
 
@@ -312,7 +314,7 @@ func TestSQLite_GetForeignKeys(t *testing.T) {
 	mock.ExpectQuery(fmt.Sprintf("PRAGMA foreign_key_list\\(%s\\)", sqlite.formatTableName(testDBTableNameSQLite))).
 		WillReturnRows(rows)
 
-	constraints, err := sqlite.GetForeignKeys(testDBNameSQLite, testDBTableNameSQLite)
+	constraints, err := sqlite.GetForeignKeys(context.Background(), testDBNameSQLite, testDBTableNameSQLite)
 	if err != nil {
 		t.Fatalf("GetForeignKeys failed: %v", err)
 	}
@@ -350,7 +352,7 @@ func TestSQLite_GetIndexes(t *testing.T) {
 	// 	WillReturnRows(sqlmock.NewRows([]string{"seqno", "cid", "name"}).
 	// 		AddRow(0, 1, "name"))
 
-	indexes, err := sqlite.GetIndexes(testDBNameSQLite, testDBTableNameSQLite)
+	indexes, err := sqlite.GetIndexes(context.Background(), testDBNameSQLite, testDBTableNameSQLite)
 	if err != nil {
 		t.Fatalf("GetIndexes failed: %v", err)
 	}
@@ -433,7 +435,7 @@ func TestSQLite_ExecutePendingChanges(t *testing.T) {
 		WillReturnResult(sqlmock.NewResult(0, 1))
 	mock.ExpectCommit()
 
-	err = sqlite.ExecutePendingChanges(changes)
+	err = sqlite.ExecutePendingChanges(context.Background(), changes)
 	if err != nil {
 		t.Fatalf("ExecutePendingChanges failed: %v", err)
 	}
@@ -459,7 +461,7 @@ func TestSQLite_GetPrimaryKeyColumnNames(t *testing.T) {
 	mock.ExpectQuery(fmt.Sprintf("PRAGMA table_info\\(%s\\)", sqlite.formatTableName(testDBTableNameSQLite))).
 		WillReturnRows(rows)
 
-	keys, err := sqlite.GetPrimaryKeyColumnNames(testDBNameSQLite, testDBTableNameSQLite)
+	keys, err := sqlite.GetPrimaryKeyColumnNames(context.Background(), testDBNameSQLite, testDBTableNameSQLite)
 	if err != nil {
 		t.Fatalf("GetPrimaryKeyColumnNames failed: %v", err)
 	}
@@ -502,3 +504,150 @@ func TestSQLite_formatTableName(t *testing.T) {
 		t.Fatalf("formatTableName failed: got %q, expected %q", tableName, expectedTableName)
 	}
 }
+
+func TestSQLite_GetTableDefinition(t *testing.T) {
+	ctx := context.Background()
+
+	db := &SQLite{}
+	if err := db.Connect(ctx, filepath.Join(t.TempDir(), "test.db")); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	t.Cleanup(func() { db.Connection.Close() })
+
+	for _, statement := range []string{
+		"CREATE TABLE users (id INTEGER PRIMARY KEY, email TEXT UNIQUE)",
+		"CREATE INDEX users_email ON users (email)",
+	} {
+		if _, err := db.Connection.ExecContext(ctx, statement); err != nil {
+			t.Fatalf("%s failed: %v", statement, err)
+		}
+	}
+
+	definition, err := db.GetTableDefinition(ctx, "main", "users")
+	if err != nil {
+		t.Fatalf("GetTableDefinition failed: %v", err)
+	}
+
+	expected := "CREATE TABLE users (id INTEGER PRIMARY KEY, email TEXT UNIQUE);\n\nCREATE INDEX users_email ON users (email);"
+	if definition != expected {
+		t.Fatalf("GetTableDefinition failed: got %q, expected %q", definition, expected)
+	}
+
+	if _, err := db.GetTableDefinition(ctx, "main", "missing"); err == nil {
+		t.Fatal("Expected an error for a missing table")
+	}
+}
+
+func TestSQLite_GetViews(t *testing.T) {
+	ctx := context.Background()
+
+	db := &SQLite{}
+	if err := db.Connect(ctx, filepath.Join(t.TempDir(), "test.db")); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	t.Cleanup(func() { db.Connection.Close() })
+
+	for _, statement := range []string{
+		"CREATE TABLE users (id INTEGER PRIMARY KEY, active INTEGER)",
+		"CREATE VIEW active_users AS SELECT id FROM users WHERE active = 1",
+	} {
+		if _, err := db.Connection.ExecContext(ctx, statement); err != nil {
+			t.Fatalf("%s failed: %v", statement, err)
+		}
+	}
+
+	views, err := db.GetViews(ctx, "test_db")
+	if err != nil {
+		t.Fatalf("GetViews failed: %v", err)
+	}
+	expected := map[string][]string{"test_db": {"active_users"}}
+	if !reflect.DeepEqual(views, expected) {
+		t.Fatalf("GetViews failed: got %v, expected %v", views, expected)
+	}
+
+	definition, err := db.GetViewDefinition(ctx, "test_db", "active_users")
+	if err != nil {
+		t.Fatalf("GetViewDefinition failed: %v", err)
+	}
+	if expected := "CREATE VIEW active_users AS SELECT id FROM users WHERE active = 1;"; definition != expected {
+		t.Fatalf("GetViewDefinition failed: got %q, expected %q", definition, expected)
+	}
+
+	if _, err := db.GetViewDefinition(ctx, "test_db", "missing"); err == nil {
+		t.Fatal("Expected an error for a missing view")
+	}
+}
+
+func TestSQLite_GetTriggers(t *testing.T) {
+	ctx := context.Background()
+
+	db := &SQLite{}
+	if err := db.Connect(ctx, filepath.Join(t.TempDir(), "test.db")); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	t.Cleanup(func() { db.Connection.Close() })
+
+	for _, statement := range []string{
+		"CREATE TABLE users (id INTEGER PRIMARY KEY, updated_at TEXT)",
+		"CREATE TRIGGER users_touch AFTER UPDATE ON users BEGIN UPDATE users SET updated_at = datetime('now') WHERE id = NEW.id; END",
+	} {
+		if _, err := db.Connection.ExecContext(ctx, statement); err != nil {
+			t.Fatalf("%s failed: %v", statement, err)
+		}
+	}
+
+	triggers, err := db.GetTriggers(ctx, "test_db")
+	if err != nil {
+		t.Fatalf("GetTriggers failed: %v", err)
+	}
+	expected := map[string][]string{"test_db": {"users_touch"}}
+	if !reflect.DeepEqual(triggers, expected) {
+		t.Fatalf("GetTriggers failed: got %v, expected %v", triggers, expected)
+	}
+
+	definition, err := db.GetTriggerDefinition(ctx, "test_db", "users_touch")
+	if err != nil {
+		t.Fatalf("GetTriggerDefinition failed: %v", err)
+	}
+	if expected := "CREATE TRIGGER users_touch AFTER UPDATE ON users BEGIN UPDATE users SET updated_at = datetime('now') WHERE id = NEW.id; END;"; definition != expected {
+		t.Fatalf("GetTriggerDefinition failed: got %q, expected %q", definition, expected)
+	}
+
+	if _, err := db.GetTriggerDefinition(ctx, "test_db", "missing"); err == nil {
+		t.Fatal("Expected an error for a missing trigger")
+	}
+}
+
+func TestSQLite_GetReferencingForeignKeys(t *testing.T) {
+	ctx := context.Background()
+
+	db := &SQLite{}
+	if err := db.Connect(ctx, filepath.Join(t.TempDir(), "test.db")); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	t.Cleanup(func() { db.Connection.Close() })
+
+	for _, statement := range []string{
+		"CREATE TABLE users (id INTEGER PRIMARY KEY, email TEXT UNIQUE)",
+		"CREATE TABLE orders (id INTEGER PRIMARY KEY, user_id INTEGER REFERENCES users)",
+		"CREATE TABLE invites (id INTEGER PRIMARY KEY, email TEXT REFERENCES users (email))",
+		"CREATE TABLE products (id INTEGER PRIMARY KEY)",
+	} {
+		if _, err := db.Connection.ExecContext(ctx, statement); err != nil {
+			t.Fatalf("%s failed: %v", statement, err)
+		}
+	}
+
+	keys, err := db.GetReferencingForeignKeys(ctx, "test_db", "users")
+	if err != nil {
+		t.Fatalf("GetReferencingForeignKeys failed: %v", err)
+	}
+	expected := []models.ReferencingForeignKey{
+		{Table: "invites", ForeignKeySchema: models.ForeignKeySchema{Columns: []string{"email"}, ReferencedColumns: []string{"email"}}},
+		// A key naming no column references the primary key.
+		{Table: "orders", ForeignKeySchema: models.ForeignKeySchema{Columns: []string{"user_id"}, ReferencedColumns: []string{"id"}}},
+	}
+	if !reflect.DeepEqual(keys, expected) {
+		t.Fatalf("GetReferencingForeignKeys failed: got %+v, expected %+v", keys, expected)
+	}
+}
@@ -0,0 +1,178 @@
+package drivers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+
+	"github.com/jorgerojas26/lazysql/helpers/logger"
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+var (
+	ErrTransactionOpen = errors.New("a transaction is already open")
+	ErrNoTransaction   = errors.New("no transaction is open")
+	// ErrTransactionOtherDatabase is returned by a write to a database other
+	// than the one of the open transaction, which would commit on its own.
+	ErrTransactionOtherDatabase = errors.New("the open transaction is on another database, commit or roll it back first")
+)
+
+// queryer is implemented by both *sql.DB and *sql.Tx, so a statement can run
+// either on the connection pool or inside the open transaction.
+type queryer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// transaction is the transaction a driver keeps open on one pinned connection
+// while the connection session is in transaction mode.
+type transaction struct {
+	mu   sync.Mutex
+	pool *sql.DB
+	conn *sql.Conn
+	tx   *sql.Tx
+}
+
+// begin pins a connection of pool and opens a transaction on it. The
+// transaction is rolled back if ctx is cancelled before it is committed.
+func (t *transaction) begin(ctx context.Context, pool *sql.DB) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.tx != nil {
+		return ErrTransactionOpen
+	}
+
+	conn, err := pool.Conn(ctx)
+	if err != nil {
+		return err
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Join(err, conn.Close())
+	}
+
+	t.pool, t.conn, t.tx = pool, conn, tx
+	return nil
+}
+
+func (t *transaction) commit() error {
+	return t.end((*sql.Tx).Commit)
+}
+
+func (t *transaction) rollback() error {
+	return t.end((*sql.Tx).Rollback)
+}
+
+// end finishes the open transaction with finish and returns the pinned
+// connection to the pool.
+func (t *transaction) end(finish func(*sql.Tx) error) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.tx == nil {
+		return ErrNoTransaction
+	}
+
+	err := finish(t.tx)
+	err = errors.Join(err, t.conn.Close())
+	t.pool, t.conn, t.tx = nil, nil, nil
+
+	return err
+}
+
+// replacePool closes pool, the connection pool replaced when connecting
+// again, and forgets the transaction open on it, lost with the connection.
+// Both are closed in the background as the connection may not answer.
+func (t *transaction) replacePool(pool *sql.DB) {
+	if pool == nil {
+		return
+	}
+
+	t.mu.Lock()
+	var conn *sql.Conn
+	var tx *sql.Tx
+	if t.pool == pool {
+		conn, tx = t.conn, t.tx
+		t.pool, t.conn, t.tx = nil, nil, nil
+	}
+	t.mu.Unlock()
+
+	go func() {
+		if tx != nil {
+			_ = tx.Rollback()
+			_ = conn.Close()
+		}
+		if err := pool.Close(); err != nil {
+			logger.Error("Failed to close the replaced connection", map[string]any{"error": err})
+		}
+	}()
+}
+
+func (t *transaction) active() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.tx != nil
+}
+
+// on returns the open transaction if it was started on pool, and pool itself
+// otherwise, so reads of another database still work. Writes use writer.
+func (t *transaction) on(pool *sql.DB) queryer {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.tx != nil && t.pool == pool {
+		return t.tx
+	}
+	return pool
+}
+
+// writer returns what a write to pool must run on: the open transaction if
+// it was started on pool, and pool itself if none is open. A write to another
+// pool while a transaction is open, e.g. to another database of the server,
+// fails with ErrTransactionOtherDatabase instead of committing on its own.
+func (t *transaction) writer(pool *sql.DB) (queryer, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.tx == nil {
+		return pool, nil
+	}
+	if t.pool != pool {
+		return nil, ErrTransactionOtherDatabase
+	}
+	return t.tx, nil
+}
+
+// execQueries runs queries inside the open transaction, or in a transaction
+// of their own if none is open.
+func (t *transaction) execQueries(ctx context.Context, pool *sql.DB, queries []models.Query) error {
+	q, err := t.writer(pool)
+	if err != nil {
+		return err
+	}
+	tx, ok := q.(*sql.Tx)
+	if !ok {
+		return queriesInTransaction(ctx, pool, queries)
+	}
+
+	for _, query := range queries {
+		if _, err := tx.ExecContext(ctx, query.Query, query.Args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pingPool pings pool, newly opened, and closes it if the database does not
+// answer.
+func pingPool(ctx context.Context, pool *sql.DB) error {
+	if err := pool.PingContext(ctx); err != nil {
+		return errors.Join(err, pool.Close())
+	}
+	return nil
+}
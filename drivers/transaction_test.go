@@ -0,0 +1,159 @@
+package drivers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	gomock "github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+func Test_transaction(t *testing.T) {
+	tests := []struct {
+		setMockExpectations func(mock gomock.Sqlmock)
+		run                 func(t *testing.T, tx *transaction, db *sql.DB)
+		name                string
+	}{
+		{
+			name: "queries run inside the open transaction until commit",
+			setMockExpectations: func(mock gomock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE users").WillReturnResult(gomock.NewResult(0, 1))
+				mock.ExpectExec("DELETE FROM users").WillReturnResult(gomock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			run: func(t *testing.T, tx *transaction, db *sql.DB) {
+				t.Helper()
+				if err := tx.begin(context.Background(), db); err != nil {
+					t.Fatalf("begin: %v", err)
+				}
+				if !tx.active() {
+					t.Fatal("expected transaction to be active")
+				}
+				if _, ok := tx.on(db).(*sql.Tx); !ok {
+					t.Fatal("expected statements to run on the transaction")
+				}
+				if _, err := tx.on(db).ExecContext(context.Background(), "UPDATE users SET name = 'a'"); err != nil {
+					t.Fatalf("exec: %v", err)
+				}
+				if err := tx.execQueries(context.Background(), db, []models.Query{{Query: "DELETE FROM users"}}); err != nil {
+					t.Fatalf("execQueries: %v", err)
+				}
+				if err := tx.commit(); err != nil {
+					t.Fatalf("commit: %v", err)
+				}
+				if tx.active() {
+					t.Fatal("expected transaction to be closed after commit")
+				}
+			},
+		},
+		{
+			name: "rollback",
+			setMockExpectations: func(mock gomock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectRollback()
+			},
+			run: func(t *testing.T, tx *transaction, db *sql.DB) {
+				t.Helper()
+				if err := tx.begin(context.Background(), db); err != nil {
+					t.Fatalf("begin: %v", err)
+				}
+				if err := tx.rollback(); err != nil {
+					t.Fatalf("rollback: %v", err)
+				}
+				if _, ok := tx.on(db).(*sql.DB); !ok {
+					t.Fatal("expected statements to run on the pool after rollback")
+				}
+			},
+		},
+		{
+			name: "begin twice",
+			setMockExpectations: func(mock gomock.Sqlmock) {
+				mock.ExpectBegin()
+			},
+			run: func(t *testing.T, tx *transaction, db *sql.DB) {
+				t.Helper()
+				if err := tx.begin(context.Background(), db); err != nil {
+					t.Fatalf("begin: %v", err)
+				}
+				if err := tx.begin(context.Background(), db); !errors.Is(err, ErrTransactionOpen) {
+					t.Fatalf("expected ErrTransactionOpen, got %v", err)
+				}
+			},
+		},
+		{
+			name:                "commit without transaction",
+			setMockExpectations: func(gomock.Sqlmock) {},
+			run: func(t *testing.T, tx *transaction, _ *sql.DB) {
+				t.Helper()
+				if err := tx.commit(); !errors.Is(err, ErrNoTransaction) {
+					t.Fatalf("expected ErrNoTransaction, got %v", err)
+				}
+				if err := tx.rollback(); !errors.Is(err, ErrNoTransaction) {
+					t.Fatalf("expected ErrNoTransaction, got %v", err)
+				}
+			},
+		},
+		{
+			name: "writes to another pool fail while a transaction is open",
+			setMockExpectations: func(mock gomock.Sqlmock) {
+				mock.ExpectBegin()
+			},
+			run: func(t *testing.T, tx *transaction, db *sql.DB) {
+				t.Helper()
+				other, _, err := gomock.New()
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer other.Close()
+
+				if err := tx.begin(context.Background(), db); err != nil {
+					t.Fatalf("begin: %v", err)
+				}
+				if _, err := tx.writer(other); !errors.Is(err, ErrTransactionOtherDatabase) {
+					t.Fatalf("expected ErrTransactionOtherDatabase, got %v", err)
+				}
+				if err := tx.execQueries(context.Background(), other, []models.Query{{Query: "DELETE FROM users"}}); !errors.Is(err, ErrTransactionOtherDatabase) {
+					t.Fatalf("expected ErrTransactionOtherDatabase, got %v", err)
+				}
+				if _, ok := tx.on(other).(*sql.DB); !ok {
+					t.Fatal("expected reads of another pool to run on it")
+				}
+			},
+		},
+		{
+			name: "execQueries without transaction commits on its own",
+			setMockExpectations: func(mock gomock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("DELETE FROM users").WillReturnResult(gomock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			run: func(t *testing.T, tx *transaction, db *sql.DB) {
+				t.Helper()
+				if err := tx.execQueries(context.Background(), db, []models.Query{{Query: "DELETE FROM users"}}); err != nil {
+					t.Fatalf("execQueries: %v", err)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := gomock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			tt.setMockExpectations(mock)
+			tt.run(t, &transaction{}, db)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package drivers
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
@@ -10,8 +11,8 @@ import (
 	"github.com/jorgerojas26/lazysql/models"
 )
 
-func queriesInTransaction(db *sql.DB, queries []models.Query) (err error) {
-	trx, err := db.Begin()
+func queriesInTransaction(ctx context.Context, db *sql.DB, queries []models.Query) (err error) {
+	trx, err := db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
@@ -24,7 +25,7 @@ func queriesInTransaction(db *sql.DB, queries []models.Query) (err error) {
 	}()
 
 	for _, query := range queries {
-		if _, err := trx.Exec(query.Query, query.Args...); err != nil {
+		if _, err := trx.ExecContext(ctx, query.Query, query.Args...); err != nil {
 			return err
 		}
 	}
@@ -61,7 +62,7 @@ func buildInsertQuery(formattedTableName string, values []models.CellValue, driv
 
 		if value.Value != nil && value.Type != models.Default {
 			placeholders = append(placeholders, driver.FormatPlaceholder(index))
-			args = append(args, value.Value)
+			args = append(args, driver.FormatArg(value.Value, value.Type))
 			index++
 		}
 	}
@@ -293,3 +294,31 @@ func buildPlaceholders(values []models.CellValue, driver Driver) []string {
 	}
 	return placeholders
 }
+
+// scanReferencingForeignKeys reads the foreign keys referencing a table from
+// rows of the table of the key, a key identifying it in the table, its name,
+// a column and the column it references, ordered by key and by position.
+func scanReferencingForeignKeys(rows *sql.Rows) ([]models.ReferencingForeignKey, error) {
+	var keys []models.ReferencingForeignKey
+	var lastTable, lastKey string
+	for rows.Next() {
+		var table, key, name, column, referencedColumn string
+		if err := rows.Scan(&table, &key, &name, &column, &referencedColumn); err != nil {
+			return nil, err
+		}
+
+		if len(keys) == 0 || table != lastTable || key != lastKey {
+			keys = append(keys, models.ReferencingForeignKey{Table: table, ForeignKeySchema: models.ForeignKeySchema{Name: name}})
+			lastTable, lastKey = table, key
+		}
+
+		current := &keys[len(keys)-1]
+		current.Columns = append(current.Columns, column)
+		current.ReferencedColumns = append(current.ReferencedColumns, referencedColumn)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
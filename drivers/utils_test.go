@@ -1,6 +1,8 @@
 package drivers
 
 import (
+	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"reflect"
@@ -15,37 +17,111 @@ import (
 // mockDriver implements Driver with postgres-like formatting for unit tests.
 type mockDriver struct{}
 
-func (m *mockDriver) Connect(string) error                               { panic("not used") }
-func (m *mockDriver) TestConnection(string) error                        { panic("not used") }
-func (m *mockDriver) GetDatabases() ([]string, error)                    { panic("not used") }
-func (m *mockDriver) GetTables(string) (map[string][]string, error)      { panic("not used") }
-func (m *mockDriver) GetTableColumns(string, string) ([][]string, error) { panic("not used") }
-func (m *mockDriver) GetConstraints(string, string) ([][]string, error)  { panic("not used") }
-func (m *mockDriver) GetForeignKeys(string, string) ([][]string, error)  { panic("not used") }
-func (m *mockDriver) GetIndexes(string, string) ([][]string, error)      { panic("not used") }
-func (m *mockDriver) GetRecords(string, string, string, string, int, int) ([][]string, int, string, error) {
+func (m *mockDriver) Connect(context.Context, string) error          { panic("not used") }
+func (m *mockDriver) TestConnection(context.Context, string) error   { panic("not used") }
+func (m *mockDriver) Ping(context.Context) error                     { panic("not used") }
+func (m *mockDriver) GetDatabases(context.Context) ([]string, error) { panic("not used") }
+func (m *mockDriver) GetTables(context.Context, string) (map[string][]string, error) {
+	panic("not used")
+}
+func (m *mockDriver) GetTableColumns(context.Context, string, string) ([][]string, error) {
+	panic("not used")
+}
+func (m *mockDriver) GetConstraints(context.Context, string, string) ([][]string, error) {
+	panic("not used")
+}
+func (m *mockDriver) GetForeignKeys(context.Context, string, string) ([][]string, error) {
+	panic("not used")
+}
+func (m *mockDriver) GetIndexes(context.Context, string, string) ([][]string, error) {
+	panic("not used")
+}
+func (m *mockDriver) GetRecords(context.Context, string, string, string, string, int, int) ([]models.Record, int, string, error) {
 	panic("not used")
 }
 
-func (m *mockDriver) UpdateRecord(string, string, string, string, string, string) error {
+func (m *mockDriver) UpdateRecord(context.Context, string, string, string, string, string, string) error {
+	panic("not used")
+}
+func (m *mockDriver) DeleteRecord(context.Context, string, string, string, string) error {
+	panic("not used")
+}
+func (m *mockDriver) ExecuteDMLStatement(context.Context, string, ...any) (string, error) {
+	panic("not used")
+}
+func (m *mockDriver) ExecuteQuery(context.Context, string, ...any) ([]models.Record, int, error) {
+	panic("not used")
+}
+func (m *mockDriver) QueryRows(context.Context, string, ...any) (Rows, error) {
+	panic("not used")
+}
+func (m *mockDriver) ExplainQuery(context.Context, string, ...any) (*models.PlanNode, error) {
+	panic("not used")
+}
+func (m *mockDriver) ExecutePendingChanges(context.Context, []models.DBDMLChange) error {
+	panic("not used")
+}
+func (m *mockDriver) GetProvider() string { return "mock" }
+func (m *mockDriver) GetPrimaryKeyColumnNames(context.Context, string, string) ([]string, error) {
+	panic("not used")
+}
+func (m *mockDriver) BeginTransaction(context.Context) error { panic("not used") }
+func (m *mockDriver) CommitTransaction() error               { panic("not used") }
+func (m *mockDriver) RollbackTransaction() error             { panic("not used") }
+func (m *mockDriver) InTransaction() bool                    { return false }
+func (m *mockDriver) SupportsProgramming() bool              { return false }
+func (m *mockDriver) UseSchemas() bool                       { return false }
+func (m *mockDriver) GetFunctions(context.Context, string) (map[string][]string, error) {
+	panic("not used")
+}
+func (m *mockDriver) GetProcedures(context.Context, string) (map[string][]string, error) {
+	panic("not used")
+}
+func (m *mockDriver) GetViews(context.Context, string) (map[string][]string, error) {
+	panic("not used")
+}
+func (m *mockDriver) GetFunctionDefinition(context.Context, string, string) (string, error) {
 	panic("not used")
 }
-func (m *mockDriver) DeleteRecord(string, string, string, string) error { panic("not used") }
-func (m *mockDriver) ExecuteDMLStatement(string) (string, error)        { panic("not used") }
-func (m *mockDriver) ExecuteQuery(string) ([][]string, int, error)      { panic("not used") }
-func (m *mockDriver) ExecutePendingChanges([]models.DBDMLChange) error  { panic("not used") }
-func (m *mockDriver) GetProvider() string                               { return "mock" }
-func (m *mockDriver) GetPrimaryKeyColumnNames(string, string) ([]string, error) {
+func (m *mockDriver) GetProcedureDefinition(context.Context, string, string) (string, error) {
+	panic("not used")
+}
+func (m *mockDriver) GetViewDefinition(context.Context, string, string) (string, error) {
+	panic("not used")
+}
+func (m *mockDriver) GetTableDefinition(context.Context, string, string) (string, error) {
+	panic("not used")
+}
+func (m *mockDriver) GetReferencingForeignKeys(context.Context, string, string) ([]models.ReferencingForeignKey, error) {
+	panic("not used")
+}
+func (m *mockDriver) GetTriggers(context.Context, string) (map[string][]string, error) {
+	panic("not used")
+}
+func (m *mockDriver) GetSequences(context.Context, string) (map[string][]string, error) {
+	panic("not used")
+}
+func (m *mockDriver) GetMaterializedViews(context.Context, string) (map[string][]string, error) {
+	panic("not used")
+}
+func (m *mockDriver) GetTypes(context.Context, string) (map[string][]string, error) {
+	panic("not used")
+}
+func (m *mockDriver) GetTriggerDefinition(context.Context, string, string) (string, error) {
+	panic("not used")
+}
+func (m *mockDriver) GetSequenceDefinition(context.Context, string, string) (string, error) {
+	panic("not used")
+}
+func (m *mockDriver) GetMaterializedViewDefinition(context.Context, string, string) (string, error) {
+	panic("not used")
+}
+func (m *mockDriver) GetTypeDefinition(context.Context, string, string) (string, error) {
+	panic("not used")
+}
+func (m *mockDriver) RefreshMaterializedView(context.Context, string, string) error {
 	panic("not used")
 }
-func (m *mockDriver) SupportsProgramming() bool                                 { return false }
-func (m *mockDriver) UseSchemas() bool                                          { return false }
-func (m *mockDriver) GetFunctions(string) (map[string][]string, error)          { panic("not used") }
-func (m *mockDriver) GetProcedures(string) (map[string][]string, error)         { panic("not used") }
-func (m *mockDriver) GetViews(string) (map[string][]string, error)              { panic("not used") }
-func (m *mockDriver) GetFunctionDefinition(string, string) (string, error)      { panic("not used") }
-func (m *mockDriver) GetProcedureDefinition(string, string) (string, error)     { panic("not used") }
-func (m *mockDriver) GetViewDefinition(string, string) (string, error)          { panic("not used") }
 func (m *mockDriver) DMLChangeToQueryString(models.DBDMLChange) (string, error) { panic("not used") }
 func (m *mockDriver) SetProvider(string)                                        {}
 
@@ -174,7 +250,7 @@ func Test_queriesInTransaction(t *testing.T) {
 			}
 			defer db.Close()
 			tt.setMockExpectations(mock)
-			queryErr := queriesInTransaction(db, tt.queries)
+			queryErr := queriesInTransaction(context.Background(), db, tt.queries)
 			if tt.assertErr != nil {
 				tt.assertErr(t, queryErr)
 			}
@@ -182,6 +258,26 @@ func Test_queriesInTransaction(t *testing.T) {
 	}
 }
 
+func Test_buildInsertQuery(t *testing.T) {
+	values := []models.CellValue{
+		{Column: "id", Value: "DEFAULT", Type: models.Default},
+		{Column: "name", Value: "Alice", Type: models.String},
+		{Column: "nickname", Value: "NULL", Type: models.Null},
+		{Column: "note", Value: "EMPTY", Type: models.Empty},
+	}
+
+	got := buildInsertQuery(`"test_table"`, values, &Postgres{})
+
+	wantQuery := `INSERT INTO "test_table" ("name", "nickname", "note") VALUES ($1, $2, $3)`
+	if got.Query != wantQuery {
+		t.Errorf("query mismatch:\n  got:  %s\n  want: %s", got.Query, wantQuery)
+	}
+	wantArgs := []any{"Alice", sql.NullString{}, ""}
+	if !reflect.DeepEqual(got.Args, wantArgs) {
+		t.Errorf("args mismatch:\n  got:  %v\n  want: %v", got.Args, wantArgs)
+	}
+}
+
 func Test_buildUpdateQuery(t *testing.T) {
 	d := &mockDriver{}
 
@@ -1,142 +1,52 @@
 package helpers
 
 import (
-	"bufio"
 	"encoding/csv"
-	"os"
-	"path/filepath"
-	"strings"
-)
+	"io"
 
-// CSVWriter supports streaming CSV writing with atomic file creation.
-// It writes to a temporary file and renames it to the final path on Commit().
-type CSVWriter struct {
-	file           *os.File
-	bufferedWriter *bufio.Writer
-	csvWriter      *csv.Writer
-	columnCount    int
-	rowCount       int
-	cleanedRecord  []string // reusable slice to reduce allocations
-	tempPath       string
-	finalPath      string
-	done           bool
-}
+	"github.com/jorgerojas26/lazysql/models"
+)
 
-// NewCSVWriter creates a new CSVWriter that writes to a temporary file.
+// NewCSVWriter creates a RecordWriter that writes CSV to a temporary file.
 // Call Commit() to finalize the file, or Abort() to discard it.
-func NewCSVWriter(filePath string) (*CSVWriter, error) {
-	dir := filepath.Dir(filePath)
-	if err := os.MkdirAll(dir, 0o700); err != nil {
-		return nil, err
-	}
-
-	tempFile, err := os.CreateTemp(dir, ".lazysql_export_*.tmp")
-	if err != nil {
-		return nil, err
-	}
-
-	bufferedWriter := bufio.NewWriterSize(tempFile, 64*1024)
-	csvWriter := csv.NewWriter(bufferedWriter)
-
-	return &CSVWriter{
-		file:           tempFile,
-		bufferedWriter: bufferedWriter,
-		csvWriter:      csvWriter,
-		tempPath:       tempFile.Name(),
-		finalPath:      filePath,
-	}, nil
+func NewCSVWriter(filePath string) (RecordWriter, error) {
+	return NewRecordWriter(filePath, ExportCSV, ExportOptions{})
 }
 
-// WriteRecords writes records to CSV.
-// If includeHeader is true, records[0] is written as the header.
-// If includeHeader is false, records[0] (header) is skipped and only records[1:] are written.
-func (w *CSVWriter) WriteRecords(records [][]string, includeHeader bool) error {
-	if len(records) == 0 {
-		return nil
-	}
-
-	// Initialize column count and reusable slice on first write
-	if w.columnCount == 0 {
-		w.columnCount = len(records[0])
-		w.cleanedRecord = make([]string, w.columnCount)
-	}
-
-	writeRow := func(record []string) error {
-		for i := range w.cleanedRecord {
-			if i < len(record) {
-				w.cleanedRecord[i] = CleanCellValue(record[i])
-			} else {
-				w.cleanedRecord[i] = ""
-			}
-		}
-		return w.csvWriter.Write(w.cleanedRecord)
-	}
-
-	if includeHeader {
-		if err := writeRow(records[0]); err != nil {
-			return err
-		}
-	}
-	for _, record := range records[1:] {
-		if err := writeRow(record); err != nil {
-			return err
-		}
-		w.rowCount++
-	}
-
-	return nil
+// delimitedEncoder writes CSV, or TSV with a tab as separator. NULL is
+// written as an empty field, the formats have no way to tell them apart.
+type delimitedEncoder struct {
+	csvWriter    *csv.Writer
+	paddedRecord []string // reusable slice to reduce allocations
 }
 
-// Commit flushes, closes the temp file, and renames it to the final path.
-// After Commit, the CSVWriter should not be used.
-func (w *CSVWriter) Commit() error {
-	if w.done {
-		return nil
-	}
-
-	w.csvWriter.Flush()
-	if err := w.csvWriter.Error(); err != nil {
-		w.Abort()
-		return err
-	}
-	if err := w.bufferedWriter.Flush(); err != nil {
-		w.Abort()
-		return err
-	}
-
-	w.done = true
-	if err := w.file.Close(); err != nil {
-		_ = os.Remove(w.tempPath)
-		return err
-	}
-
-	return os.Rename(w.tempPath, w.finalPath)
+func newDelimitedEncoder(w io.Writer, separator rune) *delimitedEncoder {
+	csvWriter := csv.NewWriter(w)
+	csvWriter.Comma = separator
+	return &delimitedEncoder{csvWriter: csvWriter}
 }
 
-// Abort closes the temp file and removes it.
-// Safe to call multiple times or after Commit.
-func (w *CSVWriter) Abort() {
-	if w.done {
-		return
-	}
-	w.done = true
-
-	_ = w.file.Close()
-	_ = os.Remove(w.tempPath)
+func (e *delimitedEncoder) writeHeader(header models.Record) error {
+	return e.writeRow(header, header)
 }
 
-// RowCount returns the number of data rows written (excluding header)
-func (w *CSVWriter) RowCount() int {
-	return w.rowCount
-}
+// writeRow pads or cuts record to the number of columns of header.
+func (e *delimitedEncoder) writeRow(header, record models.Record) error {
+	if e.paddedRecord == nil {
+		e.paddedRecord = make([]string, len(header))
+	}
 
-// CleanCellValue removes special markers from cell values (NULL&, EMPTY&, DEFAULT&)
-func CleanCellValue(value string) string {
-	if cleaned, found := strings.CutSuffix(value, "&"); found {
-		switch cleaned {
-		case "NULL", "EMPTY", "DEFAULT":
-			return ""
+	for i := range e.paddedRecord {
+		if i < len(record) {
+			e.paddedRecord[i] = record[i].String()
+		} else {
+			e.paddedRecord[i] = ""
 		}
 	}
-	return value
+	return e.csvWriter.Write(e.paddedRecord)
+}
+
+func (e *delimitedEncoder) finish() error {
+	e.csvWriter.Flush()
+	return e.csvWriter.Error()
 }
@@ -5,69 +5,25 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/jorgerojas26/lazysql/models"
 )
 
-func TestCleanCellValue(t *testing.T) {
-	testCases := []struct {
-		name     string
-		input    string
-		expected string
-	}{
-		{
-			name:     "NULL marker",
-			input:    "NULL&",
-			expected: "",
-		},
-		{
-			name:     "EMPTY marker",
-			input:    "EMPTY&",
-			expected: "",
-		},
-		{
-			name:     "DEFAULT marker",
-			input:    "DEFAULT&",
-			expected: "",
-		},
-		{
-			name:     "Regular string",
-			input:    "hello world",
-			expected: "hello world",
-		},
-		{
-			name:     "String ending with ampersand but not a marker",
-			input:    "test&",
-			expected: "test&",
-		},
-		{
-			name:     "Empty string",
-			input:    "",
-			expected: "",
-		},
-		{
-			name:     "String with NULL inside",
-			input:    "contains NULL text",
-			expected: "contains NULL text",
-		},
-		{
-			name:     "Ampersand only",
-			input:    "&",
-			expected: "&",
-		},
-		{
-			name:     "NULLABLE (not NULL&)",
-			input:    "NULLABLE",
-			expected: "NULLABLE",
-		},
+func textRecord(row []string) models.Record {
+	record := make(models.Record, len(row))
+	for i, text := range row {
+		record[i] = models.TextValue(text)
 	}
+	return record
+}
 
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			result := CleanCellValue(tc.input)
-			if result != tc.expected {
-				t.Fatalf("CleanCellValue(%q): expected %q, got %q", tc.input, tc.expected, result)
-			}
-		})
+// textRecords returns records holding text only.
+func textRecords(rows [][]string) []models.Record {
+	records := make([]models.Record, len(rows))
+	for i, row := range rows {
+		records[i] = textRecord(row)
 	}
+	return records
 }
 
 func TestCSVWriter(t *testing.T) {
@@ -86,7 +42,7 @@ func TestCSVWriter(t *testing.T) {
 			{"2", "Bob", "200"},
 		}
 
-		err = writer.WriteRecords(records, true)
+		err = writer.WriteRecords(textRecords(records), true)
 		if err != nil {
 			t.Fatalf("WriteRecords failed: %v", err)
 		}
@@ -133,7 +89,7 @@ func TestCSVWriter(t *testing.T) {
 			{"1", "Alice"},
 		}
 
-		err = writer.WriteRecords(records, true)
+		err = writer.WriteRecords(textRecords(records), true)
 		if err != nil {
 			t.Fatalf("WriteRecords failed: %v", err)
 		}
@@ -164,7 +120,7 @@ func TestCSVWriter(t *testing.T) {
 		}
 
 		records := [][]string{{"header"}, {"value"}}
-		_ = writer.WriteRecords(records, true)
+		_ = writer.WriteRecords(textRecords(records), true)
 		_ = writer.Commit()
 
 		// Abort after Commit should be no-op
@@ -192,7 +148,7 @@ func TestCSVWriter(t *testing.T) {
 			{"1", "Alice"},
 			{"2", "Bob"},
 		}
-		err = writer.WriteRecords(batch1, true)
+		err = writer.WriteRecords(textRecords(batch1), true)
 		if err != nil {
 			t.Fatalf("WriteRecords batch1 failed: %v", err)
 		}
@@ -203,7 +159,7 @@ func TestCSVWriter(t *testing.T) {
 			{"3", "Charlie"},
 			{"4", "Diana"},
 		}
-		err = writer.WriteRecords(batch2, false)
+		err = writer.WriteRecords(textRecords(batch2), false)
 		if err != nil {
 			t.Fatalf("WriteRecords batch2 failed: %v", err)
 		}
@@ -228,7 +184,7 @@ func TestCSVWriter(t *testing.T) {
 		}
 	})
 
-	t.Run("Write with NULL markers", func(t *testing.T) {
+	t.Run("Write marker-like text as is", func(t *testing.T) {
 		tempDir := t.TempDir()
 		filePath := filepath.Join(tempDir, "test_null_markers.csv")
 
@@ -244,7 +200,7 @@ func TestCSVWriter(t *testing.T) {
 			{"2", "EMPTY&"},
 		}
 
-		err = writer.WriteRecords(records, true)
+		err = writer.WriteRecords(textRecords(records), true)
 		if err != nil {
 			t.Fatalf("WriteRecords failed: %v", err)
 		}
@@ -259,7 +215,7 @@ func TestCSVWriter(t *testing.T) {
 			t.Fatalf("Failed to read file: %v", err)
 		}
 
-		expected := "id,name\n1,\n2,\n"
+		expected := "id,name\n1,NULL&\n2,EMPTY&\n"
 		if string(content) != expected {
 			t.Fatalf("Content mismatch:\nexpected: %q\ngot: %q", expected, string(content))
 		}
@@ -275,7 +231,7 @@ func TestCSVWriter(t *testing.T) {
 		}
 		defer writer.Abort()
 
-		err = writer.WriteRecords([][]string{}, true)
+		err = writer.WriteRecords(textRecords([][]string{}), true)
 		if err != nil {
 			t.Fatalf("WriteRecords failed: %v", err)
 		}
@@ -304,7 +260,7 @@ func TestCSVWriter(t *testing.T) {
 			{"id", "name", "value"},
 		}
 
-		err = writer.WriteRecords(records, true)
+		err = writer.WriteRecords(textRecords(records), true)
 		if err != nil {
 			t.Fatalf("WriteRecords failed: %v", err)
 		}
@@ -329,6 +285,44 @@ func TestCSVWriter(t *testing.T) {
 		}
 	})
 
+	t.Run("Write streamed rows", func(t *testing.T) {
+		tempDir := t.TempDir()
+		filePath := filepath.Join(tempDir, "test_streamed.csv")
+
+		writer, err := NewCSVWriter(filePath)
+		if err != nil {
+			t.Fatalf("NewCSVWriter failed: %v", err)
+		}
+		defer writer.Abort()
+
+		if err := writer.WriteHeader(textRecord([]string{"id", "name"})); err != nil {
+			t.Fatalf("WriteHeader failed: %v", err)
+		}
+		for _, row := range [][]string{{"1", "Alice"}, {"2", ""}} {
+			if err := writer.WriteRow(textRecord(row)); err != nil {
+				t.Fatalf("WriteRow failed: %v", err)
+			}
+		}
+
+		if writer.RowCount() != 2 {
+			t.Fatalf("Expected RowCount 2, got %d", writer.RowCount())
+		}
+
+		if err := writer.Commit(); err != nil {
+			t.Fatalf("Commit failed: %v", err)
+		}
+
+		content, err := os.ReadFile(filePath)
+		if err != nil {
+			t.Fatalf("Failed to read file: %v", err)
+		}
+
+		expected := "id,name\n1,Alice\n2,\n"
+		if string(content) != expected {
+			t.Fatalf("Content mismatch:\nexpected: %q\ngot: %q", expected, string(content))
+		}
+	})
+
 	t.Run("Creates parent directories", func(t *testing.T) {
 		tempDir := t.TempDir()
 		filePath := filepath.Join(tempDir, "nested", "dir", "test.csv")
@@ -340,7 +334,7 @@ func TestCSVWriter(t *testing.T) {
 		defer writer.Abort()
 
 		records := [][]string{{"header"}, {"value"}}
-		err = writer.WriteRecords(records, true)
+		err = writer.WriteRecords(textRecords(records), true)
 		if err != nil {
 			t.Fatalf("WriteRecords failed: %v", err)
 		}
@@ -0,0 +1,239 @@
+package helpers
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+// ExportFormat is a file format records can be exported to.
+type ExportFormat int
+
+const (
+	ExportCSV ExportFormat = iota
+	ExportTSV
+	ExportJSON
+	ExportNDJSON
+	ExportSQLInsert
+	ExportMarkdown
+)
+
+// ExportFormats lists the export formats in the order they are offered.
+var ExportFormats = []ExportFormat{ExportCSV, ExportTSV, ExportJSON, ExportNDJSON, ExportSQLInsert, ExportMarkdown}
+
+func (f ExportFormat) String() string {
+	switch f {
+	case ExportTSV:
+		return "TSV"
+	case ExportJSON:
+		return "JSON"
+	case ExportNDJSON:
+		return "NDJSON"
+	case ExportSQLInsert:
+		return "SQL INSERT"
+	case ExportMarkdown:
+		return "Markdown"
+	default:
+		return "CSV"
+	}
+}
+
+// Extension returns the file extension of the format, without the dot.
+func (f ExportFormat) Extension() string {
+	switch f {
+	case ExportTSV:
+		return "tsv"
+	case ExportJSON:
+		return "json"
+	case ExportNDJSON:
+		return "ndjson"
+	case ExportSQLInsert:
+		return "sql"
+	case ExportMarkdown:
+		return "md"
+	default:
+		return "csv"
+	}
+}
+
+// RecordWriter streams records to a file. Everything is written to a
+// temporary file that Commit renames to the final path, so a failed export
+// never leaves a partial file behind.
+type RecordWriter interface {
+	// WriteHeader writes the header record, which holds the column names.
+	// It must be called before WriteRow.
+	WriteHeader(header models.Record) error
+	// WriteRow writes a single data row.
+	WriteRow(record models.Record) error
+	// WriteRecords writes records, records[0] being the header. The header
+	// is written only if includeHeader is true, so that batches of the
+	// same export can be written one after the other.
+	WriteRecords(records []models.Record, includeHeader bool) error
+	// Commit finishes the file and renames it to the final path. After
+	// Commit, the writer should not be used.
+	Commit() error
+	// Abort discards the temporary file. Safe to call multiple times or
+	// after Commit.
+	Abort()
+	// RowCount returns the number of data rows written (excluding header).
+	RowCount() int
+}
+
+// SQLDialect formats the identifiers and values of the statements of a SQL
+// INSERT export. It is implemented by the database drivers.
+type SQLDialect interface {
+	FormatReference(reference string) string
+	FormatArgForQueryString(arg any) string
+}
+
+// ExportOptions holds the settings that only some formats use.
+type ExportOptions struct {
+	// TableName is the table of the SQL INSERT statements, e.g.
+	// "public.users".
+	TableName string
+	Dialect   SQLDialect
+}
+
+// recordEncoder writes records in the syntax of a format.
+type recordEncoder interface {
+	writeHeader(header models.Record) error
+	writeRow(header, record models.Record) error
+	// finish writes what follows the last row, e.g. a closing bracket.
+	finish() error
+}
+
+// exportWriter implements RecordWriter on top of a recordEncoder.
+type exportWriter struct {
+	file           *os.File
+	bufferedWriter *bufio.Writer
+	encoder        recordEncoder
+	header         models.Record
+	rowCount       int
+	tempPath       string
+	finalPath      string
+	done           bool
+}
+
+// NewRecordWriter creates a RecordWriter of format that writes to a
+// temporary file. Call Commit() to finalize the file, or Abort() to discard
+// it.
+func NewRecordWriter(filePath string, format ExportFormat, opts ExportOptions) (RecordWriter, error) {
+	dir := filepath.Dir(filePath)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+
+	tempFile, err := os.CreateTemp(dir, ".lazysql_export_*.tmp")
+	if err != nil {
+		return nil, err
+	}
+
+	bufferedWriter := bufio.NewWriterSize(tempFile, 64*1024)
+
+	return &exportWriter{
+		file:           tempFile,
+		bufferedWriter: bufferedWriter,
+		encoder:        newRecordEncoder(bufferedWriter, format, opts),
+		tempPath:       tempFile.Name(),
+		finalPath:      filePath,
+	}, nil
+}
+
+func newRecordEncoder(w io.Writer, format ExportFormat, opts ExportOptions) recordEncoder {
+	switch format {
+	case ExportTSV:
+		return newDelimitedEncoder(w, '\t')
+	case ExportJSON:
+		return &jsonEncoder{w: w}
+	case ExportNDJSON:
+		return &jsonEncoder{w: w, lines: true}
+	case ExportSQLInsert:
+		return &sqlInsertEncoder{w: w, tableName: opts.TableName, dialect: opts.Dialect}
+	case ExportMarkdown:
+		return &markdownEncoder{w: w}
+	default:
+		return newDelimitedEncoder(w, ',')
+	}
+}
+
+func (w *exportWriter) WriteHeader(header models.Record) error {
+	if w.header != nil {
+		return nil
+	}
+	w.header = header
+	return w.encoder.writeHeader(header)
+}
+
+func (w *exportWriter) WriteRow(record models.Record) error {
+	if w.header == nil {
+		// Without a header, the columns are only known by position.
+		w.header = make(models.Record, len(record))
+	}
+	if err := w.encoder.writeRow(w.header, record); err != nil {
+		return err
+	}
+	w.rowCount++
+	return nil
+}
+
+func (w *exportWriter) WriteRecords(records []models.Record, includeHeader bool) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	if includeHeader {
+		if err := w.WriteHeader(records[0]); err != nil {
+			return err
+		}
+	} else if w.header == nil {
+		w.header = records[0]
+	}
+
+	for _, record := range records[1:] {
+		if err := w.WriteRow(record); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (w *exportWriter) Commit() error {
+	if w.done {
+		return nil
+	}
+
+	if err := w.encoder.finish(); err != nil {
+		w.Abort()
+		return err
+	}
+	if err := w.bufferedWriter.Flush(); err != nil {
+		w.Abort()
+		return err
+	}
+
+	w.done = true
+	if err := w.file.Close(); err != nil {
+		_ = os.Remove(w.tempPath)
+		return err
+	}
+
+	return os.Rename(w.tempPath, w.finalPath)
+}
+
+func (w *exportWriter) Abort() {
+	if w.done {
+		return
+	}
+	w.done = true
+
+	_ = w.file.Close()
+	_ = os.Remove(w.tempPath)
+}
+
+func (w *exportWriter) RowCount() int {
+	return w.rowCount
+}
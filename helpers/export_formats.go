@@ -0,0 +1,217 @@
+package helpers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+// isNumber reports whether value can be written as a number literal, which
+// is the case for the numbers of numeric columns.
+func isNumber(value models.Value) bool {
+	if value.Null || !value.IsNumeric() {
+		return false
+	}
+	if _, err := strconv.ParseFloat(value.String(), 64); err != nil {
+		return false
+	}
+	// ParseFloat also accepts e.g. "Inf" and hexadecimal numbers.
+	return json.Valid(value.Raw)
+}
+
+// columnName returns the name of column i of header.
+func columnName(header models.Record, i int) string {
+	if i < len(header) && header[i].String() != "" {
+		return header[i].String()
+	}
+	return fmt.Sprintf("column%d", i+1)
+}
+
+// jsonEncoder writes the records as objects keyed by column name, either
+// in a JSON array or one object per line (NDJSON).
+type jsonEncoder struct {
+	w       io.Writer
+	lines   bool
+	started bool
+}
+
+func (e *jsonEncoder) writeHeader(models.Record) error {
+	return nil
+}
+
+func (e *jsonEncoder) writeRow(header, record models.Record) error {
+	var object strings.Builder
+	object.WriteString("{")
+	for i, value := range record {
+		if i > 0 {
+			object.WriteString(",")
+		}
+
+		key, err := json.Marshal(columnName(header, i))
+		if err != nil {
+			return err
+		}
+		object.Write(key)
+		object.WriteString(":")
+
+		switch {
+		case value.Null:
+			object.WriteString("null")
+		case isNumber(value):
+			object.Write(value.Raw)
+		default:
+			text, err := json.Marshal(value.String())
+			if err != nil {
+				return err
+			}
+			object.Write(text)
+		}
+	}
+	object.WriteString("}")
+
+	separator := "\n"
+	if !e.lines {
+		separator = ",\n  "
+		if !e.started {
+			separator = "[\n  "
+		}
+	}
+	e.started = true
+
+	if e.lines {
+		_, err := io.WriteString(e.w, object.String()+separator)
+		return err
+	}
+	_, err := io.WriteString(e.w, separator+object.String())
+	return err
+}
+
+func (e *jsonEncoder) finish() error {
+	if e.lines {
+		return nil
+	}
+	if !e.started {
+		_, err := io.WriteString(e.w, "[]\n")
+		return err
+	}
+	_, err := io.WriteString(e.w, "\n]\n")
+	return err
+}
+
+// sqlInsertEncoder writes an INSERT statement per record in the dialect of
+// the database the records come from.
+type sqlInsertEncoder struct {
+	w         io.Writer
+	tableName string
+	dialect   SQLDialect
+	columns   string
+}
+
+func (e *sqlInsertEncoder) writeHeader(models.Record) error {
+	return nil
+}
+
+func (e *sqlInsertEncoder) writeRow(header, record models.Record) error {
+	if e.dialect == nil {
+		return errors.New("sql insert export needs the dialect of the database")
+	}
+
+	if e.columns == "" {
+		names := make([]string, len(record))
+		for i := range record {
+			names[i] = e.dialect.FormatReference(columnName(header, i))
+		}
+		e.columns = strings.Join(names, ", ")
+	}
+
+	values := make([]string, len(record))
+	for i, value := range record {
+		values[i] = e.formatValue(value)
+	}
+
+	_, err := fmt.Fprintf(e.w, "INSERT INTO %s (%s) VALUES (%s);\n", e.formatTableName(), e.columns, strings.Join(values, ", "))
+	return err
+}
+
+// formatTableName quotes each part of e.g. "schema.table".
+func (e *sqlInsertEncoder) formatTableName() string {
+	parts := strings.Split(e.tableName, ".")
+	for i, part := range parts {
+		parts[i] = e.dialect.FormatReference(part)
+	}
+	return strings.Join(parts, ".")
+}
+
+func (e *sqlInsertEncoder) formatValue(value models.Value) string {
+	switch text := value.String(); {
+	case value.Null:
+		return "NULL"
+	case isNumber(value):
+		return text
+	case text == "NULL" || text == "DEFAULT":
+		// The drivers leave these keywords unquoted.
+		return "'" + text + "'"
+	default:
+		return e.dialect.FormatArgForQueryString(text)
+	}
+}
+
+func (e *sqlInsertEncoder) finish() error {
+	return nil
+}
+
+// markdownEncoder writes the records as a Markdown table. Numeric columns
+// are aligned to the right.
+type markdownEncoder struct {
+	w             io.Writer
+	headerWritten bool
+}
+
+var markdownEscaper = strings.NewReplacer("|", `\|`, "\r\n", "<br>", "\n", "<br>")
+
+func (e *markdownEncoder) writeHeader(header models.Record) error {
+	e.headerWritten = true
+
+	names := make([]string, len(header))
+	separators := make([]string, len(header))
+	for i, column := range header {
+		names[i] = markdownEscaper.Replace(columnName(header, i))
+		separators[i] = "---"
+		if column.IsNumeric() {
+			separators[i] = "---:"
+		}
+	}
+
+	_, err := fmt.Fprintf(e.w, "| %s |\n| %s |\n", strings.Join(names, " | "), strings.Join(separators, " | "))
+	return err
+}
+
+func (e *markdownEncoder) writeRow(header, record models.Record) error {
+	// A Markdown table can't do without a header.
+	if !e.headerWritten {
+		if err := e.writeHeader(header); err != nil {
+			return err
+		}
+	}
+
+	cells := make([]string, len(record))
+	for i, value := range record {
+		if value.Null {
+			cells[i] = "*NULL*"
+		} else {
+			cells[i] = markdownEscaper.Replace(value.String())
+		}
+	}
+
+	_, err := fmt.Fprintf(e.w, "| %s |\n", strings.Join(cells, " | "))
+	return err
+}
+
+func (e *markdownEncoder) finish() error {
+	return nil
+}
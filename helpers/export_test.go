@@ -0,0 +1,126 @@
+package helpers
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+type quotingDialect struct{}
+
+func (quotingDialect) FormatReference(reference string) string {
+	return `"` + reference + `"`
+}
+
+func (quotingDialect) FormatArgForQueryString(arg any) string {
+	return "'" + strings.ReplaceAll(fmt.Sprint(arg), "'", "''") + "'"
+}
+
+func exportRecords() []models.Record {
+	return []models.Record{
+		models.HeaderRecord([]models.ResultColumn{{Name: "id", Type: "INT4"}, {Name: "name", Type: "TEXT"}}),
+		{{Type: "INT4", Raw: []byte("1")}, {Type: "TEXT", Raw: []byte("O'Brien | Co")}},
+		{{Type: "INT4", Raw: []byte("2")}, {Type: "TEXT", Null: true}},
+	}
+}
+
+func exportToString(t *testing.T, format ExportFormat, opts ExportOptions, batches ...[]models.Record) string {
+	t.Helper()
+
+	filePath := filepath.Join(t.TempDir(), "export."+format.Extension())
+	writer, err := NewRecordWriter(filePath, format, opts)
+	if err != nil {
+		t.Fatalf("NewRecordWriter failed: %v", err)
+	}
+	defer writer.Abort()
+
+	for i, batch := range batches {
+		if err := writer.WriteRecords(batch, i == 0); err != nil {
+			t.Fatalf("WriteRecords failed: %v", err)
+		}
+	}
+	if err := writer.Commit(); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatalf("Failed to read file: %v", err)
+	}
+	return string(content)
+}
+
+func TestRecordWriterFormats(t *testing.T) {
+	testCases := []struct {
+		format   ExportFormat
+		opts     ExportOptions
+		expected string
+	}{
+		{
+			format:   ExportTSV,
+			expected: "id\tname\n1\tO'Brien | Co\n2\t\n",
+		},
+		{
+			format:   ExportJSON,
+			expected: "[\n  {\"id\":1,\"name\":\"O'Brien | Co\"},\n  {\"id\":2,\"name\":null}\n]\n",
+		},
+		{
+			format:   ExportNDJSON,
+			expected: "{\"id\":1,\"name\":\"O'Brien | Co\"}\n{\"id\":2,\"name\":null}\n",
+		},
+		{
+			format: ExportSQLInsert,
+			opts:   ExportOptions{TableName: "public.users", Dialect: quotingDialect{}},
+			expected: "INSERT INTO \"public\".\"users\" (\"id\", \"name\") VALUES (1, 'O''Brien | Co');\n" +
+				"INSERT INTO \"public\".\"users\" (\"id\", \"name\") VALUES (2, NULL);\n",
+		},
+		{
+			format:   ExportMarkdown,
+			expected: "| id | name |\n| ---: | --- |\n| 1 | O'Brien \\| Co |\n| 2 | *NULL* |\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.format.String(), func(t *testing.T) {
+			content := exportToString(t, tc.format, tc.opts, exportRecords())
+			if content != tc.expected {
+				t.Fatalf("Content mismatch:\nexpected: %q\ngot: %q", tc.expected, content)
+			}
+		})
+	}
+}
+
+func TestRecordWriterBatches(t *testing.T) {
+	records := exportRecords()
+	second := []models.Record{records[0], {{Type: "INT4", Raw: []byte("3")}, {Type: "TEXT", Raw: []byte("")}}}
+
+	content := exportToString(t, ExportJSON, ExportOptions{}, records, second)
+	expected := "[\n  {\"id\":1,\"name\":\"O'Brien | Co\"},\n  {\"id\":2,\"name\":null},\n  {\"id\":3,\"name\":\"\"}\n]\n"
+	if content != expected {
+		t.Fatalf("Content mismatch:\nexpected: %q\ngot: %q", expected, content)
+	}
+}
+
+func TestRecordWriterEmptyJSON(t *testing.T) {
+	content := exportToString(t, ExportJSON, ExportOptions{}, exportRecords()[:1])
+	if content != "[]\n" {
+		t.Fatalf("expected an empty array, got %q", content)
+	}
+}
+
+func TestRecordWriterSQLInsertQuotesKeywords(t *testing.T) {
+	records := []models.Record{
+		models.HeaderRecord([]models.ResultColumn{{Name: "note", Type: "TEXT"}, {Name: "amount", Type: "NUMERIC"}}),
+		{{Type: "TEXT", Raw: []byte("NULL")}, {Type: "NUMERIC", Raw: []byte("NaN")}},
+	}
+
+	content := exportToString(t, ExportSQLInsert, ExportOptions{TableName: "notes", Dialect: quotingDialect{}}, records)
+	expected := "INSERT INTO \"notes\" (\"note\", \"amount\") VALUES ('NULL', 'NaN');\n"
+	if content != expected {
+		t.Fatalf("Content mismatch:\nexpected: %q\ngot: %q", expected, content)
+	}
+}
@@ -0,0 +1,274 @@
+package helpers
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+// ImportReader reads the rows of a file to import into a table.
+type ImportReader interface {
+	// Columns returns the column names of the file.
+	Columns() []string
+	// Read returns the next row, with a value per column. It returns io.EOF
+	// after the last row.
+	Read() (models.Record, error)
+	Close() error
+}
+
+// OpenImportFile opens a file to import. The format is chosen by extension:
+// .ndjson, .jsonl and .json files hold JSON objects, one per line or in an
+// array, .tsv files are tab separated and anything else is read as CSV.
+//
+// The first line of a CSV or TSV file holds the column names and its empty
+// fields are read as NULL, as they are exported. The columns of a JSON file
+// are the keys of its first object.
+func OpenImportFile(filePath string) (ImportReader, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var reader ImportReader
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".ndjson", ".jsonl", ".json":
+		reader, err = newJSONImportReader(file)
+	case ".tsv":
+		reader, err = newDelimitedImportReader(file, '\t')
+	default:
+		reader, err = newDelimitedImportReader(file, ',')
+	}
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return reader, nil
+}
+
+// CountImportRows returns the number of rows of a file to import.
+func CountImportRows(filePath string) (int, error) {
+	reader, err := OpenImportFile(filePath)
+	if err != nil {
+		return 0, err
+	}
+	defer reader.Close()
+
+	count := 0
+	for {
+		if _, err := reader.Read(); err != nil {
+			if errors.Is(err, io.EOF) {
+				return count, nil
+			}
+			return count, err
+		}
+		count++
+	}
+}
+
+// delimitedImportReader reads CSV and TSV files.
+type delimitedImportReader struct {
+	file    *os.File
+	reader  *csv.Reader
+	columns []string
+}
+
+func newDelimitedImportReader(file *os.File, comma rune) (*delimitedImportReader, error) {
+	reader := csv.NewReader(bufio.NewReader(file))
+	reader.Comma = comma
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("the file is empty")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(header) > 0 {
+		header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	}
+
+	return &delimitedImportReader{file: file, reader: reader, columns: header}, nil
+}
+
+func (r *delimitedImportReader) Columns() []string {
+	return r.columns
+}
+
+func (r *delimitedImportReader) Read() (models.Record, error) {
+	fields, err := r.reader.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	record := make(models.Record, len(r.columns))
+	for i := range record {
+		if i < len(fields) && fields[i] != "" {
+			record[i] = models.TextValue(fields[i])
+		} else {
+			record[i] = models.Value{Null: true}
+		}
+	}
+	return record, nil
+}
+
+func (r *delimitedImportReader) Close() error {
+	return r.file.Close()
+}
+
+// jsonImportReader reads JSON objects, either one after the other (NDJSON)
+// or in an array.
+type jsonImportReader struct {
+	file    *os.File
+	decoder *json.Decoder
+	inArray bool
+	columns []string
+	// first is the first object, read to know the columns.
+	first models.Record
+}
+
+func newJSONImportReader(file *os.File) (*jsonImportReader, error) {
+	buffered := bufio.NewReader(file)
+	r := &jsonImportReader{file: file}
+
+	// Skip the whitespace to see whether the objects are in an array.
+	for {
+		b, err := buffered.ReadByte()
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("the file is empty")
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !isJSONSpace(b) {
+			r.inArray = b == '['
+			_ = buffered.UnreadByte()
+			break
+		}
+	}
+	r.decoder = json.NewDecoder(buffered)
+	r.decoder.UseNumber()
+	if r.inArray {
+		if _, err := r.decoder.Token(); err != nil {
+			return nil, err
+		}
+	}
+
+	keys, values, err := r.readObject()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("the file has no objects")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	r.columns = keys
+	r.first = r.record(values)
+	return r, nil
+}
+
+func isJSONSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\r' || b == '\n'
+}
+
+func (r *jsonImportReader) Columns() []string {
+	return r.columns
+}
+
+func (r *jsonImportReader) Read() (models.Record, error) {
+	if r.first != nil {
+		record := r.first
+		r.first = nil
+		return record, nil
+	}
+
+	_, values, err := r.readObject()
+	if err != nil {
+		return nil, err
+	}
+	return r.record(values), nil
+}
+
+// readObject reads the next object and returns its keys in order.
+func (r *jsonImportReader) readObject() ([]string, map[string]json.RawMessage, error) {
+	if r.inArray && !r.decoder.More() {
+		return nil, nil, io.EOF
+	}
+
+	var raw json.RawMessage
+	if err := r.decoder.Decode(&raw); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil, io.EOF
+		}
+		return nil, nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return nil, nil, fmt.Errorf("expected a JSON object, got %s", truncate(string(raw), 40))
+	}
+
+	var keys []string
+	values := map[string]json.RawMessage{}
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+		key := token.(string)
+
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return nil, nil, err
+		}
+		if _, ok := values[key]; !ok {
+			keys = append(keys, key)
+		}
+		values[key] = value
+	}
+
+	return keys, values, nil
+}
+
+// record returns the values of an object in the order of the columns. A
+// missing key is NULL. Strings are unquoted and numbers, booleans, objects
+// and arrays are kept as JSON text.
+func (r *jsonImportReader) record(values map[string]json.RawMessage) models.Record {
+	record := make(models.Record, len(r.columns))
+	for i, column := range r.columns {
+		raw, ok := values[column]
+		if !ok || string(raw) == "null" {
+			record[i] = models.Value{Null: true}
+			continue
+		}
+
+		var text string
+		if err := json.Unmarshal(raw, &text); err == nil {
+			record[i] = models.TextValue(text)
+		} else {
+			record[i] = models.TextValue(string(raw))
+		}
+	}
+	return record
+}
+
+func (r *jsonImportReader) Close() error {
+	return r.file.Close()
+}
+
+func truncate(text string, length int) string {
+	if len(text) <= length {
+		return text
+	}
+	return text[:length] + "..."
+}
@@ -0,0 +1,147 @@
+package helpers
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+func readImportFile(t *testing.T, fileName, content string) ([]string, []models.Record) {
+	t.Helper()
+
+	filePath := filepath.Join(t.TempDir(), fileName)
+	if err := os.WriteFile(filePath, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	reader, err := OpenImportFile(filePath)
+	if err != nil {
+		t.Fatalf("OpenImportFile failed: %v", err)
+	}
+	defer reader.Close()
+
+	var records []models.Record
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Read failed: %v", err)
+		}
+		records = append(records, record)
+	}
+
+	count, err := CountImportRows(filePath)
+	if err != nil {
+		t.Fatalf("CountImportRows failed: %v", err)
+	}
+	if count != len(records) {
+		t.Fatalf("expected %d rows to be counted, got %d", len(records), count)
+	}
+
+	return reader.Columns(), records
+}
+
+func null() models.Value {
+	return models.Value{Null: true}
+}
+
+func TestImportReaderFormats(t *testing.T) {
+	expectedColumns := []string{"id", "name"}
+
+	testCases := []struct {
+		name     string
+		fileName string
+		content  string
+		expected []models.Record
+	}{
+		{
+			name:     "CSV",
+			fileName: "users.csv",
+			content:  "\ufeffid,name\n1,\"O'Brien, Co\"\n2,\n3\n",
+			expected: []models.Record{
+				{models.TextValue("1"), models.TextValue("O'Brien, Co")},
+				{models.TextValue("2"), null()},
+				{models.TextValue("3"), null()},
+			},
+		},
+		{
+			name:     "TSV",
+			fileName: "users.tsv",
+			content:  "id\tname\n1\tAda\n",
+			expected: []models.Record{
+				{models.TextValue("1"), models.TextValue("Ada")},
+			},
+		},
+		{
+			name:     "NDJSON",
+			fileName: "users.ndjson",
+			content:  "{\"id\":1,\"name\":\"Ada\"}\n{\"name\":\"\",\"id\":2.50}\n{\"id\":3,\"name\":null,\"extra\":true}\n{\"id\":4}\n",
+			expected: []models.Record{
+				{models.TextValue("1"), models.TextValue("Ada")},
+				{models.TextValue("2.50"), models.TextValue("")},
+				{models.TextValue("3"), null()},
+				{models.TextValue("4"), null()},
+			},
+		},
+		{
+			name:     "JSON array",
+			fileName: "users.json",
+			content:  "[\n  {\"id\":1,\"name\":{\"first\":\"Ada\"}},\n  {\"id\":2,\"name\":[1, 2]}\n]\n",
+			expected: []models.Record{
+				{models.TextValue("1"), models.TextValue(`{"first":"Ada"}`)},
+				{models.TextValue("2"), models.TextValue("[1, 2]")},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			columns, records := readImportFile(t, tc.fileName, tc.content)
+
+			if !reflect.DeepEqual(columns, expectedColumns) {
+				t.Fatalf("expected columns %v, got %v", expectedColumns, columns)
+			}
+			if !reflect.DeepEqual(records, tc.expected) {
+				t.Fatalf("Records mismatch:\nexpected: %v\ngot: %v", tc.expected, records)
+			}
+		})
+	}
+}
+
+func TestImportReaderEmptyJSONArray(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "empty.json")
+	if err := os.WriteFile(filePath, []byte("[]\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	if _, err := OpenImportFile(filePath); err == nil {
+		t.Fatal("expected an error for a file without objects")
+	}
+}
+
+func TestImportReaderInvalidJSON(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "invalid.ndjson")
+	if err := os.WriteFile(filePath, []byte("{\"id\":1}\n[1]\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	reader, err := OpenImportFile(filePath)
+	if err != nil {
+		t.Fatalf("OpenImportFile failed: %v", err)
+	}
+	defer reader.Close()
+
+	if _, err := reader.Read(); err != nil {
+		t.Fatalf("Read of the first object failed: %v", err)
+	}
+	if _, err := reader.Read(); err == nil {
+		t.Fatal("expected an error for a value that is not an object")
+	}
+}
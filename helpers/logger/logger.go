@@ -26,6 +26,18 @@ type logMessage struct {
 
 var logInstance *logger
 
+// redacted replaces the secrets in the log.
+const redacted = "********"
+
+// minSecretLength is the length of the shortest secret hidden from the log.
+const minSecretLength = 6
+
+// secrets are the values added by AddSecret.
+var secrets struct {
+	sync.RWMutex
+	values []string
+}
+
 func init() {
 	logInstance = &logger{level: slog.LevelInfo}
 }
@@ -38,8 +50,8 @@ func (l *logger) log(level slog.Level, msg string, data map[string]any) {
 	logMessage := logMessage{
 		Timestamp: time.Now().Format(time.RFC3339),
 		Level:     level.String(),
-		Message:   msg,
-		Data:      data,
+		Message:   Redact(msg),
+		Data:      redactData(data),
 	}
 
 	logData, err := json.Marshal(logMessage)
@@ -95,6 +107,63 @@ func (l *logger) SetLevel(level slog.Level) {
 	l.level = level
 }
 
+// AddSecret makes the log, and Redact, hide value, e.g. a password read
+// from a secret provider. Values shorter than minSecretLength are not hidden,
+// as they would be replaced all over the log, which is logged as a warning.
+func AddSecret(value string) {
+	if length := len(strings.TrimSpace(value)); length < minSecretLength {
+		if length > 0 {
+			Warn("A secret is too short to be hidden from the log and the query history", map[string]any{"length": length, "minLength": minSecretLength})
+		}
+		return
+	}
+
+	secrets.Lock()
+	defer secrets.Unlock()
+
+	for _, secret := range secrets.values {
+		if secret == value {
+			return
+		}
+	}
+	secrets.values = append(secrets.values, value)
+}
+
+// Redact replaces the secrets added by AddSecret in s.
+func Redact(s string) string {
+	secrets.RLock()
+	defer secrets.RUnlock()
+
+	for _, secret := range secrets.values {
+		s = strings.ReplaceAll(s, secret, redacted)
+	}
+	return s
+}
+
+// redactData returns data with the secrets of its strings and errors
+// redacted, leaving data as is.
+func redactData(data map[string]any) map[string]any {
+	secrets.RLock()
+	empty := len(secrets.values) == 0
+	secrets.RUnlock()
+	if empty || data == nil {
+		return data
+	}
+
+	result := make(map[string]any, len(data))
+	for key, value := range data {
+		switch value := value.(type) {
+		case string:
+			result[key] = Redact(value)
+		case error:
+			result[key] = Redact(value.Error())
+		default:
+			result[key] = value
+		}
+	}
+	return result
+}
+
 func SetLevel(level slog.Level) {
 	logInstance.SetLevel(level)
 }
@@ -0,0 +1,394 @@
+package helpers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+
+	"github.com/jorgerojas26/lazysql/helpers/logger"
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+const (
+	sshDialTimeout       = 10 * time.Second
+	sshKeepAliveInterval = 15 * time.Second
+)
+
+// SSHTunnel forwards a local port to a remote address through an SSH
+// server. The SSH connection is kept alive, and opened again when lost.
+type SSHTunnel struct {
+	config   models.SSHTunnel
+	remote   string
+	listener net.Listener
+
+	mu       sync.Mutex
+	client   *ssh.Client
+	err      error
+	onStatus func(error)
+	done     chan struct{}
+}
+
+// OpenSSHTunnel connects to the SSH server of config and listens on a free
+// local port, whose connections are forwarded to remote.
+func OpenSSHTunnel(ctx context.Context, config models.SSHTunnel, remote string) (*SSHTunnel, error) {
+	if config.Host == "" {
+		return nil, errors.New("the SSH tunnel needs a host")
+	}
+
+	tunnel := &SSHTunnel{
+		config: config,
+		remote: remote,
+		done:   make(chan struct{}),
+	}
+
+	client, err := tunnel.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tunnel.client = client
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		_ = client.Close()
+		return nil, err
+	}
+	tunnel.listener = listener
+
+	logger.Info("SSH tunnel opened", map[string]any{"host": config.Host, "remote": remote, "local": listener.Addr().String()})
+
+	go tunnel.accept()
+	go tunnel.keepAlive()
+
+	return tunnel, nil
+}
+
+// Port returns the local port forwarded to the remote address.
+func (t *SSHTunnel) Port() string {
+	return fmt.Sprint(t.listener.Addr().(*net.TCPAddr).Port)
+}
+
+// SetStatusFunc sets the function called with the error of the SSH
+// connection when it is lost, and with nil when it is opened again.
+func (t *SSHTunnel) SetStatusFunc(onStatus func(error)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.onStatus = onStatus
+}
+
+// Close stops forwarding and closes the SSH connection.
+func (t *SSHTunnel) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	select {
+	case <-t.done:
+		return nil
+	default:
+	}
+	close(t.done)
+
+	err := t.listener.Close()
+	if t.client != nil {
+		err = errors.Join(err, t.client.Close())
+	}
+	return err
+}
+
+func (t *SSHTunnel) accept() {
+	for {
+		local, err := t.listener.Accept()
+		if err != nil {
+			select {
+			case <-t.done:
+			default:
+				logger.Error("SSH tunnel stopped accepting connections", map[string]any{"error": err})
+			}
+			return
+		}
+
+		go t.forward(local)
+	}
+}
+
+func (t *SSHTunnel) forward(local net.Conn) {
+	defer local.Close()
+
+	remote, err := t.dialRemote()
+	if err != nil {
+		logger.Error("SSH tunnel failed to reach the remote address", map[string]any{"remote": t.remote, "error": err})
+		return
+	}
+	defer remote.Close()
+
+	copied := make(chan struct{}, 2)
+	go func() {
+		_, _ = io.Copy(remote, local)
+		copied <- struct{}{}
+	}()
+	go func() {
+		_, _ = io.Copy(local, remote)
+		copied <- struct{}{}
+	}()
+	<-copied
+}
+
+// dialRemote opens a connection to the remote address, reconnecting to the
+// SSH server first if the connection was lost.
+func (t *SSHTunnel) dialRemote() (net.Conn, error) {
+	client, err := t.currentClient()
+	if err != nil {
+		return nil, err
+	}
+
+	remote, err := client.Dial("tcp", t.remote)
+	if err == nil {
+		return remote, nil
+	}
+
+	// The SSH connection may have been lost since the last keepalive.
+	if ping(client) == nil {
+		return nil, err
+	}
+	t.lost(client, err)
+
+	client, err = t.currentClient()
+	if err != nil {
+		return nil, err
+	}
+	return client.Dial("tcp", t.remote)
+}
+
+// currentClient returns the SSH connection, opening it again if it was
+// lost.
+func (t *SSHTunnel) currentClient() (*ssh.Client, error) {
+	client, notify, err := t.openClient()
+	notify()
+	return client, err
+}
+
+// openClient is currentClient with the lock held. It returns the report of
+// the new state of the SSH connection, to notify once the lock is released.
+func (t *SSHTunnel) openClient() (*ssh.Client, func(), error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.client != nil {
+		return t.client, func() {}, nil
+	}
+
+	select {
+	case <-t.done:
+		return nil, func() {}, errors.New("the SSH tunnel is closed")
+	default:
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), sshDialTimeout)
+	defer cancel()
+
+	client, err := t.dial(ctx)
+	if err != nil {
+		return nil, t.report(err), err
+	}
+	t.client = client
+	return client, t.report(nil), nil
+}
+
+// lost drops client, the SSH connection lost with err.
+func (t *SSHTunnel) lost(client *ssh.Client, err error) {
+	t.mu.Lock()
+	if t.client != client {
+		t.mu.Unlock()
+		return
+	}
+	_ = client.Close()
+	t.client = nil
+	notify := t.report(fmt.Errorf("SSH connection to %s lost: %w", t.config.Host, err))
+	t.mu.Unlock()
+
+	notify()
+}
+
+// report records err, the state of the SSH connection, with the lock held.
+// It returns the function calling onStatus when the state changes, to call
+// once the lock is released as onStatus may wait for the UI.
+func (t *SSHTunnel) report(err error) (notify func()) {
+	if (err == nil) == (t.err == nil) {
+		t.err = err
+		return func() {}
+	}
+	t.err = err
+
+	if err != nil {
+		logger.Error("SSH tunnel error", map[string]any{"host": t.config.Host, "error": err})
+	} else {
+		logger.Info("SSH tunnel reconnected", map[string]any{"host": t.config.Host})
+	}
+
+	onStatus := t.onStatus
+	return func() {
+		if onStatus != nil {
+			onStatus(err)
+		}
+	}
+}
+
+// keepAlive checks the SSH connection periodically, opening it again when
+// lost.
+func (t *SSHTunnel) keepAlive() {
+	ticker := time.NewTicker(sshKeepAliveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-t.done:
+			return
+		case <-ticker.C:
+		}
+
+		client, err := t.currentClient()
+		if err != nil {
+			continue
+		}
+
+		if err := ping(client); err != nil {
+			t.lost(client, err)
+			_, _ = t.currentClient()
+		}
+	}
+}
+
+// ping sends a keepalive to the SSH server, which may not answer when the
+// network is down.
+func ping(client *ssh.Client) error {
+	replied := make(chan error, 1)
+	go func() {
+		_, _, err := client.SendRequest("keepalive@openssh.com", true, nil)
+		replied <- err
+	}()
+
+	select {
+	case err := <-replied:
+		return err
+	case <-time.After(sshDialTimeout):
+		return errors.New("keepalive timed out")
+	}
+}
+
+func (t *SSHTunnel) dial(ctx context.Context) (*ssh.Client, error) {
+	clientConfig, err := sshClientConfig(t.config)
+	if err != nil {
+		return nil, err
+	}
+
+	address := t.config.Host
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		address = net.JoinHostPort(address, "22")
+	}
+
+	dialer := net.Dialer{Timeout: sshDialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach the SSH server %s: %w", address, err)
+	}
+
+	sshConn, channels, requests, err := ssh.NewClientConn(conn, address, clientConfig)
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("failed to connect to the SSH server %s: %w", address, err)
+	}
+
+	return ssh.NewClient(sshConn, channels, requests), nil
+}
+
+// sshClientConfig authenticates with the key file and the agent of config,
+// the agent being used when no key file is set, and checks the host key
+// against its known_hosts file.
+func sshClientConfig(config models.SSHTunnel) (*ssh.ClientConfig, error) {
+	username := config.User
+	if username == "" {
+		current, err := user.Current()
+		if err != nil {
+			return nil, err
+		}
+		username = current.Username
+	}
+
+	var auth []ssh.AuthMethod
+
+	if config.KeyFile != "" {
+		key, err := os.ReadFile(expandHome(config.KeyFile))
+		if err != nil {
+			return nil, err
+		}
+		signer, err := ssh.ParsePrivateKey(key)
+		var passphraseErr *ssh.PassphraseMissingError
+		if errors.As(err, &passphraseErr) {
+			return nil, fmt.Errorf("the key %s is encrypted, add it to the SSH agent instead", config.KeyFile)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the key %s: %w", config.KeyFile, err)
+		}
+		auth = append(auth, ssh.PublicKeys(signer))
+	}
+
+	if config.Agent || config.KeyFile == "" {
+		socket := os.Getenv("SSH_AUTH_SOCK")
+		if socket == "" {
+			return nil, errors.New("the SSH agent is not running: SSH_AUTH_SOCK is not set")
+		}
+		auth = append(auth, ssh.PublicKeysCallback(func() ([]ssh.Signer, error) {
+			conn, err := net.Dial("unix", socket)
+			if err != nil {
+				return nil, err
+			}
+			defer conn.Close()
+			return agent.NewClient(conn).Signers()
+		}))
+	}
+
+	knownHostsFile := config.KnownHosts
+	if knownHostsFile == "" {
+		knownHostsFile = "~/.ssh/known_hosts"
+	}
+	hostKeyCallback, err := knownhosts.New(expandHome(knownHostsFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the known hosts: %w", err)
+	}
+
+	return &ssh.ClientConfig{
+		User: username,
+		Auth: auth,
+		HostKeyCallback: func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+			err := hostKeyCallback(hostname, remote, key)
+			var keyErr *knownhosts.KeyError
+			if errors.As(err, &keyErr) && len(keyErr.Want) == 0 {
+				return fmt.Errorf("%s is not a known host, add its key to %s, e.g. by connecting once with ssh", hostname, knownHostsFile)
+			}
+			return err
+		},
+		Timeout: sshDialTimeout,
+	}, nil
+}
+
+// expandHome replaces the leading ~ of path by the home directory.
+func expandHome(path string) string {
+	if rest, found := strings.CutPrefix(path, "~"); found && (rest == "" || rest[0] == '/' || rest[0] == filepath.Separator) {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, rest)
+		}
+	}
+	return path
+}
@@ -0,0 +1,238 @@
+package helpers
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+// startSSHServer starts an SSH server accepting clientKey and forwarding
+// its direct-tcpip channels. It returns its address and known_hosts line.
+func startSSHServer(t *testing.T, clientKey ssh.PublicKey) (string, string) {
+	t.Helper()
+
+	_, hostKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hostSigner, err := ssh.NewSignerFromKey(hostKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := &ssh.ServerConfig{
+		PublicKeyCallback: func(_ ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if !bytes.Equal(key.Marshal(), clientKey.Marshal()) {
+				return nil, errors.New("unknown key")
+			}
+			return nil, nil
+		},
+	}
+	config.AddHostKey(hostSigner)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveSSH(conn, config)
+		}
+	}()
+
+	address := listener.Addr().String()
+	return address, knownhosts.Line([]string{knownhosts.Normalize(address)}, hostSigner.PublicKey())
+}
+
+func serveSSH(conn net.Conn, config *ssh.ServerConfig) {
+	_, channels, requests, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(requests)
+
+	for newChannel := range channels {
+		var target struct {
+			Host       string
+			Port       uint32
+			OriginHost string
+			OriginPort uint32
+		}
+		if newChannel.ChannelType() != "direct-tcpip" || ssh.Unmarshal(newChannel.ExtraData(), &target) != nil {
+			_ = newChannel.Reject(ssh.UnknownChannelType, "unsupported channel")
+			continue
+		}
+
+		remote, err := net.Dial("tcp", net.JoinHostPort(target.Host, fmt.Sprint(target.Port)))
+		if err != nil {
+			_ = newChannel.Reject(ssh.ConnectionFailed, err.Error())
+			continue
+		}
+		channel, channelRequests, err := newChannel.Accept()
+		if err != nil {
+			_ = remote.Close()
+			continue
+		}
+		go ssh.DiscardRequests(channelRequests)
+		go func() {
+			_, _ = io.Copy(channel, remote)
+			_ = channel.Close()
+		}()
+		go func() {
+			_, _ = io.Copy(remote, channel)
+			_ = remote.Close()
+		}()
+	}
+}
+
+// startEchoServer starts a TCP server writing back what it reads.
+func startEchoServer(t *testing.T) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				_, _ = io.Copy(conn, conn)
+			}()
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+func TestOpenSSHTunnel(t *testing.T) {
+	dir := t.TempDir()
+
+	clientPublic, clientKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, err := ssh.MarshalPrivateKey(clientKey, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyFile := filepath.Join(dir, "id_ed25519")
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(block), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	sshPublic, err := ssh.NewPublicKey(clientPublic)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	host, knownHostsLine := startSSHServer(t, sshPublic)
+	knownHosts := filepath.Join(dir, "known_hosts")
+	if err := os.WriteFile(knownHosts, []byte(knownHostsLine+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	remote := startEchoServer(t)
+
+	config := models.SSHTunnel{Host: host, User: "lazysql", KeyFile: keyFile, KnownHosts: knownHosts}
+
+	t.Run("forwards to the remote address", func(t *testing.T) {
+		tunnel, err := OpenSSHTunnel(context.Background(), config, remote)
+		if err != nil {
+			t.Fatalf("OpenSSHTunnel() error = %v", err)
+		}
+		defer tunnel.Close()
+
+		conn, err := net.Dial("tcp", net.JoinHostPort("127.0.0.1", tunnel.Port()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+
+		if _, err := conn.Write([]byte("ping")); err != nil {
+			t.Fatal(err)
+		}
+		reply := make([]byte, 4)
+		if _, err := io.ReadFull(conn, reply); err != nil || string(reply) != "ping" {
+			t.Errorf("got reply %q, error %v", reply, err)
+		}
+	})
+
+	t.Run("reports the state without holding the lock", func(t *testing.T) {
+		tunnel, err := OpenSSHTunnel(context.Background(), config, remote)
+		if err != nil {
+			t.Fatalf("OpenSSHTunnel() error = %v", err)
+		}
+		defer tunnel.Close()
+
+		client, err := tunnel.currentClient()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var statuses []error
+		tunnel.SetStatusFunc(func(err error) {
+			statuses = append(statuses, err)
+			if err != nil {
+				// The status func may use the tunnel, e.g. while the UI is
+				// waiting for it.
+				_, _ = tunnel.currentClient()
+			}
+		})
+
+		done := make(chan struct{})
+		go func() {
+			tunnel.lost(client, errors.New("connection reset"))
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("the status func was called with the lock held")
+		}
+
+		if len(statuses) != 2 || statuses[0] == nil || statuses[1] != nil {
+			t.Errorf("got statuses %v, want the lost connection then its reconnection", statuses)
+		}
+	})
+
+	t.Run("unknown host", func(t *testing.T) {
+		empty := filepath.Join(dir, "empty_known_hosts")
+		if err := os.WriteFile(empty, nil, 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		unknown := config
+		unknown.KnownHosts = empty
+		_, err := OpenSSHTunnel(context.Background(), unknown, remote)
+		if err == nil || !strings.Contains(err.Error(), "is not a known host") {
+			t.Errorf("OpenSSHTunnel() error = %v", err)
+		}
+	})
+}
@@ -125,6 +125,10 @@ func AddQueryToHistory(connectionIdentifier string, queryText string) error {
 		return nil // Don't add empty or whitespace-only queries
 	}
 
+	// Keep the secrets of the connections, e.g. in a CREATE USER statement,
+	// out of the history file.
+	queryText = logger.Redact(queryText)
+
 	historyFilePath, err := GetHistoryFilePath(connectionIdentifier)
 	if err != nil {
 		return fmt.Errorf("failed to get history file path for AddQueryToHistory: %w", err)
@@ -0,0 +1,35 @@
+package history
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jorgerojas26/lazysql/helpers/logger"
+)
+
+func TestAddQueryToHistory_RedactsSecrets(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	logger.AddSecret("s3cr3t-password")
+
+	if err := AddQueryToHistory("test", "CREATE USER app WITH PASSWORD 's3cr3t-password'"); err != nil {
+		t.Fatal(err)
+	}
+
+	path, err := GetHistoryFilePath("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	items, err := ReadHistory(path, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 {
+		t.Fatalf("expected 1 history item, got %d", len(items))
+	}
+	if strings.Contains(items[0].QueryText, "s3cr3t-password") {
+		t.Errorf("the secret was written to the history: %q", items[0].QueryText)
+	}
+	if want := "CREATE USER app WITH PASSWORD '********'"; items[0].QueryText != want {
+		t.Errorf("expected %q, got %q", want, items[0].QueryText)
+	}
+}
@@ -145,6 +145,48 @@ func DeleteSavedQuery(connectionIdentifier, name string) error {
 	return writeSavedQueries(connectionIdentifier, newQueries)
 }
 
+// GetQueryParameters returns the parameter values last used with the saved
+// query whose text is query. It returns nil if query is not saved.
+func GetQueryParameters(connectionIdentifier, query string) ([]models.QueryParameter, error) {
+	savedQueries, err := ReadSavedQueries(connectionIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	query = strings.TrimSpace(query)
+	for _, q := range savedQueries {
+		if strings.TrimSpace(q.Query) == query {
+			return q.Parameters, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// SaveQueryParameters remembers the parameter values used with every saved
+// query whose text is query. Queries that are not saved are ignored.
+func SaveQueryParameters(connectionIdentifier, query string, parameters []models.QueryParameter) error {
+	savedQueries, err := ReadSavedQueries(connectionIdentifier)
+	if err != nil {
+		return err
+	}
+
+	query = strings.TrimSpace(query)
+	found := false
+	for i, q := range savedQueries {
+		if strings.TrimSpace(q.Query) == query {
+			savedQueries[i].Parameters = parameters
+			found = true
+		}
+	}
+
+	if !found {
+		return nil
+	}
+
+	return writeSavedQueries(connectionIdentifier, savedQueries)
+}
+
 func writeSavedQueries(connectionIdentifier string, queries []models.SavedQuery) error {
 	filePath, err := GetSavedQueriesFilePath(connectionIdentifier)
 	if err != nil {
@@ -0,0 +1,16 @@
+package models
+
+// RowDiff is a row that differs between the source and the target of a
+// data diff. Its Type is the statement that makes the target match the
+// source: an insert for a row only in the source, a delete for a row only
+// in the target and an update for a row whose values differ.
+type RowDiff struct {
+	Type DMLType
+	// Source and Target hold the values of the compared columns on each
+	// side, nil on the side the row is missing from.
+	Source Record
+	Target Record
+	// Changed holds the indexes of the columns whose values differ in an
+	// update.
+	Changed []int
+}
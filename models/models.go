@@ -15,6 +15,7 @@ type AppConfig struct {
 	JSONViewerWordWrap           bool
 	EnterOpensJSONViewer         bool
 	ConfirmOnQuit                bool
+	ContinueScriptOnError        bool
 }
 
 type Connection struct {
@@ -24,12 +25,16 @@ type Connection struct {
 	URL string `toml:",omitempty"`
 
 	// or parse manually
-	Provider  string `toml:",omitempty"`
-	Username  string `toml:",omitempty"`
-	Password  string `toml:",omitempty"`
-	Hostname  string `toml:",omitempty"`
-	Port      string `toml:",omitempty"`
-	DBName    string `toml:",omitempty"`
+	Provider string `toml:",omitempty"`
+	Username string `toml:",omitempty"`
+	Password string `toml:",omitempty"`
+	Hostname string `toml:",omitempty"`
+	Port     string `toml:",omitempty"`
+	DBName   string `toml:",omitempty"`
+	// SSLMode is passed as the sslmode parameter on PostgreSQL, tls on
+	// MySQL and encrypt on MSSQL.
+	SSLMode string `toml:",omitempty"`
+	// URLParams are extra parameters of the URL, e.g. "connect_timeout=10".
 	URLParams string `toml:",omitempty"`
 
 	ReadOnly bool `toml:",omitempty"`
@@ -39,6 +44,40 @@ type Connection struct {
 	Schemas []string `toml:",omitempty"`
 
 	Commands []*Command `toml:",omitempty"`
+
+	// StatementTimeout cancels the statements running longer, e.g. "30s"
+	// (PostgreSQL, and MySQL for SELECT statements only).
+	StatementTimeout string `toml:",omitempty"`
+	// Role is the role of the session (PostgreSQL and MySQL).
+	Role string `toml:",omitempty"`
+	// SearchPath are the schemas of the unqualified names (PostgreSQL only).
+	SearchPath []string `toml:",omitempty"`
+	// InitSQL are statements run on every connection opened to the database,
+	// after the options above, e.g. "SET TIME ZONE 'UTC'".
+	InitSQL []string `toml:",omitempty"`
+
+	// SSH tunnels the connection through an SSH server, its local port
+	// replacing ${port} in the URL.
+	SSH *SSHTunnel `toml:",omitempty"`
+}
+
+type SSHTunnel struct {
+	// Host is the SSH server, "host" or "host:port".
+	Host string
+	// User defaults to the current user.
+	User string `toml:",omitempty"`
+	// KeyFile is a private key, e.g. "~/.ssh/id_ed25519".
+	KeyFile string `toml:",omitempty"`
+	// Agent authenticates with the keys of the SSH agent, which is always
+	// used when no KeyFile is set.
+	Agent bool `toml:",omitempty"`
+	// KnownHosts is the file checking the key of Host, "~/.ssh/known_hosts"
+	// by default.
+	KnownHosts string `toml:",omitempty"`
+	// Remote is the address forwarded to, as seen from Host, e.g.
+	// "localhost:5432". It defaults to the Hostname and Port of the
+	// connection, which is then made to the local port.
+	Remote string `toml:",omitempty"`
 }
 
 type KeymapConfig map[string]map[string]string
@@ -131,3 +170,11 @@ type QueryHistoryItem struct {
 	QueryText string
 	Timestamp time.Time
 }
+
+// ResultColumn describes a column of a query result.
+type ResultColumn struct {
+	Name string
+	// Type is the database type name, e.g. "VARCHAR" or "INT4". It is
+	// empty if the driver does not report it.
+	Type string
+}
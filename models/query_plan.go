@@ -0,0 +1,19 @@
+package models
+
+// PlanNode is one step of a query plan as reported by the EXPLAIN statement
+// of the database.
+type PlanNode struct {
+	// Type is the operation of the step, e.g. "Seq Scan" or "Hash Join".
+	Type string
+	// Detail names what the step works on, e.g. a table or an index.
+	Detail string
+	// Cost is the estimated cost of the step including its children, 0 if
+	// the database does not report one.
+	Cost float64
+	// Rows is the estimated number of rows the step returns, 0 if the
+	// database does not report one.
+	Rows float64
+	// FullScan is set if the step reads a whole table.
+	FullScan bool
+	Children []*PlanNode
+}
@@ -0,0 +1,79 @@
+package models
+
+import "strings"
+
+// Value is a single value of a result as read from the database.
+type Value struct {
+	// Type is the database type name of the column, see ResultColumn.
+	Type string
+	Null bool
+	// Raw is the value as text. It is nil for NULL.
+	Raw []byte
+}
+
+// Record is a row of a result. The first record of a result holds the
+// column names.
+type Record []Value
+
+// numericTypes are the database type names, without size or sign, of the
+// columns whose values are numbers.
+var numericTypes = map[string]bool{
+	"BIGINT": true, "BIGSERIAL": true, "DECIMAL": true, "DOUBLE": true,
+	"DOUBLE PRECISION": true, "FLOAT": true, "FLOAT4": true, "FLOAT8": true,
+	"INT": true, "INT2": true, "INT4": true, "INT8": true, "INTEGER": true,
+	"MEDIUMINT": true, "MONEY": true, "NUMBER": true, "NUMERIC": true,
+	"REAL": true, "SERIAL": true, "SMALLINT": true, "SMALLMONEY": true,
+	"SMALLSERIAL": true, "TINYINT": true,
+}
+
+// TextValue returns a value holding text.
+func TextValue(text string) Value {
+	return Value{Raw: []byte(text)}
+}
+
+// String returns the value as text, the empty string for NULL.
+func (v Value) String() string {
+	return string(v.Raw)
+}
+
+// IsEmpty reports whether the value is an empty string, which is not NULL.
+func (v Value) IsEmpty() bool {
+	return !v.Null && len(v.Raw) == 0
+}
+
+// IsNumeric reports whether the column of the value holds numbers.
+func (v Value) IsNumeric() bool {
+	typeName := strings.ToUpper(strings.TrimSpace(v.Type))
+	typeName, _, _ = strings.Cut(typeName, "(")
+	typeName = strings.TrimPrefix(typeName, "UNSIGNED ")
+	typeName = strings.TrimSuffix(typeName, " UNSIGNED")
+	return numericTypes[strings.TrimSpace(typeName)]
+}
+
+// HeaderRecord returns the record holding the names of columns.
+func HeaderRecord(columns []ResultColumn) Record {
+	header := make(Record, len(columns))
+	for i, column := range columns {
+		header[i] = Value{Type: column.Type, Raw: []byte(column.Name)}
+	}
+	return header
+}
+
+// Strings returns the values of the record as text, NULL as the empty
+// string.
+func (r Record) Strings() []string {
+	texts := make([]string, len(r))
+	for i, value := range r {
+		texts[i] = value.String()
+	}
+	return texts
+}
+
+// RecordStrings returns records as text, see Record.Strings.
+func RecordStrings(records []Record) [][]string {
+	texts := make([][]string, len(records))
+	for i, record := range records {
+		texts[i] = record.Strings()
+	}
+	return texts
+}
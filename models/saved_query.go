@@ -2,6 +2,15 @@ package models
 
 // SavedQuery represents a query that the user has saved for later use.
 type SavedQuery struct {
+	Name       string           `toml:"name"`
+	Query      string           `toml:"query"`
+	Parameters []QueryParameter `toml:"parameters,omitempty"`
+}
+
+// QueryParameter is the value and type last bound to a placeholder of a
+// saved query, e.g. "$1", "?1" or ":name".
+type QueryParameter struct {
 	Name  string `toml:"name"`
-	Query string `toml:"query"`
+	Type  string `toml:"type"`
+	Value string `toml:"value"`
 }
@@ -0,0 +1,142 @@
+package models
+
+// TableSchema is the structure of a table, as compared by a schema diff.
+type TableSchema struct {
+	// Name is the table name, prefixed by its schema for the databases that
+	// use schemas, e.g. "public.users".
+	Name        string
+	Columns     []ColumnSchema
+	PrimaryKey  *ConstraintSchema
+	Indexes     []IndexSchema
+	ForeignKeys []ForeignKeySchema
+}
+
+type ColumnSchema struct {
+	Name     string
+	Type     string
+	Nullable bool
+	// Default is the default expression as reported by the database, empty
+	// if the column has none.
+	Default string
+}
+
+type ConstraintSchema struct {
+	Name    string
+	Columns []string
+}
+
+type IndexSchema struct {
+	Name    string
+	Columns []string
+	Unique  bool
+	// Constraint is true for the indexes backing a UNIQUE constraint, which
+	// are created and dropped through their constraint.
+	Constraint bool
+}
+
+type ForeignKeySchema struct {
+	Name              string
+	Columns           []string
+	ReferencedTable   string
+	ReferencedColumns []string
+}
+
+// ReferencingForeignKey is a foreign key of Table referencing the table it
+// was read for, whose ReferencedTable is left empty.
+type ReferencingForeignKey struct {
+	// Table is the table of the foreign key, prefixed by its schema for the
+	// databases that use schemas.
+	Table string
+	ForeignKeySchema
+}
+
+// SchemaChangeType is the kind of difference of a schema diff.
+type SchemaChangeType int8
+
+const (
+	SchemaAdded SchemaChangeType = iota
+	SchemaRemoved
+	SchemaChanged
+)
+
+func (t SchemaChangeType) String() string {
+	switch t {
+	case SchemaAdded:
+		return "added"
+	case SchemaRemoved:
+		return "removed"
+	default:
+		return "changed"
+	}
+}
+
+// SchemaObjectKind is the kind of object a schema change is about.
+type SchemaObjectKind int8
+
+const (
+	SchemaTable SchemaObjectKind = iota
+	SchemaColumn
+	SchemaPrimaryKey
+	SchemaIndex
+	SchemaForeignKey
+)
+
+func (k SchemaObjectKind) String() string {
+	switch k {
+	case SchemaColumn:
+		return "column"
+	case SchemaPrimaryKey:
+		return "primary key"
+	case SchemaIndex:
+		return "index"
+	case SchemaForeignKey:
+		return "foreign key"
+	default:
+		return "table"
+	}
+}
+
+// SchemaChange is a difference between the source and the target of a
+// schema diff. Added objects are only in the source and removed objects
+// only in the target, so that applying the changes to the target makes it
+// match the source.
+type SchemaChange struct {
+	Type  SchemaChangeType
+	Kind  SchemaObjectKind
+	Table string
+	// Name is the name of the column, index or foreign key. It is empty for
+	// tables and primary keys.
+	Name string
+	// Source and Target describe the object on each side, empty on the side
+	// it is missing from.
+	Source string
+	Target string
+}
+
+// DDLChangeType is the kind of a change to the structure of a table.
+type DDLChangeType int8
+
+const (
+	DDLAddColumn DDLChangeType = iota
+	DDLDropColumn
+	DDLRenameColumn
+	DDLAlterColumn
+	DDLCreateIndex
+	DDLDropIndex
+)
+
+// DBDDLChange is a change to the structure of a table staged from its
+// Columns, Constraints or Indexes view, as DBDMLChange is for its records.
+type DBDDLChange struct {
+	Database string
+	Table    string
+	Type     DDLChangeType
+	// Column is the column added, dropped or altered, and the new name of a
+	// renamed column.
+	Column ColumnSchema
+	// OldColumn is the column before it is renamed or altered.
+	OldColumn ColumnSchema
+	// Index is the index created or dropped. Indexes with Constraint set are
+	// UNIQUE constraints.
+	Index IndexSchema
+}